@@ -1,6 +1,8 @@
 package api
 
 import (
+	"net"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,6 +23,25 @@ func ClientIP(c *gin.Context) (ip string) {
 	return UnknownIP
 }
 
+// LocalClient checks if the request comes from localhost or a private network. Requests forwarded by an untrusted
+// proxy are not considered local, since the proxy address would be checked instead of the client address.
+func LocalClient(c *gin.Context) bool {
+	if c == nil || c.Request == nil {
+		return false
+	}
+
+	ip := net.ParseIP(c.ClientIP())
+
+	if ip == nil || !ip.IsLoopback() && !ip.IsPrivate() {
+		return false
+	}
+
+	// Forwarded requests are only local if the client address was taken from the headers of a trusted proxy.
+	forwarded := c.GetHeader("X-Forwarded-For") != "" || c.GetHeader("X-Real-Ip") != ""
+
+	return !forwarded || c.ClientIP() != c.RemoteIP()
+}
+
 // UserAgent returns the user agent from the request context or an empty string if it is unknown.
 func UserAgent(c *gin.Context) string {
 	if c == nil {
@@ -4,10 +4,16 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
 	"github.com/photoprism/photoprism/pkg/clean"
 )
 
+// TokenPlaceholder may be used instead of a token in URLs that require one, e.g. by local tools.
+const TokenPlaceholder = "-"
+
 // InvalidPreviewToken checks if the token found in the request is valid for image thumbnails and video streams.
+// Requests without token are allowed from localhost and private networks if enabled, while requests with a
+// wrong token are always rejected.
 func InvalidPreviewToken(c *gin.Context) bool {
 	token := clean.UrlToken(c.Param("token"))
 
@@ -15,9 +21,19 @@ func InvalidPreviewToken(c *gin.Context) bool {
 		token = clean.UrlToken(c.Query("t"))
 	}
 
+	// Tokens that cannot be sanitized are wrong, not missing, so the raw values are checked.
+	if MissingToken(c.Param("token")) && MissingToken(c.Query("t")) && get.Config().PreviewTokenLocal() && LocalClient(c) {
+		return false
+	}
+
 	return entity.InvalidPreviewToken(token)
 }
 
+// MissingToken checks if no token was provided, as opposed to a wrong token.
+func MissingToken(token string) bool {
+	return token == "" || token == TokenPlaceholder
+}
+
 // InvalidDownloadToken checks if the token found in the request is valid for file downloads.
 func InvalidDownloadToken(c *gin.Context) bool {
 	return entity.InvalidDownloadToken(clean.UrlToken(c.Query("t")))
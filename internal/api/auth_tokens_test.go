@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingToken(t *testing.T) {
+	assert.True(t, MissingToken(""))
+	assert.True(t, MissingToken("-"))
+	assert.False(t, MissingToken("public"))
+}
+
+func TestLocalClient(t *testing.T) {
+	newContext := func(remoteAddr string, headers map[string]string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.RemoteAddr = remoteAddr
+
+		for k, v := range headers {
+			c.Request.Header.Set(k, v)
+		}
+
+		return c
+	}
+
+	t.Run("Localhost", func(t *testing.T) {
+		assert.True(t, LocalClient(newContext("127.0.0.1:1234", nil)))
+	})
+	t.Run("PrivateNetwork", func(t *testing.T) {
+		assert.True(t, LocalClient(newContext("192.168.1.20:1234", nil)))
+	})
+	t.Run("Public", func(t *testing.T) {
+		assert.False(t, LocalClient(newContext("8.8.8.8:1234", nil)))
+	})
+	t.Run("UntrustedProxy", func(t *testing.T) {
+		assert.False(t, LocalClient(newContext("192.168.1.20:1234", map[string]string{"X-Forwarded-For": "8.8.8.8"})))
+	})
+	t.Run("Nil", func(t *testing.T) {
+		assert.False(t, LocalClient(nil))
+	})
+}
@@ -6,7 +6,9 @@ import (
 	"path"
 
 	"github.com/gin-gonic/gin"
+	gc "github.com/patrickmn/go-cache"
 
+	"github.com/photoprism/photoprism/internal/customize"
 	"github.com/photoprism/photoprism/internal/get"
 	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/internal/thumb"
@@ -18,6 +20,8 @@ import (
 type ThumbCache struct {
 	FileName  string
 	ShareName string
+	GPS       string
+	PhotoUID  string
 }
 
 type ByteCache struct {
@@ -29,6 +33,58 @@ func CacheKey(ns, uid, name string) string {
 	return fmt.Sprintf("%s:%s:%s", ns, uid, name)
 }
 
+// ThumbCacheKey returns the thumb cache key for the specified size. Downloads are cached separately
+// for each naming scheme, as their file names differ from the share names cached for inline thumbs.
+func ThumbCacheKey(thumbHash string, sizeName thumb.Name, downloadName customize.DownloadName) string {
+	if downloadName == "" {
+		return CacheKey("thumbs", thumbHash, string(sizeName))
+	}
+
+	return CacheKey("thumbs", thumbHash, fmt.Sprintf("%s:%s", sizeName, downloadName))
+}
+
+// ShareNameCacheKey returns the cache key of the download name and location of a file hash for the
+// specified naming scheme, which are the same for all thumbnail sizes.
+func ShareNameCacheKey(fileHash string, downloadName customize.DownloadName) string {
+	return CacheKey("share-names", fileHash, string(downloadName))
+}
+
+// SetThumbCache adds a thumbnail filename to the memory cache. Pinned thumbnails never expire, see thumb.Pin,
+// while those of uncached sizes expire after their TTL, see thumb.UncachedTTL.
+func SetThumbCache(cacheKey, fileHash string, sizeName thumb.Name, data ThumbCache) {
+	if thumb.Pinned(fileHash, sizeName) {
+		get.ThumbCache().Set(cacheKey, data, gc.NoExpiration)
+	} else if ttl := thumb.Sizes[sizeName].TTL(); ttl > 0 {
+		get.ThumbCache().Set(cacheKey, data, ttl)
+	} else {
+		get.ThumbCache().SetDefault(cacheKey, data)
+	}
+}
+
+// UpdateThumbCachePin updates the expiration of cached thumbnail filenames after they were pinned or unpinned.
+func UpdateThumbCachePin(fileHash string, sizeName thumb.Name) {
+	cache := get.ThumbCache()
+	cacheKeys := []string{ThumbCacheKey(fileHash, sizeName, "")}
+
+	for _, downloadName := range thumbDownloadNames {
+		cacheKeys = append(cacheKeys, ThumbCacheKey(fileHash, sizeName, downloadName))
+	}
+
+	for _, cacheKey := range cacheKeys {
+		if data, ok := cache.Get(cacheKey); ok {
+			SetThumbCache(cacheKey, fileHash, sizeName, data.(ThumbCache))
+		}
+	}
+}
+
+// thumbDownloadNames contains the download naming schemes used in thumb cache keys.
+var thumbDownloadNames = []customize.DownloadName{
+	customize.DownloadNameFile,
+	customize.DownloadNameOriginal,
+	customize.DownloadNameShare,
+	customize.DownloadNameTemplate,
+}
+
 // RemoveFromFolderCache removes an item from the folder cache e.g. after indexing.
 func RemoveFromFolderCache(rootName string) {
 	cache := get.FolderCache()
@@ -44,6 +100,29 @@ func RemoveFromFolderCache(rootName string) {
 	log.Debugf("removed %s from cache", cacheKey)
 }
 
+// RemoveFromThumbCache removes the cached thumbnail file names of a file hash e.g. after its thumbnails were recreated.
+func RemoveFromThumbCache(fileHash string) {
+	if fileHash == "" {
+		return
+	}
+
+	cache := get.ThumbCache()
+
+	for thumbName := range thumb.Sizes {
+		cache.Delete(ThumbCacheKey(fileHash, thumbName, ""))
+
+		for _, downloadName := range thumbDownloadNames {
+			cache.Delete(ThumbCacheKey(fileHash, thumbName, downloadName))
+		}
+	}
+
+	for _, downloadName := range thumbDownloadNames {
+		cache.Delete(ShareNameCacheKey(fileHash, downloadName))
+	}
+
+	log.Debugf("removed %s from thumb cache", fileHash)
+}
+
 // RemoveFromAlbumCoverCache removes covers by album UID e.g. after adding or removing photos.
 func RemoveFromAlbumCoverCache(uid string) {
 	if !rnd.IsAlnum(uid) {
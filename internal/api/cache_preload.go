@@ -0,0 +1,141 @@
+package api
+
+import (
+	"container/heap"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// thumbPreload represents a thumbnail file found in the cache folder.
+type thumbPreload struct {
+	FileName string
+	FileHash string
+	Sizes    []thumb.Name
+	ModTime  time.Time
+}
+
+// thumbPreloads is a min-heap of thumbnails ordered by modification time, so that only the most recent
+// ones are kept while the cache folder is walked.
+type thumbPreloads []thumbPreload
+
+func (h thumbPreloads) Len() int           { return len(h) }
+func (h thumbPreloads) Less(i, j int) bool { return h[i].ModTime.Before(h[j].ModTime) }
+func (h thumbPreloads) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *thumbPreloads) Push(x interface{}) {
+	*h = append(*h, x.(thumbPreload))
+}
+
+func (h *thumbPreloads) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// PreloadScanLimit is the maximum number of thumbnail files that are checked when preloading the cache,
+// so that startup is not delayed by walking a cache with millions of files.
+var PreloadScanLimit = 100000
+
+// PreloadThumbCache adds the file names of up to limit thumbnails that were created within
+// maxAge to the memory cache, so that the first requests after a restart do not need to query the index.
+// Sidecar folders below the originals folder are included if this layout is used, see thumb.Walk.
+// Only the most recent thumbnails are kept while walking the cache, which stops after PreloadScanLimit files.
+func PreloadThumbCache(cachePath, originalsPath string, limit int, maxAge time.Duration) (count int, err error) {
+	if limit <= 0 || cachePath == "" {
+		return 0, nil
+	}
+
+	start := time.Now()
+
+	// Map thumbnail file name suffixes to the matching size names.
+	suffixes := make(map[string][]thumb.Name, len(thumb.Sizes))
+
+	for name, size := range thumb.Sizes {
+		suffix := thumb.Suffix(size.Width, size.Height, size.Options...)
+		suffixes[suffix] = append(suffixes[suffix], name)
+	}
+
+	found := make(thumbPreloads, 0, limit)
+	scanned := 0
+
+	minTime := start.Add(-1 * maxAge)
+
+	err = thumb.Walk(cachePath, originalsPath, func(fileName string, d os.DirEntry) error {
+		// Thumbnail file names have the format "[hash]_[width]x[height]_[method].[format]".
+		hash, suffix, ok := strings.Cut(d.Name(), "_")
+
+		if !ok || len(hash) < 4 {
+			return nil
+		}
+
+		sizes, ok := suffixes[suffix]
+
+		if !ok {
+			return nil
+		} else if scanned++; scanned > PreloadScanLimit {
+			return thumb.ErrStopWalk
+		}
+
+		info, err := d.Info()
+
+		if err != nil || info.ModTime().Before(minTime) {
+			return nil
+		} else if len(found) >= limit && !info.ModTime().After(found[0].ModTime) {
+			return nil
+		}
+
+		heap.Push(&found, thumbPreload{FileName: fileName, FileHash: hash, Sizes: sizes, ModTime: info.ModTime()})
+
+		// Drop the oldest thumbnail if the limit is exceeded.
+		if len(found) > limit {
+			heap.Pop(&found)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	// Add the most recent thumbnails first.
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].ModTime.After(found[j].ModTime)
+	})
+
+	files := make(map[string]*entity.File)
+
+	for _, t := range found {
+		f, ok := files[t.FileHash]
+
+		if !ok {
+			if f, err = query.FileByHash(t.FileHash); err != nil {
+				f = nil
+			}
+
+			files[t.FileHash] = f
+		}
+
+		// Skip thumbnails of files that are no longer indexed.
+		if f == nil {
+			continue
+		}
+
+		for _, sizeName := range t.Sizes {
+			SetThumbCache(ThumbCacheKey(t.FileHash, sizeName, ""), t.FileHash, sizeName, ThumbCache{FileName: t.FileName, ShareName: f.ShareBase(0), GPS: ThumbGPS(f), PhotoUID: f.PhotoUID})
+			count++
+		}
+	}
+
+	log.Infof("thumbs: preloaded %d cache entries [%s]", count, time.Since(start))
+
+	return count, nil
+}
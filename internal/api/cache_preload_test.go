@@ -0,0 +1,101 @@
+package api
+
+import (
+	"container/heap"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestPreloadThumbCache(t *testing.T) {
+	thumbPath := t.TempDir()
+	fileHash := "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+
+	fileName, err := thumb.Sizes[thumb.Tile50].FileName(fileHash, thumbPath)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(fileName, []byte("jpeg"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ignored because the file is too old.
+	oldName, err := thumb.Sizes[thumb.Tile50].FileName("acad9168fa6acc5c5c2965ddf6ec465ca42fd834", thumbPath)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(oldName, []byte("jpeg"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	oldTime := time.Now().Add(-48 * time.Hour)
+
+	if err = os.Chtimes(oldName, oldTime, oldTime); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ignored because the file name has an unknown suffix.
+	if err = os.WriteFile(filepath.Join(thumbPath, "foo_bar.jpg"), []byte("jpeg"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		count, err := PreloadThumbCache(thumbPath, "", 0, time.Hour)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+	t.Run("Success", func(t *testing.T) {
+		cacheKey := CacheKey("thumbs", fileHash, string(thumb.Tile50))
+		defer get.ThumbCache().Delete(cacheKey)
+
+		count, err := PreloadThumbCache(thumbPath, "", 100, 24*time.Hour)
+
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, count, 1)
+
+		if cached, ok := get.ThumbCache().Get(cacheKey); !ok {
+			t.Fatal("expected cache entry")
+		} else {
+			assert.Equal(t, fileName, cached.(ThumbCache).FileName)
+			assert.NotEmpty(t, cached.(ThumbCache).ShareName)
+		}
+	})
+	t.Run("ScanLimit", func(t *testing.T) {
+		PreloadScanLimit = 0
+		defer func() { PreloadScanLimit = 100000 }()
+
+		count, err := PreloadThumbCache(thumbPath, "", 100, 24*time.Hour)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func TestThumbPreloads(t *testing.T) {
+	now := time.Now()
+	found := make(thumbPreloads, 0, 3)
+
+	for i := 0; i < 5; i++ {
+		heap.Push(&found, thumbPreload{FileHash: fmt.Sprintf("%d", i), ModTime: now.Add(time.Duration(i) * time.Minute)})
+
+		if len(found) > 2 {
+			heap.Pop(&found)
+		}
+	}
+
+	assert.Len(t, found, 2)
+	assert.Equal(t, "3", found[0].FileHash)
+}
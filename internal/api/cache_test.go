@@ -3,10 +3,15 @@ package api
 import (
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/customize"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
 )
 
 func TestAddVideoCacheHeader(t *testing.T) {
@@ -27,3 +32,32 @@ func TestAddVideoCacheHeader(t *testing.T) {
 		assert.Equal(t, "private, max-age=21600, immutable", s)
 	})
 }
+
+func TestThumbCacheKey(t *testing.T) {
+	assert.Equal(t, "thumbs:abc:tile_224", ThumbCacheKey("abc", thumb.Tile224, ""))
+	assert.Equal(t, "thumbs:abc:tile_224:file", ThumbCacheKey("abc", thumb.Tile224, customize.DownloadNameFile))
+	assert.NotEqual(t, ThumbCacheKey("abc", thumb.Tile224, customize.DownloadNameShare), ThumbCacheKey("abc", thumb.Tile224, ""))
+}
+
+func TestShareNameCacheKey(t *testing.T) {
+	assert.Equal(t, "share-names:abc:file", ShareNameCacheKey("abc", customize.DownloadNameFile))
+	assert.NotEqual(t, ShareNameCacheKey("abc", customize.DownloadNameShare), ShareNameCacheKey("abc", customize.DownloadNameFile))
+}
+
+func TestSetThumbCache(t *testing.T) {
+	hash := "ca5e8f767d5604289bccfeaa526a36e19b555fd4"
+
+	t.Run("UncachedTTL", func(t *testing.T) {
+		thumb.UncachedTTL = map[thumb.Name]time.Duration{thumb.Fit7680: time.Minute}
+		defer func() { thumb.UncachedTTL = map[thumb.Name]time.Duration{} }()
+
+		cacheKey := ThumbCacheKey(hash, thumb.Fit7680, "")
+		SetThumbCache(cacheKey, hash, thumb.Fit7680, ThumbCache{FileName: "test.jpg"})
+
+		_, expires, found := get.ThumbCache().GetWithExpiration(cacheKey)
+		assert.True(t, found)
+		assert.True(t, time.Until(expires) <= time.Minute)
+
+		get.ThumbCache().Delete(cacheKey)
+	})
+}
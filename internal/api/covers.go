@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/get"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/query"
@@ -31,107 +32,18 @@ const (
 //	token: string security token (see config)
 //	size: string thumb type, see photoprism.ThumbnailTypes
 func AlbumCover(router *gin.RouterGroup) {
-	router.GET("/albums/:uid/t/:token/:size", func(c *gin.Context) {
+	router.GET("/albums/:uid/t/:token/:size", ThumbBudget(func(c *gin.Context) {
 		if InvalidPreviewToken(c) {
-			c.Data(http.StatusForbidden, "image/svg+xml", albumIconSvg)
+			IconData(c, http.StatusForbidden, albumIconSvg)
 			return
 		}
 
-		start := time.Now()
-		conf := get.Config()
-		thumbName := thumb.Name(clean.Token(c.Param("size")))
 		uid := clean.UID(c.Param("uid"))
 
-		size, ok := thumb.Sizes[thumbName]
-
-		if !ok {
-			log.Errorf("%s: invalid size %s", albumCover, clean.Log(thumbName.String()))
-			c.Data(http.StatusOK, "image/svg+xml", albumIconSvg)
-			return
-		}
-
-		cache := get.CoverCache()
-		cacheKey := CacheKey(albumCover, uid, string(thumbName))
-
-		if cacheData, ok := cache.Get(cacheKey); ok {
-			log.Tracef("api-v1: cache hit for %s [%s]", cacheKey, time.Since(start))
-
-			cached := cacheData.(ThumbCache)
-
-			if !fs.FileExists(cached.FileName) {
-				log.Errorf("%s: %s not found", albumCover, uid)
-				c.Data(http.StatusOK, "image/svg+xml", albumIconSvg)
-				return
-			}
-
-			AddCoverCacheHeader(c)
-
-			if c.Query("download") != "" {
-				c.FileAttachment(cached.FileName, cached.ShareName)
-			} else {
-				c.File(cached.FileName)
-			}
-
-			return
-		}
-
-		f, err := query.AlbumCoverByUID(uid, conf.Settings().Features.Private)
-
-		if err != nil {
-			log.Debugf("%s: %s contains no pictures, using generic cover", albumCover, uid)
-			c.Data(http.StatusOK, "image/svg+xml", albumIconSvg)
-			return
-		}
-
-		fileName := photoprism.FileName(f.FileRoot, f.FileName)
-
-		if !fs.FileExists(fileName) {
-			log.Errorf("%s: found no original for %s", albumCover, clean.Log(fileName))
-			c.Data(http.StatusOK, "image/svg+xml", albumIconSvg)
-
-			// Set missing flag so that the file doesn't show up in search results anymore.
-			log.Warnf("%s: %s is missing", albumCover, clean.Log(f.FileName))
-			logError(albumCover, f.Update("FileMissing", true))
-			return
-		}
-
-		// Use original file if thumb size exceeds limit, see https://github.com/photoprism/photoprism/issues/157
-		if size.ExceedsLimit() && c.Query("download") == "" {
-			log.Debugf("%s: using original, size exceeds limit (width %d, height %d)", albumCover, size.Width, size.Height)
-			AddCoverCacheHeader(c)
-			c.File(fileName)
-			return
-		}
-
-		var thumbnail string
-
-		if conf.ThumbUncached() || size.Uncached() {
-			thumbnail, err = thumb.FromFile(fileName, f.FileHash, conf.ThumbCachePath(), size.Width, size.Height, f.FileOrientation, size.Options...)
-		} else {
-			thumbnail, err = thumb.FromCache(fileName, f.FileHash, conf.ThumbCachePath(), size.Width, size.Height, size.Options...)
-		}
-
-		if err != nil {
-			log.Errorf("%s: %s", albumCover, err)
-			c.Data(http.StatusOK, "image/svg+xml", albumIconSvg)
-			return
-		} else if thumbnail == "" {
-			log.Errorf("%s: %s has empty thumb name - you may have found a bug", albumCover, filepath.Base(fileName))
-			c.Data(http.StatusOK, "image/svg+xml", albumIconSvg)
-			return
-		}
-
-		cache.SetDefault(cacheKey, ThumbCache{thumbnail, f.ShareBase(0)})
-		log.Debugf("cached %s [%s]", cacheKey, time.Since(start))
-
-		AddCoverCacheHeader(c)
-
-		if c.Query("download") != "" {
-			c.FileAttachment(thumbnail, f.DownloadName(DownloadName(c), 0))
-		} else {
-			c.File(thumbnail)
-		}
-	})
+		CoverThumb(c, albumCover, uid, albumIconSvg, func() (entity.File, error) {
+			return query.AlbumCoverByUID(uid, get.Config().Settings().Features.Private)
+		})
+	}))
 }
 
 // LabelCover returns a label cover image.
@@ -144,107 +56,120 @@ func AlbumCover(router *gin.RouterGroup) {
 //	token: string security token (see config)
 //	size: string thumb type, see photoprism.ThumbnailTypes
 func LabelCover(router *gin.RouterGroup) {
-	router.GET("/labels/:uid/t/:token/:size", func(c *gin.Context) {
+	router.GET("/labels/:uid/t/:token/:size", ThumbBudget(func(c *gin.Context) {
 		if InvalidPreviewToken(c) {
-			c.Data(http.StatusForbidden, "image/svg+xml", labelIconSvg)
+			IconData(c, http.StatusForbidden, labelIconSvg)
 			return
 		}
 
-		start := time.Now()
-		conf := get.Config()
-		thumbName := thumb.Name(clean.Token(c.Param("size")))
 		uid := clean.UID(c.Param("uid"))
 
-		size, ok := thumb.Sizes[thumbName]
+		CoverThumb(c, labelCover, uid, labelIconSvg, func() (entity.File, error) {
+			return query.LabelThumbByUID(uid)
+		})
+	}))
+}
 
-		if !ok {
-			log.Errorf("%s: invalid size %s", labelCover, clean.Log(thumbName.String()))
-			c.Data(http.StatusOK, "image/svg+xml", labelIconSvg)
-			return
-		}
+// CoverThumb sends the cover image of an album, label, or folder in the requested size, using the same
+// size policies, originals, and thumbnails as GetThumb. The file is only looked up with the find function
+// if the cover is not cached yet. The generic icon is returned with status 200 if there is no cover.
+func CoverThumb(c *gin.Context, namespace, uid string, icon []byte, find func() (entity.File, error)) {
+	start := time.Now()
+	download := c.Query("download") != ""
+	sizeName := thumb.Name(clean.Token(c.Param("size")))
 
-		cache := get.CoverCache()
-		cacheKey := CacheKey(labelCover, uid, string(thumbName))
+	size, ok := thumb.Sizes[sizeName]
 
-		if cacheData, ok := cache.Get(cacheKey); ok {
-			log.Tracef("api-v1: cache hit for %s [%s]", cacheKey, time.Since(start))
+	if !ok {
+		log.Errorf("%s: invalid size %s", namespace, clean.Log(sizeName.String()))
+		IconData(c, http.StatusOK, icon)
+		return
+	}
 
-			cached := cacheData.(ThumbCache)
+	// Use the largest cached size instead if this size is not created, see thumb.Policies.
+	if sizeName, size = PolicySize("", "", sizeName, size); sizeName == "" {
+		log.Errorf("%s: invalid size %d", namespace, get.Config().ThumbSizePrecached())
+		IconData(c, http.StatusOK, icon)
+		return
+	}
 
-			if !fs.FileExists(cached.FileName) {
-				log.Errorf("%s: %s not found", labelCover, uid)
-				c.Data(http.StatusOK, "image/svg+xml", labelIconSvg)
-				return
-			}
+	cache := get.CoverCache()
+	cacheKey := CacheKey(namespace, uid, string(sizeName))
 
-			AddCoverCacheHeader(c)
+	if cacheData, ok := cache.Get(cacheKey); ok {
+		log.Tracef("api-v1: cache hit for %s [%s]", cacheKey, time.Since(start))
 
-			if c.Query("download") != "" {
-				c.FileAttachment(cached.FileName, cached.ShareName)
-			} else {
-				c.File(cached.FileName)
-			}
+		cached := cacheData.(ThumbCache)
 
+		if !fs.FileExists(cached.FileName) {
+			log.Errorf("%s: %s not found", namespace, uid)
+			IconData(c, http.StatusOK, icon)
 			return
 		}
 
-		f, err := query.LabelThumbByUID(uid)
+		AddCoverCacheHeader(c)
 
-		if err != nil {
-			log.Errorf(err.Error())
-			c.Data(http.StatusOK, "image/svg+xml", labelIconSvg)
-			return
+		if download {
+			c.FileAttachment(cached.FileName, cached.ShareName)
+		} else {
+			c.File(cached.FileName)
 		}
 
-		fileName := photoprism.FileName(f.FileRoot, f.FileName)
+		return
+	}
 
-		if !fs.FileExists(fileName) {
-			log.Errorf("%s: file %s is missing", labelCover, clean.Log(f.FileName))
-			c.Data(http.StatusOK, "image/svg+xml", labelIconSvg)
+	f, err := find()
 
-			// Set missing flag so that the file doesn't show up in search results anymore.
-			logError(labelCover, f.Update("FileMissing", true))
+	if err != nil {
+		log.Debugf("%s: %s contains no pictures, using generic cover", namespace, uid)
+		IconData(c, http.StatusOK, icon)
+		return
+	}
 
-			return
-		}
-
-		// Use original file if thumb size exceeds limit, see https://github.com/photoprism/photoprism/issues/157
-		if size.ExceedsLimit() {
-			log.Debugf("%s: using original, size exceeds limit (width %d, height %d)", labelCover, size.Width, size.Height)
-
-			AddCoverCacheHeader(c)
-			c.File(fileName)
-
-			return
-		}
+	// Download remote originals to the cache folder first, and flag missing files.
+	fileName, _, err := OriginalFile(&f, namespace)
 
-		var thumbnail string
+	if err != nil {
+		log.Errorf("%s: %s", namespace, err)
+		IconData(c, http.StatusOK, icon)
+		return
+	}
 
-		if conf.ThumbUncached() || size.Uncached() {
-			thumbnail, err = thumb.FromFile(fileName, f.FileHash, conf.ThumbCachePath(), size.Width, size.Height, f.FileOrientation, size.Options...)
-		} else {
-			thumbnail, err = thumb.FromCache(fileName, f.FileHash, conf.ThumbCachePath(), size.Width, size.Height, size.Options...)
-		}
+	thumbPath := thumb.Path(get.Config().ThumbCachePath(), photoprism.FileName(f.FileRoot, f.FileName))
 
-		if err != nil {
-			log.Errorf("%s: %s", labelCover, err)
-			c.Data(http.StatusOK, "image/svg+xml", labelIconSvg)
-			return
-		} else if thumbnail == "" {
-			log.Errorf("%s: %s has empty thumb name - you may have found a bug", labelCover, filepath.Base(fileName))
-			c.Data(http.StatusOK, "image/svg+xml", labelIconSvg)
+	// Use original file if thumb size exceeds limit, see https://github.com/photoprism/photoprism/issues/157
+	if size.ExceedsLimit() && !download {
+		if fileName, err = OriginalThumb(c, &f, fileName, thumbPath, size, namespace); err != nil {
+			log.Errorf("%s: %s, rejected (strip original)", namespace, err)
+			IconData(c, http.StatusOK, icon)
 			return
 		}
 
-		cache.SetDefault(cacheKey, ThumbCache{thumbnail, f.ShareBase(0)})
-		log.Debugf("cached %s [%s]", cacheKey, time.Since(start))
-
 		AddCoverCacheHeader(c)
-
-		if c.Query("download") != "" {
-			c.FileAttachment(thumbnail, f.DownloadName(DownloadName(c), 0))
-		} else {
-			c.File(thumbnail)
-		}
-	})
+		c.File(fileName)
+		return
+	}
+
+	thumbnail, err := CreateThumb(&f, fileName, thumbPath, size)
+
+	if err != nil {
+		log.Errorf("%s: %s", namespace, err)
+		IconData(c, http.StatusOK, icon)
+		return
+	} else if thumbnail == "" {
+		log.Errorf("%s: %s has empty thumb name - you may have found a bug", namespace, filepath.Base(fileName))
+		IconData(c, http.StatusOK, icon)
+		return
+	}
+
+	cache.SetDefault(cacheKey, ThumbCache{FileName: thumbnail, ShareName: f.ShareBase(0)})
+	log.Debugf("cached %s [%s]", cacheKey, time.Since(start))
+
+	AddCoverCacheHeader(c)
+
+	if download {
+		c.FileAttachment(thumbnail, f.DownloadName(DownloadName(c), 0))
+	} else {
+		c.File(thumbnail)
+	}
 }
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// CropBudgetResult represents the largest crop of an area that fits within a pixel budget.
+type CropBudgetResult struct {
+	Hash string `json:"hash"`
+	crop.Budget
+}
+
+// GetCropBudget returns the largest crop of a detected subject, e.g. a face, that fits within a pixel budget,
+// so that downstream models get inputs of consistent size without upscaling, see crop.FromBudget.
+// The relative region and the actual dimensions are returned in the X-Crop-Region and X-Crop-Size headers,
+// or as JSON if the "json" format is requested.
+//
+// GET /api/v1/t/:thumb/:token/budget/:pixels
+//
+// Parameters:
+//
+//	thumb: string sha1 file hash and crop area, e.g. as returned for markers
+//	token: string security token
+//	pixels: int maximum number of pixels, see crop.BudgetMin and crop.BudgetMax
+//	format: string optional, "json" returns the region and dimensions without the image
+func GetCropBudget(router *gin.RouterGroup) {
+	router.GET("/t/:thumb/:token/budget/:pixels", ThumbBudget(func(c *gin.Context) {
+		if InvalidPreviewToken(c) {
+			AbortForbidden(c)
+			return
+		}
+
+		fileHash, cropArea := crop.ParseThumb(clean.Token(c.Param("thumb")))
+		pixels, err := strconv.Atoi(clean.Token(c.Param("pixels")))
+
+		if cropArea == "" || err != nil || pixels < crop.BudgetMin || pixels > crop.BudgetMax {
+			AbortBadRequest(c)
+			return
+		}
+
+		// Resolve other hash types like "blake3:..." to the sha1 hash thumbnails are addressed by.
+		if hashType, hash := fs.ParseHash(fileHash); hashType == fs.HashSHA1 {
+			fileHash = hash
+		} else if f, err := query.FileByHash(fileHash); err != nil {
+			AbortEntityNotFound(c)
+			return
+		} else {
+			fileHash = f.FileHash
+		}
+
+		result, err := crop.FromBudget(fileHash, cropArea, pixels, ThumbPath(fileHash))
+
+		if err != nil {
+			log.Warnf("crop: %s (budget)", err)
+			AbortEntityNotFound(c)
+			return
+		}
+
+		a := result.Area
+
+		// Crops only change if the file changes, in which case its hash changes too.
+		AddImmutableCacheHeader(c)
+		c.Header("X-Crop-Region", thumb.Region{X: float64(a.X), Y: float64(a.Y), W: float64(a.W), H: float64(a.H)}.String())
+		c.Header("X-Crop-Size", fmt.Sprintf("%dx%d", result.Width, result.Height))
+
+		if c.Query("format") == "json" {
+			c.JSON(http.StatusOK, CropBudgetResult{Hash: fileHash, Budget: result})
+			return
+		}
+
+		AddContentTypeHeader(c, fs.MimeTypeJPEG)
+		c.File(result.FileName)
+	}))
+}
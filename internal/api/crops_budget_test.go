@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+func TestGetCropBudget(t *testing.T) {
+	t.Run("WrongToken", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetCropBudget(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818-0680bc31420d/xxx/budget/4096")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("NoArea", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetCropBudget(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"/budget/4096")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("InvalidBudget", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetCropBudget(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818-0680bc31420d/"+conf.PreviewToken()+"/budget/1")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetCropBudget(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/0000000000000000000000000000000000000000-0680bc31420d/"+conf.PreviewToken()+"/budget/4096")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
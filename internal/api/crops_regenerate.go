@@ -0,0 +1,134 @@
+package api
+
+import (
+	"net/http"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// CropsResult reports the number of files checked and updated, as well as the number of crops created.
+type CropsResult struct {
+	Files   int `json:"files"`
+	Updated int `json:"updated"`
+	Crops   int `json:"crops"`
+}
+
+// RegeneratePhotoCrops regenerates the face crops of a photo if its face markers changed since they were rendered.
+//
+// POST /api/v1/photos/:uid/crops
+//
+// Parameters:
+//
+//	uid: string Photo UID as returned by the API
+//	force: bool optional, regenerate crops even if the face markers did not change
+func RegeneratePhotoCrops(router *gin.RouterGroup) {
+	router.POST("/photos/:uid/crops", func(c *gin.Context) {
+		s := Auth(c, acl.ResourcePhotos, acl.ActionUpdate)
+
+		if s.Abort(c) {
+			return
+		}
+
+		regenerateCrops(c, form.Selection{Photos: []string{clean.UID(c.Param("uid"))}})
+	})
+}
+
+// RegenerateAlbumCrops regenerates the face crops of all photos in an album whose face markers changed since they were rendered.
+//
+// POST /api/v1/albums/:uid/crops
+//
+// Parameters:
+//
+//	uid: string Album UID as returned by the API
+//	force: bool optional, regenerate crops even if the face markers did not change
+func RegenerateAlbumCrops(router *gin.RouterGroup) {
+	router.POST("/albums/:uid/crops", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceAlbums, acl.ActionUpdate)
+
+		if s.Abort(c) {
+			return
+		}
+
+		regenerateCrops(c, form.Selection{Albums: []string{clean.UID(c.Param("uid"))}})
+	})
+}
+
+// regenerateCrops regenerates the face crops of the primary files in the selection and publishes
+// "crops.updating" events to report progress.
+func regenerateCrops(c *gin.Context, sel form.Selection) {
+	files, err := query.SelectedFiles(sel, query.FileSelection{Primary: true, Private: true, Archived: true, Hidden: true})
+
+	if err != nil {
+		log.Errorf("crops: %s", err)
+		AbortEntityNotFound(c)
+		return
+	} else if len(files) == 0 {
+		AbortEntityNotFound(c)
+		return
+	}
+
+	force := txt.Bool(c.Query("force"))
+	result := CropsResult{Files: len(files)}
+
+	for i := range files {
+		updated, created, err := RegenerateFileCrops(&files[i], force)
+
+		if err != nil {
+			log.Errorf("crops: %s in %s", err, clean.Log(files[i].FileName))
+			continue
+		} else if !updated {
+			continue
+		}
+
+		result.Updated++
+		result.Crops += created
+
+		event.Publish("crops.updating", event.Data{
+			"fileName": files[i].FileName,
+			"baseName": filepath.Base(files[i].FileName),
+			"done":     i + 1,
+			"total":    len(files),
+		})
+	}
+
+	log.Infof("crops: updated %s, created %s", english.Plural(result.Updated, "file", "files"), english.Plural(result.Crops, "crop", "crops"))
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RegenerateFileCrops regenerates the face crops of the file if the crop version no longer matches its face markers.
+func RegenerateFileCrops(f *entity.File, force bool) (updated bool, created int, err error) {
+	var areas []string
+
+	for _, m := range *f.Markers() {
+		if !m.ValidFace() || m.Thumb == "" {
+			continue
+		}
+
+		if _, area := crop.ParseThumb(m.Thumb); area != "" {
+			areas = append(areas, area)
+		}
+	}
+
+	thumbPath := ThumbPath(f.FileHash)
+	version := crop.Version(areas)
+
+	if !force && crop.CachedVersion(f.FileHash, thumbPath) == version {
+		return false, 0, nil
+	}
+
+	created, err = crop.Regenerate(f.FileHash, thumbPath, areas, version)
+
+	return err == nil, created, err
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestRegeneratePhotoCrops(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		RegeneratePhotoCrops(router)
+		r := PerformRequest(app, "POST", "/api/v1/photos/pt9jtdre2lvl0y11/crops?force=true")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.LessOrEqual(t, int64(1), gjson.Get(r.Body.String(), "files").Int())
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		RegeneratePhotoCrops(router)
+		r := PerformRequest(app, "POST", "/api/v1/photos/pt9jtdre2lvl0yxx/crops")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
+
+func TestRegenerateAlbumCrops(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		RegenerateAlbumCrops(router)
+		r := PerformRequest(app, "POST", "/api/v1/albums/at9lxuqxpoaaaaaa/crops")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
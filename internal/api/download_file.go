@@ -28,9 +28,16 @@ func DownloadName(c *gin.Context) customize.DownloadName {
 		return customize.DownloadNameShare
 	case "original":
 		return customize.DownloadNameOriginal
-	default:
-		return get.Config().Settings().Download.Name
+	case "template":
+		return customize.DownloadNameTemplate
 	}
+
+	// Use the download name template, if configured.
+	if conf := get.Config(); conf.DownloadTemplate() != "" {
+		return customize.DownloadNameTemplate
+	}
+
+	return get.Config().Settings().Download.Name
 }
 
 // GetDownload returns the raw file data.
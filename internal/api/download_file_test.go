@@ -2,12 +2,16 @@ package api
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/tidwall/gjson"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/customize"
+	"github.com/photoprism/photoprism/internal/get"
 )
 
 func TestGetDownload(t *testing.T) {
@@ -34,3 +38,35 @@ func TestGetDownload(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, r.Code)
 	})
 }
+
+func TestDownloadName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Query", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/v1/dl/123?name=template", nil)
+
+		assert.Equal(t, customize.DownloadNameTemplate, DownloadName(c))
+	})
+	t.Run("Template", func(t *testing.T) {
+		conf := get.Config()
+		conf.Options().DownloadTemplate = "{date}-{title}"
+		defer func() { conf.Options().DownloadTemplate = "" }()
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/v1/dl/123", nil)
+
+		assert.Equal(t, customize.DownloadNameTemplate, DownloadName(c))
+
+		c, _ = gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/v1/dl/123?name=file", nil)
+
+		assert.Equal(t, customize.DownloadNameFile, DownloadName(c))
+	})
+	t.Run("Default", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/v1/dl/123", nil)
+
+		assert.Equal(t, get.Config().Settings().Download.Name, DownloadName(c))
+	})
+}
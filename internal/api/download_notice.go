@@ -0,0 +1,106 @@
+package api
+
+import (
+	"bytes"
+	"image/jpeg"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// DownloadNotice returns the creator and copyright notice for downloaded thumbnails of a file,
+// using the configured values if set and the photo metadata otherwise.
+func DownloadNotice(f *entity.File) (notice thumb.Notice) {
+	conf := get.Config()
+
+	notice.Artist = conf.DownloadArtist()
+	notice.Copyright = conf.DownloadCopyright()
+
+	if f == nil {
+		return notice
+	} else if p := f.RelatedPhoto(); p == nil {
+		return notice
+	} else if d := p.GetDetails(); d == nil {
+		return notice
+	} else {
+		if notice.Artist == "" {
+			notice.Artist = d.Artist
+		}
+
+		if notice.Copyright == "" {
+			notice.Copyright = d.Copyright
+		}
+	}
+
+	return notice
+}
+
+// DownloadThumb sends a thumbnail as attachment, sets its print resolution, and embeds a copyright notice if enabled.
+//
+// Parameters:
+//
+//	dpi: int optional print resolution, overrides the default
+//	print: string optional print length of the longer side, e.g. 20cm, 200mm, or 8in
+func DownloadThumb(c *gin.Context, thumbName, downloadName string, f *entity.File) {
+	conf := get.Config()
+
+	if fs.FileType(thumbName) != fs.ImageJPEG {
+		c.FileAttachment(thumbName, downloadName)
+		return
+	}
+
+	var notice thumb.Notice
+
+	if conf.DownloadNotice() {
+		notice = DownloadNotice(f)
+	}
+
+	printSize := c.Query("print")
+	dpi := txt.Int(c.Query("dpi"))
+
+	if dpi <= 0 {
+		dpi = conf.DownloadDpi()
+	}
+
+	if notice.Empty() && dpi <= 0 && printSize == "" {
+		c.FileAttachment(thumbName, downloadName)
+		return
+	}
+
+	data, err := os.ReadFile(thumbName)
+
+	if err != nil {
+		log.Errorf("download: %s", err)
+		c.FileAttachment(thumbName, downloadName)
+		return
+	}
+
+	// Calculate the resolution based on the print size, if specified.
+	if printSize != "" {
+		if inches, err := thumb.ParsePrintSize(printSize); err != nil {
+			log.Debugf("download: %s", err)
+		} else if cfg, err := jpeg.DecodeConfig(bytes.NewReader(data)); err == nil {
+			dpi = thumb.PrintDpi(cfg.Width, cfg.Height, inches)
+		}
+	}
+
+	if data, err = thumb.SetDpi(data, dpi); err != nil {
+		log.Warnf("download: %s in %s (set dpi)", err, clean.Log(filepath.Base(thumbName)))
+	}
+
+	if data, err = notice.Embed(data); err != nil {
+		log.Warnf("download: %s in %s (embed notice)", err, clean.Log(filepath.Base(thumbName)))
+	}
+
+	AddDownloadHeader(c, downloadName)
+	c.Data(http.StatusOK, "image/jpeg", data)
+}
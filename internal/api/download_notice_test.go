@@ -0,0 +1,22 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+func TestDownloadNotice(t *testing.T) {
+	t.Run("NoFile", func(t *testing.T) {
+		notice := DownloadNotice(nil)
+		assert.True(t, notice.Empty())
+	})
+	t.Run("PhotoMetadata", func(t *testing.T) {
+		f := &entity.File{Photo: &entity.Photo{Details: &entity.Details{Artist: "Jens Mander", Copyright: "© 2023 Jens Mander"}}}
+		notice := DownloadNotice(f)
+		assert.Equal(t, "Jens Mander", notice.Artist)
+		assert.Equal(t, "© 2023 Jens Mander", notice.Copyright)
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// ChangeFileAngle changes the angle by which the thumbnails of a file are straightened,
+// and recreates them so the change is reflected in all sizes and crops.
+// PUT /api/v1/photos/:uid/files/:file_uid/angle
+//
+// Parameters:
+//
+//	uid: string Photo UID as returned by the API
+//	file_uid: string File UID as returned by the API
+func ChangeFileAngle(router *gin.RouterGroup) {
+	router.PUT("/photos/:uid/files/:file_uid/angle", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceFiles, acl.ActionUpdate)
+
+		if s.Abort(c) {
+			return
+		}
+
+		conf := get.Config()
+
+		// Abort in read-only mode or if editing is disabled.
+		if conf.ReadOnly() || !conf.Settings().Features.Edit {
+			c.AbortWithStatusJSON(http.StatusForbidden, i18n.NewResponse(http.StatusForbidden, i18n.ErrReadOnly))
+			return
+		}
+
+		fileUid := clean.UID(c.Param("file_uid"))
+
+		m, err := query.FileByUID(fileUid)
+
+		// Abort if the file was not found.
+		if err != nil {
+			log.Errorf("files: %s (change angle)", err)
+			AbortEntityNotFound(c)
+			return
+		}
+
+		// Init form with model values
+		f, err := form.NewFile(m)
+
+		if err != nil {
+			Abort(c, http.StatusInternalServerError, i18n.ErrSaveFailed)
+			return
+		}
+
+		// Update form with values from request
+		if err = c.BindJSON(&f); err != nil {
+			Abort(c, http.StatusBadRequest, i18n.ErrBadRequest)
+			return
+		}
+
+		// Update angle and thumbnails if it was changed.
+		if angle := thumb.NormalizeAngle(float64(f.FileAngle)); angle != thumb.NormalizeAngle(float64(m.FileAngle)) {
+			fileName := photoprism.FileName(m.FileRoot, m.FileName)
+			mf, err := photoprism.NewMediaFile(fileName)
+
+			// Check if file exists.
+			if err != nil {
+				Abort(c, http.StatusInternalServerError, i18n.ErrFileNotFound)
+				return
+			}
+
+			if err = m.Update("FileAngle", float32(angle)); err != nil {
+				log.Errorf("file: %s in %s (change angle)", err, clean.Log(mf.BaseName()))
+				AbortSaveFailed(c)
+				return
+			}
+
+			// Remove existing thumbnails and crops.
+			if removed, err := thumb.Remove(m.FileHash, thumb.Path(conf.ThumbCachePath(), fileName)); err != nil {
+				log.Warnf("file: %s in %s (remove thumbnails)", err, clean.Log(mf.BaseName()))
+			} else {
+				log.Debugf("file: removed %d thumbnails of %s", removed, clean.Log(mf.BaseName()))
+			}
+
+			RemoveFromThumbCache(m.FileHash)
+
+			// Recreate default thumbnails.
+			mf.SetAngle(angle)
+
+			if err = mf.CreateThumbnails(conf.ThumbCachePath(), false); err != nil {
+				log.Errorf("file: %s in %s (change angle)", err, clean.Log(mf.BaseName()))
+				AbortSaveFailed(c)
+				return
+			}
+		}
+
+		// Return updated photo.
+		p, err := query.PhotoPreloadByUID(m.PhotoUID)
+
+		if err != nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		PublishPhotoEvent(EntityUpdated, m.PhotoUID, c)
+
+		c.JSON(http.StatusOK, p)
+	})
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeFileAngle(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ChangeFileAngle(router)
+		r := PerformRequestWithBody(app, "PUT", "/api/v1/photos/pt9jtdre2lvl0yh7/files/xxx/angle", `{"Angle": 2.5}`)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("BadRequest", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ChangeFileAngle(router)
+		r := PerformRequestWithBody(app, "PUT", "/api/v1/photos/pt9jtdre2lvl0yh7/files/ft2es49whhbnlqdn/angle", `{"Angle": "foo"}`)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Unchanged", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ChangeFileAngle(router)
+		r := PerformRequestWithBody(app, "PUT", "/api/v1/photos/pt9jtdre2lvl0yh7/files/ft2es49whhbnlqdn/angle", `{"Angle": 0}`)
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// ChangeFileFocus sets the focus point that crops of a file keep in frame, and recreates
+// the banner crops and crop variants that depend on it.
+// PUT /api/v1/photos/:uid/files/:file_uid/focus
+//
+// Parameters:
+//
+//	uid: string Photo UID as returned by the API
+//	file_uid: string File UID as returned by the API
+func ChangeFileFocus(router *gin.RouterGroup) {
+	router.PUT("/photos/:uid/files/:file_uid/focus", func(c *gin.Context) {
+		m, ok := fileFocusRequest(c)
+
+		if !ok {
+			return
+		}
+
+		// Init form with model values
+		f, err := form.NewFile(m)
+
+		if err != nil {
+			Abort(c, http.StatusInternalServerError, i18n.ErrSaveFailed)
+			return
+		}
+
+		// Update form with values from request
+		if err = c.BindJSON(&f); err != nil {
+			Abort(c, http.StatusBadRequest, i18n.ErrBadRequest)
+			return
+		}
+
+		// Update focus point and crops if it was changed.
+		if x, y, _ := m.Focus(); f.FileFocusX != x || f.FileFocusY != y {
+			if err = m.SetFocus(f.FileFocusX, f.FileFocusY); err != nil {
+				log.Errorf("file: %s in %s (change focus)", err, clean.Log(m.FileName))
+				Abort(c, http.StatusBadRequest, i18n.ErrBadRequest)
+				return
+			}
+
+			RegenerateHintedCrops(m)
+		}
+
+		fileFocusResponse(c, m)
+	})
+}
+
+// ClearFileFocus removes the focus point of a file, so that crops are chosen automatically again.
+// DELETE /api/v1/photos/:uid/files/:file_uid/focus
+//
+// Parameters:
+//
+//	uid: string Photo UID as returned by the API
+//	file_uid: string File UID as returned by the API
+func ClearFileFocus(router *gin.RouterGroup) {
+	router.DELETE("/photos/:uid/files/:file_uid/focus", func(c *gin.Context) {
+		m, ok := fileFocusRequest(c)
+
+		if !ok {
+			return
+		}
+
+		if _, _, found := m.Focus(); found {
+			if err := m.ClearFocus(); err != nil {
+				log.Errorf("file: %s in %s (clear focus)", err, clean.Log(m.FileName))
+				AbortSaveFailed(c)
+				return
+			}
+
+			RegenerateHintedCrops(m)
+		}
+
+		fileFocusResponse(c, m)
+	})
+}
+
+// CropHints returns the areas of valid faces and the focus point of the file, if any, as hints for crops.
+func CropHints(f *entity.File) (hints crop.Areas) {
+	if f == nil {
+		return hints
+	}
+
+	hints = HeroFaceAreas(f)
+
+	if x, y, ok := f.Focus(); ok {
+		hints = append(hints, crop.NewFocus(x, y))
+	}
+
+	return hints
+}
+
+// RegenerateHintedCrops recreates the cached crops of the file that depend on its faces and focus point.
+func RegenerateHintedCrops(f *entity.File) {
+	if created, err := crop.RegenerateHinted(f.FileHash, ThumbPath(f.FileHash), CropHints(f)); err != nil {
+		log.Warnf("file: %s in %s (regenerate crops)", err, clean.Log(f.FileName))
+	} else if created > 0 {
+		log.Debugf("file: regenerated %d crops of %s", created, clean.Log(f.FileName))
+	}
+}
+
+// fileFocusRequest returns the file whose focus point should be changed, or aborts the request.
+func fileFocusRequest(c *gin.Context) (m *entity.File, ok bool) {
+	s := Auth(c, acl.ResourceFiles, acl.ActionUpdate)
+
+	if s.Abort(c) {
+		return nil, false
+	}
+
+	conf := get.Config()
+
+	// Abort in read-only mode or if editing is disabled.
+	if conf.ReadOnly() || !conf.Settings().Features.Edit {
+		c.AbortWithStatusJSON(http.StatusForbidden, i18n.NewResponse(http.StatusForbidden, i18n.ErrReadOnly))
+		return nil, false
+	}
+
+	m, err := query.FileByUID(clean.UID(c.Param("file_uid")))
+
+	// Abort if the file was not found.
+	if err != nil {
+		log.Errorf("files: %s (focus)", err)
+		AbortEntityNotFound(c)
+		return nil, false
+	}
+
+	return m, true
+}
+
+// fileFocusResponse returns the updated photo.
+func fileFocusResponse(c *gin.Context, m *entity.File) {
+	p, err := query.PhotoPreloadByUID(m.PhotoUID)
+
+	if err != nil {
+		AbortEntityNotFound(c)
+		return
+	}
+
+	PublishPhotoEvent(EntityUpdated, m.PhotoUID, c)
+
+	c.JSON(http.StatusOK, p)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestChangeFileFocus(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ChangeFileFocus(router)
+		r := PerformRequestWithBody(app, "PUT", "/api/v1/photos/pt9jtdre2lvl0yh7/files/xxx/focus", `{"FocusX": 0.3, "FocusY": 0.6}`)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("BadRequest", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ChangeFileFocus(router)
+		r := PerformRequestWithBody(app, "PUT", "/api/v1/photos/pt9jtdre2lvl0yh7/files/ft2es49whhbnlqdn/focus", `{"FocusX": "foo"}`)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("OutOfRange", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ChangeFileFocus(router)
+		r := PerformRequestWithBody(app, "PUT", "/api/v1/photos/pt9jtdre2lvl0yh7/files/ft2es49whhbnlqdn/focus", `{"FocusX": 1.5, "FocusY": 0.5}`)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Success", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ChangeFileFocus(router)
+		ClearFileFocus(router)
+		r := PerformRequestWithBody(app, "PUT", "/api/v1/photos/pt9jtdre2lvl0yh7/files/ft2es49whhbnlqdn/focus", `{"FocusX": 0.3, "FocusY": 0.6}`)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "pt9jtdre2lvl0yh0", gjson.Get(r.Body.String(), "UID").String())
+
+		r = PerformRequest(app, "DELETE", "/api/v1/photos/pt9jtdre2lvl0yh7/files/ft2es49whhbnlqdn/focus")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+}
+
+func TestClearFileFocus(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ClearFileFocus(router)
+		r := PerformRequest(app, "DELETE", "/api/v1/photos/pt9jtdre2lvl0yh7/files/xxx/focus")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
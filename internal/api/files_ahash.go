@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// FileAHashResult represents the average hash of a file, see thumb.AHash.
+type FileAHashResult struct {
+	Hash  string `json:"hash"`
+	AHash string `json:"ahash"`
+}
+
+// GetFileAHash returns the average hash of a file, so that clients can cheaply group near-duplicates,
+// e.g. by comparing the number of different bits. Hashes of files indexed before they were stored are
+// computed from the thumbnail and saved when requested, see photoprism.MediaFile.AverageHash.
+//
+// GET /api/v1/files/:hash/ahash
+//
+// Parameters:
+//
+//	hash: string sha1 file hash, other hash types require a prefix like "blake3:"
+func GetFileAHash(router *gin.RouterGroup) {
+	router.GET("/files/:hash/ahash", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceFiles, acl.ActionView)
+
+		// Abort if permission was not granted.
+		if s.Abort(c) {
+			return
+		}
+
+		f, err := query.FileByHash(clean.Token(c.Param("hash")))
+
+		if err != nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		if f.FileAHash == "" {
+			mediaFile, mediaErr := photoprism.NewMediaFile(photoprism.FileName(f.FileRoot, f.FileName))
+
+			if mediaErr != nil {
+				log.Debugf("files: %s (ahash)", mediaErr)
+				AbortEntityNotFound(c)
+				return
+			}
+
+			h, hashErr := mediaFile.AverageHash(get.Config().ThumbCachePath())
+
+			if hashErr != nil {
+				log.Debugf("files: %s in %s (ahash)", hashErr, clean.Log(f.FileName))
+				AbortUnexpected(c)
+				return
+			}
+
+			f.FileAHash = h.String()
+
+			if err = f.Update("FileAHash", f.FileAHash); err != nil {
+				log.Warnf("files: %s in %s (save ahash)", err, clean.Log(f.FileName))
+			}
+		}
+
+		c.JSON(http.StatusOK, FileAHashResult{Hash: f.FileHash, AHash: f.FileAHash})
+	})
+}
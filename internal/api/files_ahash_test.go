@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestGetFileAHash(t *testing.T) {
+	t.Run("Stored", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetFileAHash(router)
+		r := PerformRequest(app, "GET", "/api/v1/files/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/ahash")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818", gjson.Get(r.Body.String(), "hash").String())
+		assert.Equal(t, "ff81818181c3e7ff", gjson.Get(r.Body.String(), "ahash").String())
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetFileAHash(router)
+		r := PerformRequest(app, "GET", "/api/v1/files/111/ahash")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
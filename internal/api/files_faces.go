@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// FileFace represents a face detected in a file with its bounding box and crop URL.
+type FileFace struct {
+	UID     string  `json:"uid"`
+	SubjUID string  `json:"subj_uid,omitempty"`
+	Name    string  `json:"name,omitempty"`
+	X       float32 `json:"x"`
+	Y       float32 `json:"y"`
+	W       float32 `json:"w"`
+	H       float32 `json:"h"`
+	Area    string  `json:"area"`
+	Url     string  `json:"url"`
+}
+
+// FileFacesResult represents the faces detected in a file and the crop size of their URLs.
+type FileFacesResult struct {
+	Hash  string     `json:"hash"`
+	Size  crop.Size  `json:"size"`
+	Faces []FileFace `json:"faces"`
+}
+
+// GetFileFaces returns the faces detected in a file with their bounding boxes and crop URLs,
+// so that clients can display them without composing the area strings, see GetThumb.
+//
+// GET /api/v1/files/:hash/faces
+//
+// Parameters:
+//
+//	hash: string sha1 file hash, other hash types require a prefix like "blake3:"
+//	size: string optional crop size of the URLs, see crop.Sizes, tile_160 by default
+func GetFileFaces(router *gin.RouterGroup) {
+	router.GET("/files/:hash/faces", func(c *gin.Context) {
+		s := Auth(c, acl.ResourcePeople, acl.ActionView)
+
+		// Abort if permission was not granted.
+		if s.Abort(c) {
+			return
+		}
+
+		size := crop.Sizes[crop.Tile160]
+
+		if name := clean.Token(c.Query("size")); name != "" {
+			var ok bool
+
+			if size, ok = crop.Sizes[crop.Name(name)]; !ok {
+				AbortBadRequest(c)
+				return
+			}
+		}
+
+		f, err := query.FileByHash(clean.Token(c.Param("hash")))
+
+		if err != nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusOK, FileFacesResult{
+			Hash:  f.FileHash,
+			Size:  size,
+			Faces: FileFaces(f, size, get.Config().ContentUri(), s.PreviewToken),
+		})
+	})
+}
+
+// FileFaces returns the valid faces of the file with their crop URLs in the specified size.
+func FileFaces(f *entity.File, size crop.Size, contentUri, previewToken string) []FileFace {
+	result := make([]FileFace, 0, len(*f.Markers()))
+
+	for _, m := range *f.Markers() {
+		if !m.ValidFace() {
+			continue
+		}
+
+		area := crop.NewArea("face", m.X, m.Y, m.W, m.H)
+
+		result = append(result, FileFace{
+			UID:     m.MarkerUID,
+			SubjUID: m.SubjUID,
+			Name:    m.MarkerName,
+			X:       m.X,
+			Y:       m.Y,
+			W:       m.W,
+			H:       m.H,
+			Area:    area.String(),
+			Url:     thumb.Url(area.Thumb(f.FileHash), string(size.Name), contentUri, previewToken),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+func TestGetFileFaces(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetFileFaces(router)
+		r := PerformRequest(app, "GET", "/api/v1/files/pcad9168fa6acc5c5c2965ddf6ec465ca42fd818/faces?size=tile_320")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "pcad9168fa6acc5c5c2965ddf6ec465ca42fd818", gjson.Get(r.Body.String(), "hash").String())
+		assert.Equal(t, "tile_320", gjson.Get(r.Body.String(), "size.name").String())
+
+		faces := gjson.Get(r.Body.String(), "faces").Array()
+		assert.NotEmpty(t, faces)
+
+		for _, f := range faces {
+			area := f.Get("area").String()
+			assert.Len(t, area, 12)
+			assert.Contains(t, f.Get("url").String(), "/t/pcad9168fa6acc5c5c2965ddf6ec465ca42fd818-"+area+"/")
+			assert.True(t, strings.HasSuffix(f.Get("url").String(), "/tile_320"))
+		}
+	})
+	t.Run("InvalidSize", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetFileFaces(router)
+		r := PerformRequest(app, "GET", "/api/v1/files/pcad9168fa6acc5c5c2965ddf6ec465ca42fd818/faces?size=fit_720")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetFileFaces(router)
+		r := PerformRequest(app, "GET", "/api/v1/files/111/faces")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
+
+func TestFileFaces(t *testing.T) {
+	f := entity.FileFixtures.Get("bridge.jpg")
+	faces := FileFaces(&f, crop.Sizes[crop.Tile160], "/api/v1", "abc")
+	assert.NotEmpty(t, faces)
+
+	for _, face := range faces {
+		assert.Equal(t, "/api/v1/t/"+f.FileHash+"-"+face.Area+"/abc/tile_160", face.Url)
+	}
+}
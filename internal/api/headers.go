@@ -9,6 +9,7 @@ import (
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/get"
 	"github.com/photoprism/photoprism/internal/session"
+	"github.com/photoprism/photoprism/internal/thumb"
 )
 
 // AddCountHeader adds the actual result count to the response.
@@ -60,3 +61,24 @@ func AddTokenHeaders(c *gin.Context, s *entity.Session) {
 		c.Header("X-Download-Token", s.DownloadToken)
 	}
 }
+
+// AddCropRegionHeader adds the normalized crop region to the response if a computed crop was applied to the thumbnail.
+func AddCropRegionHeader(c *gin.Context, thumbName string) {
+	if r, ok := thumb.CropRegion(thumbName); ok {
+		c.Header("X-Crop-Region", r.String())
+	}
+}
+
+// AddPhotoUIDHeader adds the UID of the photo a thumbnail belongs to, if known and enabled, see config.ThumbPhotoUID.
+func AddPhotoUIDHeader(c *gin.Context, photoUID string) {
+	if photoUID != "" && get.Config().ThumbPhotoUID() {
+		c.Header("X-Photo-UID", photoUID)
+	}
+}
+
+// AddGPSHeader adds the "lat,lng" coordinates of a photo to the response, if any.
+func AddGPSHeader(c *gin.Context, gps string) {
+	if gps != "" {
+		c.Header("X-GPS", gps)
+	}
+}
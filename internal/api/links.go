@@ -38,6 +38,15 @@ func UpdateLink(c *gin.Context) {
 	link.SetSlug(f.ShareSlug)
 	link.MaxViews = f.MaxViews
 	link.LinkExpires = f.LinkExpires
+	link.BlurFaces = f.BlurFaces
+
+	if headers, err := ShareHeaders(f.Headers); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UpperFirst(err.Error())})
+		return
+	} else if err = link.SetHeaders(headers); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UpperFirst(err.Error())})
+		return
+	}
 
 	if f.LinkToken != "" {
 		link.LinkToken = strings.TrimSpace(strings.ToLower(f.LinkToken))
@@ -117,6 +126,15 @@ func CreateLink(c *gin.Context) {
 	link.SetSlug(f.ShareSlug)
 	link.MaxViews = f.MaxViews
 	link.LinkExpires = f.LinkExpires
+	link.BlurFaces = f.BlurFaces
+
+	if headers, err := ShareHeaders(f.Headers); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UpperFirst(err.Error())})
+		return
+	} else if err = link.SetHeaders(headers); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UpperFirst(err.Error())})
+		return
+	}
 
 	if f.Password != "" {
 		if err := link.SetPassword(f.Password); err != nil {
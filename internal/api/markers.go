@@ -221,12 +221,17 @@ func UpdateMarker(router *gin.RouterGroup) {
 			return
 		}
 
+		// Remember the face, as it may change if the marker is updated.
+		faceId := marker.FaceID
+
 		// Update marker from form values.
 		if changed, saveErr := marker.SaveForm(frm); saveErr != nil {
 			log.Errorf("faces: %s (update marker)", saveErr)
 			AbortSaveFailed(c)
 			return
 		} else if changed {
+			RemoveFromFaceThumbCache(faceId, marker.FaceID)
+
 			if marker.FaceID != "" && marker.SubjUID != "" && marker.SubjSrc == entity.SrcManual {
 				if res, err := get.Faces().Optimize(); err != nil {
 					log.Errorf("faces: %s (optimize)", err)
@@ -295,11 +300,17 @@ func ClearMarkerSubject(router *gin.RouterGroup) {
 			return
 		}
 
+		faceId := marker.FaceID
+
 		if err := marker.ClearSubject(entity.SrcManual); err != nil {
 			log.Errorf("faces: %s (clear marker subject)", err)
 			AbortSaveFailed(c)
 			return
-		} else if err := query.UpdateSubjectCovers(); err != nil {
+		}
+
+		RemoveFromFaceThumbCache(faceId, marker.FaceID)
+
+		if err := query.UpdateSubjectCovers(); err != nil {
 			log.Errorf("faces: %s (update covers)", err)
 		} else if err := entity.UpdateSubjectCounts(); err != nil {
 			log.Errorf("faces: %s (update counts)", err)
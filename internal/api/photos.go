@@ -114,6 +114,11 @@ func UpdatePhoto(router *gin.RouterGroup) {
 
 		SavePhotoAsYaml(p)
 
+		// Download names depend on the title and date, so cached names must be removed.
+		for _, file := range p.Files {
+			RemoveFromThumbCache(file.FileHash)
+		}
+
 		UpdateClientConfig()
 
 		c.JSON(http.StatusOK, p)
@@ -128,14 +133,14 @@ func UpdatePhoto(router *gin.RouterGroup) {
 func GetPhotoDownload(router *gin.RouterGroup) {
 	router.GET("/photos/:uid/dl", func(c *gin.Context) {
 		if InvalidDownloadToken(c) {
-			c.Data(http.StatusForbidden, "image/svg+xml", brokenIconSvg)
+			IconData(c, http.StatusForbidden, brokenIconSvg)
 			return
 		}
 
 		f, err := query.FileByPhotoUID(clean.UID(c.Param("uid")))
 
 		if err != nil {
-			c.Data(http.StatusNotFound, "image/svg+xml", photoIconSvg)
+			IconData(c, http.StatusNotFound, photoIconSvg)
 			return
 		}
 
@@ -143,7 +148,7 @@ func GetPhotoDownload(router *gin.RouterGroup) {
 
 		if !fs.FileExists(fileName) {
 			log.Errorf("photo: file %s is missing", clean.Log(f.FileName))
-			c.Data(http.StatusNotFound, "image/svg+xml", photoIconSvg)
+			IconData(c, http.StatusNotFound, photoIconSvg)
 
 			// Set missing flag so that the file doesn't show up in search results anymore.
 			logError("photo", f.Update("FileMissing", true))
@@ -64,6 +64,9 @@ func Shares(router *gin.RouterGroup) {
 
 		uri := conf.BaseUri(path.Join("/library/albums", uid, shared))
 
+		// Push the first thumbnails so that they can be shown right away, see config.ThumbPush.
+		PushAlbumThumbs(c, uid)
+
 		c.HTML(http.StatusOK, "share.gohtml", gin.H{"shared": gin.H{"token": token, "uri": uri}, "config": clientConfig})
 	})
 }
@@ -41,6 +41,7 @@ func SharePreview(router *gin.RouterGroup) {
 			return
 		}
 
+		// Album previews are composed of several pictures, so they are always stored in the cache folder.
 		thumbPath := path.Join(conf.ThumbCachePath(), "share")
 
 		if err := os.MkdirAll(thumbPath, fs.ModeDir); err != nil {
@@ -121,7 +122,7 @@ func SharePreview(router *gin.RouterGroup) {
 				return
 			}
 
-			thumbnail, imgErr := thumb.FromFile(fileName, file.FileHash, conf.ThumbCachePath(), size.Width, size.Height, file.FileOrientation, size.Options...)
+			thumbnail, imgErr := thumb.FromFileAngle(fileName, file.FileHash, thumb.Path(conf.ThumbCachePath(), fileName), size.Width, size.Height, file.FileOrientation, float64(file.FileAngle), size.Options...)
 
 			if imgErr != nil {
 				log.Warn(imgErr)
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/search"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// PushThumbSize is the thumbnail size shown in the album grid, whose first tiles are pushed to clients.
+const PushThumbSize = thumb.Tile500
+
+// AlbumThumbUrls returns the URLs of the first thumbnails shown when the shared album is opened.
+func AlbumThumbUrls(albumUid string, limit int) (urls []string, err error) {
+	if albumUid == "" || limit <= 0 {
+		return nil, nil
+	}
+
+	conf := get.Config()
+
+	var f form.SearchPhotos
+
+	// Shared albums may only contain public content.
+	f.Album = albumUid
+	f.Public = true
+	f.Private = false
+	f.Hidden = false
+	f.Archived = false
+	f.Review = false
+	f.Primary = true
+	f.Count = limit
+
+	if a, err := query.AlbumByUID(albumUid); err == nil {
+		f.Order = a.AlbumOrder
+	}
+
+	if err = f.ParseQueryString(); err != nil {
+		return nil, err
+	}
+
+	results, _, err := search.Photos(f)
+
+	if err != nil {
+		return nil, err
+	}
+
+	urls = make([]string, 0, len(results))
+
+	for _, p := range results {
+		if p.FileHash != "" {
+			urls = append(urls, thumb.Url(p.FileHash, string(PushThumbSize), conf.ContentUri(), conf.PreviewToken()))
+		}
+	}
+
+	return urls, nil
+}
+
+// PushAlbumThumbs pushes the first thumbnails of a shared album with HTTP/2 so that they can be shown
+// without waiting for the album to be loaded, see config.ThumbPush. If push is not supported, e.g. with
+// HTTP/1.1, or thumbnails are served from a different origin, they are announced with 103 Early Hints
+// instead, so that clients can preload them. It returns the number of thumbnails pushed or announced.
+func PushAlbumThumbs(c *gin.Context, albumUid string) int {
+	limit := get.Config().ThumbPush()
+
+	if limit <= 0 || c.Request.Method != http.MethodGet {
+		return 0
+	}
+
+	urls, err := AlbumThumbUrls(albumUid, limit)
+
+	if err != nil {
+		log.Warnf("share: %s while finding thumbnails to push for %s", err, clean.Log(albumUid))
+		return 0
+	} else if len(urls) == 0 {
+		return 0
+	}
+
+	var hints []string
+
+	pusher := c.Writer.Pusher()
+
+	for _, u := range urls {
+		if pusher == nil || !strings.HasPrefix(u, "/") {
+			hints = append(hints, u)
+		} else if err = pusher.Push(u, nil); err != nil {
+			// Push may be disabled by the client, in which case the remaining thumbnails are announced.
+			log.Debugf("share: %s while pushing thumbnails", err)
+			pusher = nil
+			hints = append(hints, u)
+		}
+	}
+
+	if len(hints) == 0 {
+		return len(urls)
+	}
+
+	for _, u := range hints {
+		c.Writer.Header().Add("Link", fmt.Sprintf("<%s>; rel=preload; as=image", u))
+	}
+
+	// Send the preload links as 103 Early Hints, and again with the final response.
+	if w, ok := c.Writer.(interface{ Unwrap() http.ResponseWriter }); ok {
+		w.Unwrap().WriteHeader(http.StatusEarlyHints)
+	}
+
+	return len(urls)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/get"
+)
+
+func TestAlbumThumbUrls(t *testing.T) {
+	t.Run("Album", func(t *testing.T) {
+		urls, err := AlbumThumbUrls("at9lxuqxpogaaba8", 2)
+
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(urls), 2)
+
+		for _, u := range urls {
+			assert.True(t, strings.HasSuffix(u, "/"+string(PushThumbSize)))
+			assert.Contains(t, u, get.Config().PreviewToken())
+		}
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		urls, err := AlbumThumbUrls("at9lxuqxpogaaba8", 0)
+
+		assert.NoError(t, err)
+		assert.Empty(t, urls)
+	})
+}
+
+func TestPushAlbumThumbs(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/s/4jxf3jfn2k/at9lxuqxpogaaba8", nil)
+
+		assert.Equal(t, 0, PushAlbumThumbs(c, "at9lxuqxpogaaba8"))
+		assert.Empty(t, w.Header().Values("Link"))
+	})
+	t.Run("EarlyHints", func(t *testing.T) {
+		conf := get.Config()
+		conf.Options().ThumbPush = 2
+		defer func() { conf.Options().ThumbPush = 0 }()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/s/4jxf3jfn2k/at9lxuqxpogaaba8", nil)
+
+		n := PushAlbumThumbs(c, "at9lxuqxpogaaba8")
+		links := w.Header().Values("Link")
+
+		assert.LessOrEqual(t, n, 2)
+		assert.Len(t, links, n)
+
+		for _, l := range links {
+			assert.True(t, strings.HasSuffix(l, "; rel=preload; as=image"))
+		}
+	})
+}
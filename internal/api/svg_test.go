@@ -40,6 +40,27 @@ func TestGetSvg(t *testing.T) {
 		assert.Equal(t, videoIconSvg, r.Body.Bytes())
 		assert.Equal(t, http.StatusOK, r.Code)
 	})
+	t.Run("medical", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetSvg(router)
+		r := PerformRequest(app, "GET", "/api/v1/svg/medical")
+		assert.Equal(t, medicalIconSvg, r.Body.Bytes())
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("document", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetSvg(router)
+		r := PerformRequest(app, "GET", "/api/v1/svg/document")
+		assert.Equal(t, documentIconSvg, r.Body.Bytes())
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("archive", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetSvg(router)
+		r := PerformRequest(app, "GET", "/api/v1/svg/archive")
+		assert.Equal(t, archiveIconSvg, r.Body.Bytes())
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
 	t.Run("label", func(t *testing.T) {
 		app, router, conf := NewApiTest()
 		t.Log(conf)
@@ -1,231 +1,440 @@
 package api
 
 import (
+	"encoding/base64"
+	"fmt"
 	"net/http"
-	"path/filepath"
-	"time"
+	"os"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
 )
 
+// DataUriSizeLimit is the maximum size of crops returned as data URI in bytes.
+var DataUriSizeLimit int64 = 512 * 1024
+
 // GetThumb returns a thumbnail image matching the file hash, crop area, and type.
 //
 // GET /api/v1/t/:thumb/:token/:size
+// GET /api/v1/t/:thumb/:token?w=:width
+// GET /api/v1/t/:thumb/:token/:width/:height
+// GET /api/v1/t/:thumb/:token/:size/:slug
 //
 // Parameters:
 //
-//	thumb: string sha1 file hash plus optional crop area
+//	thumb: string sha1 file hash plus optional crop area, other hash types require a prefix like "blake3:"
 //	token: string url security token, see config
-//	size: string thumb type, see thumb.Sizes
+//	size: string thumb type, see thumb.Sizes, or wide banner crop type, see crop.HeroSizes
+//	w: int width in pixels if no size is specified, snapped to the next larger fit size, see thumb.FitWidth
+//	width, height: int dimensions in pixels instead of a size name, snapped to the nearest size, see thumb.FitDimensions
+//	slug: string optional descriptive file name like "my-photo-title.jpg" that is ignored, see ThumbSlug
+//	angle: float optional angle in degrees to straighten the image by instead of the stored file angle
+//	progressive: bool optional, send a preview before the full image if the size is large enough
+//	area: string optional, "center" returns the largest centered square crop, see CenterCrop
+//	format: string optional, "datauri" returns crops as JSON with a base64 encoded data URI, see CropDataUri
+//	object: string optional label of a detected object, e.g. "dog", to crop tiles to its box if found, see ObjectThumb
+//	gif: string optional, "first", "middle", or "animated" to choose how thumbnails of GIFs are created, see GifThumb
+//	overlay: string optional, "rating" draws the rating or reject flag onto the thumbnail, "map" a map inset of the location
+//	wait: int optional time in seconds to wait for thumbnails that are being created, see ThumbWait
+//	filter: string optional resample filter like "lanczos", "bilinear", or "box" for comparison by admins,
+//	   the result is neither cached nor saved, as this is only a debug aid, see ThumbFilter
+//	strict: bool optional, return error status codes with placeholder icons instead of 200, see StrictStatus
+//
+// Share link visitors are recognized on the server by the preview token, see ShareVisitor. They may only
+// request the sizes allowed for share links, see ShareSize, and faces are blurred if one of their links
+// has this enabled, see ShareBlurFaces. Custom response headers of the link that includes the file are
+// added, see AddShareHeaders. Responses to them are verified to contain no metadata if this is
+// enforced, see ThumbStrip, and originals served instead of sizes that exceed the limit are stripped if
+// enabled, see StripOriginal.
+//
+// Clients may request large fit sizes progressively with "Accept: multipart/x-mixed-replace" or the
+// "progressive" query parameter, in which case the fit_720 preview is sent first, see ThumbFile.
+// Other clients receive a single image.
+//
+// Crops are served as AVIF or WebP if the client lists the format in the "Accept" header and an encoder
+// is installed, see thumb.NegotiateFormat, or else as JPEG.
+//
+// The X-GPS response header contains the "lat,lng" coordinates of the photo if known and enabled, see ThumbGPS.
+// The X-Photo-UID header contains the UID of the photo if enabled, see config.ThumbPhotoUID. Existing
+// thumbnails are served without an index query if both are disabled.
+//
+// Cached thumbnails are redirected to a signed CDN URL if a CDN is configured and they are known to
+// exist there, see CdnRedirect. Otherwise, they are served directly.
+//
+// If asynchronous creation is enabled, thumbnails that are not created in time are finished in the
+// background, and 202 Accepted is returned with a Retry-After header instead, see ThumbPending.
+// Clients may also hold the connection until the thumbnail is done with long polling, see ThumbWait.
+// Otherwise, the broken icon is returned if creation exceeds the timeout, and the file is flagged so
+// that subsequent requests don't keep timing out, see config.ThumbTimeout.
+//
+// Stacked files, e.g. bursts or RAW+JPEG, may all show the thumbnail of the cover, see StackCover.
 func GetThumb(router *gin.RouterGroup) {
-	router.GET("/t/:thumb/:token/:size", func(c *gin.Context) {
+	handler := func(c *gin.Context) {
 		if InvalidPreviewToken(c) {
-			c.Data(http.StatusForbidden, "image/svg+xml", brokenIconSvg)
+			IconData(c, http.StatusForbidden, brokenIconSvg)
 			return
 		}
 
 		logPrefix := "thumb"
 
-		start := time.Now()
-		conf := get.Config()
-		download := c.Query("download") != ""
 		fileHash, cropArea := crop.ParseThumb(clean.Token(c.Param("thumb")))
 
-		// Is cropped thumbnail?
-		if cropArea != "" {
-			cropName := crop.Name(clean.Token(c.Param("size")))
+		// Resolve other hash types like "blake3:..." to the sha1 hash thumbnails are addressed by.
+		fileHash, err := ThumbFileHash(fileHash)
 
-			cropSize, ok := crop.Sizes[cropName]
-
-			if !ok {
-				log.Errorf("%s: invalid size %s", logPrefix, clean.Log(string(cropName)))
-				c.Data(http.StatusOK, "image/svg+xml", photoIconSvg)
-				return
-			}
-
-			fileName, err := crop.FromRequest(fileHash, cropArea, cropSize, conf.ThumbCachePath())
+		if err != nil {
+			log.Debugf("%s: %s", logPrefix, err)
+			ThumbIcon(c, http.StatusNotFound, photoIconSvg)
+			return
+		}
 
-			if err != nil {
-				log.Warnf("%s: %s", logPrefix, err)
-				c.Data(http.StatusOK, "image/svg+xml", brokenIconSvg)
-				return
-			} else if fileName == "" {
-				log.Errorf("%s: empty file name - you may have found a bug", logPrefix)
-				c.Data(http.StatusOK, "image/svg+xml", brokenIconSvg)
-				return
-			}
+		// Serve the cover thumbnail for all files in a stack?
+		fileHash = StackCover(fileHash)
 
-			// Add HTTP cache header.
-			AddImmutableCacheHeader(c)
+		// Add custom headers of the visitor's share link that includes the file, if any.
+		AddShareHeaders(c, fileHash)
 
-			if download {
-				c.FileAttachment(fileName, cropName.Jpeg())
-			} else {
-				c.File(fileName)
-			}
+		thumbPath := ThumbPath(fileHash)
 
+		// Is cropped thumbnail?
+		if cropArea, ok := ThumbCropArea(c, fileHash, cropArea); !ok {
+			return
+		} else if cropArea != "" {
+			CropThumb(c, fileHash, cropArea, thumbPath)
 			return
 		}
 
+		download := c.Query("download") != ""
 		sizeName := thumb.Name(clean.Token(c.Param("size")))
 
+		// Is wide banner crop, e.g. for album headers?
+		if heroSize, ok := crop.HeroSizes[crop.Name(sizeName)]; ok {
+			HeroThumb(c, fileHash, thumbPath, heroSize)
+			return
+		}
+
 		size, ok := thumb.Sizes[sizeName]
 
 		if !ok {
 			log.Errorf("%s: invalid size %s", logPrefix, clean.Log(sizeName.String()))
-			c.Data(http.StatusOK, "image/svg+xml", photoIconSvg)
+			ThumbIcon(c, http.StatusBadRequest, photoIconSvg)
 			return
 		}
 
-		if size.Uncached() && !conf.ThumbUncached() {
-			sizeName, size = thumb.Find(conf.ThumbSizePrecached())
+		// Restrict the sizes available to share links, see config.ShareThumbSizes.
+		if sizeName, size, ok = ShareSize(c, sizeName, size); !ok {
+			log.Debugf("%s: size %s not allowed for share links", logPrefix, clean.Log(sizeName.String()))
+			ThumbIcon(c, http.StatusForbidden, photoIconSvg)
+			return
+		}
 
-			if sizeName == "" {
-				log.Errorf("%s: invalid size %d", logPrefix, conf.ThumbSizePrecached())
-				c.Data(http.StatusOK, "image/svg+xml", photoIconSvg)
-				return
-			}
+		// Crop tiles to a detected object if requested, e.g. the dog rather than the whole scene.
+		if label := c.Query("object"); label != "" && !download && ObjectThumb(c, fileHash, thumbPath, sizeName, label) {
+			return
 		}
 
-		cache := get.ThumbCache()
-		cacheKey := CacheKey("thumbs", fileHash, string(sizeName))
+		// Use the largest cached size instead if this size is not created, see thumb.Policies.
+		if sizeName, size = PolicySize(fileHash, thumbPath, sizeName, size); sizeName == "" {
+			log.Errorf("%s: invalid size %d", logPrefix, get.Config().ThumbSizePrecached())
+			ThumbIcon(c, http.StatusInternalServerError, photoIconSvg)
+			return
+		}
 
-		if cacheData, ok := cache.Get(cacheKey); ok {
-			log.Tracef("api-v1: cache hit for %s [%s]", cacheKey, time.Since(start))
+		// Create thumbnails of GIFs in the requested mode, e.g. animated, see thumb.GifMode.
+		if gifMode, ok := thumb.ParseGifMode(c.Query("gif")); ok && !download && GifThumb(c, fileHash, size, gifMode) {
+			return
+		}
 
-			cached := cacheData.(ThumbCache)
+		r := NewThumbRequest(c, fileHash, thumbPath, sizeName, size)
 
-			if !fs.FileExists(cached.FileName) {
-				log.Errorf("%s: %s not found", logPrefix, fileHash)
-				c.Data(http.StatusOK, "image/svg+xml", brokenIconSvg)
-				return
-			}
+		if r.WithFilter && !ThumbFilterAllowed(c) {
+			ThumbIcon(c, http.StatusForbidden, brokenIconSvg)
+			return
+		}
 
-			// Add HTTP cache header.
-			AddImmutableCacheHeader(c)
+		// Serve thumbnails that have already been created, if possible without index query.
+		if CachedThumb(c, r) {
+			return
+		}
 
-			if download {
-				c.FileAttachment(cached.FileName, cached.ShareName)
-			} else {
-				c.File(cached.FileName)
-			}
+		// Find the file and the image that the thumbnail is created from.
+		f, fileName, ok := ThumbSource(c, r)
 
+		if !ok {
 			return
 		}
 
-		// Return existing thumbs straight away.
-		if !download {
-			if fileName, err := size.ResolvedName(fileHash, conf.ThumbCachePath()); err == nil {
-				// Add HTTP cache header.
-				AddImmutableCacheHeader(c)
+		// Choose the smallest fitting size if the original image is smaller.
+		if r.Size.Fit && f.Bounds().In(r.Size.Bounds()) {
+			r.Size = thumb.FitBounds(f.Bounds())
+			log.Tracef("%s: smallest fitting size for %s is %s (width %d, height %d)", logPrefix, clean.Log(f.FileName), r.Size.Name, r.Size.Width, r.Size.Height)
+		}
 
-				// Return requested content.
-				c.File(fileName)
+		// Use original file if thumb size exceeds limit, see https://github.com/photoprism/photoprism/issues/157
+		if r.Size.ExceedsLimit() && !r.Download {
+			if fileName, err = OriginalThumb(c, f, fileName, r.ThumbPath, r.Size, logPrefix); err != nil {
+				log.Errorf("%s: %s, rejected (strip original)", logPrefix, err)
+				ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
 				return
 			}
-		}
 
-		// Query index for file infos.
-		f, err := query.FileByHash(fileHash)
+			// Add HTTP cache header.
+			AddImmutableCacheHeader(c)
 
-		if err != nil {
-			c.Data(http.StatusOK, "image/svg+xml", photoIconSvg)
+			// Return requested content.
+			c.File(fileName)
 			return
 		}
 
-		// Find supported preview image if media file is not a JPEG or PNG.
-		if f.NoJPEG() && f.NoPNG() {
-			if f, err = query.FileByPhotoUID(f.PhotoUID); err != nil {
-				c.Data(http.StatusOK, "image/svg+xml", fileIconSvg)
-				return
-			}
+		// Send a copy resampled with the filter passed for comparison, which is not cached, see FilterThumb.
+		if r.WithFilter {
+			FilterThumb(c, fileName, f, r.Size, r.Filter)
+			return
 		}
 
-		// Return SVG icon as placeholder if file has errors.
-		if f.FileError != "" {
-			c.Data(http.StatusOK, "image/svg+xml", brokenIconSvg)
+		// Find or create the thumbnail, unless it was already sent while it was created.
+		thumbName, ok := RenderThumb(c, r, f, fileName)
+
+		if !ok {
 			return
 		}
 
-		fileName := photoprism.FileName(f.FileRoot, f.FileName)
+		// Apply the requested modifications, if any, and return the thumbnail.
+		ServeThumb(c, r, f, thumbName)
+	}
 
-		if fileName, err = fs.Resolve(fileName); err != nil {
-			log.Errorf("%s: file %s is missing", logPrefix, clean.Log(f.FileName))
-			c.Data(http.StatusOK, "image/svg+xml", brokenIconSvg)
+	// Verify that shared thumbnails contain no metadata if this is enforced.
+	handler = ThumbStrip(handler)
 
-			// Set missing flag so that the file doesn't show up in search results anymore.
-			logError(logPrefix, f.Update("FileMissing", true))
+	// Count the bytes served per preview token if a daily budget is set.
+	handler = ThumbBudget(handler)
 
-			if f.AllFilesMissing() {
-				log.Infof("%s: deleting photo, all files missing for %s", logPrefix, clean.Log(f.FileName))
+	router.GET("/t/:thumb/:token/:size", handler)
 
-				if _, err := f.RelatedPhoto().Delete(false); err != nil {
-					log.Errorf("%s: %s while deleting %s", logPrefix, err, clean.Log(f.FileName))
-				}
-			}
+	// Clients that do not depend on size names can request a width instead.
+	router.GET("/t/:thumb/:token", func(c *gin.Context) {
+		var sizeName string
+
+		if w := txt.Int(c.Query("w")); w > 0 {
+			sizeName = thumb.FitWidth(w).Name.String()
+		}
 
+		c.Params = append(c.Params, gin.Param{Key: "size", Value: sizeName})
+
+		handler(c)
+	})
+
+	// Clients built for other servers may request explicit dimensions instead, which snap to the nearest
+	// size so that thumbnails are cached by their effective dimensions. The router requires the width
+	// to use the same parameter name as the size.
+	router.GET("/t/:thumb/:token/:size/:height", func(c *gin.Context) {
+		// Descriptive file names after the size name are cosmetic, see ThumbSlug.
+		if ThumbSlug(c) {
+			handler(c)
 			return
 		}
 
-		// Choose the smallest fitting size if the original image is smaller.
-		if size.Fit && f.Bounds().In(size.Bounds()) {
-			size = thumb.FitBounds(f.Bounds())
-			log.Tracef("%s: smallest fitting size for %s is %s (width %d, height %d)", logPrefix, clean.Log(f.FileName), size.Name, size.Width, size.Height)
+		var sizeName string
+
+		if w, h := txt.Int(c.Param("size")), txt.Int(c.Param("height")); w > 0 && h > 0 {
+			sizeName = thumb.FitDimensions(w, h).Name.String()
 		}
 
-		// Use original file if thumb size exceeds limit, see https://github.com/photoprism/photoprism/issues/157
-		if size.ExceedsLimit() && !download {
-			log.Debugf("%s: using original, size exceeds limit (width %d, height %d)", logPrefix, size.Width, size.Height)
+		for i := range c.Params {
+			if c.Params[i].Key == "size" {
+				c.Params[i].Value = sizeName
+			}
+		}
 
-			// Add HTTP cache header.
-			AddImmutableCacheHeader(c)
+		handler(c)
+	})
+}
 
-			// Return requested content.
-			c.File(fileName)
+// HeadThumb checks if a thumbnail image matching the file hash, crop area, and type already exists,
+// and returns status 200 with cache headers if so, or 204 otherwise. HEAD requests never create thumbnails.
+//
+// HEAD /api/v1/t/:thumb/:token/:size
+//
+// Parameters:
+//
+//	thumb: string sha1 file hash plus optional crop area, other hash types require a prefix like "blake3:"
+//	token: string url security token, see config
+//	size: string thumb type, see thumb.Sizes
+//	angle: float optional angle in degrees to straighten the image by instead of the stored file angle
+func HeadThumb(router *gin.RouterGroup) {
+	router.HEAD("/t/:thumb/:token/:size", func(c *gin.Context) {
+		if InvalidPreviewToken(c) {
+			c.Status(http.StatusForbidden)
 			return
 		}
 
-		// thumbName is the thumbnail filename.
-		var thumbName string
+		fileHash, cropArea := crop.ParseThumb(clean.Token(c.Param("thumb")))
 
-		// Try to find or create thumbnail image.
-		if conf.ThumbUncached() || size.Uncached() {
-			thumbName, err = size.FromFile(fileName, f.FileHash, conf.ThumbCachePath(), f.FileOrientation)
-		} else {
-			thumbName, err = size.FromCache(fileName, f.FileHash, conf.ThumbCachePath())
-		}
+		// Resolve other hash types like "blake3:..." to the sha1 hash thumbnails are addressed by.
+		fileHash, err := ThumbFileHash(fileHash)
 
-		// Failed?
 		if err != nil {
-			log.Errorf("%s: %s", logPrefix, err)
-			c.Data(http.StatusOK, "image/svg+xml", brokenIconSvg)
+			c.Status(http.StatusNotFound)
 			return
-		} else if thumbName == "" {
-			log.Errorf("%s: %s has empty thumb name - you may have found a bug", logPrefix, filepath.Base(fileName))
-			c.Data(http.StatusOK, "image/svg+xml", brokenIconSvg)
+		}
+
+		thumbPath := ThumbPath(fileHash)
+
+		// Is cropped thumbnail?
+		if cropArea != "" {
+			cropSize, ok := crop.Sizes[crop.Name(clean.Token(c.Param("size")))]
+
+			if !ok {
+				c.Status(http.StatusBadRequest)
+			} else if _, err := crop.FromCache(fileHash, cropArea, cropSize, thumbPath, thumb.NegotiateFormat(c.GetHeader("Accept"))); err != nil {
+				c.Status(http.StatusNoContent)
+			} else {
+				AddImmutableCacheHeader(c)
+				c.Status(http.StatusOK)
+			}
+
 			return
 		}
 
-		// Cache thumbnail filename to reduce the number of index queries.
-		cache.SetDefault(cacheKey, ThumbCache{thumbName, f.ShareBase(0)})
-		log.Debugf("cached %s [%s]", cacheKey, time.Since(start))
+		sizeName := thumb.Name(clean.Token(c.Param("size")))
 
-		// Add HTTP cache header.
-		AddImmutableCacheHeader(c)
+		size, ok := thumb.Sizes[sizeName]
+
+		if !ok {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+
+		// GET requests use the largest cached size in this case.
+		if sizeName, size = PolicySize(fileHash, thumbPath, sizeName, size); sizeName == "" {
+			c.Status(http.StatusNoContent)
+			return
+		}
+
+		angle, _ := thumb.ParseAngle(c.Query("angle"))
+		thumbHash := thumb.AngleHash(fileHash, angle)
 
-		// Return requested content.
-		if download {
-			c.FileAttachment(thumbName, f.DownloadName(DownloadName(c), 0))
+		// Check the thumbnail filename cache first, then the storage folder.
+		if cacheData, ok := get.ThumbCache().Get(ThumbCacheKey(thumbHash, sizeName, "")); ok && fs.FileExists(cacheData.(ThumbCache).FileName) {
+			AddImmutableCacheHeader(c)
+			c.Status(http.StatusOK)
+		} else if _, err := size.ResolvedName(thumbHash, thumbPath); err == nil {
+			AddImmutableCacheHeader(c)
+			c.Status(http.StatusOK)
 		} else {
-			c.File(thumbName)
+			c.Status(http.StatusNoContent)
 		}
 	})
 }
+
+// CropDataUri returns the cropped thumbnail as JSON with a base64 encoded data URI, e.g. for embedding it in emails.
+// Files that exceed DataUriSizeLimit are rejected to avoid huge payloads.
+func CropDataUri(c *gin.Context, fileName string) {
+	if info, err := os.Stat(fileName); err != nil {
+		log.Errorf("thumb: %s", err)
+		AbortEntityNotFound(c)
+		return
+	} else if info.Size() > DataUriSizeLimit {
+		Abort(c, http.StatusRequestEntityTooLarge, i18n.ErrFileTooLarge)
+		return
+	}
+
+	data, err := os.ReadFile(fileName)
+
+	if err != nil {
+		log.Errorf("thumb: %s", err)
+		AbortUnexpected(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": fmt.Sprintf("data:%s;base64,%s", fs.MimeTypeJPEG, base64.StdEncoding.EncodeToString(data))})
+}
+
+// ThumbPath returns the folder that contains the thumbnails of the file with the specified hash.
+// The index is only queried if thumbnails are stored next to the originals, see thumb.Path.
+func ThumbPath(fileHash string) string {
+	cachePath := get.Config().ThumbCachePath()
+
+	if !thumb.Sidecar() {
+		return cachePath
+	} else if f, err := query.FileByHash(fileHash); err != nil {
+		return cachePath
+	} else {
+		return thumb.Path(cachePath, photoprism.FileName(f.FileRoot, f.FileName))
+	}
+}
+
+// ThumbBadge returns the rating overlay badge of the file, based on the quality score of the related photo.
+// Archived photos are flagged as rejected.
+func ThumbBadge(f *entity.File) thumb.Badge {
+	if f == nil {
+		return thumb.Badge{}
+	}
+
+	p := f.RelatedPhoto()
+
+	return thumb.NewBadge(p.PhotoQuality, p.DeletedAt != nil || p.PhotoQuality < 0)
+}
+
+// CropSource creates the fit thumbnail that crops of the file are made from, straightened by the stored angle
+// like other thumbnails, so that crops look the same as the thumbnail they are made from.
+func CropSource(f *entity.File, thumbPath string) error {
+	_, err := thumb.Sizes[thumb.Fit720].FromFileAngle(photoprism.FileName(f.FileRoot, f.FileName), f.FileHash, thumbPath, f.FileOrientation, float64(f.FileAngle))
+
+	return err
+}
+
+// ThumbGPS returns the coordinates of the photo as "lat,lng", e.g. for clustering thumbnails on a map.
+// It returns an empty string if the photo has no location, is private, or places are disabled.
+// The location is only returned if enabled, see config.ThumbGPS.
+func ThumbGPS(f *entity.File) string {
+	if f == nil || !get.Config().ThumbGPS() || get.Config().DisablePlaces() {
+		return ""
+	}
+
+	p := f.RelatedPhoto()
+
+	if p.PhotoPrivate || p.NoLatLng() {
+		return ""
+	}
+
+	return fmt.Sprintf("%.6f,%.6f", p.PhotoLat, p.PhotoLng)
+}
+
+// ThumbMapLocation returns the location of the photo if a map overlay was requested and the location
+// may be shown, see ThumbGPS.
+func ThumbMapLocation(f *entity.File, overlay string) (lat, lng float64, ok bool) {
+	if overlay != thumb.OverlayMap || thumb.MapTileUrl == "" || ThumbGPS(f) == "" {
+		return 0, 0, false
+	}
+
+	p := f.RelatedPhoto()
+
+	return float64(p.PhotoLat), float64(p.PhotoLng), true
+}
+
+// StrictStatus checks if the client requested error status codes instead of placeholder icons
+// with status 200, e.g. for uptime monitors and prefetching.
+func StrictStatus(c *gin.Context) bool {
+	return txt.Bool(c.Query("strict")) || txt.Bool(c.GetHeader("X-Strict-Status"))
+}
+
+// ThumbIcon returns an SVG placeholder icon with status 200, or with the specified
+// error status if strict status codes were requested.
+func ThumbIcon(c *gin.Context, status int, icon []byte) {
+	if StrictStatus(c) {
+		IconData(c, status, icon)
+	} else {
+		IconData(c, http.StatusOK, icon)
+	}
+}
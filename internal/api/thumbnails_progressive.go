@@ -0,0 +1,115 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// ProgressiveMinWidth is the minimum width of fit sizes that may be returned progressively.
+var ProgressiveMinWidth = 1920
+
+// ProgressiveContentType is the response content type of progressive thumbnails, which browsers
+// render by replacing the preview with the full image once it has been received.
+const ProgressiveContentType = "multipart/x-mixed-replace"
+
+// ProgressiveRequested checks if the client accepts progressive thumbnails, either with the Accept
+// header or with the "progressive" query parameter, since browsers do not send custom headers for images.
+func ProgressiveRequested(c *gin.Context) bool {
+	// Multipart responses cannot be verified to contain no metadata, see ThumbStrip.
+	if ShareStripMetadata(c) {
+		return false
+	}
+
+	return txt.Bool(c.Query("progressive")) || strings.Contains(c.GetHeader("Accept"), ProgressiveContentType)
+}
+
+// ThumbFile returns the thumbnail file. If the client requested it, large fit sizes are preceded
+// by the cached fit_720 preview within the same response, see ProgressiveThumb.
+func ThumbFile(c *gin.Context, fileName, thumbHash, thumbPath string, size thumb.Size) {
+	if !size.Fit || size.Width < ProgressiveMinWidth || !ProgressiveRequested(c) {
+		c.File(fileName)
+		return
+	}
+
+	previewName, err := thumb.Sizes[thumb.Fit720].ResolvedName(thumbHash, thumbPath)
+
+	if err != nil || previewName == fileName {
+		c.File(fileName)
+		return
+	}
+
+	ProgressiveThumb(c, previewName, fileName)
+}
+
+// ProgressiveThumb sends a preview and the full image as parts of a single multipart response,
+// flushing the preview first so that it can be shown while the full image is still loading.
+func ProgressiveThumb(c *gin.Context, previewName, fileName string) {
+	mw := multipart.NewWriter(c.Writer)
+
+	c.Header("Content-Type", fmt.Sprintf("%s; boundary=%s", ProgressiveContentType, mw.Boundary()))
+	c.Header("Vary", "Accept")
+	c.Status(http.StatusOK)
+
+	for _, partName := range []string{previewName, fileName} {
+		if err := writeThumbPart(mw, partName); err != nil {
+			log.Errorf("thumb: %s while sending %s", err, clean.Log(partName))
+			return
+		}
+
+		c.Writer.Flush()
+	}
+
+	if err := mw.Close(); err != nil {
+		log.Debugf("thumb: %s", err)
+	}
+}
+
+// writeThumbPart writes an image file as part of a multipart response.
+func writeThumbPart(mw *multipart.Writer, fileName string) error {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	h := textproto.MIMEHeader{}
+
+	// Thumbnails are either PNG or JPEG images, so their content does not need to be inspected.
+	if fs.FileType(fileName) == fs.ImagePNG {
+		h.Set("Content-Type", fs.MimeTypePNG)
+	} else {
+		h.Set("Content-Type", fs.MimeTypeJPEG)
+	}
+
+	h.Set("Content-Length", fmt.Sprintf("%d", info.Size()))
+
+	part, err := mw.CreatePart(h)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, f)
+
+	return err
+}
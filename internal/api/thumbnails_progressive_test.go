@@ -0,0 +1,126 @@
+package api
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestProgressiveRequested(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/abc/token/fit_1920", nil)
+		assert.False(t, ProgressiveRequested(c))
+	})
+	t.Run("Query", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/abc/token/fit_1920?progressive=true", nil)
+		assert.True(t, ProgressiveRequested(c))
+	})
+	t.Run("Accept", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/abc/token/fit_1920", nil)
+		c.Request.Header.Set("Accept", "multipart/x-mixed-replace, image/jpeg")
+		assert.True(t, ProgressiveRequested(c))
+	})
+}
+
+func TestThumbFile(t *testing.T) {
+	hash := "e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0"
+	thumbPath := t.TempDir()
+
+	previewName, err := thumb.Sizes[thumb.Fit720].FileName(hash, thumbPath)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileName := filepath.Join(thumbPath, hash+"_1920x1200_fit.jpg")
+
+	if err = os.WriteFile(previewName, []byte("preview"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(fileName, []byte("full image"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Single", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/abc/token/fit_1920", nil)
+
+		ThumbFile(c, fileName, hash, thumbPath, thumb.Sizes[thumb.Fit1920])
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "full image", w.Body.String())
+	})
+	t.Run("TooSmall", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/abc/token/fit_720?progressive=true", nil)
+
+		ThumbFile(c, previewName, hash, thumbPath, thumb.Sizes[thumb.Fit720])
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "preview", w.Body.String())
+	})
+	t.Run("Progressive", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/abc/token/fit_1920?progressive=true", nil)
+
+		ThumbFile(c, fileName, hash, thumbPath, thumb.Sizes[thumb.Fit1920])
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		mediaType, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, ProgressiveContentType, mediaType)
+
+		r := multipart.NewReader(w.Body, params["boundary"])
+
+		for _, expected := range []string{"preview", "full image"} {
+			part, err := r.NextPart()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := io.ReadAll(part)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, "image/jpeg", part.Header.Get("Content-Type"))
+			assert.Equal(t, expected, string(data))
+		}
+
+		_, err = r.NextPart()
+		assert.Equal(t, io.EOF, err)
+	})
+	t.Run("NoPreview", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/abc/token/fit_1920?progressive=true", nil)
+
+		ThumbFile(c, fileName, hash, filepath.Join(thumbPath, "missing"), thumb.Sizes[thumb.Fit1920])
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "full image", w.Body.String())
+	})
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// StreamThumb creates a thumbnail and sends it to the client while it is being encoded, see thumb.FromFileStream.
+// It returns streamed = false if nothing was sent, e.g. because the thumbnail was already cached. Since the
+// response cannot be aborted once it has started, the configured thumbnail timeout does not apply.
+func StreamThumb(c *gin.Context, size thumb.Size, fileName, fileHash, thumbPath string, orientation int, angle float64) (thumbName string, streamed bool, err error) {
+	if thumbName, err = size.FileName(fileHash, thumbPath); err != nil {
+		return "", false, err
+	}
+
+	w := &thumbStreamWriter{c: c, thumbName: thumbName}
+
+	return size.FromFileStream(fileName, fileHash, thumbPath, orientation, angle, w)
+}
+
+// thumbStreamWriter sends the response headers with the first bytes, so that the crop region
+// computed while rendering is included, and flushes each chunk to reduce the time to first byte.
+type thumbStreamWriter struct {
+	c         *gin.Context
+	thumbName string
+	started   bool
+}
+
+// Write implements io.Writer.
+func (w *thumbStreamWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+
+		AddImmutableCacheHeader(w.c)
+		AddCropRegionHeader(w.c, w.thumbName)
+		w.c.Header("Content-Type", "image/jpeg")
+		w.c.Status(http.StatusOK)
+	}
+
+	n, err := w.c.Writer.Write(p)
+
+	if err == nil {
+		w.c.Writer.Flush()
+	}
+
+	return n, err
+}
@@ -1,12 +1,26 @@
 package api
 
 import (
+	"image/color"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/disintegration/imaging"
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/customize"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
 )
 
 func TestGetThumb(t *testing.T) {
@@ -57,5 +71,432 @@ func TestGetThumb(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, r.Code)
 	})
+	t.Run("Blake3", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/blake3:af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262/"+conf.PreviewToken()+"/tile_500")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("UnsupportedHashType", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/md5:2cad9168fa6acc5c5c2965ddf6ec465c/"+conf.PreviewToken()+"/tile_500")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, photoIconSvg, r.Body.Bytes())
+	})
+	t.Run("StrictInvalidType", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/1/"+conf.PreviewToken()+"/xxx?strict=true")
+
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+		assert.Equal(t, photoIconSvg, r.Body.Bytes())
+	})
+	t.Run("StrictWrongHash", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/1/"+conf.PreviewToken()+"/tile_500?strict=true")
+
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("StrictHeader", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		req, _ := http.NewRequest("GET", "/api/v1/t/1/"+conf.PreviewToken()+"/tile_500", nil)
+		req.Header.Set("X-Strict-Status", "true")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("CustomAngle", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"/tile_500?angle=2.5")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("NoCropRegion", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"/tile_500")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "", r.Header().Get("X-Crop-Region"))
+	})
+	t.Run("CropRegion", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		hash := "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+		thumbName, err := thumb.Sizes[thumb.Tile224].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = thumb.Sizes[thumb.Tile224].Create(imaging.New(300, 224, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(thumbName)
+
+		thumb.SetCropRegion(thumbName, thumb.Region{X: 0.25, W: 0.5, H: 1})
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/tile_224")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "0.2500,0.0000,0.5000,1.0000", r.Header().Get("X-Crop-Region"))
+	})
+	t.Run("Width", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		hash := "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+		thumbName, err := thumb.Sizes[thumb.Fit720].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = thumb.Sizes[thumb.Fit720].Create(imaging.New(720, 480, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(thumbName)
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"?w=640")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "image/jpeg", r.Header().Get("Content-Type"))
+	})
+	t.Run("InlineThenDownload", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		hash := "pcad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+		fileName := filepath.Join(conf.OriginalsPath(), "Germany/bridge.jpg")
+
+		if err := os.MkdirAll(filepath.Dir(fileName), fs.ModeDir); err != nil {
+			t.Fatal(err)
+		} else if err = imaging.Save(imaging.New(300, 200, color.White), fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(fileName)
+
+		conf.Options().ThumbUncached = true
+		defer func() { conf.Options().ThumbUncached = false }()
+		defer RemoveFromThumbCache(hash)
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/tile_224?strict=1")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "image/jpeg", r.Header().Get("Content-Type"))
+		assert.Equal(t, "", r.Header().Get("Content-Disposition"))
+		// The photo is private, so its location must not be exposed.
+		assert.Equal(t, "", r.Header().Get("X-GPS"))
+
+		if thumbName, err := thumb.Sizes[thumb.Tile224].FileName(hash, conf.ThumbCachePath()); err == nil {
+			defer os.Remove(thumbName)
+		}
+
+		// The download must not use the share name cached for the inline thumbnail.
+		r = PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/tile_224?download=1&name=file&strict=1")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Contains(t, r.Header().Get("Content-Disposition"), "bridge.jpg")
+
+		// The download name is cached for all sizes, so that existing thumbs can be downloaded without index query.
+		_, ok := get.ThumbCache().Get(ShareNameCacheKey(hash, customize.DownloadNameFile))
+		assert.True(t, ok)
+
+		get.ThumbCache().Delete(ThumbCacheKey(hash, thumb.Tile224, customize.DownloadNameFile))
+		r = PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/tile_224?download=1&name=file&strict=1")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Contains(t, r.Header().Get("Content-Disposition"), "bridge.jpg")
+	})
+	t.Run("RatingOverlay", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		hash := "pcad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+		fileName := filepath.Join(conf.OriginalsPath(), "Germany/bridge.jpg")
+
+		if err := os.MkdirAll(filepath.Dir(fileName), fs.ModeDir); err != nil {
+			t.Fatal(err)
+		} else if err = imaging.Save(imaging.New(300, 200, color.White), fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(fileName)
+
+		thumbName, err := thumb.Sizes[thumb.Tile224].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err = thumb.Sizes[thumb.Tile224].Create(imaging.New(224, 224, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(thumbName)
+		defer RemoveFromThumbCache(hash)
+
+		f, err := query.FileByHash(hash)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		overlayName := thumb.OverlayName(thumbName, ThumbBadge(f))
+		defer os.Remove(overlayName)
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/tile_224?overlay=rating&strict=1")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "image/jpeg", r.Header().Get("Content-Type"))
+		assert.NotContains(t, r.Header().Get("Cache-Control"), "immutable")
+		assert.FileExists(t, overlayName)
+	})
+	t.Run("CropDataUri", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		hash := "dcad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+
+		thumbName, err := thumb.Sizes[thumb.Fit720].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		} else if err = imaging.Save(imaging.New(720, 480, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(filepath.Dir(thumbName))
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"-016014058037/"+conf.PreviewToken()+"/tile_160?format=datauri")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.True(t, strings.HasPrefix(gjson.Get(r.Body.String(), "data").String(), "data:image/jpeg;base64,/9j/"))
+
+		DataUriSizeLimit = 16
+		defer func() { DataUriSizeLimit = 512 * 1024 }()
+
+		r = PerformRequest(app, "GET", "/api/v1/t/"+hash+"-016014058037/"+conf.PreviewToken()+"/tile_160?format=datauri")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, r.Code)
+	})
+	t.Run("InvalidWidth", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"?w=abc&strict=true")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Dimensions", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		hash := "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+		thumbName, err := thumb.Sizes[thumb.Fit720].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = thumb.Sizes[thumb.Fit720].Create(imaging.New(720, 480, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(thumbName)
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/640/480")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "image/jpeg", r.Header().Get("Content-Type"))
+	})
+	t.Run("InvalidDimensions", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"/abc/0?strict=true")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Slug", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		hash := "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+		thumbName, err := thumb.Sizes[thumb.Fit720].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = thumb.Sizes[thumb.Fit720].Create(imaging.New(720, 480, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(thumbName)
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/fit_720/my-photo-title.jpg")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "image/jpeg", r.Header().Get("Content-Type"))
+
+		// The file name must not affect which thumbnail is served.
+		expected := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/fit_720")
+		assert.Equal(t, expected.Body.Bytes(), r.Body.Bytes())
+	})
+	t.Run("SlugInvalidSize", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"/xxx/my-photo-title.jpg?strict=true")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+}
+
+func TestHeadThumb(t *testing.T) {
+	t.Run("WrongToken", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		HeadThumb(router)
+		r := PerformRequest(app, "HEAD", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/xxx/tile_500")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("InvalidType", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		HeadThumb(router)
+		r := PerformRequest(app, "HEAD", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"/xxx")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("NotCached", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		HeadThumb(router)
+		r := PerformRequest(app, "HEAD", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"/tile_100?angle=7.5")
+		assert.Equal(t, http.StatusNoContent, r.Code)
+		assert.Equal(t, "", r.Header().Get("Cache-Control"))
+	})
+	t.Run("Cached", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		HeadThumb(router)
+		hash := "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+		thumbName, err := thumb.Sizes[thumb.Tile50].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = thumb.Sizes[thumb.Tile50].Create(imaging.New(100, 100, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(thumbName)
+
+		r := PerformRequest(app, "HEAD", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/tile_50")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.NotEmpty(t, r.Header().Get("Cache-Control"))
+		assert.Equal(t, 0, r.Body.Len())
+	})
+	t.Run("CropNotCached", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		HeadThumb(router)
+		r := PerformRequest(app, "HEAD", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818-016014058037/"+conf.PreviewToken()+"/tile_160")
+		assert.Equal(t, http.StatusNoContent, r.Code)
+	})
+}
+
+func TestThumbPath(t *testing.T) {
+	conf := get.Config()
+
+	t.Run("Central", func(t *testing.T) {
+		assert.Equal(t, conf.ThumbCachePath(), ThumbPath("2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"))
+	})
+	t.Run("Sidecar", func(t *testing.T) {
+		thumb.Layout = thumb.LayoutSidecar
+		defer func() { thumb.Layout = thumb.LayoutCentral }()
+
+		assert.Equal(t, conf.ThumbCachePath(), ThumbPath("0000000000000000000000000000000000000000"))
+		thumbPath := ThumbPath("3cad9168fa6acc5c5c2965ddf6ec465ca42fd818")
+
+		assert.True(t, strings.HasPrefix(thumbPath, conf.OriginalsPath()))
+		assert.True(t, strings.HasSuffix(thumbPath, thumb.SidecarFolder))
+	})
+}
+
+func TestThumbBadge(t *testing.T) {
+	t.Run("Nil", func(t *testing.T) {
+		assert.Equal(t, thumb.Badge{}, ThumbBadge(nil))
+	})
+	t.Run("Photo", func(t *testing.T) {
+		f := &entity.File{Photo: &entity.Photo{PhotoQuality: 4}}
+		assert.Equal(t, thumb.Badge{Stars: 4}, ThumbBadge(f))
+	})
+	t.Run("Archived", func(t *testing.T) {
+		now := time.Now()
+		f := &entity.File{Photo: &entity.Photo{PhotoQuality: 3, DeletedAt: &now}}
+		assert.Equal(t, thumb.Badge{Stars: 3, Reject: true}, ThumbBadge(f))
+	})
+}
 
+func TestThumbGPS(t *testing.T) {
+	conf := get.Config()
+	conf.Options().ThumbGPS = true
+	defer func() { conf.Options().ThumbGPS = false }()
+
+	t.Run("Disabled", func(t *testing.T) {
+		conf.Options().ThumbGPS = false
+		defer func() { conf.Options().ThumbGPS = true }()
+
+		f := &entity.File{Photo: &entity.Photo{PhotoLat: 48.519234, PhotoLng: 9.057997}}
+		assert.Equal(t, "", ThumbGPS(f))
+	})
+	t.Run("Nil", func(t *testing.T) {
+		assert.Equal(t, "", ThumbGPS(nil))
+	})
+	t.Run("Location", func(t *testing.T) {
+		f := &entity.File{Photo: &entity.Photo{PhotoLat: 48.519234, PhotoLng: 9.057997}}
+		assert.Equal(t, "48.519234,9.057997", ThumbGPS(f))
+	})
+	t.Run("NoLocation", func(t *testing.T) {
+		f := &entity.File{Photo: &entity.Photo{}}
+		assert.Equal(t, "", ThumbGPS(f))
+	})
+	t.Run("Private", func(t *testing.T) {
+		f := &entity.File{Photo: &entity.Photo{PhotoLat: 48.519234, PhotoLng: 9.057997, PhotoPrivate: true}}
+		assert.Equal(t, "", ThumbGPS(f))
+	})
+}
+
+func TestGetThumb_PhotoUID(t *testing.T) {
+	// Fixture that is not modified by other tests.
+	hash := "pcad9a68fa6acc5c5ba965adf6ec465ca42fd925"
+
+	t.Run("Enabled", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+
+		conf.Options().ThumbPhotoUID = true
+		defer func() { conf.Options().ThumbPhotoUID = false }()
+
+		thumbName, err := thumb.Sizes[thumb.Fit720].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err = thumb.Sizes[thumb.Fit720].Create(imaging.New(720, 480, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(thumbName)
+		defer RemoveFromThumbCache(hash)
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/fit_720")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "pt9jtdre2lvl0y19", r.Header().Get("X-Photo-UID"))
+
+		// Cached thumbnails include the photo UID as well.
+		r = PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/fit_720")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "pt9jtdre2lvl0y19", r.Header().Get("X-Photo-UID"))
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+
+		thumbName, err := thumb.Sizes[thumb.Fit720].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err = thumb.Sizes[thumb.Fit720].Create(imaging.New(720, 480, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(thumbName)
+		defer RemoveFromThumbCache(hash)
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/fit_720")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "", r.Header().Get("X-Photo-UID"))
+	})
 }
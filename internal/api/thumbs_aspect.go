@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// GetAspectThumb returns a thumbnail with the specified aspect ratio, e.g. for responsive layouts, so that
+// clients don't need to decide between crops and fit sizes. Depending on the config, the image is cropped
+// with the subject kept centered, or padded to show the whole image. Images that would lose too much when
+// cropped are always padded, see crop.AspectMethod.
+//
+// GET /api/v1/t/aspect/:hash/:token/:ratio/:size
+//
+// Parameters:
+//
+//	hash: string sha1 file hash
+//	token: string security token (see config)
+//	ratio: string aspect ratio, e.g. 16x9 or 4x5
+//	size: string fit size the thumbnail must fit into, e.g. fit_720
+func GetAspectThumb(router *gin.RouterGroup) {
+	router.GET("/t/aspect/:hash/:token/:ratio/:size", ThumbBudget(func(c *gin.Context) {
+		if InvalidPreviewToken(c) {
+			IconData(c, http.StatusForbidden, brokenIconSvg)
+			return
+		}
+
+		fileHash := clean.Token(c.Param("hash"))
+		sizeName := thumb.Name(clean.Token(c.Param("size")))
+
+		w, h, err := crop.ParseAspect(clean.Token(c.Param("ratio")))
+
+		if err != nil {
+			log.Debugf("thumb: %s", err)
+			ThumbIcon(c, http.StatusBadRequest, photoIconSvg)
+			return
+		}
+
+		size, ok := thumb.Sizes[sizeName]
+
+		if !ok || !size.Fit {
+			log.Errorf("thumb: invalid size %s", clean.Log(sizeName.String()))
+			ThumbIcon(c, http.StatusBadRequest, photoIconSvg)
+			return
+		}
+
+		// Restrict the sizes available to share links, see config.ShareThumbSizes.
+		if sizeName, size, ok = ShareSize(c, sizeName, size); !ok {
+			log.Debugf("thumb: size %s not allowed for share links", clean.Log(sizeName.String()))
+			ThumbIcon(c, http.StatusForbidden, photoIconSvg)
+			return
+		}
+
+		f, err := query.FileByHash(fileHash)
+
+		if err != nil {
+			log.Debugf("thumb: %s", err)
+			ThumbIcon(c, http.StatusNotFound, photoIconSvg)
+			return
+		}
+
+		fileName, err := AspectThumb(f, crop.AspectSize(w, h, size), size)
+
+		if err != nil {
+			log.Warnf("thumb: %s (aspect)", err)
+			ThumbIcon(c, http.StatusNotFound, brokenIconSvg)
+			return
+		}
+
+		// Cached files are named by size and strategy, and change if faces or the focus point change.
+		AddImmutableCacheHeader(c)
+		AddContentTypeHeader(c, fs.MimeTypeJPEG)
+		c.File(fileName)
+	}))
+}
+
+// AspectThumb creates a thumbnail of the file with the aspect ratio of the target size, and returns the filename.
+// The fit thumbnail it is created from is generated first if it does not exist yet and the original is available,
+// straightened by the stored angle of the file.
+func AspectThumb(f *entity.File, target crop.Size, fit thumb.Size) (string, error) {
+	thumbPath := ThumbPath(f.FileHash)
+
+	if fitName, err := fit.FileName(f.FileHash, thumbPath); err != nil {
+		return "", err
+	} else if !fs.FileExists(fitName) {
+		if _, err = fit.FromFileAngle(photoprism.FileName(f.FileRoot, f.FileName), f.FileHash, thumbPath, f.FileOrientation, float64(f.FileAngle)); err != nil {
+			log.Debugf("thumb: %s (aspect)", err)
+		}
+	}
+
+	method := crop.AspectMethod(f.FileWidth, f.FileHeight, target)
+
+	return crop.FromAspect(f.FileHash, thumbPath, target, method, CropHints(f))
+}
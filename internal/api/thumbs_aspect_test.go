@@ -0,0 +1,66 @@
+package api
+
+import (
+	"image/color"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestGetAspectThumb(t *testing.T) {
+	// Fixture that is not modified by other tests.
+	hash := "pcad9a68fa6acc5c5ba965adf6ec465ca42fd926"
+
+	t.Run("Ok", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetAspectThumb(router)
+
+		thumbName, err := thumb.Sizes[thumb.Fit720].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		} else if err = imaging.Save(imaging.New(720, 480, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(filepath.Dir(thumbName))
+
+		r := PerformRequest(app, "GET", "/api/v1/t/aspect/"+hash+"/"+conf.PreviewToken()+"/16x9/fit_720")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, fs.MimeTypeJPEG, r.Header().Get("Content-Type"))
+	})
+	t.Run("InvalidRatio", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetAspectThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/aspect/"+hash+"/"+conf.PreviewToken()+"/100x1/fit_720?strict=true")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("InvalidSize", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetAspectThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/aspect/"+hash+"/"+conf.PreviewToken()+"/16x9/tile_500?strict=true")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetAspectThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/aspect/0000000000000000000000000000000000000000/"+conf.PreviewToken()+"/16x9/fit_720?strict=true")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("InvalidToken", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetAspectThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/aspect/"+hash+"/xxx/16x9/fit_720")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// ThumbAsync creates a thumbnail with the configured timeout, see thumb.WithTimeout. If asynchronous creation
+// is enabled, see config.ThumbAsync, or the client requested long polling, see ThumbWait, thumb.ErrPending
+// is returned when it is not done in time, and clients are notified with a "thumbs.created" event once it is.
+func ThumbAsync(c *gin.Context, thumbHash string, size thumb.Size, create func() (string, error)) (string, error) {
+	conf := get.Config()
+	wait := conf.ThumbAsync()
+
+	if longPoll := ThumbWait(c); longPoll > wait {
+		wait = longPoll
+	}
+
+	key := thumbHash + ":" + size.Name.String()
+
+	// Creation continues in the background after a timeout, see thumb.WithTimeout.
+	if wait <= 0 {
+		return thumb.WithTimeout(key, conf.ThumbTimeout(), create)
+	}
+
+	// The time clients wait is already limited, so no timeout is needed in this case.
+	return thumb.Async(key, wait, func() (string, error) {
+		fileName, err := create()
+
+		if err != nil {
+			log.Warnf("thumb: %s (create %s in background)", err, size.Name)
+		} else {
+			event.Publish("thumbs.created", event.Data{"hash": thumbHash, "size": size.Name})
+		}
+
+		return fileName, err
+	})
+}
+
+// ThumbWait returns how long the client wants to wait for a thumbnail that is being created, as requested
+// with the "wait" query parameter or the "Prefer: wait=N" header in seconds, up to the configured maximum
+// for long polling, see config.ThumbWait. It returns 0 if long polling was not requested or is disabled.
+func ThumbWait(c *gin.Context) time.Duration {
+	max := get.Config().ThumbWait()
+
+	if max <= 0 {
+		return 0
+	}
+
+	seconds := txt.Int(c.Query("wait"))
+
+	for _, pref := range strings.Split(c.GetHeader("Prefer"), ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(pref), "="); ok && strings.EqualFold(k, "wait") {
+			seconds = txt.Int(v)
+		}
+	}
+
+	if seconds <= 0 {
+		return 0
+	} else if wait := time.Duration(seconds) * time.Second; wait < max {
+		return wait
+	}
+
+	return max
+}
+
+// ThumbPending responds with 202 Accepted, a placeholder icon, and a Retry-After header if a thumbnail
+// is still being created, so that clients can check again later, see thumb.AsyncRetry.
+func ThumbPending(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Retry-After", strconv.Itoa(thumb.AsyncRetry))
+	IconData(c, http.StatusAccepted, photoIconSvg)
+}
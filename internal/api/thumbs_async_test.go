@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestThumbAsync(t *testing.T) {
+	newContext := func(query string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/hash/token/tile_500"+query, nil)
+		return c
+	}
+
+	t.Run("Blocking", func(t *testing.T) {
+		fileName, err := ThumbAsync(newContext(""), "3cad9168fa6acc5c5c2965ddf6ec465ca42fd818", thumb.Sizes[thumb.Tile500], func() (string, error) {
+			time.Sleep(10 * time.Millisecond)
+			return "foo.jpg", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "foo.jpg", fileName)
+	})
+	t.Run("Pending", func(t *testing.T) {
+		conf := get.Config()
+		conf.Options().ThumbAsync = 1
+		defer func() { conf.Options().ThumbAsync = 0 }()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		fileName, err := ThumbAsync(newContext(""), "4cad9168fa6acc5c5c2965ddf6ec465ca42fd818", thumb.Sizes[thumb.Tile500], func() (string, error) {
+			<-release
+			return "bar.jpg", nil
+		})
+
+		assert.ErrorIs(t, err, thumb.ErrPending)
+		assert.Equal(t, "", fileName)
+	})
+	t.Run("LongPoll", func(t *testing.T) {
+		conf := get.Config()
+		conf.Options().ThumbWait = 5
+		defer func() { conf.Options().ThumbWait = 0 }()
+
+		fileName, err := ThumbAsync(newContext("?wait=5"), "5cad9168fa6acc5c5c2965ddf6ec465ca42fd818", thumb.Sizes[thumb.Tile500], func() (string, error) {
+			time.Sleep(50 * time.Millisecond)
+			return "baz.jpg", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "baz.jpg", fileName)
+	})
+}
+
+func TestThumbWait(t *testing.T) {
+	newContext := func(query, prefer string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/hash/token/tile_500"+query, nil)
+
+		if prefer != "" {
+			c.Request.Header.Set("Prefer", prefer)
+		}
+
+		return c
+	}
+
+	conf := get.Config()
+
+	t.Run("Disabled", func(t *testing.T) {
+		assert.Equal(t, time.Duration(0), ThumbWait(newContext("?wait=10", "")))
+	})
+
+	conf.Options().ThumbWait = 30
+	defer func() { conf.Options().ThumbWait = 0 }()
+
+	t.Run("NotRequested", func(t *testing.T) {
+		assert.Equal(t, time.Duration(0), ThumbWait(newContext("", "")))
+	})
+	t.Run("Query", func(t *testing.T) {
+		assert.Equal(t, 10*time.Second, ThumbWait(newContext("?wait=10", "")))
+	})
+	t.Run("Header", func(t *testing.T) {
+		assert.Equal(t, 15*time.Second, ThumbWait(newContext("", "respond-async, wait=15")))
+	})
+	t.Run("Limit", func(t *testing.T) {
+		assert.Equal(t, 30*time.Second, ThumbWait(newContext("?wait=600", "")))
+	})
+}
+
+func TestThumbPending(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/hash/token/tile_500", nil)
+
+	ThumbPending(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+}
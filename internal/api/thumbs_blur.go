@@ -0,0 +1,44 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// ShareBlurFaces checks if the request belongs to a share link visitor session with a link that has face
+// blurring enabled, in which case faces must be blurred in thumbnails, see ShareLinks.
+func ShareBlurFaces(c *gin.Context) bool {
+	for _, link := range ShareLinks(c) {
+		if link.BlurFaces {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ThumbFaceRegions returns the regions of valid faces in the file relative to a thumbnail of the specified size.
+func ThumbFaceRegions(f *entity.File, size thumb.Size) (regions []thumb.Region) {
+	if f == nil {
+		return regions
+	}
+
+	visible := thumb.FillRegion(f.FileWidth, f.FileHeight, size)
+
+	for _, m := range *f.Markers() {
+		if !m.ValidFace() {
+			continue
+		}
+
+		regions = append(regions, thumb.Region{
+			X: float64(m.X),
+			Y: float64(m.Y),
+			W: float64(m.W),
+			H: float64(m.H),
+		}.Within(visible))
+	}
+
+	return regions
+}
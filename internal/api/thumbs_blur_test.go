@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestShareBlurFaces(t *testing.T) {
+	app, router, _ := NewApiTest()
+
+	router.GET("/blur/:token", func(c *gin.Context) {
+		if ShareBlurFaces(c) {
+			c.Status(http.StatusOK)
+		} else {
+			c.Status(http.StatusNoContent)
+		}
+	})
+
+	entity.PreviewToken.Set("visitor2preview", entity.SessionFixtures.Get("visitor").ID)
+	defer entity.PreviewToken.Unset("visitor2preview")
+
+	t.Run("NoVisitor", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/blur/unknown2preview?s=1jxf3jfn2k")
+		assert.Equal(t, http.StatusNoContent, r.Code)
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/blur/visitor2preview")
+		assert.Equal(t, http.StatusNoContent, r.Code)
+	})
+}
+
+func TestThumbFaceRegions(t *testing.T) {
+	t.Run("Nil", func(t *testing.T) {
+		assert.Empty(t, ThumbFaceRegions(nil, thumb.Sizes[thumb.Tile224]))
+	})
+	t.Run("NoMarkers", func(t *testing.T) {
+		f := &entity.File{FileWidth: 400, FileHeight: 200}
+		assert.Empty(t, ThumbFaceRegions(f, thumb.Sizes[thumb.Tile224]))
+	})
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/server/limiter"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// ThumbBudget wraps a thumbnail handler to count the bytes served per preview token, and rejects
+// requests with status 429 once the daily budget of the token is exceeded, see limiter.Thumbs.
+// It must wrap every handler that renders images with a preview token, e.g. thumbnails, crops and covers.
+func ThumbBudget(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limiter.Thumbs.Enabled() {
+			handler(c)
+			return
+		}
+
+		token := clean.UrlToken(c.Param("token"))
+
+		if limiter.Thumbs.Exceeded(token) {
+			limiter.Abort(c)
+			return
+		}
+
+		handler(c)
+
+		// Only count successful responses, so that invalid tokens are not tracked.
+		if c.Writer.Status() == http.StatusOK {
+			limiter.Thumbs.Add(token, int64(c.Writer.Size()))
+		}
+	}
+}
+
+// GetThumbBudget returns the approximate number of thumbnail bytes served per preview token today.
+//
+// GET /api/v1/thumbs/budget
+func GetThumbBudget(router *gin.RouterGroup) {
+	router.GET("/thumbs/budget", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionView)
+
+		// Abort if permission was not granted.
+		if s.Invalid() || get.Config().Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		c.JSON(http.StatusOK, limiter.Thumbs.Usage())
+	})
+}
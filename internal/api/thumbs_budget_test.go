@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/server/limiter"
+)
+
+func TestThumbBudget(t *testing.T) {
+	app, router, _ := NewApiTest()
+
+	router.GET("/budget/:token", ThumbBudget(func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/jpeg", make([]byte, 100))
+	}))
+
+	limiter.Thumbs.SetLimit(150)
+	defer limiter.Thumbs.SetLimit(0)
+
+	r := PerformRequest(app, "GET", "/api/v1/budget/budgettoken1")
+	assert.Equal(t, http.StatusOK, r.Code)
+	r = PerformRequest(app, "GET", "/api/v1/budget/budgettoken1")
+	assert.Equal(t, http.StatusOK, r.Code)
+	r = PerformRequest(app, "GET", "/api/v1/budget/budgettoken1")
+	assert.Equal(t, http.StatusTooManyRequests, r.Code)
+	r = PerformRequest(app, "GET", "/api/v1/budget/budgettoken2")
+	assert.Equal(t, http.StatusOK, r.Code)
+}
+
+func TestGetThumbBudget(t *testing.T) {
+	t.Run("Unauthorized", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetThumbBudget(router)
+		r := PerformRequest(app, "GET", "/api/v1/thumbs/budget")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("Success", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetThumbBudget(router)
+
+		limiter.Thumbs.Add("budgettoken3", 1234)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "GET", "/api/v1/thumbs/budget", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, int64(1234), gjson.Get(r.Body.String(), "bytes.budgettoken3").Int())
+		assert.NotEmpty(t, gjson.Get(r.Body.String(), "day").String())
+	})
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Namespace for caching and logs.
+const thumbCdn = "thumb-cdn"
+
+// CdnCheckTimeout is the maximum time to wait for the CDN when checking if a thumbnail exists.
+var CdnCheckTimeout = 5 * time.Second
+
+// CdnMissingTTL is the time until thumbnails that were not found on the CDN are checked again.
+var CdnMissingTTL = 5 * time.Minute
+
+// ThumbCdnPath returns the slash-separated path of a cached thumbnail relative to the thumbnail cache folder,
+// or an empty string if it is not stored there, e.g. if thumbnails are stored in sidecar folders.
+func ThumbCdnPath(fileName string) string {
+	rel, err := filepath.Rel(get.Config().ThumbCachePath(), fileName)
+
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// SignCdnUrl adds the expiration time as Unix timestamp and the hex-encoded HMAC-SHA256 signature of the
+// escaped URL path and expiration time, e.g. "/thumbs/a/b/c/[hash]_720x720_fit.jpg?expires=1700000000",
+// as "expires" and "signature" query parameters, so that the CDN can verify requests with the same secret.
+func SignCdnUrl(rawUrl, secret string, expires time.Time) string {
+	if secret == "" {
+		return rawUrl
+	}
+
+	u, err := url.Parse(rawUrl)
+
+	if err != nil {
+		return rawUrl
+	}
+
+	exp := strconv.FormatInt(expires.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(u.EscapedPath() + "?expires=" + exp))
+
+	q := u.Query()
+	q.Set("expires", exp)
+	q.Set("signature", hex.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// CdnExpires returns the expiration time of signed CDN URLs. It is rounded, so that URLs stay the same for
+// half the configured duration and can be cached, while remaining valid for at least as long.
+func CdnExpires(now time.Time) time.Time {
+	d := get.Config().ThumbCdnExpires()
+
+	return now.Truncate(d / 2).Add(d)
+}
+
+// ThumbCdnUrl returns the signed CDN URL of a cached thumbnail, or an empty string if no CDN is configured.
+func ThumbCdnUrl(fileName string) string {
+	conf := get.Config()
+	baseUrl := conf.ThumbCdnUrl()
+
+	if baseUrl == "" {
+		return ""
+	}
+
+	rel := ThumbCdnPath(fileName)
+
+	if rel == "" {
+		return ""
+	}
+
+	return SignCdnUrl(baseUrl+"/"+rel, conf.ThumbCdnSecret(), CdnExpires(time.Now()))
+}
+
+// CheckCdn sends a HEAD request to the signed CDN URL of a cached thumbnail and reports if it exists.
+func CheckCdn(fileName string) bool {
+	cdnUrl := ThumbCdnUrl(fileName)
+
+	if cdnUrl == "" {
+		return false
+	}
+
+	client := &http.Client{Timeout: CdnCheckTimeout}
+
+	resp, err := client.Head(cdnUrl)
+
+	if err != nil {
+		log.Debugf("%s: %s", thumbCdn, clean.Error(err))
+		return false
+	}
+
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
+// ThumbOnCdn reports if a cached thumbnail is known to exist on the CDN. Unknown thumbnails are checked
+// in the background, so that the request can be served directly in the meantime, see CheckCdn.
+func ThumbOnCdn(fileName string) bool {
+	rel := ThumbCdnPath(fileName)
+
+	if rel == "" {
+		return false
+	}
+
+	cache := get.ThumbCache()
+	cacheKey := CacheKey(thumbCdn, rel, "")
+
+	if found, hit := cache.Get(cacheKey); hit {
+		return found.(bool)
+	}
+
+	// Prevent concurrent requests from checking the same thumbnail.
+	cache.Set(cacheKey, false, CdnMissingTTL)
+
+	go func() {
+		if CheckCdn(fileName) {
+			cache.SetDefault(cacheKey, true)
+		}
+	}()
+
+	return false
+}
+
+// CdnRedirect redirects the request to the signed CDN URL of a cached thumbnail if it is known to exist
+// there, and returns true. It returns false if the thumbnail must be served directly instead, e.g. because
+// no CDN is configured, or the request belongs to a share link visitor session that may restrict or modify
+// the image, see ShareVisitor.
+func CdnRedirect(c *gin.Context, fileName string) bool {
+	if get.Config().ThumbCdnUrl() == "" || ProgressiveRequested(c) || ShareVisitor(c) {
+		return false
+	} else if !ThumbOnCdn(fileName) {
+		return false
+	}
+
+	cdnUrl := ThumbCdnUrl(fileName)
+
+	if cdnUrl == "" {
+		return false
+	}
+
+	// Redirects may be cached for as long as the signed URL is guaranteed to be valid.
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(get.Config().ThumbCdnExpires().Seconds()/2)))
+	c.Redirect(http.StatusFound, cdnUrl)
+
+	return true
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestThumbCdnPath(t *testing.T) {
+	cachePath := get.Config().ThumbCachePath()
+
+	assert.Equal(t, "a/b/c/abc_720x720_fit.jpg", ThumbCdnPath(filepath.Join(cachePath, "a", "b", "c", "abc_720x720_fit.jpg")))
+	assert.Equal(t, "", ThumbCdnPath(filepath.Join(cachePath, "..", "abc_720x720_fit.jpg")))
+	assert.Equal(t, "", ThumbCdnPath(cachePath))
+}
+
+func TestSignCdnUrl(t *testing.T) {
+	expires := time.Unix(1700000000, 0)
+
+	t.Run("Signed", func(t *testing.T) {
+		result := SignCdnUrl("https://cdn.example.com/thumbs/a/b/c/abc_720x720_fit.jpg", "secret", expires)
+
+		u, err := url.Parse(result)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte("/thumbs/a/b/c/abc_720x720_fit.jpg?expires=1700000000"))
+
+		assert.Equal(t, "1700000000", u.Query().Get("expires"))
+		assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), u.Query().Get("signature"))
+	})
+	t.Run("NoSecret", func(t *testing.T) {
+		assert.Equal(t, "https://cdn.example.com/a.jpg", SignCdnUrl("https://cdn.example.com/a.jpg", "", expires))
+	})
+}
+
+func TestCdnExpires(t *testing.T) {
+	now := time.Now()
+	a := CdnExpires(now)
+
+	assert.True(t, a.After(now.Add(30*time.Minute-time.Second)))
+	assert.True(t, a.Before(now.Add(time.Hour+time.Second)))
+	assert.Equal(t, a, CdnExpires(now.Truncate(30*time.Minute)))
+}
+
+func TestThumbOnCdn(t *testing.T) {
+	conf := get.Config()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Query().Get("signature") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+		} else if r.URL.Path == "/thumbs/a/b/c/abc_720x720_fit.jpg" {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	conf.Options().ThumbCdnUrl = server.URL + "/thumbs"
+	conf.Options().ThumbCdnSecret = "secret"
+
+	defer func() {
+		conf.Options().ThumbCdnUrl = ""
+		conf.Options().ThumbCdnSecret = ""
+	}()
+
+	t.Run("Found", func(t *testing.T) {
+		fileName := filepath.Join(conf.ThumbCachePath(), "a", "b", "c", "abc_720x720_fit.jpg")
+
+		defer get.ThumbCache().Delete(CacheKey(thumbCdn, "a/b/c/abc_720x720_fit.jpg", ""))
+
+		assert.True(t, CheckCdn(fileName))
+		assert.False(t, ThumbOnCdn(fileName))
+		assert.Eventually(t, func() bool { return ThumbOnCdn(fileName) }, 2*time.Second, 10*time.Millisecond)
+	})
+	t.Run("Missing", func(t *testing.T) {
+		fileName := filepath.Join(conf.ThumbCachePath(), "d", "e", "f", "def_720x720_fit.jpg")
+
+		defer get.ThumbCache().Delete(CacheKey(thumbCdn, "d/e/f/def_720x720_fit.jpg", ""))
+
+		assert.False(t, CheckCdn(fileName))
+		assert.False(t, ThumbOnCdn(fileName))
+	})
+}
+
+func TestGetThumb_Cdn(t *testing.T) {
+	hash := "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer server.Close()
+
+	app, router, conf := NewApiTest()
+	GetThumb(router)
+
+	conf.Options().ThumbCdnUrl = server.URL
+	defer func() { conf.Options().ThumbCdnUrl = "" }()
+
+	fileName, err := thumb.Sizes[thumb.Tile224].FileName(hash, conf.ThumbCachePath())
+
+	if err != nil {
+		t.Fatal(err)
+	} else if err = imaging.Save(imaging.New(224, 224, color.White), fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(fileName)
+	defer RemoveFromThumbCache(hash)
+
+	rel := ThumbCdnPath(fileName)
+	get.ThumbCache().SetDefault(CacheKey(thumbCdn, rel, ""), true)
+	defer get.ThumbCache().Delete(CacheKey(thumbCdn, rel, ""))
+
+	t.Run("Redirect", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/"+conf.PreviewToken()+"/tile_224")
+		assert.Equal(t, http.StatusFound, r.Code)
+		assert.Equal(t, server.URL+"/"+rel, r.Header().Get("Location"))
+	})
+	t.Run("ShareVisitor", func(t *testing.T) {
+		entity.PreviewToken.Set("visitor6preview", entity.SessionFixtures.Get("visitor").ID)
+		defer entity.PreviewToken.Unset("visitor6preview")
+
+		r := PerformRequest(app, "GET", "/api/v1/t/"+hash+"/visitor6preview/tile_224")
+		assert.NotEqual(t, http.StatusFound, r.Code)
+	})
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// CenterCrop checks if a thumbnail without crop area should be a centered square crop, see crop.CenterArea.
+// This is the case if requested with "area=center", or if the size only exists as crop size, e.g. tile_160.
+func CenterCrop(c *gin.Context) bool {
+	if c.Query("area") == crop.AreaCenter {
+		return true
+	}
+
+	name := clean.Token(c.Param("size"))
+
+	if _, ok := crop.Sizes[crop.Name(name)]; !ok {
+		return false
+	} else if _, ok = thumb.Sizes[thumb.Name(name)]; ok {
+		return false
+	} else if _, ok = crop.HeroSizes[crop.Name(name)]; ok {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCenterCrop(t *testing.T) {
+	newContext := func(size, query string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/hash/token/"+size+query, nil)
+		c.Params = gin.Params{{Key: "size", Value: size}}
+		return c
+	}
+
+	t.Run("Query", func(t *testing.T) {
+		assert.True(t, CenterCrop(newContext("tile_224", "?area=center")))
+		assert.False(t, CenterCrop(newContext("tile_224", "?area=foo")))
+	})
+	t.Run("CropSize", func(t *testing.T) {
+		assert.True(t, CenterCrop(newContext("tile_160", "")))
+		assert.True(t, CenterCrop(newContext("tile_320", "")))
+	})
+	t.Run("ThumbSize", func(t *testing.T) {
+		assert.False(t, CenterCrop(newContext("tile_224", "")))
+		assert.False(t, CenterCrop(newContext("fit_720", "")))
+	})
+	t.Run("HeroSize", func(t *testing.T) {
+		assert.False(t, CenterCrop(newContext("hero_1500", "")))
+	})
+}
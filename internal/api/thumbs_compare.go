@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// CompareThumb creates a thumbnail of an original with the specified size and JPEG quality, and returns
+// its SSIM and PSNR compared to the same thumbnail without compression loss, see thumb.Compare. This is
+// intended to choose quality settings, so the thumbnail is not cached.
+//
+// GET /api/v1/thumbs/compare/:hash/:size
+//
+// Parameters:
+//
+//	hash: string sha1 file hash
+//	size: string thumb type, see thumb.Sizes
+//	quality: int optional JPEG quality from 25 to 100, the configured quality is used by default
+func CompareThumb(router *gin.RouterGroup) {
+	router.GET("/thumbs/compare/:hash/:size", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionManage)
+		conf := get.Config()
+
+		// Abort if permission was not granted.
+		if s.Invalid() || conf.Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		size, ok := thumb.Sizes[thumb.Name(clean.Token(c.Param("size")))]
+
+		if !ok {
+			AbortBadRequest(c)
+			return
+		}
+
+		quality := conf.JpegQuality()
+
+		if q := c.Query("quality"); q != "" {
+			quality = thumb.ParseQuality(q)
+		}
+
+		f, err := query.FileByHash(clean.Token(c.Param("hash")))
+
+		if err != nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		img, err := thumb.Open(photoprism.FileName(f.FileRoot, f.FileName), f.FileOrientation)
+
+		if err != nil {
+			log.Errorf("thumbs: %s in %s (compare)", err, clean.Log(f.FileName))
+			AbortUnexpected(c)
+			return
+		}
+
+		result, err := thumb.Compare(img, f.FileHash, conf.TempPath(), size, quality)
+
+		if err != nil {
+			log.Errorf("thumbs: %s in %s (compare)", err, clean.Log(f.FileName))
+			AbortUnexpected(c)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	})
+}
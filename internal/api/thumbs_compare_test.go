@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+func TestCompareThumb(t *testing.T) {
+	t.Run("Unauthorized", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		CompareThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/thumbs/compare/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/tile_224")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("InvalidSize", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		CompareThumb(router)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "GET", "/api/v1/thumbs/compare/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/foo", sess)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		CompareThumb(router)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "GET", "/api/v1/thumbs/compare/0000000000000000000000000000000000000000/tile_224", sess)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ThumbCropArea returns the crop area of the request. If the largest centered square was requested without
+// an area, e.g. for avatar-style tiles, it returns the area of that square, or the whole image if entropy
+// crops are configured for the size, see crop.SetEntropy. It returns false if an error response was sent.
+func ThumbCropArea(c *gin.Context, fileHash, cropArea string) (string, bool) {
+	logPrefix := "thumb"
+
+	if cropArea != "" || !CenterCrop(c) {
+		return cropArea, true
+	}
+
+	if s, ok := crop.Sizes[crop.Name(clean.Token(c.Param("size")))]; ok && s.Entropy() && c.Query("area") != crop.AreaCenter {
+		return crop.FullArea().String(), true
+	} else if f, err := query.FileByHash(fileHash); err != nil {
+		log.Debugf("%s: %s", logPrefix, err)
+		ThumbIcon(c, http.StatusNotFound, photoIconSvg)
+		return "", false
+	} else if cropArea = crop.CenterArea(f.FileWidth, f.FileHeight).String(); cropArea == "" {
+		log.Debugf("%s: unknown dimensions of %s, cannot crop center", logPrefix, clean.Log(f.FileName))
+		ThumbIcon(c, http.StatusUnprocessableEntity, photoIconSvg)
+		return "", false
+	}
+
+	return cropArea, true
+}
+
+// CropThumb sends the crop of the specified area in the requested crop size, and creates the thumbnail
+// that crops are made from first if it does not exist yet, see CropSource.
+func CropThumb(c *gin.Context, fileHash, cropArea, thumbPath string) {
+	logPrefix := "thumb"
+	cropName := crop.Name(clean.Token(c.Param("size")))
+
+	cropSize, ok := crop.Sizes[cropName]
+
+	if !ok {
+		log.Errorf("%s: invalid size %s", logPrefix, clean.Log(string(cropName)))
+		ThumbIcon(c, http.StatusBadRequest, photoIconSvg)
+		return
+	}
+
+	// Negotiate crop image format, data URIs are always JPEG.
+	format := fs.ImageJPEG
+
+	if c.Query("format") != "datauri" {
+		format = thumb.NegotiateFormat(c.GetHeader("Accept"))
+	}
+
+	fileName, err := crop.FromRequest(fileHash, cropArea, cropSize, thumbPath, format)
+
+	// Create the thumbnail that crops are made from if it does not exist yet, see CropSource.
+	if errors.Is(err, crop.ErrNotFound) {
+		if f, findErr := query.FileByHash(fileHash); findErr != nil {
+			log.Debugf("%s: %s", logPrefix, findErr)
+		} else if findErr = CropSource(f, thumbPath); findErr != nil {
+			log.Debugf("%s: %s (crop)", logPrefix, findErr)
+		} else {
+			fileName, err = crop.FromRequest(fileHash, cropArea, cropSize, thumbPath, format)
+		}
+	}
+
+	if err != nil {
+		log.Warnf("%s: %s", logPrefix, err)
+		ThumbIcon(c, http.StatusNotFound, brokenIconSvg)
+		return
+	} else if fileName == "" {
+		log.Errorf("%s: empty file name - you may have found a bug", logPrefix)
+		ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+		return
+	}
+
+	// Add HTTP cache header.
+	AddImmutableCacheHeader(c)
+
+	// Responses depend on the formats supported by the client.
+	if len(thumb.Formats()) > 1 {
+		c.Header("Vary", "Accept")
+	}
+
+	if c.Query("format") == "datauri" {
+		CropDataUri(c, fileName)
+	} else if c.Query("download") != "" {
+		c.FileAttachment(fileName, cropName.Format(fs.FileType(fileName)))
+	} else {
+		AddContentTypeHeader(c, thumb.FormatMimeType(fs.FileType(fileName)))
+		c.File(fileName)
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Namespace for caching and logs.
+const faceThumb = "face-thumb"
+
+// GetFaceThumb returns the cropped thumbnail of a face, so that clients don't need to build crop areas from
+// face data. The face is resolved to the stored box of its most representative marker, see query.FaceMarker.
+//
+// GET /api/v1/t/face/:uid/:token/:size
+//
+// Parameters:
+//
+//	uid: string face id as returned by the API
+//	token: string security token (see config)
+//	size: string crop size, see crop.Sizes
+func GetFaceThumb(router *gin.RouterGroup) {
+	router.GET("/t/face/:uid/:token/:size", ThumbBudget(func(c *gin.Context) {
+		if InvalidPreviewToken(c) {
+			IconData(c, http.StatusForbidden, portraitIconSvg)
+			return
+		}
+
+		start := time.Now()
+		faceId := clean.Token(c.Param("uid"))
+		cropName := crop.Name(clean.Token(c.Param("size")))
+
+		cropSize, ok := crop.Sizes[cropName]
+
+		if !ok {
+			log.Errorf("%s: invalid size %s", faceThumb, clean.Log(string(cropName)))
+			ThumbIcon(c, http.StatusBadRequest, portraitIconSvg)
+			return
+		}
+
+		// Responses depend on the formats supported by the client.
+		format := thumb.NegotiateFormat(c.GetHeader("Accept"))
+
+		if len(thumb.Formats()) > 1 {
+			c.Header("Vary", "Accept")
+		}
+
+		cache := get.CoverCache()
+		cacheKey := FaceThumbCacheKey(faceId, cropName, format)
+
+		if cacheData, ok := cache.Get(cacheKey); ok {
+			log.Tracef("api-v1: cache hit for %s [%s]", cacheKey, time.Since(start))
+
+			if cached := cacheData.(ThumbCache); fs.FileExists(cached.FileName) {
+				AddCoverCacheHeader(c)
+				AddContentTypeHeader(c, thumb.FormatMimeType(fs.FileType(cached.FileName)))
+				c.File(cached.FileName)
+				return
+			}
+
+			cache.Delete(cacheKey)
+		}
+
+		// Show a placeholder if the face or all of its markers have been deleted.
+		if f := entity.FindFace(faceId); f == nil {
+			log.Debugf("%s: face %s not found", faceThumb, clean.Log(faceId))
+			ThumbIcon(c, http.StatusNotFound, portraitIconSvg)
+			return
+		}
+
+		marker, err := query.FaceMarker(faceId)
+
+		if err != nil {
+			log.Debugf("%s: no valid marker for face %s", faceThumb, clean.Log(faceId))
+			ThumbIcon(c, http.StatusNotFound, portraitIconSvg)
+			return
+		}
+
+		fileHash, _ := crop.ParseThumb(marker.Thumb)
+		cropArea := crop.NewArea("face", marker.X, marker.Y, marker.W, marker.H).String()
+
+		fileName, err := crop.FromRequest(fileHash, cropArea, cropSize, ThumbPath(fileHash), format)
+
+		if err != nil {
+			log.Warnf("%s: %s", faceThumb, err)
+			ThumbIcon(c, http.StatusNotFound, brokenIconSvg)
+			return
+		} else if fileName == "" {
+			log.Errorf("%s: empty file name - you may have found a bug", faceThumb)
+			ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+			return
+		}
+
+		cache.SetDefault(cacheKey, ThumbCache{FileName: fileName})
+		log.Debugf("cached %s [%s]", cacheKey, time.Since(start))
+
+		AddCoverCacheHeader(c)
+		AddContentTypeHeader(c, thumb.FormatMimeType(fs.FileType(fileName)))
+		c.File(fileName)
+	}))
+}
+
+// FaceThumbCacheKey returns the cover cache key of a face thumbnail in the specified size and format.
+func FaceThumbCacheKey(faceId string, cropName crop.Name, format fs.Type) string {
+	return CacheKey(faceThumb, strings.ToUpper(faceId), fmt.Sprintf("%s:%s", cropName, format))
+}
+
+// RemoveFromFaceThumbCache removes the cached face thumbnails of the specified face ids,
+// e.g. after markers have been changed.
+func RemoveFromFaceThumbCache(faceIds ...string) {
+	cache := get.CoverCache()
+
+	for _, faceId := range faceIds {
+		if faceId == "" {
+			continue
+		}
+
+		for cropName := range crop.Sizes {
+			for _, format := range thumb.Formats() {
+				cache.Delete(FaceThumbCacheKey(faceId, cropName, format))
+			}
+		}
+	}
+}
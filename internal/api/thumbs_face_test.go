@@ -0,0 +1,77 @@
+package api
+
+import (
+	"image/color"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestGetFaceThumb(t *testing.T) {
+	faceId := entity.FaceFixtures.Get("actress-1").ID
+
+	t.Run("Ok", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetFaceThumb(router)
+
+		hash := "pcad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+		thumbName, err := thumb.Sizes[thumb.Fit720].FileName(hash, conf.ThumbCachePath())
+
+		if err != nil {
+			t.Fatal(err)
+		} else if err = imaging.Save(imaging.New(720, 480, color.White), thumbName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(filepath.Dir(thumbName))
+		defer RemoveFromFaceThumbCache(faceId)
+
+		r := PerformRequest(app, "GET", "/api/v1/t/face/"+faceId+"/"+conf.PreviewToken()+"/tile_160")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, fs.MimeTypeJPEG, r.Header().Get("Content-Type"))
+
+		_, cached := get.CoverCache().Get(FaceThumbCacheKey(faceId, crop.Tile160, fs.ImageJPEG))
+		assert.True(t, cached)
+
+		r = PerformRequest(app, "GET", "/api/v1/t/face/"+faceId+"/"+conf.PreviewToken()+"/tile_160")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("Deleted", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetFaceThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/face/XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX/"+conf.PreviewToken()+"/tile_160")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "image/svg+xml", r.Header().Get("Content-Type"))
+	})
+	t.Run("DeletedStrict", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetFaceThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/face/XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX/"+conf.PreviewToken()+"/tile_160?strict=true")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("InvalidSize", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetFaceThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/face/"+faceId+"/"+conf.PreviewToken()+"/fit_720?strict=true")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("InvalidToken", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetFaceThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/face/"+faceId+"/xxx/tile_160")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// PreviewFallback returns the image file from which thumbnails of a photo are created if the requested file
+// is not a JPEG or PNG. If the original of the primary file is missing, the next-best image files of the
+// photo are tried, see config.ThumbFileFallback.
+func PreviewFallback(photoUID string) (*entity.File, error) {
+	retries := get.Config().ThumbFileFallback()
+
+	if retries <= 0 {
+		return query.FileByPhotoUID(photoUID)
+	}
+
+	files, err := query.PreviewFilesByPhotoUID(photoUID, retries+1)
+
+	if err != nil {
+		return nil, err
+	} else if len(files) == 0 {
+		return nil, fmt.Errorf("no image file found for %s", clean.Log(photoUID))
+	}
+
+	for i := range files {
+		fileName := photoprism.FileName(files[i].FileRoot, files[i].FileName)
+
+		if thumb.IsRemote(fileName) {
+			return &files[i], nil
+		} else if _, err = fs.Resolve(fileName); err == nil {
+			return &files[i], nil
+		}
+
+		log.Debugf("thumb: %s is missing, trying next image file", clean.Log(files[i].FileName))
+	}
+
+	return nil, fmt.Errorf("originals of %d image files of %s are missing", len(files), clean.Log(photoUID))
+}
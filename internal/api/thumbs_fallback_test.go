@@ -0,0 +1,104 @@
+package api
+
+import (
+	"image/color"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// createFallbackOriginal creates the original of the second image file of a photo with multiple files.
+func createFallbackOriginal(t *testing.T) (fileName string) {
+	fileName = filepath.Join(get.Config().OriginalsPath(), "London", "bridge3.jpg")
+
+	if fs.FileExists(fileName) {
+		t.Skipf("%s already exists", fileName)
+	} else if err := os.MkdirAll(filepath.Dir(fileName), fs.ModeDir); err != nil {
+		t.Fatal(err)
+	} else if err = imaging.Save(imaging.New(1200, 800, color.White), fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+func TestPreviewFallback(t *testing.T) {
+	conf := get.Config()
+
+	t.Run("Disabled", func(t *testing.T) {
+		f, err := PreviewFallback("pt9jtdre2lvl0yh0")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "1990/04/bridge2.jpg", f.FileName)
+	})
+	t.Run("NextFile", func(t *testing.T) {
+		conf.Options().ThumbFileFallback = 3
+		defer func() { conf.Options().ThumbFileFallback = 0 }()
+
+		fileName := createFallbackOriginal(t)
+		defer os.Remove(fileName)
+
+		f, err := PreviewFallback("pt9jtdre2lvl0yh0")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "London/bridge3.jpg", f.FileName)
+	})
+	t.Run("AllMissing", func(t *testing.T) {
+		conf.Options().ThumbFileFallback = 3
+		defer func() { conf.Options().ThumbFileFallback = 0 }()
+
+		_, err := PreviewFallback("pt9jtdre2lvl0yh0")
+
+		assert.Error(t, err)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		conf.Options().ThumbFileFallback = 3
+		defer func() { conf.Options().ThumbFileFallback = 0 }()
+
+		_, err := PreviewFallback("pt9jtdre2lvl0xxx")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestGetThumb_Fallback(t *testing.T) {
+	t.Run("MultipleFiles", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+
+		conf.Options().ThumbFileFallback = 3
+		defer func() { conf.Options().ThumbFileFallback = 0 }()
+
+		fileName := createFallbackOriginal(t)
+		defer os.Remove(fileName)
+		defer RemoveFromThumbCache("pcad9168fa6acc5c5ba965adf6ec465ca42fd819")
+
+		// The video has no JPEG, and the original of the primary JPEG is missing.
+		r := PerformRequest(app, "GET", "/api/v1/t/pcad9168fa6acc5c5ba965adf6ec465ca42fd819/"+conf.PreviewToken()+"/tile_224?strict=true")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, fs.MimeTypeJPEG, r.Header().Get("Content-Type"))
+	})
+	t.Run("AllMissing", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+
+		conf.Options().ThumbFileFallback = 3
+		defer func() { conf.Options().ThumbFileFallback = 0 }()
+
+		r := PerformRequest(app, "GET", "/api/v1/t/pcad9168fa6acc5c5ba965adf6ec465ca42fd819/"+conf.PreviewToken()+"/tile_224?strict=true")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"bytes"
+	"image/jpeg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ThumbFilter returns the resample filter passed as "filter" query parameter, e.g. "lanczos", "bilinear",
+// or "box", see thumb.ParseResampleFilter. This is a debug aid to compare filters without changing the config.
+func ThumbFilter(c *gin.Context) (thumb.ResampleFilter, bool) {
+	if c.Query("filter") == "" {
+		return "", false
+	}
+
+	return thumb.ParseResampleFilter(c.Query("filter"))
+}
+
+// ThumbFilterAllowed checks if the request was made by an authenticated admin, as only they may override the filter.
+func ThumbFilterAllowed(c *gin.Context) bool {
+	s := Auth(c, acl.ResourceConfig, acl.ActionManage)
+
+	return !s.Invalid() && !get.Config().Public()
+}
+
+// FilterThumb resamples the original with the specified filter and sends the result, which is not saved,
+// so that it cannot end up in the shared thumbnail cache. Adjustments such as auto levels are not applied.
+func FilterThumb(c *gin.Context, fileName string, f *entity.File, size thumb.Size, filter thumb.ResampleFilter) {
+	img, err := thumb.Open(fileName, f.FileOrientation)
+
+	if err != nil {
+		log.Errorf("thumb: %s in %s (filter)", err, clean.Log(f.FileName))
+		ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+		return
+	}
+
+	img = thumb.ResampleWith(thumb.Straighten(img, float64(f.FileAngle)), size.Width, size.Height, filter, size.Options...)
+
+	var buf bytes.Buffer
+
+	if err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: int(thumb.SizeQuality(size.Width, size.Height))}); err != nil {
+		log.Errorf("thumb: %s in %s (filter)", err, clean.Log(f.FileName))
+		ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Resample-Filter", string(filter))
+	c.Data(http.StatusOK, fs.MimeTypeJPEG, buf.Bytes())
+}
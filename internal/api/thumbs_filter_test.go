@@ -0,0 +1,79 @@
+package api
+
+import (
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestThumbFilter(t *testing.T) {
+	newContext := func(query string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/hash/token/tile_500"+query, nil)
+		return c
+	}
+
+	t.Run("None", func(t *testing.T) {
+		_, ok := ThumbFilter(newContext(""))
+		assert.False(t, ok)
+	})
+	t.Run("Bilinear", func(t *testing.T) {
+		filter, ok := ThumbFilter(newContext("?filter=bilinear"))
+		assert.True(t, ok)
+		assert.Equal(t, thumb.ResampleLinear, filter)
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		_, ok := ThumbFilter(newContext("?filter=foo"))
+		assert.False(t, ok)
+	})
+}
+
+func TestGetThumb_Filter(t *testing.T) {
+	t.Run("Forbidden", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumb(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"/tile_500?filter=box&strict=true")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("Admin", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetThumb(router)
+
+		// Filtered thumbnails are created from the original, which is not part of the test storage.
+		fileName := filepath.Join(conf.OriginalsPath(), "2016", "12", "Photo11.jpg")
+
+		if err := os.MkdirAll(filepath.Dir(fileName), fs.ModeDir); err != nil {
+			t.Fatal(err)
+		} else if err = imaging.Save(imaging.New(640, 1136, color.White), fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(fileName)
+
+		sess := AuthenticateAdmin(app, router)
+
+		// The config preview token is not registered in this auth mode, so the token of the session is used.
+		entity.PreviewToken.Set("admin1preview", sess)
+		defer entity.PreviewToken.Unset("admin1preview")
+
+		r := AuthenticatedRequest(app, "GET", "/api/v1/t/pcad9a68fa6acc5c5ba965adf6ec465ca42fd924/admin1preview/tile_500?filter=box", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "box", r.Header().Get("X-Resample-Filter"))
+		assert.Equal(t, "no-store", r.Header().Get("Cache-Control"))
+		assert.Equal(t, "image/jpeg", r.Header().Get("Content-Type"))
+	})
+}
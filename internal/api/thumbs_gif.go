@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// GifThumb returns a thumbnail of a GIF in the requested mode, i.e. created from the first or middle frame,
+// or animated, see thumb.GifMode. It returns false if the file is not a GIF, in which case the mode
+// does not apply and nothing was sent.
+func GifThumb(c *gin.Context, fileHash string, size thumb.Size, mode thumb.GifMode) bool {
+	f, err := query.FileByHash(fileHash)
+
+	if err != nil || !fs.ImageGIF.Equal(f.FileType) {
+		return false
+	} else if f.FileError != "" {
+		ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+		return true
+	}
+
+	conf := get.Config()
+	fileName := photoprism.FileName(f.FileRoot, f.FileName)
+	thumbPath := thumb.Path(conf.ThumbCachePath(), fileName)
+	opts := append(append(make([]thumb.ResampleOption, 0, len(size.Options)+1), size.Options...), mode.Option())
+
+	thumbName, err := thumb.WithTimeout(f.FileHash+":"+size.Name.String()+":"+string(mode), conf.ThumbTimeout(), func() (string, error) {
+		return thumb.FromFile(fileName, f.FileHash, thumbPath, size.Width, size.Height, f.FileOrientation, opts...)
+	})
+
+	if errors.Is(err, thumb.ErrTimeout) {
+		log.Warnf("thumb: creating %s for %s timed out after %s (%s gif)", size.Name, clean.Log(f.FileName), conf.ThumbTimeout(), mode)
+		ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+
+		// Flag the file so that subsequent requests return the broken icon right away.
+		logError("thumb", f.Update("FileError", err.Error()))
+		return true
+	} else if err != nil {
+		log.Errorf("thumb: %s in %s (%s gif)", err, clean.Log(f.FileName), mode)
+		ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+		return true
+	}
+
+	AddImmutableCacheHeader(c)
+	c.File(thumbName)
+
+	return true
+}
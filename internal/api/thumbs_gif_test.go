@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestGifThumb(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		assert.False(t, GifThumb(c, "0000000000000000000000000000000000000000", thumb.Sizes[thumb.Tile224], thumb.GifAnimated))
+		assert.False(t, c.Writer.Written())
+	})
+	t.Run("NoGif", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		assert.False(t, GifThumb(c, "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818", thumb.Sizes[thumb.Tile224], thumb.GifMiddle))
+		assert.False(t, c.Writer.Written())
+	})
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/server/header"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// ShareHeaders parses the custom response headers of a share link, one "Name: value" pair per line, and returns
+// them by canonical name. Only headers in header.ShareHeaders are allowed, see entity.Link.SetHeaders.
+func ShareHeaders(s string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(line, ":")
+
+		if !found {
+			return result, fmt.Errorf("invalid header %s", clean.Log(line))
+		}
+
+		value = strings.TrimSpace(value)
+
+		if name, ok := header.ShareHeader(name, value); !ok {
+			return result, fmt.Errorf("header %s not allowed", clean.Log(name))
+		} else {
+			result[name] = value
+		}
+	}
+
+	return result, nil
+}
+
+// AddShareHeaders adds the custom response headers of the first share link of the visitor session that
+// includes the photo of the file, e.g. so that shared thumbnails can be embedded in other sites, see ShareLinks.
+func AddShareHeaders(c *gin.Context, fileHash string) {
+	var f *entity.File
+	var err error
+
+	for _, link := range ShareLinks(c) {
+		if link.Headers == "" {
+			continue
+		}
+
+		// The file is only queried if a link has custom headers.
+		if f == nil {
+			if f, err = query.FileByHash(fileHash); err != nil {
+				return
+			}
+		}
+
+		if !LinkIncludes(link, f.PhotoUID) {
+			continue
+		}
+
+		for name, value := range link.ResponseHeaders() {
+			// Stored headers are validated again in case the allowlist changed.
+			if name, ok := header.ShareHeader(name, value); ok {
+				c.Header(name, value)
+			}
+		}
+
+		return
+	}
+}
+
+// LinkIncludes checks if the share link includes the photo, either directly or as an album entry.
+// The result is cached for ShareLinksTTL.
+func LinkIncludes(link entity.Link, photoUid string) bool {
+	if photoUid == "" {
+		return false
+	} else if link.ShareUID == photoUid {
+		return true
+	}
+
+	cache := get.ThumbCache()
+	cacheKey := CacheKey(shareLinks, link.ShareUID, photoUid)
+
+	if found, ok := cache.Get(cacheKey); ok {
+		return found.(bool)
+	}
+
+	result := query.AlbumHasPhoto(link.ShareUID, photoUid)
+
+	cache.Set(cacheKey, result, ShareLinksTTL)
+
+	return result
+}
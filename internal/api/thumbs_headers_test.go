@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+func TestShareHeaders(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		headers, err := ShareHeaders("access-control-allow-origin: https://example.com\n\n Referrer-Policy:no-referrer ")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"Access-Control-Allow-Origin": "https://example.com",
+			"Referrer-Policy":             "no-referrer",
+		}, headers)
+	})
+	t.Run("NotAllowed", func(t *testing.T) {
+		_, err := ShareHeaders("Set-Cookie: foo=bar")
+		assert.Error(t, err)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ShareHeaders("Referrer-Policy")
+		assert.Error(t, err)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		headers, err := ShareHeaders("")
+		assert.NoError(t, err)
+		assert.Empty(t, headers)
+	})
+}
+
+func TestAddShareHeaders(t *testing.T) {
+	app, router, _ := NewApiTest()
+
+	router.GET("/share-headers/:token", func(c *gin.Context) {
+		AddShareHeaders(c, "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818")
+		c.Status(http.StatusOK)
+	})
+
+	entity.PreviewToken.Set("visitor5preview", entity.SessionFixtures.Get("visitor").ID)
+	defer entity.PreviewToken.Unset("visitor5preview")
+
+	t.Run("NoVisitor", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/share-headers/unknown5preview?s=1jxf3jfn2k")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "", r.Header().Get("Access-Control-Allow-Origin"))
+	})
+	t.Run("NoHeaders", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/share-headers/visitor5preview")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "", r.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestLinkIncludes(t *testing.T) {
+	// Album fixture that is not modified by other tests.
+	link := entity.Link{ShareUID: "at1lxuqipotaab24"}
+
+	assert.True(t, LinkIncludes(link, "pt9jtdre2lvl0yh9"))
+	assert.False(t, LinkIncludes(link, "pt9jtdre2lvl0y11"))
+	assert.False(t, LinkIncludes(link, ""))
+	assert.True(t, LinkIncludes(entity.Link{ShareUID: "pt9jtdre2lvl0y11"}, "pt9jtdre2lvl0y11"))
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+)
+
+// HeroThumb returns a wide banner crop of the whole image, e.g. for album headers, which keeps
+// the faces and the focus point of the file visible if possible, see crop.FromHero.
+func HeroThumb(c *gin.Context, fileHash, thumbPath string, size crop.Size) {
+	var hints crop.Areas
+
+	if f, err := query.FileByHash(fileHash); err == nil {
+		hints = CropHints(f)
+	}
+
+	fileName, err := crop.FromHero(fileHash, thumbPath, size, hints)
+
+	if err != nil {
+		log.Warnf("thumb: %s (hero)", err)
+		ThumbIcon(c, http.StatusNotFound, brokenIconSvg)
+		return
+	}
+
+	// Faces may be added or moved, in which case the file name changes.
+	AddImmutableCacheHeader(c)
+	c.File(fileName)
+}
+
+// HeroFaceAreas returns the areas of valid faces in the file, which should not be cut in banner crops.
+func HeroFaceAreas(f *entity.File) (areas crop.Areas) {
+	if f == nil {
+		return areas
+	}
+
+	for _, m := range *f.Markers() {
+		if m.ValidFace() {
+			areas = append(areas, crop.NewArea(m.MarkerName, m.X, m.Y, m.W, m.H))
+		}
+	}
+
+	return areas
+}
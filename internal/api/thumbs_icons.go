@@ -0,0 +1,60 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/media"
+)
+
+// errorIcons maps the names of placeholder icons that can be configured for files that cannot be displayed
+// to their SVG data, see config.ThumbErrorIcons.
+var errorIcons = map[string][]byte{
+	"broken":       brokenIconSvg,
+	"broken-video": brokenVideoIconSvg,
+	"photo":        photoIconSvg,
+	"video":        videoIconSvg,
+	"raw":          rawIconSvg,
+	"file":         fileIconSvg,
+}
+
+// FileMediaType returns the media type of a file, based on its name if it is not indexed.
+func FileMediaType(f *entity.File) media.Type {
+	if f == nil {
+		return media.Unknown
+	} else if f.MediaType != "" {
+		return media.Type(f.MediaType)
+	}
+
+	return media.FromName(f.FileName)
+}
+
+// ErrorIcon returns the placeholder icon for files of the media type that are corrupt or missing,
+// e.g. a broken video icon for videos, or the broken image icon by default.
+func ErrorIcon(mediaType media.Type) []byte {
+	if name, ok := get.Config().ThumbErrorIcons()[mediaType]; !ok {
+		return brokenIconSvg
+	} else if icon, ok := errorIcons[name]; ok {
+		return icon
+	}
+
+	return brokenIconSvg
+}
+
+// IconData sends a placeholder icon with the specified status code in the configured format, see thumb.IconFormat.
+// Icons are rendered as PNG if SVG images are blocked by a strict Content-Security-Policy, or sent as SVG if this fails.
+func IconData(c *gin.Context, status int, icon []byte) {
+	if thumb.IconFormat == fs.ImagePNG {
+		if data, err := thumb.IconPng(icon); err != nil {
+			log.Warnf("thumb: %s (render icon)", err)
+		} else {
+			c.Data(status, fs.MimeTypePNG, data)
+			return
+		}
+	}
+
+	c.Data(status, fs.MimeTypeSVG, icon)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/media"
+)
+
+func TestFileMediaType(t *testing.T) {
+	assert.Equal(t, media.Unknown, FileMediaType(nil))
+	assert.Equal(t, media.Video, FileMediaType(&entity.File{MediaType: "video", FileName: "foo.jpg"}))
+	assert.Equal(t, media.Video, FileMediaType(&entity.File{FileName: "foo.mp4"}))
+	assert.Equal(t, media.Image, FileMediaType(&entity.File{FileName: "foo.jpg"}))
+}
+
+func TestErrorIcon(t *testing.T) {
+	conf := get.Config()
+	icons := conf.Options().ThumbErrorIcons
+
+	conf.Options().ThumbErrorIcons = "video=broken-video,raw=unknown"
+
+	assert.Equal(t, brokenVideoIconSvg, ErrorIcon(media.Video))
+	assert.Equal(t, brokenIconSvg, ErrorIcon(media.Image))
+	assert.Equal(t, brokenIconSvg, ErrorIcon(media.Raw))
+
+	conf.Options().ThumbErrorIcons = icons
+}
+
+func TestIconData(t *testing.T) {
+	t.Run("Svg", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		IconData(c, http.StatusNotFound, brokenIconSvg)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, fs.MimeTypeSVG, w.Header().Get("Content-Type"))
+		assert.Equal(t, brokenIconSvg, w.Body.Bytes())
+	})
+	t.Run("Png", func(t *testing.T) {
+		thumb.IconFormat = fs.ImagePNG
+		defer func() { thumb.IconFormat = fs.VectorSVG }()
+
+		for _, icon := range [][]byte{brokenIconSvg, brokenVideoIconSvg, photoIconSvg, videoIconSvg, rawIconSvg, fileIconSvg,
+			medicalIconSvg, documentIconSvg, archiveIconSvg, folderIconSvg, labelIconSvg, uncachedIconSvg} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			IconData(c, http.StatusOK, icon)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, fs.MimeTypePNG, w.Header().Get("Content-Type"))
+		}
+	})
+	t.Run("Unsupported", func(t *testing.T) {
+		thumb.IconFormat = fs.ImagePNG
+		defer func() { thumb.IconFormat = fs.VectorSVG }()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		IconData(c, http.StatusOK, userIconSvg)
+
+		assert.Equal(t, fs.MimeTypeSVG, w.Header().Get("Content-Type"))
+	})
+}
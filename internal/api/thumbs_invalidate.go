@@ -0,0 +1,120 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// ThumbInvalidateResult contains the number of files whose thumbnails were invalidated.
+type ThumbInvalidateResult struct {
+	Label      string `json:"Label,omitempty"`
+	Keyword    string `json:"Keyword,omitempty"`
+	Files      int    `json:"Files"`
+	Removed    int    `json:"Removed"`
+	Regenerate bool   `json:"Regenerate"`
+}
+
+// InvalidateThumbs deletes the cached thumbnails and crops of all photos with the specified label or keyword,
+// e.g. after a batch of photos was mislabeled, and creates the default thumbnails again in the background
+// unless "regenerate=false" is passed. Other crops are created again when they are requested.
+//
+// POST /api/v1/thumbs/invalidate?label=:slug
+// POST /api/v1/thumbs/invalidate?keyword=:keyword
+//
+// Parameters:
+//
+//	label: string label slug or UID
+//	keyword: string keyword, e.g. a tag from the metadata
+//	regenerate: bool optional, false to only remove the thumbnails
+func InvalidateThumbs(router *gin.RouterGroup) {
+	router.POST("/thumbs/invalidate", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionManage)
+		conf := get.Config()
+
+		// Abort if permission was not granted.
+		if s.Invalid() || conf.Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		result := ThumbInvalidateResult{
+			Label:      clean.Token(c.Query("label")),
+			Keyword:    clean.Name(c.Query("keyword")),
+			Regenerate: c.Query("regenerate") == "" || txt.Bool(c.Query("regenerate")),
+		}
+
+		var files entity.Files
+		var err error
+
+		if result.Label != "" {
+			files, err = query.FilesByLabel(result.Label)
+		} else if result.Keyword != "" {
+			files, err = query.FilesByKeyword(result.Keyword)
+		} else {
+			AbortBadRequest(c)
+			return
+		}
+
+		if err != nil {
+			log.Errorf("thumbs: %s (invalidate)", err)
+			AbortUnexpected(c)
+			return
+		}
+
+		result.Files = len(files)
+
+		for _, f := range files {
+			thumbPath := thumb.Path(conf.ThumbCachePath(), photoprism.FileName(f.FileRoot, f.FileName))
+
+			if removed, err := thumb.Remove(f.FileHash, thumbPath); err != nil {
+				log.Warnf("thumbs: %s in %s (invalidate)", err, clean.Log(f.FileName))
+			} else {
+				result.Removed += removed
+			}
+
+			RemoveFromThumbCache(f.FileHash)
+		}
+
+		log.Infof("thumbs: removed %d thumbnails of %d files", result.Removed, result.Files)
+
+		if result.Regenerate && len(files) > 0 {
+			go regenerateThumbs(files, conf.ThumbCachePath())
+		}
+
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// regenerateThumbs creates the default thumbnails of the files again.
+func regenerateThumbs(files entity.Files, thumbPath string) {
+	created := 0
+
+	for _, f := range files {
+		mf, err := photoprism.NewMediaFile(photoprism.FileName(f.FileRoot, f.FileName))
+
+		if err != nil {
+			log.Debugf("thumbs: %s (regenerate)", err)
+			continue
+		}
+
+		mf.SetAngle(float64(f.FileAngle))
+
+		if err = mf.CreateThumbnails(thumbPath, false); err != nil {
+			log.Warnf("thumbs: %s in %s (regenerate)", err, clean.Log(f.FileName))
+		} else {
+			created++
+		}
+	}
+
+	log.Infof("thumbs: regenerated thumbnails of %d files", created)
+}
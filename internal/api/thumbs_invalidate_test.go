@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+func TestInvalidateThumbs(t *testing.T) {
+	t.Run("Unauthorized", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		InvalidateThumbs(router)
+		r := PerformRequest(app, "POST", "/api/v1/thumbs/invalidate?label=landscape")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("MissingFilter", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		InvalidateThumbs(router)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "POST", "/api/v1/thumbs/invalidate", sess)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Label", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		InvalidateThumbs(router)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "POST", "/api/v1/thumbs/invalidate?label=landscape&regenerate=false", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "landscape", gjson.Get(r.Body.String(), "Label").String())
+		assert.Greater(t, gjson.Get(r.Body.String(), "Files").Int(), int64(0))
+		assert.False(t, gjson.Get(r.Body.String(), "Regenerate").Bool())
+	})
+	t.Run("UnknownKeyword", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		InvalidateThumbs(router)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "POST", "/api/v1/thumbs/invalidate?keyword=xxx-unknown", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, int64(0), gjson.Get(r.Body.String(), "Files").Int())
+	})
+}
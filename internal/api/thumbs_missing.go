@@ -0,0 +1,139 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/search"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// ThumbsMissingLimit is the maximum number of search results checked for missing thumbnails per request.
+const ThumbsMissingLimit = 1000
+
+// ThumbsMissingResult represents a photo with thumbnail sizes that have not been created yet.
+type ThumbsMissingResult struct {
+	UID     string   `json:"UID"`
+	Hash    string   `json:"Hash"`
+	Missing []string `json:"Missing"`
+}
+
+// MissingSizes returns the names of the sizes for which no thumbnail of the file exists.
+func MissingSizes(fileName, fileHash, thumbPath string, sizes []thumb.Name) (missing []string) {
+	for _, name := range sizes {
+		size := thumb.Sizes[name]
+
+		// Thumbnails of GIF sources are cached by mode, see thumb.GifOptions.
+		if _, err := thumb.ResolvedName(fileHash, thumbPath, size.Width, size.Height, thumb.GifOptions(fileName, size.Options...)...); err != nil {
+			missing = append(missing, name.String())
+		}
+	}
+
+	return missing
+}
+
+// ThumbsMissingSizes returns the sizes to check from a comma-separated list of size names, or all sizes
+// that are pre-cached if the list is empty. It returns false if the list contains unknown sizes.
+func ThumbsMissingSizes(s string) (sizes []thumb.Name, ok bool) {
+	if s = strings.TrimSpace(s); s == "" {
+		for _, name := range thumb.Names {
+			if !thumb.Sizes[name].Uncached() {
+				sizes = append(sizes, name)
+			}
+		}
+
+		return sizes, true
+	}
+
+	for _, v := range strings.Split(s, ",") {
+		name := thumb.Name(strings.ToLower(strings.TrimSpace(v)))
+
+		if _, exists := thumb.Sizes[name]; !exists {
+			return nil, false
+		}
+
+		sizes = append(sizes, name)
+	}
+
+	return sizes, true
+}
+
+// GetThumbsMissing returns the photos matching the search, e.g. in an album, for which thumbnails have not
+// been created yet, together with the missing sizes, so that clients can request them in a targeted way.
+// Photos that have all sizes are omitted. The results are paginated like searches, see form.SearchPhotos.
+//
+// GET /api/v1/thumbs/missing
+//
+// Parameters:
+//
+//	count: int maximum number of search results to check, see ThumbsMissingLimit
+//	offset: int search result offset
+//	sizes: string optional comma-separated list of sizes to check, all pre-cached sizes by default
+func GetThumbsMissing(router *gin.RouterGroup) {
+	router.GET("/thumbs/missing", func(c *gin.Context) {
+		s := AuthAny(c, acl.ResourcePhotos, acl.Permissions{acl.ActionSearch, acl.ActionView, acl.AccessShared})
+
+		// Abort if permission was not granted.
+		if s.Abort(c) {
+			return
+		}
+
+		var f form.SearchPhotos
+
+		// Abort if request params are invalid.
+		if err := c.MustBindWith(&f, binding.Form); err != nil {
+			event.AuditWarn([]string{ClientIP(c), "session %s", string(acl.ResourcePhotos), "form invalid", "%s"}, s.RefID, err)
+			AbortBadRequest(c)
+			return
+		}
+
+		sizes, ok := ThumbsMissingSizes(c.Query("sizes"))
+
+		if !ok {
+			AbortBadRequest(c)
+			return
+		}
+
+		if f.Count > ThumbsMissingLimit {
+			f.Count = ThumbsMissingLimit
+		}
+
+		photos, count, err := search.UserPhotos(f, s)
+
+		if err != nil {
+			event.AuditWarn([]string{ClientIP(c), "session %s", string(acl.ResourcePhotos), "search", "%s"}, s.RefID, err)
+			AbortBadRequest(c)
+			return
+		}
+
+		cachePath := get.Config().ThumbCachePath()
+		results := make([]ThumbsMissingResult, 0, len(photos))
+
+		for _, p := range photos {
+			if p.FileHash == "" {
+				continue
+			}
+
+			fileName := photoprism.FileName(p.FileRoot, p.FileName)
+
+			if missing := MissingSizes(fileName, p.FileHash, thumb.Path(cachePath, fileName), sizes); len(missing) > 0 {
+				results = append(results, ThumbsMissingResult{UID: p.PhotoUID, Hash: p.FileHash, Missing: missing})
+			}
+		}
+
+		// Add response headers.
+		AddCountHeader(c, count)
+		AddLimitHeader(c, f.Count)
+		AddOffsetHeader(c, f.Offset)
+
+		c.JSON(http.StatusOK, results)
+	})
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestThumbsMissingSizes(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		sizes, ok := ThumbsMissingSizes("")
+		assert.True(t, ok)
+		assert.Contains(t, sizes, thumb.Tile500)
+		assert.NotContains(t, sizes, thumb.Fit7680)
+	})
+	t.Run("List", func(t *testing.T) {
+		sizes, ok := ThumbsMissingSizes("tile_50, FIT_7680")
+		assert.True(t, ok)
+		assert.Equal(t, []thumb.Name{thumb.Tile50, thumb.Fit7680}, sizes)
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		_, ok := ThumbsMissingSizes("tile_50,fit_1")
+		assert.False(t, ok)
+	})
+}
+
+func TestMissingSizes(t *testing.T) {
+	missing := MissingSizes("example.jpg", "0000000000000000000000000000000000000000", t.TempDir(), []thumb.Name{thumb.Tile50, thumb.Tile500})
+	assert.Equal(t, []string{"tile_50", "tile_500"}, missing)
+}
+
+func TestGetThumbsMissing(t *testing.T) {
+	t.Run("Ok", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetThumbsMissing(router)
+		r := PerformRequest(app, "GET", "/api/v1/thumbs/missing?count=10&sizes=fit_7680")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.LessOrEqual(t, int64(1), gjson.Get(r.Body.String(), "#").Int())
+		assert.Equal(t, "fit_7680", gjson.Get(r.Body.String(), "0.Missing.0").String())
+		assert.Equal(t, "10", r.Header().Get("X-Limit"))
+	})
+	t.Run("UnknownSize", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetThumbsMissing(router)
+		r := PerformRequest(app, "GET", "/api/v1/thumbs/missing?count=10&sizes=fit_1")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("MissingCount", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetThumbsMissing(router)
+		r := PerformRequest(app, "GET", "/api/v1/thumbs/missing")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+}
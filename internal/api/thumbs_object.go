@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// ObjectCacheKey returns the cache key of a thumbnail cropped to the detected object with the specified label.
+func ObjectCacheKey(fileHash, label string, sizeName thumb.Name) string {
+	return CacheKey("thumb-objects", fileHash, label+":"+string(sizeName))
+}
+
+// ObjectMarker returns the valid marker of a detected object with the specified label, e.g. "dog".
+// If the object was detected more than once, the marker with the highest score is returned.
+func ObjectMarker(f *entity.File, label string) (result entity.Marker, found bool) {
+	if f == nil || label == "" {
+		return result, false
+	}
+
+	for _, m := range *f.Markers() {
+		if m.MarkerType != entity.MarkerLabel || m.MarkerInvalid || m.W <= 0 || m.H <= 0 {
+			continue
+		} else if txt.Slug(m.MarkerName) != label {
+			continue
+		} else if !found || m.Score > result.Score {
+			result, found = m, true
+		}
+	}
+
+	return result, found
+}
+
+// ObjectThumb returns a thumbnail cropped to the box of a detected object with the specified label,
+// e.g. to show the dog rather than the whole scene. It returns false if the size cannot be cropped
+// or no such object was detected, in which case nothing was sent and the regular thumbnail should
+// be returned instead.
+func ObjectThumb(c *gin.Context, fileHash, thumbPath string, sizeName thumb.Name, label string) bool {
+	label = txt.Slug(label)
+	cropSize, ok := crop.Sizes[crop.Name(sizeName)]
+
+	if label == "" || !ok {
+		return false
+	}
+
+	cache := get.ThumbCache()
+	cacheKey := ObjectCacheKey(fileHash, label, sizeName)
+
+	var fileName string
+
+	if cacheData, hit := cache.Get(cacheKey); hit && fs.FileExists(cacheData.(ThumbCache).FileName) {
+		fileName = cacheData.(ThumbCache).FileName
+	} else if f, err := query.FileByHash(fileHash); err != nil {
+		return false
+	} else if m, found := ObjectMarker(f, label); !found {
+		return false
+	} else if fileName, err = crop.FromRequest(fileHash, crop.NewArea(label, m.X, m.Y, m.W, m.H).String(), cropSize, thumbPath, fs.ImageJPEG); err != nil {
+		log.Warnf("thumb: %s (object %s)", err, clean.Log(label))
+		ThumbIcon(c, http.StatusNotFound, brokenIconSvg)
+		return true
+	} else {
+		SetThumbCache(cacheKey, fileHash, sizeName, ThumbCache{FileName: fileName, ShareName: f.ShareBase(0)})
+	}
+
+	// Markers may be moved, in which case the crop area and file name change.
+	AddImmutableCacheHeader(c)
+	AddContentTypeHeader(c, fs.MimeTypeJPEG)
+	c.File(fileName)
+
+	return true
+}
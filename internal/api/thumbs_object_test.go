@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestObjectCacheKey(t *testing.T) {
+	assert.Equal(t, "thumb-objects:ca7d4e9c:dog:tile_500", ObjectCacheKey("ca7d4e9c", "dog", thumb.Tile500))
+}
+
+func TestObjectMarker(t *testing.T) {
+	t.Run("Nil", func(t *testing.T) {
+		_, found := ObjectMarker(nil, "dog")
+		assert.False(t, found)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, found := ObjectMarker(entity.FileFixtures.Pointer("bridge.jpg"), "dog")
+		assert.False(t, found)
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		m, found := ObjectMarker(entity.FileFixtures.Pointer("bridge.jpg"), "unknown")
+		assert.True(t, found)
+		assert.Equal(t, "mt9k3pw1wowuy3c3", m.MarkerUID)
+	})
+	t.Run("EmptyBox", func(t *testing.T) {
+		_, found := ObjectMarker(entity.FileFixtures.Pointer("bridge.jpg"), "center")
+		assert.False(t, found)
+	})
+}
+
+func TestObjectThumb(t *testing.T) {
+	t.Run("NotCropped", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		assert.False(t, ObjectThumb(c, "pcad9168fa6acc5c5c2965ddf6ec465ca42fd818", "", thumb.Fit1280, "unknown"))
+		assert.False(t, c.Writer.Written())
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		assert.False(t, ObjectThumb(c, "pcad9168fa6acc5c5c2965ddf6ec465ca42fd818", "", thumb.Tile500, "dog"))
+		assert.False(t, c.Writer.Written())
+	})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// GetThumbPins returns the list of pinned thumbnails, which never expire from the cache and are not purged.
+//
+// GET /api/v1/thumbs/pins
+func GetThumbPins(router *gin.RouterGroup) {
+	router.GET("/thumbs/pins", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionView)
+
+		// Abort if permission was not granted.
+		if s.Invalid() || get.Config().Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		c.JSON(http.StatusOK, thumb.PinList())
+	})
+}
+
+// PinThumb pins the thumbnail with the specified sha1 file hash and size, e.g. an album cover.
+//
+// POST /api/v1/thumbs/pins/:hash/:size
+func PinThumb(router *gin.RouterGroup) {
+	router.POST("/thumbs/pins/:hash/:size", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionUpdate)
+
+		// Abort if permission was not granted.
+		if s.Invalid() || get.Config().Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		fileHash, sizeName, ok := thumbPinParams(c)
+
+		if !ok {
+			AbortBadRequest(c)
+			return
+		}
+
+		if err := thumb.AddPin(fileHash, sizeName); err != nil {
+			log.Errorf("thumbs: %s", err)
+			AbortSaveFailed(c)
+			return
+		}
+
+		UpdateThumbCachePin(fileHash, sizeName)
+
+		c.JSON(http.StatusOK, thumb.PinList())
+	})
+}
+
+// UnpinThumb unpins the thumbnail with the specified sha1 file hash and size.
+//
+// DELETE /api/v1/thumbs/pins/:hash/:size
+func UnpinThumb(router *gin.RouterGroup) {
+	router.DELETE("/thumbs/pins/:hash/:size", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionUpdate)
+
+		// Abort if permission was not granted.
+		if s.Invalid() || get.Config().Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		fileHash, sizeName, ok := thumbPinParams(c)
+
+		if !ok {
+			AbortBadRequest(c)
+			return
+		}
+
+		if found, err := thumb.RemovePin(fileHash, sizeName); err != nil {
+			log.Errorf("thumbs: %s", err)
+			AbortSaveFailed(c)
+			return
+		} else if !found {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		UpdateThumbCachePin(fileHash, sizeName)
+
+		c.JSON(http.StatusOK, thumb.PinList())
+	})
+}
+
+// thumbPinParams returns the sha1 file hash and thumbnail size from the request parameters.
+func thumbPinParams(c *gin.Context) (fileHash string, sizeName thumb.Name, ok bool) {
+	hashType, fileHash := fs.ParseHash(clean.Token(c.Param("hash")))
+	sizeName = thumb.Name(clean.Token(c.Param("size")))
+
+	if hashType != fs.HashSHA1 || len(fileHash) < 4 {
+		return "", "", false
+	} else if _, ok = thumb.Sizes[sizeName]; !ok {
+		return "", "", false
+	}
+
+	return fileHash, sizeName, true
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestGetThumbPins(t *testing.T) {
+	t.Run("Unauthorized", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetThumbPins(router)
+		r := PerformRequest(app, "GET", "/api/v1/thumbs/pins")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+}
+
+func TestPinThumb(t *testing.T) {
+	hash := "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		PinThumb(router)
+		r := PerformRequest(app, "POST", "/api/v1/thumbs/pins/"+hash+"/tile_500")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("PinAndUnpin", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetThumbPins(router)
+		PinThumb(router)
+		UnpinThumb(router)
+
+		cacheKey := ThumbCacheKey(hash, thumb.Tile500, "")
+		get.ThumbCache().SetDefault(cacheKey, ThumbCache{FileName: "tile_500.jpg"})
+		defer get.ThumbCache().Delete(cacheKey)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "POST", "/api/v1/thumbs/pins/"+hash+"/tile_500", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.True(t, thumb.Pinned(hash, thumb.Tile500))
+
+		// Pinned thumbnails never expire from the memory cache.
+		if _, expires, ok := get.ThumbCache().GetWithExpiration(cacheKey); !ok {
+			t.Fatal("expected cache entry")
+		} else {
+			assert.True(t, expires.IsZero())
+		}
+
+		r = AuthenticatedRequest(app, "GET", "/api/v1/thumbs/pins", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, hash, gjson.Get(r.Body.String(), "0.hash").String())
+		assert.Equal(t, "tile_500", gjson.Get(r.Body.String(), "0.size").String())
+
+		r = AuthenticatedRequest(app, "DELETE", "/api/v1/thumbs/pins/"+hash+"/tile_500", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.False(t, thumb.Pinned(hash, thumb.Tile500))
+
+		if _, expires, ok := get.ThumbCache().GetWithExpiration(cacheKey); !ok {
+			t.Fatal("expected cache entry")
+		} else {
+			assert.False(t, expires.IsZero())
+		}
+
+		r = AuthenticatedRequest(app, "DELETE", "/api/v1/thumbs/pins/"+hash+"/tile_500", sess)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("InvalidSize", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		PinThumb(router)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "POST", "/api/v1/thumbs/pins/"+hash+"/xxx", sess)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+}
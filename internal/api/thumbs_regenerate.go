@@ -0,0 +1,125 @@
+package api
+
+import (
+	"image"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ThumbRegenerateResult contains diagnostics for a regenerated thumbnail.
+type ThumbRegenerateResult struct {
+	Hash     string  `json:"Hash"`
+	Size     string  `json:"Size"`
+	Width    int     `json:"Width"`
+	Height   int     `json:"Height"`
+	Bytes    int64   `json:"Bytes"`
+	Duration float64 `json:"Duration"`
+}
+
+// RegenerateThumb deletes a thumbnail and creates it again from the original with thumb.FromFile, bypassing
+// all caches, and returns the time it took in milliseconds, its dimensions, and its file size. This is
+// intended for debugging slow or broken thumbnails.
+//
+// POST /api/v1/thumbs/regenerate/:hash/:size
+//
+// Parameters:
+//
+//	hash: string sha1 file hash
+//	size: string thumb type, see thumb.Sizes
+func RegenerateThumb(router *gin.RouterGroup) {
+	router.POST("/thumbs/regenerate/:hash/:size", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionManage)
+		conf := get.Config()
+
+		// Abort if permission was not granted.
+		if s.Invalid() || conf.Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		sizeName := thumb.Name(clean.Token(c.Param("size")))
+		size, ok := thumb.Sizes[sizeName]
+
+		if !ok {
+			AbortBadRequest(c)
+			return
+		}
+
+		f, err := query.FileByHash(clean.Token(c.Param("hash")))
+
+		if err != nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		fileName := photoprism.FileName(f.FileRoot, f.FileName)
+		thumbPath := thumb.Path(conf.ThumbCachePath(), fileName)
+
+		// Download remote originals to the cache folder first.
+		if thumb.IsRemote(fileName) {
+			fileName, err = thumb.RemoteFile(fileName, conf.ThumbCachePath())
+		} else {
+			fileName, err = fs.Resolve(fileName)
+		}
+
+		if err != nil {
+			log.Errorf("thumbs: %s in %s (regenerate)", err, clean.Log(f.FileName))
+			AbortEntityNotFound(c)
+			return
+		}
+
+		// Remove the existing thumbnail, if any.
+		if cached, err := thumb.FileName(f.FileHash, thumbPath, size.Width, size.Height, thumb.GifOptions(fileName, size.Options...)...); err != nil {
+			log.Errorf("thumbs: %s in %s (regenerate)", err, clean.Log(f.FileName))
+			AbortUnexpected(c)
+			return
+		} else if err = os.Remove(cached); err != nil && !os.IsNotExist(err) {
+			log.Errorf("thumbs: %s in %s (regenerate)", err, clean.Log(f.FileName))
+			AbortUnexpected(c)
+			return
+		}
+
+		start := time.Now()
+
+		thumbName, err := thumb.FromFileAngle(fileName, f.FileHash, thumbPath, size.Width, size.Height, f.FileOrientation, float64(f.FileAngle), size.Options...)
+
+		if err != nil {
+			log.Errorf("thumbs: %s in %s (regenerate)", err, clean.Log(f.FileName))
+			AbortUnexpected(c)
+			return
+		}
+
+		result := ThumbRegenerateResult{
+			Hash:     f.FileHash,
+			Size:     sizeName.String(),
+			Duration: float64(time.Since(start).Microseconds()) / 1000,
+		}
+
+		if info, err := os.Stat(thumbName); err == nil {
+			result.Bytes = info.Size()
+		}
+
+		if r, err := os.Open(thumbName); err == nil {
+			if cfg, _, err := image.DecodeConfig(r); err == nil {
+				result.Width, result.Height = cfg.Width, cfg.Height
+			}
+
+			r.Close()
+		}
+
+		log.Infof("thumbs: regenerated %s of %s in %.3fms", sizeName, clean.Log(f.FileName), result.Duration)
+
+		c.JSON(http.StatusOK, result)
+	})
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// RemoteEmbeddedThumb creates a small thumbnail of a remote original from its embedded preview, which only requires
+// requesting the first bytes of the file, and returns the filename. It returns an empty string if the original
+// must be downloaded instead, e.g. because a larger size was requested or a local copy already exists.
+func RemoteEmbeddedThumb(rawUrl, thumbPath string, f *entity.File, size thumb.Size) string {
+	if thumb.RemoteCached(rawUrl, get.Config().ThumbCachePath()) {
+		return ""
+	} else if size.Fit && f.Bounds().In(size.Bounds()) {
+		// A smaller fit size is used for small images, see thumb.FitBounds.
+		return ""
+	}
+
+	fileName, err := size.FromRemoteEmbedded(rawUrl, f.FileHash, thumbPath, f.FileOrientation)
+
+	if err != nil {
+		if !errors.Is(err, thumb.ErrNoPreview) {
+			log.Debugf("thumb: %s (embedded preview of %s)", err, clean.Log(f.FileName))
+		}
+
+		return ""
+	}
+
+	return fileName
+}
@@ -0,0 +1,447 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/customize"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ThumbRequest contains the parameters of a thumbnail request that are passed between the steps of GetThumb.
+type ThumbRequest struct {
+	Start        time.Time
+	FileHash     string
+	ThumbHash    string
+	ThumbPath    string
+	SizeName     thumb.Name
+	Size         thumb.Size
+	Angle        float64
+	CustomAngle  bool
+	Download     bool
+	DownloadName customize.DownloadName
+	CacheKey     string
+	Notice       bool
+	Overlay      string
+	Blur         bool
+	Filter       thumb.ResampleFilter
+	WithFilter   bool
+	Fallback     bool
+}
+
+// NewThumbRequest returns the parameters of a request for a thumbnail of the file in the specified size.
+func NewThumbRequest(c *gin.Context, fileHash, thumbPath string, sizeName thumb.Name, size thumb.Size) *ThumbRequest {
+	r := &ThumbRequest{
+		Start:     time.Now(),
+		FileHash:  fileHash,
+		ThumbPath: thumbPath,
+		SizeName:  sizeName,
+		Size:      size,
+		Download:  c.Query("download") != "",
+		Overlay:   c.Query("overlay"),
+	}
+
+	// Straighten the image by a custom angle?
+	r.Angle, r.CustomAngle = thumb.ParseAngle(c.Query("angle"))
+	r.ThumbHash = thumb.AngleHash(fileHash, r.Angle)
+
+	// Download file names depend on the requested naming scheme.
+	if r.Download {
+		r.DownloadName = DownloadName(c)
+	}
+
+	r.CacheKey = ThumbCacheKey(r.ThumbHash, sizeName, r.DownloadName)
+
+	// Downloads with embedded notices require the photo metadata, so cached names are skipped.
+	r.Notice = r.Download && get.Config().DownloadNotice()
+
+	// Faces are blurred based on the current markers if the share link requires it.
+	r.Blur = ShareBlurFaces(c)
+
+	// Resample with a different filter for comparison? This debug aid is limited to admins, see ThumbFilter.
+	r.Filter, r.WithFilter = ThumbFilter(c)
+
+	return r
+}
+
+// WithOverlay tests if the rating or a map is drawn onto the thumbnail, which depends on the current metadata.
+func (r *ThumbRequest) WithOverlay() bool {
+	return r.Overlay == thumb.OverlayRating || r.Overlay == thumb.OverlayMap
+}
+
+// Modified tests if a modified copy of the thumbnail is requested, so that it cannot be served from the cache.
+func (r *ThumbRequest) Modified() bool {
+	return r.WithOverlay() || r.Blur || r.WithFilter
+}
+
+// CachedThumb serves a thumbnail that has already been created, using the file name cache to avoid index
+// queries where possible, and returns true if a response was sent.
+func CachedThumb(c *gin.Context, r *ThumbRequest) bool {
+	conf := get.Config()
+	cache := get.ThumbCache()
+	size := r.Size
+
+	cacheData, cacheHit := cache.Get(r.CacheKey)
+
+	// Thumbnails of uncached sizes are created again after they have expired, see thumb.UncachedTTL.
+	if size.TTL() > 0 && (size.Expire(r.ThumbHash, r.ThumbPath) || cacheHit && !fs.FileExists(cacheData.(ThumbCache).FileName)) {
+		cache.Delete(r.CacheKey)
+		cacheHit = false
+	}
+
+	if cacheHit && !r.Notice && !r.Modified() {
+		log.Tracef("api-v1: cache hit for %s [%s]", r.CacheKey, time.Since(r.Start))
+
+		cached := cacheData.(ThumbCache)
+
+		if !fs.FileExists(cached.FileName) {
+			log.Errorf("thumb: %s not found", r.FileHash)
+			ThumbIcon(c, http.StatusNotFound, brokenIconSvg)
+			return true
+		}
+
+		// Serve stale thumbnails as is, and refresh them in the background.
+		if r.ThumbHash == r.FileHash {
+			thumb.QueueRefresh(cached.FileName, r.FileHash, r.ThumbPath, size)
+		}
+
+		// Add HTTP cache, crop region, and location headers.
+		AddImmutableCacheHeader(c)
+		AddCropRegionHeader(c, cached.FileName)
+		AddGPSHeader(c, cached.GPS)
+		AddPhotoUIDHeader(c, cached.PhotoUID)
+
+		if r.Download {
+			DownloadThumb(c, cached.FileName, cached.ShareName, nil)
+		} else if !CdnRedirect(c, cached.FileName) {
+			ThumbFile(c, cached.FileName, r.ThumbHash, r.ThumbPath, size)
+		}
+
+		return true
+	}
+
+	// Return existing thumbs straight away.
+	if !r.Download && !r.Modified() {
+		fileName, err := size.ResolvedName(r.ThumbHash, r.ThumbPath)
+
+		if err != nil {
+			return false
+		}
+
+		// Serve stale thumbnails as is, and refresh them in the background.
+		if r.ThumbHash == r.FileHash {
+			thumb.QueueRefresh(fileName, r.FileHash, r.ThumbPath, size)
+		}
+
+		// Cache the filename, together with the location and photo UID if they are added as headers,
+		// which requires an index query.
+		if !conf.ThumbGPS() && !conf.ThumbPhotoUID() {
+			SetThumbCache(r.CacheKey, r.FileHash, r.SizeName, ThumbCache{FileName: fileName})
+		} else if f, err := query.FileByHash(r.FileHash); err == nil {
+			cached := ThumbCache{FileName: fileName, ShareName: f.ShareBase(0), GPS: ThumbGPS(f), PhotoUID: f.PhotoUID}
+			SetThumbCache(r.CacheKey, r.FileHash, r.SizeName, cached)
+			AddGPSHeader(c, cached.GPS)
+			AddPhotoUIDHeader(c, cached.PhotoUID)
+		}
+
+		// Add HTTP cache and crop region headers.
+		AddImmutableCacheHeader(c)
+		AddCropRegionHeader(c, fileName)
+
+		// Redirect to the CDN if the thumbnail exists there, or return requested content.
+		if !CdnRedirect(c, fileName) {
+			ThumbFile(c, fileName, r.ThumbHash, r.ThumbPath, size)
+		}
+
+		return true
+	}
+
+	// Download existing thumbs without index query if the download name is known, as it is the same for all sizes.
+	if r.Download && !r.Notice && !r.Modified() {
+		nameData, ok := cache.Get(ShareNameCacheKey(r.FileHash, r.DownloadName))
+
+		if !ok {
+			return false
+		}
+
+		fileName, err := size.ResolvedName(r.ThumbHash, r.ThumbPath)
+
+		if err != nil {
+			return false
+		}
+
+		shared := nameData.(ThumbCache)
+		cached := ThumbCache{FileName: fileName, ShareName: shared.ShareName, GPS: shared.GPS, PhotoUID: shared.PhotoUID}
+		SetThumbCache(r.CacheKey, r.FileHash, r.SizeName, cached)
+
+		// Add HTTP cache, crop region, and location headers.
+		AddImmutableCacheHeader(c)
+		AddCropRegionHeader(c, fileName)
+		AddGPSHeader(c, cached.GPS)
+		AddPhotoUIDHeader(c, cached.PhotoUID)
+
+		DownloadThumb(c, fileName, cached.ShareName, nil)
+
+		return true
+	}
+
+	return false
+}
+
+// ThumbSource returns the indexed file and the name of the image that the thumbnail is created from, and
+// updates the thumbnail path of the request accordingly. It returns false if an error response was sent,
+// e.g. because the file is missing or has errors.
+func ThumbSource(c *gin.Context, r *ThumbRequest) (f *entity.File, fileName string, ok bool) {
+	logPrefix := "thumb"
+
+	// Query index for file infos.
+	f, err := query.FileByHash(r.FileHash)
+
+	if err != nil {
+		ThumbIcon(c, http.StatusNotFound, photoIconSvg)
+		return f, "", false
+	}
+
+	// Placeholder icons for corrupt and missing files depend on the media type of the requested file.
+	mediaType := FileMediaType(f)
+
+	// Find supported preview image if media file is not a JPEG or PNG.
+	if f.NoJPEG() && f.NoPNG() {
+		icon := fileIconSvg
+
+		// Show medical file icon if a DICOM image could not be decoded, a document icon
+		// if a PSD file has no usable preview, and an archive icon if there is no cover.
+		if fs.ImageDICOM.Equal(f.FileType) {
+			icon = medicalIconSvg
+		} else if fs.ImagePSD.Equal(f.FileType) {
+			icon = documentIconSvg
+		} else if thumb.IsArchive(f.FileName) {
+			icon = archiveIconSvg
+		}
+
+		if f, err = PreviewFallback(f.PhotoUID); err != nil {
+			log.Debugf("%s: %s", logPrefix, err)
+			ThumbIcon(c, http.StatusNotFound, icon)
+			return f, "", false
+		}
+
+		// Thumbnails of other files than the primary file are not pre-cached, see config.ThumbFileFallback.
+		r.Fallback = !f.FilePrimary
+	}
+
+	// Return SVG icon as placeholder if file has errors.
+	if f.FileError != "" {
+		ThumbIcon(c, http.StatusInternalServerError, ErrorIcon(mediaType))
+		return f, "", false
+	}
+
+	fileName = photoprism.FileName(f.FileRoot, f.FileName)
+	r.ThumbPath = thumb.Path(get.Config().ThumbCachePath(), fileName)
+
+	// Download remote originals to the cache folder first, unless a small thumbnail can be created from the
+	// preview embedded in the first bytes of the file, e.g. on WebDAV servers, see thumb.FromRemoteEmbedded.
+	var embedded string
+
+	if thumb.IsRemote(fileName) && !r.CustomAngle && !r.WithFilter {
+		embedded = RemoteEmbeddedThumb(fileName, r.ThumbPath, f, r.Size)
+	}
+
+	if embedded != "" {
+		// The thumbnail is found in the cache from now on, so the original is not needed.
+		fileName = embedded
+	} else if name, status, err := OriginalFile(f, logPrefix); err != nil {
+		log.Errorf("%s: %s", logPrefix, err)
+
+		if status == http.StatusNotFound {
+			ThumbIcon(c, status, ErrorIcon(mediaType))
+		} else {
+			ThumbIcon(c, status, brokenIconSvg)
+		}
+
+		return f, "", false
+	} else {
+		fileName = name
+	}
+
+	// Reject images with zero or invalid dimensions before decoding them, see thumb.CheckDimensions.
+	if err = thumb.CheckDimensions(fileName); errors.Is(err, thumb.ErrInvalidDimensions) {
+		log.Warnf("%s: %s in %s, rejected", logPrefix, err, clean.Log(f.FileName))
+		ThumbIcon(c, http.StatusUnprocessableEntity, ErrorIcon(mediaType))
+
+		// Flag the file so that it can be filtered and subsequent requests return the icon right away.
+		if get.Config().ThumbInvalid() == "flag" {
+			logError(logPrefix, f.Update("FileError", err.Error()))
+		}
+
+		return f, "", false
+	}
+
+	return f, fileName, true
+}
+
+// RenderThumb finds or creates the thumbnail, and returns its file name. It returns false if a response
+// was already sent, e.g. because the thumbnail was streamed while it was created, or creation failed.
+func RenderThumb(c *gin.Context, r *ThumbRequest, f *entity.File, fileName string) (thumbName string, ok bool) {
+	logPrefix := "thumb"
+	conf := get.Config()
+	size := r.Size
+
+	var err error
+
+	// Try to find or create thumbnail image.
+	created := time.Now()
+
+	// streamed indicates that the thumbnail was sent while it was being created.
+	var streamed bool
+
+	if r.CustomAngle {
+		thumbName, err = ThumbAsync(c, r.ThumbHash, size, func() (string, error) {
+			return size.FromFileAngle(fileName, thumb.AngleHash(f.FileHash, r.Angle), r.ThumbPath, f.FileOrientation, r.Angle)
+		})
+	} else if (conf.ThumbUncached() || size.Uncached()) && !r.Download && !r.WithOverlay() && !r.Blur && size.Streamable() {
+		AddGPSHeader(c, ThumbGPS(f))
+		AddPhotoUIDHeader(c, f.PhotoUID)
+		thumbName, streamed, err = StreamThumb(c, size, fileName, f.FileHash, r.ThumbPath, f.FileOrientation, float64(f.FileAngle))
+	} else if conf.ThumbUncached() || size.Uncached() {
+		thumbName, err = ThumbAsync(c, r.ThumbHash, size, func() (string, error) {
+			return CreateThumb(f, fileName, r.ThumbPath, size)
+		})
+	} else if r.Fallback {
+		thumbName, err = ThumbAsync(c, r.ThumbHash, size, func() (string, error) {
+			return size.FromFileAngle(fileName, f.FileHash, r.ThumbPath, f.FileOrientation, float64(f.FileAngle))
+		})
+	} else {
+		thumbName, err = CreateThumb(f, fileName, r.ThumbPath, size)
+	}
+
+	// Tell the client to retry later if the thumbnail is still being created in the background.
+	if errors.Is(err, thumb.ErrPending) {
+		ThumbPending(c)
+		return "", false
+	}
+
+	// Update generation statistics by source format.
+	if r.CustomAngle || r.Fallback || conf.ThumbUncached() || size.Uncached() {
+		thumb.AddStats(thumb.SourceFormat(fileName), 1, time.Since(created), err != nil)
+	}
+
+	// Response already sent?
+	if streamed {
+		if err != nil {
+			// Headers have been sent, so no placeholder icon can be returned.
+			log.Errorf("%s: %s", logPrefix, err)
+		} else {
+			SetThumbCache(r.CacheKey, r.FileHash, r.SizeName, ThumbCache{FileName: thumbName, ShareName: f.ShareBase(0), GPS: ThumbGPS(f), PhotoUID: f.PhotoUID})
+		}
+
+		return "", false
+	}
+
+	// Failed?
+	if errors.Is(err, thumb.ErrTimeout) {
+		log.Warnf("%s: creating %s for %s timed out after %s", logPrefix, size.Name, clean.Log(f.FileName), conf.ThumbTimeout())
+		ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+
+		// Flag the file so that subsequent requests return the broken icon right away.
+		logError(logPrefix, f.Update("FileError", err.Error()))
+		return "", false
+	} else if errors.Is(err, thumb.ErrTooManyPixels) {
+		log.Warnf("%s: %s in %s, rejected", logPrefix, err, clean.Log(f.FileName))
+		ThumbIcon(c, http.StatusUnprocessableEntity, brokenIconSvg)
+
+		// Flag the file so that subsequent requests return the broken icon right away.
+		logError(logPrefix, f.Update("FileError", err.Error()))
+		return "", false
+	} else if err != nil {
+		log.Errorf("%s: %s", logPrefix, err)
+		ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+		return "", false
+	} else if thumbName == "" {
+		log.Errorf("%s: %s has empty thumb name - you may have found a bug", logPrefix, filepath.Base(fileName))
+		ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+		return "", false
+	}
+
+	return thumbName, true
+}
+
+// ServeThumb caches the file name of the thumbnail, applies the requested modifications to a separate
+// copy, e.g. blurred faces or overlays, and sends it to the client.
+func ServeThumb(c *gin.Context, r *ThumbRequest, f *entity.File, thumbName string) {
+	logPrefix := "thumb"
+	conf := get.Config()
+
+	var err error
+
+	// Cache thumbnail filename to reduce the number of index queries.
+	shareName := f.ShareBase(0)
+
+	gps := ThumbGPS(f)
+
+	if r.Download {
+		shareName = f.DownloadName(r.DownloadName, 0)
+		get.ThumbCache().SetDefault(ShareNameCacheKey(r.FileHash, r.DownloadName), ThumbCache{ShareName: shareName, GPS: gps, PhotoUID: f.PhotoUID})
+	}
+
+	SetThumbCache(r.CacheKey, r.FileHash, r.SizeName, ThumbCache{FileName: thumbName, ShareName: shareName, GPS: gps, PhotoUID: f.PhotoUID})
+	log.Debugf("cached %s [%s]", r.CacheKey, time.Since(r.Start))
+
+	// Blur faces in a separate copy of the thumbnail?
+	if r.Blur {
+		if thumbName, err = thumb.FromBlur(thumbName, ThumbFaceRegions(f, r.Size)); err != nil {
+			log.Errorf("%s: %s (blur)", logPrefix, err)
+			ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+			return
+		}
+	}
+
+	// Draw the rating or reject flag onto a separate copy of the thumbnail?
+	if r.Overlay == thumb.OverlayRating {
+		if thumbName, err = thumb.FromOverlay(thumbName, ThumbBadge(f)); err != nil {
+			log.Errorf("%s: %s (overlay)", logPrefix, err)
+			ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+			return
+		}
+	} else if lat, lng, ok := ThumbMapLocation(f, r.Overlay); ok {
+		// Draw a map inset of the location, if known, onto a separate copy of the thumbnail.
+		if thumbName, err = thumb.FromMap(thumbName, lat, lng, conf.CachePath()); err != nil {
+			log.Errorf("%s: %s (map)", logPrefix, err)
+			ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+			return
+		}
+	}
+
+	if r.WithOverlay() {
+		// The rating or location may change, so overlays are not immutable.
+		AddCoverCacheHeader(c)
+	} else if r.Blur {
+		// Faces may be added or moved, so blurred thumbnails are not immutable either.
+		AddCoverCacheHeader(c)
+	} else {
+		AddImmutableCacheHeader(c)
+	}
+
+	// Add crop region, location, and photo headers.
+	AddCropRegionHeader(c, thumbName)
+	AddGPSHeader(c, gps)
+	AddPhotoUIDHeader(c, f.PhotoUID)
+
+	// Return requested content.
+	if r.Download {
+		DownloadThumb(c, thumbName, shareName, f)
+	} else if r.WithOverlay() || r.Blur {
+		c.File(thumbName)
+	} else {
+		ThumbFile(c, thumbName, r.ThumbHash, r.ThumbPath, r.Size)
+	}
+}
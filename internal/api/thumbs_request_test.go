@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestNewThumbRequest(t *testing.T) {
+	fileHash := "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818"
+
+	newRequest := func(query string) *ThumbRequest {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/"+fileHash+"/token/tile_500"+query, nil)
+		return NewThumbRequest(c, fileHash, "", thumb.Tile500, thumb.Sizes[thumb.Tile500])
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		r := newRequest("")
+		assert.Equal(t, fileHash, r.ThumbHash)
+		assert.False(t, r.Download)
+		assert.False(t, r.CustomAngle)
+		assert.False(t, r.WithOverlay())
+		assert.False(t, r.Modified())
+	})
+	t.Run("Overlay", func(t *testing.T) {
+		r := newRequest("?overlay=" + thumb.OverlayRating)
+		assert.True(t, r.WithOverlay())
+		assert.True(t, r.Modified())
+	})
+	t.Run("Angle", func(t *testing.T) {
+		r := newRequest("?angle=12.5")
+		assert.True(t, r.CustomAngle)
+		assert.NotEqual(t, fileHash, r.ThumbHash)
+		assert.False(t, r.Modified())
+	})
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// GetThumbSelfTest creates thumbnails of a bundled test image to verify that the thumbnail pipeline works,
+// and returns the results with timing, see thumb.SelfTest. The status is 503 if a check failed, so that
+// the endpoint can be used for monitoring.
+//
+// GET /api/v1/thumbs/selftest
+func GetThumbSelfTest(router *gin.RouterGroup) {
+	router.GET("/thumbs/selftest", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionManage)
+		conf := get.Config()
+
+		// Abort if permission was not granted.
+		if s.Invalid() || conf.Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		result := thumb.SelfTest(conf.TempPath())
+
+		if !result.Passed {
+			log.Errorf("thumbs: self-test failed %#v", result.Checks)
+			c.JSON(http.StatusServiceUnavailable, result)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	})
+}
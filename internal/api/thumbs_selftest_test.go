@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+func TestGetThumbSelfTest(t *testing.T) {
+	t.Run("Unauthorized", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetThumbSelfTest(router)
+		r := PerformRequest(app, "GET", "/api/v1/thumbs/selftest")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("Success", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetThumbSelfTest(router)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "GET", "/api/v1/thumbs/selftest", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.True(t, gjson.Get(r.Body.String(), "passed").Bool())
+		assert.Equal(t, int64(2), gjson.Get(r.Body.String(), "checks.#").Int())
+	})
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/list"
+)
+
+// Namespace for caching share links.
+const shareLinks = "share-links"
+
+// ShareLinksTTL is the time share links are cached per token, so that they are not queried for every thumbnail.
+var ShareLinksTTL = time.Minute
+
+// shareContext represents the share link visitor session a request belongs to, see ShareVisitor.
+type shareContext struct {
+	visitor bool
+	links   entity.Links
+}
+
+// ShareVisitor checks if the request belongs to a share link visitor session. The session is found on the
+// server by the preview token in the URL, so that share restrictions cannot be bypassed by omitting
+// query parameters. Requests without a visitor session, e.g. by registered users, are not restricted.
+func ShareVisitor(c *gin.Context) bool {
+	return requestShare(c).visitor
+}
+
+// ShareLinks returns the valid share links redeemed by the visitor session the request belongs to, or an
+// empty list if it does not belong to a visitor session, see ShareVisitor.
+func ShareLinks(c *gin.Context) entity.Links {
+	return requestShare(c).links
+}
+
+// requestShare returns the share context of the request. It is stored in the request context, so that
+// the session is looked up only once per request.
+func requestShare(c *gin.Context) shareContext {
+	if c == nil {
+		return shareContext{}
+	} else if found, ok := c.Get(shareLinks); ok {
+		return found.(shareContext)
+	}
+
+	result := findShare(c)
+
+	c.Set(shareLinks, result)
+
+	return result
+}
+
+// findShare finds the visitor session of the preview token passed with the request and its share links.
+func findShare(c *gin.Context) (result shareContext) {
+	token := clean.UrlToken(c.Param("token"))
+
+	if token == "" {
+		token = clean.UrlToken(c.Query("t"))
+	}
+
+	sessId := entity.PreviewToken.Get(token)
+
+	if sessId == "" {
+		return result
+	}
+
+	s, err := get.Session().Get(sessId)
+
+	if err != nil || s == nil || s.IsRegistered() {
+		return result
+	}
+
+	result.visitor = true
+
+	for _, t := range s.Data().Tokens {
+		result.links = append(result.links, CachedLinks(t)...)
+	}
+
+	return result
+}
+
+// CachedLinks returns the valid links of a share token, which are cached for ShareLinksTTL.
+func CachedLinks(token string) entity.Links {
+	cache := get.ThumbCache()
+	cacheKey := CacheKey(shareLinks, token, "")
+
+	if found, ok := cache.Get(cacheKey); ok {
+		return found.(entity.Links)
+	}
+
+	links := entity.FindValidLinks(token, "")
+
+	cache.Set(cacheKey, links, ShareLinksTTL)
+
+	return links
+}
+
+// ShareSize returns the thumbnail size to serve if the request belongs to a share link visitor session,
+// see config.ShareThumbSizes. Sizes that are not allowed are downgraded to the largest allowed size of the
+// same kind that fits within them, and ok is false if there is none, so the request must be rejected.
+func ShareSize(c *gin.Context, name thumb.Name, size thumb.Size) (thumb.Name, thumb.Size, bool) {
+	if !ShareVisitor(c) {
+		return name, size, true
+	}
+
+	return AllowedSize(get.Config().ShareThumbSizes(), name, size)
+}
+
+// AllowedSize returns the requested size if it is in the allowed list, or the largest allowed size of the
+// same kind that fits within it otherwise, with crops also requiring the same aspect ratio.
+// An empty list allows all sizes.
+func AllowedSize(allowed []string, name thumb.Name, size thumb.Size) (thumb.Name, thumb.Size, bool) {
+	if len(allowed) == 0 || list.Contains(allowed, name.String()) {
+		return name, size, true
+	}
+
+	var found bool
+	var result thumb.Size
+
+	for _, s := range allowed {
+		t, ok := thumb.Sizes[thumb.Name(s)]
+
+		if !ok || t.Fit != size.Fit || t.Width > size.Width || t.Height > size.Height {
+			continue
+		} else if !t.Fit && t.Width*size.Height != t.Height*size.Width {
+			continue
+		} else if !found || t.Width > result.Width {
+			found = true
+			result = t
+		}
+	}
+
+	if !found {
+		return name, size, false
+	}
+
+	return result.Name, result, true
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestShareSize(t *testing.T) {
+	newContext := func(query string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/hash/token/fit_1920"+query, nil)
+		return c
+	}
+
+	t.Run("NoVisitor", func(t *testing.T) {
+		name, size, ok := ShareSize(newContext(""), thumb.Fit1920, thumb.Sizes[thumb.Fit1920])
+		assert.True(t, ok)
+		assert.Equal(t, thumb.Fit1920, name)
+		assert.Equal(t, thumb.Fit1920, size.Name)
+	})
+	t.Run("ShareTokenIgnored", func(t *testing.T) {
+		name, _, ok := ShareSize(newContext("?s=1jxf3jfn2k"), thumb.Fit1920, thumb.Sizes[thumb.Fit1920])
+		assert.True(t, ok)
+		assert.Equal(t, thumb.Fit1920, name)
+	})
+}
+
+func TestShareVisitor(t *testing.T) {
+	app, router, _ := NewApiTest()
+
+	router.GET("/share-visitor/:token", func(c *gin.Context) {
+		if ShareVisitor(c) {
+			c.JSON(http.StatusOK, ShareLinks(c))
+		} else {
+			c.Status(http.StatusNoContent)
+		}
+	})
+
+	entity.PreviewToken.Set("visitor1preview", entity.SessionFixtures.Get("visitor").ID)
+	defer entity.PreviewToken.Unset("visitor1preview")
+
+	entity.PreviewToken.Set("alice1preview", entity.SessionFixtures.Get("alice").ID)
+	defer entity.PreviewToken.Unset("alice1preview")
+
+	t.Run("Visitor", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/share-visitor/visitor1preview")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Contains(t, r.Body.String(), "at9lxuqxpogaaba8")
+	})
+	t.Run("Registered", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/share-visitor/alice1preview")
+		assert.Equal(t, http.StatusNoContent, r.Code)
+	})
+	t.Run("UnknownToken", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/share-visitor/unknown1preview?s=1jxf3jfn2k")
+		assert.Equal(t, http.StatusNoContent, r.Code)
+	})
+}
+
+func TestCachedLinks(t *testing.T) {
+	links := CachedLinks("1jxf3jfn2k")
+
+	if assert.Len(t, links, 1) {
+		assert.Equal(t, "at9lxuqxpogaaba8", links[0].ShareUID)
+	}
+
+	assert.Empty(t, CachedLinks("xxx1jxf3jfn2k"))
+}
+
+func TestAllowedSize(t *testing.T) {
+	allowed := []string{"fit_720", "fit_1280", "tile_224"}
+
+	t.Run("Empty", func(t *testing.T) {
+		name, _, ok := AllowedSize([]string{}, thumb.Fit7680, thumb.Sizes[thumb.Fit7680])
+		assert.True(t, ok)
+		assert.Equal(t, thumb.Fit7680, name)
+	})
+	t.Run("Allowed", func(t *testing.T) {
+		name, _, ok := AllowedSize(allowed, thumb.Fit720, thumb.Sizes[thumb.Fit720])
+		assert.True(t, ok)
+		assert.Equal(t, thumb.Fit720, name)
+	})
+	t.Run("Downgrade", func(t *testing.T) {
+		name, size, ok := AllowedSize(allowed, thumb.Fit1920, thumb.Sizes[thumb.Fit1920])
+		assert.True(t, ok)
+		assert.Equal(t, thumb.Fit1280, name)
+		assert.Equal(t, 1280, size.Width)
+	})
+	t.Run("DowngradeTile", func(t *testing.T) {
+		name, _, ok := AllowedSize(allowed, thumb.Tile500, thumb.Sizes[thumb.Tile500])
+		assert.True(t, ok)
+		assert.Equal(t, thumb.Tile224, name)
+	})
+	t.Run("Forbidden", func(t *testing.T) {
+		_, _, ok := AllowedSize(allowed, thumb.Tile50, thumb.Sizes[thumb.Tile50])
+		assert.False(t, ok)
+	})
+	t.Run("OtherKind", func(t *testing.T) {
+		_, _, ok := AllowedSize([]string{"tile_224"}, thumb.Fit720, thumb.Sizes[thumb.Fit720])
+		assert.False(t, ok)
+	})
+}
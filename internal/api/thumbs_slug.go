@@ -0,0 +1,20 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// ThumbSlug checks if the last segment of a thumbnail URL is a descriptive file name after the size name,
+// e.g. "/api/v1/t/:thumb/:token/fit_1280/my-photo-title.jpg", rather than the height of explicit dimensions.
+// The file name only improves link previews and search results, so it is ignored when resolving the thumbnail.
+func ThumbSlug(c *gin.Context) bool {
+	if txt.IsUInt(c.Param("size")) {
+		return false
+	}
+
+	slug := c.Param("height")
+
+	return slug != "" && !txt.IsUInt(slug)
+}
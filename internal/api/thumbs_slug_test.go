@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbSlug(t *testing.T) {
+	newContext := func(size, height string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/t/hash/token/"+size+"/"+height, nil)
+		c.Params = gin.Params{{Key: "size", Value: size}, {Key: "height", Value: height}}
+		return c
+	}
+
+	assert.True(t, ThumbSlug(newContext("fit_1280", "my-photo-title.jpg")))
+	assert.True(t, ThumbSlug(newContext("tile_500", "photo")))
+	assert.False(t, ThumbSlug(newContext("640", "480")))
+	assert.False(t, ThumbSlug(newContext("640", "my-photo-title.jpg")))
+	assert.False(t, ThumbSlug(newContext("abc", "0")))
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ThumbFileHash returns the sha1 hash that thumbnails are addressed by, and resolves other hash types
+// like "blake3:..." with an index query.
+func ThumbFileHash(hash string) (string, error) {
+	if hashType, sha1 := fs.ParseHash(hash); hashType == fs.HashSHA1 {
+		return sha1, nil
+	} else if f, err := query.FileByHash(hash); err != nil {
+		return "", err
+	} else {
+		return f.FileHash, nil
+	}
+}
+
+// PolicySize returns the largest cached size instead of the requested size if it is not created, see
+// thumb.Policies, or the requested size otherwise. Without a file hash, e.g. for covers, the largest
+// pre-cached size is returned instead. An empty name is returned if no size is found.
+func PolicySize(fileHash, thumbPath string, sizeName thumb.Name, size thumb.Size) (thumb.Name, thumb.Size) {
+	conf := get.Config()
+
+	if size.Never() || size.Uncached() && !conf.ThumbUncached() && !size.OnDemand() {
+		return thumb.FindCached(fileHash, thumbPath, conf.ThumbSizePrecached(), conf.ThumbFallback())
+	}
+
+	return sizeName, size
+}
+
+// OriginalFile returns the name of the original that thumbnails of the file are created from, and
+// downloads remote originals to the cache folder first. Missing files are flagged, and the photo is
+// deleted if all of its files are missing, so that they no longer show up in search results. The
+// HTTP status code that should be returned if this fails is returned together with the error.
+func OriginalFile(f *entity.File, logPrefix string) (fileName string, status int, err error) {
+	conf := get.Config()
+	fileName = photoprism.FileName(f.FileRoot, f.FileName)
+
+	if thumb.IsRemote(fileName) {
+		if fileName, err = thumb.RemoteFile(fileName, conf.ThumbCachePath()); err != nil {
+			return "", http.StatusBadGateway, err
+		}
+
+		return fileName, http.StatusOK, nil
+	} else if fileName, err = fs.Resolve(fileName); err == nil {
+		return fileName, http.StatusOK, nil
+	}
+
+	// Set missing flag so that the file doesn't show up in search results anymore.
+	logError(logPrefix, f.Update("FileMissing", true))
+
+	if f.AllFilesMissing() {
+		log.Infof("%s: deleting photo, all files missing for %s", logPrefix, clean.Log(f.FileName))
+
+		if _, deleteErr := f.RelatedPhoto().Delete(false); deleteErr != nil {
+			log.Errorf("%s: %s while deleting %s", logPrefix, deleteErr, clean.Log(f.FileName))
+		}
+	}
+
+	return "", http.StatusNotFound, fmt.Errorf("file %s is missing", clean.Log(f.FileName))
+}
+
+// OriginalThumb returns the name of the file that is served instead of a thumbnail whose size exceeds the
+// limit, see https://github.com/photoprism/photoprism/issues/157. This is a capped derivative of frequently
+// requested originals if available, see thumb.Downsize, or else the original, which is served as a cached
+// copy without metadata to share links and in public mode, see StripOriginal.
+func OriginalThumb(c *gin.Context, f *entity.File, fileName, thumbPath string, size thumb.Size, logPrefix string) (string, error) {
+	if downsized, ok := thumb.FromDownsized(f.FileHash, thumbPath, size); ok {
+		return downsized, nil
+	}
+
+	log.Debugf("%s: using original, size exceeds limit (width %d, height %d)", logPrefix, size.Width, size.Height)
+	thumb.CountOversized(fileName, f.FileHash, thumbPath, f.FileOrientation, size)
+
+	if StripOriginal(c) {
+		return thumb.FromStripped(fileName, f.FileHash, thumbPath, f.FileOrientation)
+	}
+
+	return fileName, nil
+}
+
+// CreateThumb returns the name of the thumbnail of the file in the specified size. Thumbnails of sizes
+// that are not pre-cached are created with the stored angle if needed, see config.ThumbUncached.
+func CreateThumb(f *entity.File, fileName, thumbPath string, size thumb.Size) (string, error) {
+	if get.Config().ThumbUncached() || size.Uncached() {
+		return size.FromFileAngle(fileName, f.FileHash, thumbPath, f.FileOrientation, float64(f.FileAngle))
+	}
+
+	return size.FromCache(fileName, f.FileHash, thumbPath)
+}
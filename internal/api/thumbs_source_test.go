@@ -0,0 +1,41 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestThumbFileHash(t *testing.T) {
+	t.Run("Sha1", func(t *testing.T) {
+		hash, err := ThumbFileHash("2cad9168fa6acc5c5c2965ddf6ec465ca42fd818")
+		assert.NoError(t, err)
+		assert.Equal(t, "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818", hash)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		hash, err := ThumbFileHash("blake3:xxx")
+		assert.Error(t, err)
+		assert.Equal(t, "", hash)
+	})
+}
+
+func TestPolicySize(t *testing.T) {
+	t.Run("Precached", func(t *testing.T) {
+		name, size := PolicySize("", "", thumb.Tile500, thumb.Sizes[thumb.Tile500])
+		assert.Equal(t, thumb.Tile500, name)
+		assert.Equal(t, thumb.Tile500, size.Name)
+	})
+	t.Run("Uncached", func(t *testing.T) {
+		if get.Config().ThumbUncached() {
+			t.Skip("uncached thumbnails are enabled")
+		}
+
+		name, size := PolicySize("", "", thumb.Fit7680, thumb.Sizes[thumb.Fit7680])
+		assert.NotEqual(t, thumb.Fit7680, name)
+		assert.Equal(t, name, size.Name)
+		assert.LessOrEqual(t, size.Width, get.Config().ThumbSizePrecached())
+	})
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/query"
+)
+
+// StackCover returns the hash of the primary file if the file with the specified hash belongs to a stack
+// and thumbnails of stacked files should show the cover, see config.ThumbStackCover. This respects the
+// primary file chosen by the user, and returns the specified hash otherwise.
+func StackCover(fileHash string) string {
+	if fileHash == "" || !get.Config().ThumbStackCover() {
+		return fileHash
+	}
+
+	f, err := query.FileByHash(fileHash)
+
+	if err != nil || f.FilePrimary || f.PhotoUID == "" {
+		return fileHash
+	}
+
+	cover, err := query.FileByPhotoUID(f.PhotoUID)
+
+	if err != nil || cover.FileHash == "" || cover.FileMissing {
+		return fileHash
+	}
+
+	log.Tracef("thumb: using stack cover %s for %s", cover.FileHash, fileHash)
+
+	return cover.FileHash
+}
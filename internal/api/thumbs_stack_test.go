@@ -0,0 +1,65 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/get"
+)
+
+func TestStackCover(t *testing.T) {
+	conf := get.Config()
+
+	// Fixture that is not modified by other tests.
+	cover := entity.FileFixtures.Get("Photo22.jpg")
+
+	// newStackedFile adds a file to the stack of the fixture, which must be deleted after the test.
+	newStackedFile := func(t *testing.T) *entity.File {
+		f := &entity.File{
+			PhotoID:     cover.PhotoID,
+			PhotoUID:    cover.PhotoUID,
+			FileName:    "Mexico-With-Family/Photo22.cr2.jpg",
+			FileRoot:    entity.RootOriginals,
+			FileHash:    "pcad9a68fa6acc5c5ba965adf6ec465ca42fd9f1",
+			FileType:    "jpg",
+			FilePrimary: false,
+		}
+
+		if err := f.Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		return f
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		f := newStackedFile(t)
+		defer f.DeletePermanently()
+
+		assert.Equal(t, f.FileHash, StackCover(f.FileHash))
+	})
+	t.Run("Stacked", func(t *testing.T) {
+		conf.Options().ThumbStackCover = true
+		defer func() { conf.Options().ThumbStackCover = false }()
+
+		f := newStackedFile(t)
+		defer f.DeletePermanently()
+
+		assert.Equal(t, cover.FileHash, StackCover(f.FileHash))
+	})
+	t.Run("Primary", func(t *testing.T) {
+		conf.Options().ThumbStackCover = true
+		defer func() { conf.Options().ThumbStackCover = false }()
+
+		assert.Equal(t, cover.FileHash, StackCover(cover.FileHash))
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		conf.Options().ThumbStackCover = true
+		defer func() { conf.Options().ThumbStackCover = false }()
+
+		assert.Equal(t, "xxx", StackCover("xxx"))
+		assert.Equal(t, "", StackCover(""))
+	})
+}
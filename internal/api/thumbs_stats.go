@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// GetThumbStats returns thumbnail generation statistics by source format, e.g. jpeg, heic, raw, and video.
+// The active JPEG encoder backend is returned in the X-Jpeg-Encoder header, and the hardware acceleration
+// backend used for resizing in the X-Thumb-Accel header.
+//
+// GET /api/v1/thumbs/stats
+func GetThumbStats(router *gin.RouterGroup) {
+	router.GET("/thumbs/stats", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionView)
+		conf := get.Config()
+
+		// Abort if permission was not granted.
+		if s.Invalid() || conf.Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		// Report which JPEG encoder backend is active, see thumb.ActiveEncoder.
+		c.Header("X-Jpeg-Encoder", thumb.ActiveEncoder())
+		c.Header("X-Thumb-Accel", thumb.ActiveAccel())
+		c.JSON(http.StatusOK, thumb.Stats())
+	})
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestGetThumbStats(t *testing.T) {
+	t.Run("Unauthorized", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetThumbStats(router)
+		r := PerformRequest(app, "GET", "/api/v1/thumbs/stats")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("Success", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetThumbStats(router)
+
+		thumb.ResetStats()
+		defer thumb.ResetStats()
+
+		thumb.AddStats(thumb.FormatRAW, 4, 2*time.Second, false)
+		thumb.AddStats(thumb.FormatRAW, 0, 0, true)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "GET", "/api/v1/thumbs/stats", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, int64(4), gjson.Get(r.Body.String(), "raw.created").Int())
+		assert.Equal(t, int64(1), gjson.Get(r.Body.String(), "raw.failed").Int())
+		assert.Equal(t, int64(500), gjson.Get(r.Body.String(), "raw.avgMs").Int())
+		assert.Equal(t, thumb.EncoderStd, r.Header().Get("X-Jpeg-Encoder"))
+		assert.Equal(t, thumb.AccelNone, r.Header().Get("X-Thumb-Accel"))
+	})
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// ShareStripMetadata checks if the thumbnail is requested by a share link visitor, and metadata must not be
+// included in the response, see config.ShareStripMetadata and ShareVisitor.
+func ShareStripMetadata(c *gin.Context) bool {
+	return get.Config().ShareStripMetadata() && ShareVisitor(c)
+}
+
+// StripOriginal checks if an original is served to a share link visitor or in public mode instead of a
+// thumbnail, so that a copy without metadata must be served, see config.ShareStripOriginals and ShareVisitor.
+func StripOriginal(c *gin.Context) bool {
+	conf := get.Config()
+	return conf.ShareStripOriginals() && (conf.Public() || ShareVisitor(c))
+}
+
+// ThumbStrip wraps a thumbnail handler to verify that JPEG responses to share link visitors contain no Exif,
+// XMP, or IPTC metadata, which is removed otherwise, see thumb.StripMetadata.
+// The response is buffered for this, so range requests are served in full.
+func ThumbStrip(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ShareStripMetadata(c) {
+			handler(c)
+			return
+		}
+
+		c.Request.Header.Del("Range")
+
+		w := &stripWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		handler(c)
+
+		c.Writer = w.ResponseWriter
+
+		// Location headers would reveal the same information as the metadata.
+		c.Writer.Header().Del("X-GPS")
+
+		data := w.buf.Bytes()
+
+		if w.Status() == http.StatusOK && strings.HasPrefix(w.Header().Get("Content-Type"), "image/jpeg") {
+			if found, err := thumb.JpegMetadata(data); err != nil {
+				log.Errorf("thumb: %s, rejected (strip metadata)", err)
+				ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+				return
+			} else if len(found) > 0 {
+				log.Warnf("thumb: removing %s metadata from shared thumbnail", clean.Log(strings.Join(found, ", ")))
+
+				if data, err = thumb.StripMetadata(data); err != nil {
+					log.Errorf("thumb: %s, rejected (strip metadata)", err)
+					ThumbIcon(c, http.StatusInternalServerError, brokenIconSvg)
+					return
+				}
+			}
+
+			c.Writer.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		}
+
+		c.Writer.WriteHeader(w.Status())
+
+		if _, err := c.Writer.Write(data); err != nil {
+			log.Debugf("thumb: %s (strip metadata)", err)
+		}
+	}
+}
+
+// stripWriter buffers a response so that it can be verified before it is sent, see ThumbStrip.
+type stripWriter struct {
+	gin.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+// WriteHeader stores the status code until the response is sent.
+func (w *stripWriter) WriteHeader(code int) {
+	if code > 0 && w.status == 0 {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow does nothing, as the headers are sent once the response has been verified.
+func (w *stripWriter) WriteHeaderNow() {}
+
+// Write adds the data to the buffer.
+func (w *stripWriter) Write(data []byte) (int, error) {
+	return w.buf.Write(data)
+}
+
+// WriteString adds the string to the buffer.
+func (w *stripWriter) WriteString(s string) (int, error) {
+	return w.buf.WriteString(s)
+}
+
+// Flush does nothing, as the response must be buffered until it has been verified.
+func (w *stripWriter) Flush() {}
+
+// Status returns the status code of the buffered response.
+func (w *stripWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+// Size returns the number of bytes buffered.
+func (w *stripWriter) Size() int {
+	return w.buf.Len()
+}
+
+// Written checks if a status code or data has been written.
+func (w *stripWriter) Written() bool {
+	return w.status != 0 || w.buf.Len() > 0
+}
+
+// GetThumbStripSelfTest creates a thumbnail of a bundled test image with Exif, XMP, and GPS metadata,
+// and verifies that neither the thumbnail nor a stripped copy contain metadata, see thumb.StripSelfTest.
+// The status is 503 if the check failed, so that the endpoint can be used for monitoring.
+//
+// GET /api/v1/thumbs/selftest/metadata
+func GetThumbStripSelfTest(router *gin.RouterGroup) {
+	router.GET("/thumbs/selftest/metadata", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceConfig, acl.ActionManage)
+		conf := get.Config()
+
+		// Abort if permission was not granted.
+		if s.Invalid() || conf.Public() {
+			AbortForbidden(c)
+			return
+		}
+
+		check := thumb.StripSelfTest(conf.TempPath())
+
+		result := gin.H{"enforced": conf.ShareStripMetadata(), "check": check}
+
+		if !check.Passed {
+			log.Errorf("thumbs: metadata self-test failed (%s)", check.Error)
+			c.JSON(http.StatusServiceUnavailable, result)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	})
+}
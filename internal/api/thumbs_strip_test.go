@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestThumbStrip(t *testing.T) {
+	data, err := os.ReadFile("../thumb/testdata/example.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app, router, conf := NewApiTest()
+
+	router.GET("/strip/:token", ThumbStrip(func(c *gin.Context) {
+		c.Header("X-GPS", "52.5200,13.4050")
+		c.Data(http.StatusOK, "image/jpeg", data)
+	}))
+
+	entity.PreviewToken.Set("visitor3preview", entity.SessionFixtures.Get("visitor").ID)
+	defer entity.PreviewToken.Unset("visitor3preview")
+
+	t.Run("Disabled", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/strip/visitor3preview")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, len(data), r.Body.Len())
+		assert.NotEmpty(t, r.Header().Get("X-GPS"))
+	})
+	t.Run("NoVisitor", func(t *testing.T) {
+		conf.Options().ShareStripMetadata = true
+		defer func() { conf.Options().ShareStripMetadata = false }()
+
+		r := PerformRequest(app, "GET", "/api/v1/strip/unknown3preview?s=1jxf3jfn2k")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, len(data), r.Body.Len())
+	})
+	t.Run("Stripped", func(t *testing.T) {
+		conf.Options().ShareStripMetadata = true
+		defer func() { conf.Options().ShareStripMetadata = false }()
+
+		r := PerformRequest(app, "GET", "/api/v1/strip/visitor3preview")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Less(t, r.Body.Len(), len(data))
+		assert.Empty(t, r.Header().Get("X-GPS"))
+
+		found, err := thumb.JpegMetadata(r.Body.Bytes())
+
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+	})
+}
+
+func TestStripOriginal(t *testing.T) {
+	app, router, conf := NewApiTest()
+
+	router.GET("/strip-original/:token", func(c *gin.Context) {
+		if StripOriginal(c) {
+			c.Status(http.StatusOK)
+		} else {
+			c.Status(http.StatusNoContent)
+		}
+	})
+
+	entity.PreviewToken.Set("visitor4preview", entity.SessionFixtures.Get("visitor").ID)
+	defer entity.PreviewToken.Unset("visitor4preview")
+
+	t.Run("Disabled", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/strip-original/visitor4preview")
+		assert.Equal(t, http.StatusNoContent, r.Code)
+	})
+
+	conf.Options().ShareStripOriginals = true
+	defer func() { conf.Options().ShareStripOriginals = false }()
+
+	conf.SetAuthMode(config.AuthModePasswd)
+	defer conf.SetAuthMode(config.AuthModePublic)
+
+	t.Run("Visitor", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/strip-original/visitor4preview")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("ShareTokenIgnored", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/strip-original/unknown4preview?s=1jxf3jfn2k")
+		assert.Equal(t, http.StatusNoContent, r.Code)
+	})
+}
+
+func TestGetThumbStripSelfTest(t *testing.T) {
+	t.Run("Unauthorized", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetThumbStripSelfTest(router)
+		r := PerformRequest(app, "GET", "/api/v1/thumbs/selftest/metadata")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("Success", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetThumbStripSelfTest(router)
+
+		sess := AuthenticateAdmin(app, router)
+		r := AuthenticatedRequest(app, "GET", "/api/v1/thumbs/selftest/metadata", sess)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.True(t, gjson.Get(r.Body.String(), "check.passed").Bool())
+		assert.False(t, gjson.Get(r.Body.String(), "enforced").Bool())
+		assert.Equal(t, int64(0), gjson.Get(r.Body.String(), "check.thumb.#").Int())
+	})
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ThumbVariant represents a suggested crop with either its URL or a base64 encoded data URI.
+type ThumbVariant struct {
+	crop.Variant
+	Url  string `json:"url,omitempty"`
+	Data string `json:"data,omitempty"`
+}
+
+// ThumbVariantsResult represents the suggested crops of a file in a specific size.
+type ThumbVariantsResult struct {
+	Hash     string         `json:"hash"`
+	Size     crop.Size      `json:"size"`
+	Variants []ThumbVariant `json:"variants"`
+}
+
+// GetThumbVariants returns the suggested crops of an image, e.g. based on faces, the center, and the
+// part with the most detail, so that users can pick one without a request per variant, see crop.FromVariants.
+//
+// GET /api/v1/t/:thumb/:token/:size/variants
+//
+// Parameters:
+//
+//	thumb: string sha1 file hash, other hash types require a prefix like "blake3:"
+//	token: string security token
+//	size: string crop size, see crop.Sizes
+//	format: string optional, "datauri" returns base64 encoded data URIs instead of URLs
+func GetThumbVariants(router *gin.RouterGroup) {
+	router.GET("/t/:thumb/:token/:size/variants", ThumbBudget(func(c *gin.Context) {
+		if InvalidPreviewToken(c) {
+			AbortForbidden(c)
+			return
+		}
+
+		fileHash, size, ok := thumbVariantRequest(c)
+
+		if !ok {
+			return
+		}
+
+		variants, err := crop.FromVariants(fileHash, ThumbPath(fileHash), size, ThumbVariantHints(fileHash))
+
+		if err != nil {
+			log.Warnf("thumb: %s (variants)", err)
+			AbortEntityNotFound(c)
+			return
+		}
+
+		conf := get.Config()
+		token := clean.UrlToken(c.Param("token"))
+		dataUri := c.Query("format") == "datauri"
+		result := ThumbVariantsResult{Hash: fileHash, Size: size, Variants: make([]ThumbVariant, len(variants))}
+
+		for i, v := range variants {
+			result.Variants[i].Variant = v
+
+			if !dataUri {
+				result.Variants[i].Url = fmt.Sprintf("%s/variants/%s", thumb.Url(fileHash, string(size.Name), conf.ContentUri(), token), v.Selector)
+			} else if data, err := os.ReadFile(v.FileName); err != nil {
+				log.Errorf("thumb: %s (variants)", err)
+				AbortUnexpected(c)
+				return
+			} else {
+				result.Variants[i].Data = fmt.Sprintf("data:%s;base64,%s", fs.MimeTypeJPEG, base64.StdEncoding.EncodeToString(data))
+			}
+		}
+
+		// Faces may be added or moved, in which case the variants change.
+		AddCoverCacheHeader(c)
+		c.JSON(http.StatusOK, result)
+	}))
+}
+
+// GetThumbVariant returns a single suggested crop of an image, see GetThumbVariants.
+//
+// GET /api/v1/t/:thumb/:token/:size/variants/:selector
+//
+// Parameters:
+//
+//	thumb: string sha1 file hash, other hash types require a prefix like "blake3:"
+//	token: string security token
+//	size: string crop size, see crop.Sizes
+//	selector: string crop selector, see crop.VariantSelectors
+func GetThumbVariant(router *gin.RouterGroup) {
+	router.GET("/t/:thumb/:token/:size/variants/:selector", ThumbBudget(func(c *gin.Context) {
+		if InvalidPreviewToken(c) {
+			IconData(c, http.StatusForbidden, brokenIconSvg)
+			return
+		}
+
+		fileHash, size, ok := thumbVariantRequest(c)
+
+		if !ok {
+			return
+		}
+
+		fileName, err := crop.FromVariant(fileHash, ThumbPath(fileHash), size, clean.TypeLower(c.Param("selector")), ThumbVariantHints(fileHash))
+
+		if err != nil {
+			log.Warnf("thumb: %s (variant)", err)
+			ThumbIcon(c, http.StatusNotFound, brokenIconSvg)
+			return
+		}
+
+		// Faces may be added or moved, in which case the crop changes.
+		AddCoverCacheHeader(c)
+		c.File(fileName)
+	}))
+}
+
+// ThumbVariantHints returns the face areas and focus point of the file with the specified hash as hints for crop variants.
+func ThumbVariantHints(fileHash string) crop.Areas {
+	if f, err := query.FileByHash(fileHash); err == nil {
+		return CropHints(f)
+	}
+
+	return nil
+}
+
+// thumbVariantRequest returns the file hash and crop size of a variant request, or aborts it if invalid.
+func thumbVariantRequest(c *gin.Context) (fileHash string, size crop.Size, ok bool) {
+	// Resolve other hash types like "blake3:..." to the sha1 hash thumbnails are addressed by.
+	fileHash, err := ThumbFileHash(clean.Token(c.Param("thumb")))
+
+	if err != nil {
+		AbortEntityNotFound(c)
+		return "", size, false
+	}
+
+	if size, ok = crop.Sizes[crop.Name(clean.Token(c.Param("size")))]; !ok {
+		AbortBadRequest(c)
+		return "", size, false
+	}
+
+	return fileHash, size, true
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+func TestGetThumbVariants(t *testing.T) {
+	t.Run("WrongToken", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetThumbVariants(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/xxx/tile_224/variants")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("InvalidSize", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumbVariants(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/"+conf.PreviewToken()+"/fit_720/variants")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Blake3", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumbVariants(router)
+
+		// Fixture that is not modified by other tests, the hash is removed afterwards.
+		f := entity.FileFixtures.Pointer("Photo14.jpg")
+		hash := "c5a1b2e3f4d5c6b7a8f9e0d1c2b3a4f5e6d7c8b9a0f1e2d3c4b5a6f7e8d9c0b1"
+
+		if err := f.Update("FileHashBlake3", hash); err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Update("FileHashBlake3", "")
+
+		// Unknown hashes return 404, so the size is only checked if the hash was resolved.
+		r := PerformRequest(app, "GET", "/api/v1/t/blake3:"+hash+"/"+conf.PreviewToken()+"/fit_720/variants")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumbVariants(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/0000000000000000000000000000000000000000/"+conf.PreviewToken()+"/tile_224/variants")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
+
+func TestGetThumbVariant(t *testing.T) {
+	t.Run("WrongToken", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetThumbVariant(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/xxx/tile_224/variants/face")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		GetThumbVariant(router)
+		r := PerformRequest(app, "GET", "/api/v1/t/0000000000000000000000000000000000000000/"+conf.PreviewToken()+"/tile_224/variants/face?strict=true")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
@@ -28,7 +28,7 @@ import (
 func GetVideo(router *gin.RouterGroup) {
 	router.GET("/videos/:hash/:token/:format", func(c *gin.Context) {
 		if InvalidPreviewToken(c) {
-			c.Data(http.StatusForbidden, "image/svg+xml", brokenIconSvg)
+			IconData(c, http.StatusForbidden, brokenIconSvg)
 			return
 		}
 
@@ -39,7 +39,7 @@ func GetVideo(router *gin.RouterGroup) {
 
 		if !ok {
 			log.Errorf("video: invalid format %s", clean.Log(formatName))
-			c.Data(http.StatusOK, "image/svg+xml", videoIconSvg)
+			IconData(c, http.StatusOK, videoIconSvg)
 			return
 		}
 
@@ -47,7 +47,7 @@ func GetVideo(router *gin.RouterGroup) {
 
 		if err != nil {
 			log.Errorf("video: requested file not found (%s)", err)
-			c.Data(http.StatusOK, "image/svg+xml", videoIconSvg)
+			IconData(c, http.StatusOK, videoIconSvg)
 			return
 		}
 
@@ -56,18 +56,18 @@ func GetVideo(router *gin.RouterGroup) {
 
 			if err != nil {
 				log.Errorf("video: no playable file found (%s)", err)
-				c.Data(http.StatusOK, "image/svg+xml", videoIconSvg)
+				IconData(c, http.StatusOK, videoIconSvg)
 				return
 			}
 		}
 
 		if f.FileError != "" {
 			log.Errorf("video: file has error %s", f.FileError)
-			c.Data(http.StatusOK, "image/svg+xml", videoIconSvg)
+			IconData(c, http.StatusOK, videoIconSvg)
 			return
 		} else if f.FileHash == "" {
 			log.Errorf("video: file hash missing in index")
-			c.Data(http.StatusOK, "image/svg+xml", videoIconSvg)
+			IconData(c, http.StatusOK, videoIconSvg)
 			return
 		}
 
@@ -26,6 +26,13 @@ var ThumbsCommand = cli.Command{
 			Usage: "scan originals only, skip sidecar folder",
 		},
 	},
+	Subcommands: []cli.Command{
+		ThumbsCheckCommand,
+		ThumbsReconcileCommand,
+		ThumbsNormalizeCommand,
+		ThumbsReshardCommand,
+		ThumbsExportCommand,
+	},
 	Action: thumbsAction,
 }
 
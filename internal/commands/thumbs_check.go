@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/report"
+)
+
+// ThumbsCheckCommand configures the command name, flags, and action.
+var ThumbsCheckCommand = cli.Command{
+	Name:      "check",
+	Usage:     "Checks if thumbnails can be created for originals without writing any files",
+	ArgsUsage: "[subfolder]",
+	Flags: append(report.CliFlags,
+		cli.StringFlag{
+			Name:  "type",
+			Usage: "comma-separated list of file `TYPES` to check, e.g. raw,heic",
+		},
+		cli.BoolFlag{
+			Name:  "failed, e",
+			Usage: "show only files for which thumbnails cannot be created",
+		},
+	),
+	Action: thumbsCheckAction,
+}
+
+// thumbsCheckAction checks if thumbnails can be created for originals.
+func thumbsCheckAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	conf, err := InitConfig(ctx)
+
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	dir := strings.TrimSpace(ctx.Args().First())
+
+	var types []string
+
+	if s := strings.TrimSpace(ctx.String("type")); s != "" {
+		types = strings.Split(s, ",")
+	}
+
+	if dir == "" {
+		log.Infof("checking thumbnails for originals")
+	} else {
+		log.Infof("checking thumbnails for originals in %s", clean.LogQuote(dir))
+	}
+
+	results, err := get.Thumbs().Check(dir, types)
+
+	if err != nil {
+		return err
+	}
+
+	cols := []string{"File", "Type", "Method", "Error"}
+	rows := make([][]string, 0, len(results))
+
+	for _, r := range results {
+		if r.Error != nil {
+			rows = append(rows, []string{r.FileName, r.FileType, r.Method, r.Error.Error()})
+		} else if !ctx.Bool("failed") {
+			rows = append(rows, []string{r.FileName, r.FileType, r.Method, ""})
+		}
+	}
+
+	result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+	fmt.Println(result)
+
+	log.Infof("checked %d files, %d failed [%s]", len(results), results.Failed(), time.Since(start))
+
+	return err
+}
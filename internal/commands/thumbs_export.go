@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ThumbsExportCommand configures the command name, flags, and action.
+var ThumbsExportCommand = cli.Command{
+	Name:      "export",
+	Usage:     "Renders thumbnails of selected pictures into a static export folder with a JSON index",
+	ArgsUsage: "[path]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "sizes, s",
+			Usage: "comma-separated list of thumbnail `SIZES` to export",
+			Value: "tile_224,tile_500,fit_720,fit_1920",
+		},
+		cli.StringFlag{
+			Name:  "formats",
+			Usage: "comma-separated list of image `FORMATS` to export, e.g. jpg,webp,avif",
+			Value: "jpg",
+		},
+		cli.StringSliceFlag{
+			Name:  "album, a",
+			Usage: "export pictures in the album with the specified `UID`",
+		},
+		cli.StringSliceFlag{
+			Name:  "label, l",
+			Usage: "export pictures with the label that has the specified `UID`",
+		},
+		cli.StringSliceFlag{
+			Name:  "photo, p",
+			Usage: "export the picture with the specified `UID`",
+		},
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "replace previously exported thumbnails instead of resuming",
+		},
+	},
+	Action: thumbsExportAction,
+}
+
+// thumbsExportAction renders thumbnails of selected pictures into a static export folder.
+func thumbsExportAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	dir := strings.TrimSpace(ctx.Args().First())
+
+	if dir == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	sel := form.Selection{
+		Albums: ctx.StringSlice("album"),
+		Labels: ctx.StringSlice("label"),
+		Photos: ctx.StringSlice("photo"),
+	}
+
+	if sel.Empty() {
+		return errors.New("no pictures selected, specify at least one album, label, or photo uid")
+	}
+
+	opt := photoprism.ThumbsExportOptions{Force: ctx.Bool("force")}
+
+	for _, s := range strings.Split(ctx.String("sizes"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			opt.Sizes = append(opt.Sizes, thumb.Name(s))
+		}
+	}
+
+	for _, s := range strings.Split(ctx.String("formats"), ",") {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			opt.Formats = append(opt.Formats, fs.Type(s))
+		}
+	}
+
+	conf, err := InitConfig(ctx)
+
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err != nil {
+		return err
+	}
+
+	conf.RegisterDb()
+	defer conf.Shutdown()
+
+	log.Infof("exporting thumbnails to %s", clean.Log(dir))
+
+	result, err := get.Thumbs().Export(dir, sel, opt)
+
+	if err != nil {
+		return err
+	}
+
+	log.Infof("exported %s of %s, %s skipped in %s",
+		english.Plural(result.Created, "thumbnail", "thumbnails"),
+		english.Plural(len(result.Files), "picture", "pictures"),
+		english.Plural(result.Skipped, "existing file", "existing files"),
+		time.Since(start))
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// ThumbsNormalizeCommand configures the command name, flags, and action.
+var ThumbsNormalizeCommand = cli.Command{
+	Name:      "normalize",
+	Usage:     "Bakes the orientation of rotated originals into upright copies and replaces their thumbnails",
+	ArgsUsage: "[subfolder]",
+	Action:    thumbsNormalizeAction,
+}
+
+// thumbsNormalizeAction normalizes the orientation of indexed files and replaces their thumbnails.
+func thumbsNormalizeAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	conf, err := InitConfig(ctx)
+
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err != nil {
+		return err
+	}
+
+	conf.RegisterDb()
+	defer conf.Shutdown()
+
+	if !conf.ThumbNormalize() {
+		log.Warnf("orientation normalization must be enabled with --thumb-normalize first")
+		return nil
+	}
+
+	dir := strings.TrimSpace(ctx.Args().First())
+
+	if dir == "" {
+		log.Infof("normalizing orientation of all originals")
+	} else {
+		log.Infof("normalizing orientation of originals in %s", clean.LogQuote(dir))
+	}
+
+	count, err := get.Thumbs().Normalize(dir)
+
+	if err != nil {
+		return err
+	}
+
+	log.Infof("normalized %s in %s", english.Plural(count, "file", "files"), time.Since(start))
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/pkg/report"
+)
+
+// ThumbsReconcileCommand configures the command name, flags, and action.
+var ThumbsReconcileCommand = cli.Command{
+	Name:  "reconcile",
+	Usage: "Finds indexed files without thumbnails and orphaned thumbnails",
+	Flags: append(report.CliFlags,
+		cli.BoolFlag{
+			Name:  "repair",
+			Usage: "create missing thumbnails and remove orphaned thumbnails",
+		},
+	),
+	Action: thumbsReconcileAction,
+}
+
+// thumbsReconcileAction finds and optionally repairs differences between the index and the thumbnail cache.
+func thumbsReconcileAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	conf, err := InitConfig(ctx)
+
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err != nil {
+		return err
+	}
+
+	conf.RegisterDb()
+	defer conf.Shutdown()
+
+	repair := ctx.Bool("repair")
+
+	if repair {
+		log.Infof("reconciling thumbnails with the index")
+	} else {
+		log.Infof("comparing thumbnails with the index")
+	}
+
+	result, err := get.Thumbs().Reconcile(repair)
+
+	if err != nil {
+		return err
+	}
+
+	cols := []string{"Name", "Problem"}
+	rows := make([][]string, 0, result.Found())
+
+	for _, name := range result.Missing {
+		rows = append(rows, []string{name, "missing thumbnails"})
+	}
+
+	for _, name := range result.Orphans {
+		rows = append(rows, []string{name, "orphaned thumbnail"})
+	}
+
+	output, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+	fmt.Println(output)
+
+	if repair {
+		log.Infof("checked %d files, %d missing, %d orphaned, %d repaired, %d removed [%s]",
+			result.Files, len(result.Missing), len(result.Orphans), result.Repaired, result.Removed, time.Since(start))
+	} else {
+		log.Infof("checked %d files, %d missing, %d orphaned [%s]",
+			result.Files, len(result.Missing), len(result.Orphans), time.Since(start))
+	}
+
+	return err
+}
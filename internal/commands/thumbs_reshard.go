@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// ThumbsReshardCommand configures the command name, flags, and action.
+var ThumbsReshardCommand = cli.Command{
+	Name:   "reshard",
+	Usage:  "Moves cached thumbnails to the subfolders of the current scheme, see --thumb-sharding",
+	Action: thumbsReshardAction,
+}
+
+// thumbsReshardAction moves cached thumbnails to the subfolders of the current scheme.
+func thumbsReshardAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	conf, err := InitConfig(ctx)
+
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	thumbPath := conf.ThumbCachePath()
+
+	log.Infof("moving thumbnails in %s to subfolders %s", clean.Log(thumbPath), thumb.ShardingString(conf.ThumbSharding()))
+
+	moved, err := thumb.Reshard(thumbPath)
+
+	if err != nil {
+		return err
+	}
+
+	log.Infof("moved %s in %s", english.Plural(moved, "file", "files"), time.Since(start))
+
+	return nil
+}
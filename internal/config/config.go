@@ -24,6 +24,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
+	"github.com/photoprism/photoprism/internal/crop"
 	"github.com/photoprism/photoprism/internal/customize"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
@@ -32,6 +33,8 @@ import (
 	"github.com/photoprism/photoprism/internal/hub/places"
 	"github.com/photoprism/photoprism/internal/i18n"
 	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/server/limiter"
 	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/internal/ttl"
 	"github.com/photoprism/photoprism/pkg/clean"
@@ -181,7 +184,88 @@ func (c *Config) Propagate() {
 	thumb.SizeUncached = c.ThumbSizeUncached()
 	thumb.Filter = c.ThumbFilter()
 	thumb.JpegQuality = c.JpegQuality()
+	thumb.JpegEncoder = c.JpegEncoder()
+	thumb.CjpegBin = c.CjpegBin()
+	thumb.CwebpBin = c.CwebpBin()
+	thumb.AvifencBin = c.AvifencBin()
+	thumb.FormatQuality = c.FormatQuality()
 	thumb.CachePublic = c.HttpCachePublic()
+	thumb.DocumentRatio = c.ThumbDocumentRatio()
+	thumb.DocumentEdges = c.ThumbDocumentEdges()
+	thumb.LevelsSizes = make(map[thumb.Name]bool)
+
+	for _, name := range c.ThumbLevels() {
+		thumb.LevelsSizes[thumb.Name(name)] = true
+	}
+
+	grayNames := make([]thumb.Name, 0, len(c.ThumbGray()))
+
+	for _, name := range c.ThumbGray() {
+		grayNames = append(grayNames, thumb.Name(name))
+	}
+
+	thumb.SetGray(grayNames)
+
+	// Downscale large images in steps for these sizes.
+	stepsNames := make([]thumb.Name, 0, len(c.ThumbSteps()))
+
+	for _, name := range c.ThumbSteps() {
+		stepsNames = append(stepsNames, thumb.Name(name))
+	}
+
+	thumb.SetSteps(stepsNames)
+
+	// Crop these sizes to the part with the most detail instead of the center.
+	entropyThumbs := make([]thumb.Name, 0, len(c.ThumbEntropy()))
+	entropyCrops := make([]crop.Name, 0, len(c.ThumbEntropy()))
+
+	for _, name := range c.ThumbEntropy() {
+		entropyThumbs = append(entropyThumbs, thumb.Name(name))
+		entropyCrops = append(entropyCrops, crop.Name(name))
+	}
+
+	thumb.SetEntropy(entropyThumbs)
+	crop.SetEntropy(entropyCrops)
+
+	// Warn if the fallback list contains unknown thumbnail sizes.
+	for _, name := range strings.Split(c.options.ThumbFallback, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		} else if _, ok := thumb.Sizes[thumb.Name(strings.ToLower(name))]; !ok {
+			log.Warnf("config: unknown thumbnail size %s in fallback list", clean.LogQuote(name))
+		}
+	}
+
+	thumb.XmpPreview = c.ThumbXmpPreview()
+	thumb.UseEmbedded = c.ThumbEmbedded()
+	thumb.IconFormat = c.ThumbIconFormat()
+	thumb.RemoteOriginals = c.ThumbRemote()
+	thumb.RemoteSizeLimit = int64(c.ThumbRemoteLimit()) * 1024 * 1024
+	thumb.Layout = c.ThumbLayout()
+	thumb.Sharding = c.ThumbSharding()
+	thumb.DecodeLimit = c.ThumbDecodeLimit()
+	thumb.DecodeMemLimit = thumb.Bytes(c.ThumbDecodeMem()) * thumb.MB
+	thumb.MigrateLegacy = c.ThumbMigrate()
+	thumb.Normalize = c.ThumbNormalize()
+	thumb.MaxPixels = int64(c.ThumbMaxPixels()) * 1000 * 1000
+	thumb.DownsizeThreshold = c.ThumbDownsize()
+	thumb.Stream = c.ThumbStream()
+	thumb.UncachedTTL = c.ThumbUncachedTTL()
+	thumb.Policies = c.ThumbPolicy()
+	thumb.Gif = c.ThumbGif()
+	thumb.Accel = c.ThumbAccel()
+	thumb.AccelBin = c.FFmpegBin()
+	thumb.MapTileUrl = c.ThumbMapUrl()
+	thumb.MapZoom = c.ThumbMapZoom()
+	thumb.MapUserAgent = fmt.Sprintf("%s/%s", c.Name(), c.Version())
+	limiter.Thumbs.SetLimit(int64(c.ThumbBudget()) * 1024 * 1024)
+
+	// Set the strategy for thumbnails with a target aspect ratio.
+	crop.AspectStrategy = c.ThumbAspect()
+	crop.AspectMaxLoss = float64(c.ThumbAspectLoss()) / 100
+
+	// Set the order in which files with the same hash are selected.
+	query.HashOrder = c.ThumbHashOrder()
 
 	// Set cache expiration defaults.
 	ttl.Default = c.HttpCacheMaxAge()
@@ -197,6 +281,16 @@ func (c *Config) Propagate() {
 	// Set path for user assets.
 	entity.UsersPath = c.UsersPath()
 
+	// Set download file name template.
+	if tmpl := strings.TrimSpace(c.options.DownloadTemplate); tmpl == "" {
+		entity.DownloadTemplate = ""
+	} else if err := entity.ValidDownloadTemplate(tmpl); err != nil {
+		log.Warnf("config: %s in download template %s", err, clean.LogQuote(tmpl))
+		entity.DownloadTemplate = ""
+	} else {
+		entity.DownloadTemplate = tmpl
+	}
+
 	// Set API preview and download default tokens.
 	entity.PreviewToken.Set(c.PreviewToken(), entity.TokenConfig)
 	entity.DownloadToken.Set(c.DownloadToken(), entity.TokenConfig)
@@ -282,10 +376,15 @@ func (c *Config) Init() error {
 
 	c.initSettings()
 	c.initHub()
+	c.initThumbPins()
+	c.initThumbBudget()
 
 	// Propagate configuration.
 	c.Propagate()
 
+	// Detect changed thumbnail quality settings.
+	c.initThumbQuality()
+
 	// Connect to database.
 	if err := c.connectDb(); err != nil {
 		return err
@@ -460,8 +559,25 @@ func (c *Config) CdnVideo() bool {
 	return c.options.CdnVideo
 }
 
+// ContentUrl returns the optional external base URL or path of thumbnails and other content without trailing slash.
+func (c *Config) ContentUrl() string {
+	s := strings.TrimSpace(c.options.ContentUrl)
+
+	if s == "" {
+		return ""
+	} else if u, err := url.Parse(s); err != nil || u.Host == "" && !strings.HasPrefix(s, "/") {
+		return ""
+	}
+
+	return strings.TrimRight(s, "/")
+}
+
 // ContentUri returns the content delivery URI.
 func (c *Config) ContentUri() string {
+	if contentUrl := c.ContentUrl(); contentUrl != "" {
+		return contentUrl + ApiUri
+	}
+
 	return c.CdnUrl(c.ApiUri())
 }
 
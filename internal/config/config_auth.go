@@ -191,6 +191,12 @@ func (c *Config) PreviewToken() string {
 	return c.options.PreviewToken
 }
 
+// PreviewTokenLocal checks if requests without preview token are allowed from localhost and private networks.
+// Requests with a wrong token are rejected in any case.
+func (c *Config) PreviewTokenLocal() bool {
+	return c.options.PreviewTokenLocal
+}
+
 // InvalidPreviewToken checks if the preview token is invalid.
 func (c *Config) InvalidPreviewToken(t string) bool {
 	return entity.InvalidPreviewToken(t)
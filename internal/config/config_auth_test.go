@@ -146,3 +146,12 @@ func TestConfig_InvalidPreviewToken(t *testing.T) {
 
 	assert.True(t, c.InvalidPreviewToken("xxx"))
 }
+
+func TestConfig_PreviewTokenLocal(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.PreviewTokenLocal())
+	c.options.PreviewTokenLocal = true
+	assert.True(t, c.PreviewTokenLocal())
+	c.options.PreviewTokenLocal = false
+}
@@ -11,6 +11,8 @@ var Cache = gc.New(time.Hour, 15*time.Minute)
 const (
 	CacheKeyAppManifest  = "app-manifest"
 	CacheKeyWallpaperUri = "wallpaper-uri"
+	CacheKeyThumbTTL     = "thumb-uncached-ttl"
+	CacheKeyThumbPolicy  = "thumb-policy"
 )
 
 // FlushCache clears the config cache.
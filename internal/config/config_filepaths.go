@@ -215,6 +215,21 @@ func (c *Config) HubConfigFile() string {
 	return filepath.Join(c.ConfigPath(), "hub.yml")
 }
 
+// ThumbPinsYaml returns the filename of the pinned thumbnails list.
+func (c *Config) ThumbPinsYaml() string {
+	return filepath.Join(c.ConfigPath(), "pins.yml")
+}
+
+// ThumbQualityStamp returns the filename of the JPEG quality settings stamp, see thumb.CheckQuality.
+func (c *Config) ThumbQualityStamp() string {
+	return filepath.Join(c.ThumbCachePath(), ".quality")
+}
+
+// ThumbBudgetYaml returns the filename of the thumbnail budget usage, see limiter.Thumbs.
+func (c *Config) ThumbBudgetYaml() string {
+	return filepath.Join(c.CachePath(), "budget.yml")
+}
+
 // SettingsYaml returns the settings YAML filename.
 func (c *Config) SettingsYaml() string {
 	return filepath.Join(c.ConfigPath(), "settings.yml")
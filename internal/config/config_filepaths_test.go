@@ -454,3 +454,21 @@ func TestConfig_SettingsYamlDefaults(t *testing.T) {
 	assert.NotEqual(t, c.SettingsYaml(), name1)
 	assert.NotEqual(t, c.SettingsYaml(), name3)
 }
+
+func TestConfig_ThumbPinsYaml(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, c.ConfigPath()+"/pins.yml", c.ThumbPinsYaml())
+}
+
+func TestConfig_ThumbBudgetYaml(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, c.CachePath()+"/budget.yml", c.ThumbBudgetYaml())
+}
+
+func TestConfig_ThumbQualityStamp(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, c.ThumbCachePath()+"/.quality", c.ThumbQualityStamp())
+}
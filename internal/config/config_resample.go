@@ -2,8 +2,13 @@ package config
 
 import (
 	"strings"
+	"time"
 
+	gc "github.com/patrickmn/go-cache"
+
+	"github.com/photoprism/photoprism/internal/crop"
 	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/list"
 )
 
 // JpegSize returns the size limit for automatically converted files in `PIXELS` (720-30000).
@@ -33,6 +38,41 @@ func (c *Config) JpegQuality() thumb.Quality {
 	return thumb.ParseQuality(c.options.JpegQuality)
 }
 
+// JpegEncoder returns the JPEG encoder backend for thumbnails, see thumb.ParseEncoder.
+// The standard library encoder is used if the cjpeg command of the native encoder was not found.
+func (c *Config) JpegEncoder() string {
+	if encoder := thumb.ParseEncoder(c.options.JpegEncoder); encoder == thumb.EncoderStd || c.CjpegBin() == "" {
+		return thumb.EncoderStd
+	} else {
+		return encoder
+	}
+}
+
+// CjpegBin returns the cjpeg executable file name of the native JPEG encoder. Since mozjpeg is usually
+// installed alongside libjpeg-turbo, it is expected in /opt/mozjpeg by default.
+func (c *Config) CjpegBin() string {
+	if thumb.ParseEncoder(c.options.JpegEncoder) == thumb.EncoderMozjpeg {
+		return findBin(c.options.CjpegBin, "/opt/mozjpeg/bin/cjpeg")
+	}
+
+	return findBin(c.options.CjpegBin, "cjpeg")
+}
+
+// CwebpBin returns the cwebp executable file name of the WebP encoder, or an empty string if it was not found.
+func (c *Config) CwebpBin() string {
+	return findBin(c.options.CwebpBin, "cwebp")
+}
+
+// AvifencBin returns the avifenc executable file name of the AVIF encoder, or an empty string if it was not found.
+func (c *Config) AvifencBin() string {
+	return findBin(c.options.AvifencBin, "avifenc")
+}
+
+// FormatQuality returns the WebP and AVIF quality mode, see thumb.ParseFormatQuality.
+func (c *Config) FormatQuality() string {
+	return thumb.ParseFormatQuality(c.options.FormatQuality)
+}
+
 // ThumbFilter returns the thumbnail resample filter (best to worst: blackman, lanczos, cubic or linear).
 func (c *Config) ThumbFilter() thumb.ResampleFilter {
 	switch strings.ToLower(c.options.ThumbFilter) {
@@ -49,6 +89,66 @@ func (c *Config) ThumbFilter() thumb.ResampleFilter {
 	}
 }
 
+// ThumbLevels returns the names of the thumbnail sizes to which conservative auto levels are applied.
+func (c *Config) ThumbLevels() []string {
+	return thumbSizeNames(c.options.ThumbLevels)
+}
+
+// ThumbGray returns the names of the thumbnail sizes that are created in grayscale.
+func (c *Config) ThumbGray() []string {
+	return thumbSizeNames(c.options.ThumbGray)
+}
+
+// ThumbSteps returns the names of the thumbnail sizes for which large images are downscaled in steps.
+func (c *Config) ThumbSteps() []string {
+	return thumbSizeNames(c.options.ThumbSteps)
+}
+
+// ThumbEntropy returns the names of the thumbnail and crop sizes that show the part of the image with the most
+// detail instead of the center if no crop area is stored, see thumb.FillEntropy.
+func (c *Config) ThumbEntropy() (result []string) {
+	result = []string{}
+
+	for _, name := range strings.Split(strings.ToLower(c.options.ThumbEntropy), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		} else if _, ok := thumb.Sizes[thumb.Name(name)]; ok {
+			result = append(result, name)
+		} else if _, ok = crop.Sizes[crop.Name(name)]; ok {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// thumbSizeNames returns the valid thumbnail size names in a comma-separated list, or all names if it is "all".
+func thumbSizeNames(s string) (result []string) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if s == "" {
+		return []string{}
+	} else if s == "all" {
+		for _, name := range thumb.Names {
+			result = append(result, name.String())
+		}
+
+		return result
+	}
+
+	result = []string{}
+
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		} else if _, ok := thumb.Sizes[thumb.Name(name)]; ok {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
 // ThumbColor returns the color profile name for thumbnails.
 func (c *Config) ThumbColor() string {
 	return c.options.ThumbColor
@@ -64,6 +164,62 @@ func (c *Config) ThumbUncached() bool {
 	return c.options.ThumbUncached
 }
 
+// ThumbUncachedTTL returns the time after which thumbnails of uncached sizes expire by size name, see thumb.UncachedTTL.
+// The option is only parsed and validated again if it changes or the config cache is flushed, see FlushCache.
+func (c *Config) ThumbUncachedTTL() map[thumb.Name]time.Duration {
+	cacheKey := CacheKeyThumbTTL + ":" + c.options.ThumbUncachedTTL
+
+	if cacheData, ok := Cache.Get(cacheKey); ok {
+		return cacheData.(map[thumb.Name]time.Duration)
+	}
+
+	result, err := thumb.ParseUncachedTTL(c.options.ThumbUncachedTTL)
+
+	if err != nil {
+		log.Warnf("config: %s", err)
+		result = map[thumb.Name]time.Duration{}
+	}
+
+	Cache.Set(cacheKey, result, gc.NoExpiration)
+
+	return result
+}
+
+// ThumbPolicy returns the thumbnail creation policy by size name, see thumb.Policies.
+// The option is only parsed and validated again if it changes or the config cache is flushed, see FlushCache.
+func (c *Config) ThumbPolicy() map[thumb.Name]thumb.Policy {
+	cacheKey := CacheKeyThumbPolicy + ":" + c.options.ThumbPolicy
+
+	if cacheData, ok := Cache.Get(cacheKey); ok {
+		return cacheData.(map[thumb.Name]thumb.Policy)
+	}
+
+	result, err := thumb.ParsePolicies(c.options.ThumbPolicy)
+
+	if err != nil {
+		log.Warnf("config: %s", err)
+		result = map[thumb.Name]thumb.Policy{}
+	}
+
+	Cache.Set(cacheKey, result, gc.NoExpiration)
+
+	return result
+}
+
+// ThumbAccel returns the hardware acceleration backend for resizing thumbnails, see thumb.ParseAccel,
+// or "none" if it is disabled or FFmpeg is not available. "auto" selects the first backend supported by FFmpeg.
+func (c *Config) ThumbAccel() string {
+	accel := thumb.ParseAccel(c.options.ThumbAccel)
+
+	if accel == thumb.AccelNone || c.DisableFFmpeg() || c.FFmpegBin() == "" {
+		return thumb.AccelNone
+	} else if accel == thumb.AccelAuto {
+		return thumb.DetectAccel(c.FFmpegBin())
+	}
+
+	return accel
+}
+
 // ThumbSizePrecached returns the pre-cached thumbnail size limit in pixels (720-7680).
 func (c *Config) ThumbSizePrecached() int {
 	size := c.options.ThumbSize
@@ -93,3 +249,39 @@ func (c *Config) ThumbSizeUncached() int {
 
 	return limit
 }
+
+// ThumbDocumentRatio returns the minimum aspect ratio of documents whose tiles are padded instead of cropped.
+func (c *Config) ThumbDocumentRatio() float64 {
+	if c.options.ThumbDocumentRatio < 1 {
+		return thumb.DocumentRatio
+	}
+
+	return c.options.ThumbDocumentRatio
+}
+
+// ThumbDocumentEdges returns the minimum share of edge pixels of documents whose tiles are padded instead of cropped.
+func (c *Config) ThumbDocumentEdges() float64 {
+	if c.options.ThumbDocumentEdges <= 0 {
+		return 0
+	} else if c.options.ThumbDocumentEdges > 1 {
+		return 1
+	}
+
+	return c.options.ThumbDocumentEdges
+}
+
+// ThumbFallback returns the ordered list of precached thumbnail sizes to use if uncached sizes are disabled.
+// Sizes that do not exist are ignored.
+func (c *Config) ThumbFallback() []string {
+	result := []string{}
+
+	for _, name := range strings.Split(strings.ToLower(c.options.ThumbFallback), ",") {
+		if name = strings.TrimSpace(name); name == "" || list.Contains(result, name) {
+			continue
+		} else if _, ok := thumb.Sizes[thumb.Name(name)]; ok {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/stretchr/testify/assert"
@@ -43,6 +44,24 @@ func TestConfig_JpegQuality(t *testing.T) {
 	assert.Equal(t, thumb.QualityDefault, c.JpegQuality())
 }
 
+func TestConfig_JpegEncoder(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, thumb.EncoderStd, c.JpegEncoder())
+	c.options.JpegEncoder = "MozJPEG"
+	c.options.CjpegBin = "/nonexistent/cjpeg"
+	assert.Equal(t, thumb.EncoderStd, c.JpegEncoder())
+	c.options.CjpegBin = "/bin/sh"
+	assert.Equal(t, thumb.EncoderMozjpeg, c.JpegEncoder())
+	assert.Equal(t, "/bin/sh", c.CjpegBin())
+	c.options.JpegEncoder = "libjpeg-turbo"
+	assert.Equal(t, thumb.EncoderTurbo, c.JpegEncoder())
+	c.options.JpegEncoder = "invalid"
+	assert.Equal(t, thumb.EncoderStd, c.JpegEncoder())
+	c.options.JpegEncoder = ""
+	c.options.CjpegBin = ""
+}
+
 func TestConfig_ThumbFilter(t *testing.T) {
 	c := NewConfig(CliTestContext())
 
@@ -63,6 +82,54 @@ func TestConfig_ThumbSizeUncached(t *testing.T) {
 	assert.False(t, c.ThumbUncached())
 }
 
+func TestConfig_ThumbUncachedTTL(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Empty(t, c.ThumbUncachedTTL())
+	c.options.ThumbUncachedTTL = "30m,fit_7680=10m"
+	assert.Equal(t, map[thumb.Name]time.Duration{"": 30 * time.Minute, thumb.Fit7680: 10 * time.Minute}, c.ThumbUncachedTTL())
+	c.options.ThumbUncachedTTL = "fit_1=10m"
+	assert.Empty(t, c.ThumbUncachedTTL())
+	_, cached := Cache.Get(CacheKeyThumbTTL + ":fit_1=10m")
+	assert.True(t, cached)
+	c.options.ThumbUncachedTTL = ""
+}
+
+func TestConfig_ThumbPolicy(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Empty(t, c.ThumbPolicy())
+	c.options.ThumbPolicy = "fit_2560=precache, Fit_7680=Never"
+	assert.Equal(t, map[thumb.Name]thumb.Policy{thumb.Fit2560: thumb.PolicyPrecache, thumb.Fit7680: thumb.PolicyNever}, c.ThumbPolicy())
+	c.options.ThumbPolicy = "fit_1=never"
+	assert.Empty(t, c.ThumbPolicy())
+	c.options.ThumbPolicy = "fit_7680=sometimes"
+	assert.Empty(t, c.ThumbPolicy())
+	_, cached := Cache.Get(CacheKeyThumbPolicy + ":fit_7680=sometimes")
+	assert.True(t, cached)
+	c.options.ThumbPolicy = ""
+}
+
+func TestConfig_ThumbAccel(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, thumb.AccelNone, c.ThumbAccel())
+	c.options.ThumbAccel = "invalid"
+	assert.Equal(t, thumb.AccelNone, c.ThumbAccel())
+	c.options.ThumbAccel = "CUDA"
+
+	if c.FFmpegBin() == "" {
+		assert.Equal(t, thumb.AccelNone, c.ThumbAccel())
+	} else {
+		assert.Equal(t, thumb.AccelCuda, c.ThumbAccel())
+	}
+
+	c.options.DisableFFmpeg = true
+	assert.Equal(t, thumb.AccelNone, c.ThumbAccel())
+	c.options.DisableFFmpeg = false
+	c.options.ThumbAccel = ""
+}
+
 func TestConfig_ThumbSize(t *testing.T) {
 	c := NewConfig(CliTestContext())
 
@@ -81,3 +148,106 @@ func TestConfig_ThumbSizeUncached2(t *testing.T) {
 	c.options.ThumbSize = 900
 	assert.Equal(t, int(900), c.ThumbSizeUncached())
 }
+
+func TestConfig_ThumbDocumentRatio(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, thumb.DocumentRatio, c.ThumbDocumentRatio())
+	c.options.ThumbDocumentRatio = 1.5
+	assert.Equal(t, 1.5, c.ThumbDocumentRatio())
+	c.options.ThumbDocumentRatio = 0.5
+	assert.Equal(t, thumb.DocumentRatio, c.ThumbDocumentRatio())
+}
+
+func TestConfig_ThumbDocumentEdges(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.ThumbDocumentEdges = 0.2
+	assert.Equal(t, 0.2, c.ThumbDocumentEdges())
+	c.options.ThumbDocumentEdges = 2
+	assert.Equal(t, 1.0, c.ThumbDocumentEdges())
+	c.options.ThumbDocumentEdges = -1
+	assert.Equal(t, 0.0, c.ThumbDocumentEdges())
+}
+
+func TestConfig_ThumbLevels(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, []string{}, c.ThumbLevels())
+	c.options.ThumbLevels = "tile_500, fit_720,foo"
+	assert.Equal(t, []string{"tile_500", "fit_720"}, c.ThumbLevels())
+	c.options.ThumbLevels = "all"
+	assert.Len(t, c.ThumbLevels(), len(thumb.Names))
+	c.options.ThumbLevels = ""
+}
+
+func TestConfig_ThumbGray(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, []string{}, c.ThumbGray())
+	c.options.ThumbGray = "Tile_500,fit_720, bar"
+	assert.Equal(t, []string{"tile_500", "fit_720"}, c.ThumbGray())
+	c.options.ThumbGray = "all"
+	assert.Len(t, c.ThumbGray(), len(thumb.Names))
+	c.options.ThumbGray = ""
+}
+
+func TestConfig_ThumbEntropy(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, []string{}, c.ThumbEntropy())
+	c.options.ThumbEntropy = "Tile_224, wide_640,foo,fit_720"
+	assert.Equal(t, []string{"tile_224", "wide_640", "fit_720"}, c.ThumbEntropy())
+	c.options.ThumbEntropy = ""
+}
+
+func TestConfig_ThumbSteps(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, []string{}, c.ThumbSteps())
+	c.options.ThumbSteps = "tile_50, Tile_100,foo"
+	assert.Equal(t, []string{"tile_50", "tile_100"}, c.ThumbSteps())
+	c.options.ThumbSteps = "all"
+	assert.Len(t, c.ThumbSteps(), len(thumb.Names))
+	c.options.ThumbSteps = ""
+}
+
+func TestConfig_ThumbFallback(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, []string{}, c.ThumbFallback())
+	c.options.ThumbFallback = "fit_1920, FIT_1280,foo,fit_1920,fit_720"
+	assert.Equal(t, []string{"fit_1920", "fit_1280", "fit_720"}, c.ThumbFallback())
+	c.options.ThumbFallback = ""
+}
+
+func TestConfig_CwebpBin(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.CwebpBin = "/nonexistent/cwebp"
+	assert.Equal(t, "", c.CwebpBin())
+	c.options.CwebpBin = "/bin/sh"
+	assert.Equal(t, "/bin/sh", c.CwebpBin())
+	c.options.CwebpBin = ""
+}
+
+func TestConfig_AvifencBin(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.AvifencBin = "/nonexistent/avifenc"
+	assert.Equal(t, "", c.AvifencBin())
+	c.options.AvifencBin = "/bin/sh"
+	assert.Equal(t, "/bin/sh", c.AvifencBin())
+	c.options.AvifencBin = ""
+}
+
+func TestConfig_FormatQuality(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, thumb.QualityFixed, c.FormatQuality())
+	c.options.FormatQuality = "Auto"
+	assert.Equal(t, thumb.QualityAuto, c.FormatQuality())
+	c.options.FormatQuality = "foo"
+	assert.Equal(t, thumb.QualityFixed, c.FormatQuality())
+	c.options.FormatQuality = ""
+}
@@ -115,7 +115,7 @@ func (c *Config) HttpCachePublic() bool {
 		return true
 	}
 
-	return c.options.CdnUrl != ""
+	return c.options.CdnUrl != "" || strings.Contains(c.ContentUrl(), "://")
 }
 
 // HttpHost returns the built-in HTTP server host name or IP address (empty for all interfaces).
@@ -10,6 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
 
@@ -466,6 +467,42 @@ func TestConfig_ContentUri(t *testing.T) {
 	assert.Equal(t, ApiUri, c.ContentUri())
 	c.options.CdnUrl = "http://foo:2342//"
 	assert.Equal(t, "http://foo:2342"+ApiUri, c.ContentUri())
+	c.options.CdnUrl = ""
+}
+
+func TestConfig_ContentUrl(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.ContentUrl())
+	c.options.ContentUrl = "https://cdn.example.com/photos/"
+	assert.Equal(t, "https://cdn.example.com/photos", c.ContentUrl())
+	c.options.ContentUrl = "/photos/"
+	assert.Equal(t, "/photos", c.ContentUrl())
+	c.options.ContentUrl = "photos"
+	assert.Equal(t, "", c.ContentUrl())
+	c.options.ContentUrl = ""
+}
+
+func TestConfig_ContentUri_ContentUrl(t *testing.T) {
+	t.Run("Subpath", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.options.SiteUrl = "http://superhost:2342/"
+		c.options.ContentUrl = "/photos"
+
+		assert.Equal(t, "/photos"+ApiUri, c.ContentUri())
+		assert.Equal(t, "/photos"+ApiUri+"/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/public/tile_500", thumb.Url("2cad9168fa6acc5c5c2965ddf6ec465ca42fd818", "tile_500", c.ContentUri(), "public"))
+		assert.False(t, c.HttpCachePublic())
+	})
+	t.Run("CustomDomain", func(t *testing.T) {
+		c := NewConfig(CliTestContext())
+		c.options.SiteUrl = "https://photos.example.com/photoprism/"
+		c.options.CdnUrl = "https://foo.example.com/"
+		c.options.ContentUrl = "https://cdn.example.com/photoprism/"
+
+		assert.Equal(t, "https://cdn.example.com/photoprism"+ApiUri, c.ContentUri())
+		assert.Equal(t, "https://cdn.example.com/photoprism"+ApiUri+"/t/2cad9168fa6acc5c5c2965ddf6ec465ca42fd818/public/fit_720", thumb.Url("2cad9168fa6acc5c5c2965ddf6ec465ca42fd818", "fit_720", c.ContentUri(), "public"))
+		assert.True(t, c.HttpCachePublic())
+	})
 }
 
 func TestConfig_VideoUri(t *testing.T) {
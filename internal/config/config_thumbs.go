@@ -0,0 +1,473 @@
+package config
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/server/limiter"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/list"
+	"github.com/photoprism/photoprism/pkg/media"
+)
+
+// ShareStripMetadata checks if thumbnails requested with a share token must be verified to contain no metadata.
+func (c *Config) ShareStripMetadata() bool {
+	return c.options.ShareStripMetadata
+}
+
+// ShareStripOriginals checks if originals served to share links and in public mode instead of thumbnails
+// that exceed the size limit must not contain metadata.
+func (c *Config) ShareStripOriginals() bool {
+	return c.options.ShareStripOriginals
+}
+
+// ShareThumbSizes returns the thumbnail sizes that may be requested with a share token, or an empty list
+// if all sizes are allowed. Sizes that do not exist are ignored.
+func (c *Config) ShareThumbSizes() []string {
+	result := []string{}
+
+	for _, name := range strings.Split(strings.ToLower(c.options.ShareThumbSizes), ",") {
+		if name = strings.TrimSpace(name); name == "" || list.Contains(result, name) {
+			continue
+		} else if _, ok := thumb.Sizes[thumb.Name(name)]; ok {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// DownloadNotice checks if copyright and creator notices should be embedded in downloaded thumbnails.
+func (c *Config) DownloadNotice() bool {
+	return c.options.DownloadNotice
+}
+
+// DownloadArtist returns the creator name embedded in downloaded thumbnails, if any.
+func (c *Config) DownloadArtist() string {
+	return strings.TrimSpace(c.options.DownloadArtist)
+}
+
+// DownloadCopyright returns the copyright notice embedded in downloaded thumbnails, if any.
+func (c *Config) DownloadCopyright() string {
+	return strings.TrimSpace(c.options.DownloadCopyright)
+}
+
+// DownloadDpi returns the default print resolution of downloaded thumbnails, or 0 if it should not be set.
+func (c *Config) DownloadDpi() int {
+	if c.options.DownloadDpi < 0 {
+		return 0
+	} else if c.options.DownloadDpi == 0 {
+		return thumb.DefaultDpi
+	} else if c.options.DownloadDpi > thumb.MaxDpi {
+		return thumb.MaxDpi
+	}
+
+	return c.options.DownloadDpi
+}
+
+// ThumbTimeout returns the maximum duration of on-demand thumbnail creation, or 0 if there is no limit.
+// Files are flagged after a timeout, so that subsequent requests return the broken icon right away.
+func (c *Config) ThumbTimeout() time.Duration {
+	if c.options.ThumbTimeout <= 0 {
+		return 0
+	}
+
+	return time.Duration(c.options.ThumbTimeout) * time.Second
+}
+
+// ThumbAsync returns how long to wait for on-demand thumbnails before telling clients to retry later,
+// or 0 if requests should block until they are created.
+func (c *Config) ThumbAsync() time.Duration {
+	if c.options.ThumbAsync <= 0 {
+		return 0
+	}
+
+	return time.Duration(c.options.ThumbAsync) * time.Second
+}
+
+// ThumbWait returns the maximum time that clients may wait for thumbnails with long polling (0-300s),
+// or 0 if it is disabled.
+func (c *Config) ThumbWait() time.Duration {
+	if c.options.ThumbWait <= 0 {
+		return 0
+	} else if c.options.ThumbWait > 300 {
+		return 300 * time.Second
+	}
+
+	return time.Duration(c.options.ThumbWait) * time.Second
+}
+
+// ThumbPreload returns the maximum number of recently created thumbnails to add to the memory cache on startup.
+func (c *Config) ThumbPreload() int {
+	if c.options.ThumbPreload <= 0 {
+		return 0
+	}
+
+	return c.options.ThumbPreload
+}
+
+// ThumbPreloadAge returns the maximum age of thumbnails added to the memory cache on startup.
+func (c *Config) ThumbPreloadAge() time.Duration {
+	if c.options.ThumbPreloadAge <= 0 {
+		return 24 * time.Hour
+	}
+
+	return time.Duration(c.options.ThumbPreloadAge) * time.Hour
+}
+
+// ThumbPush returns the number of thumbnails to push with HTTP/2, or to announce with 103 Early Hints
+// if push is not available, when a shared album is opened (0-100).
+func (c *Config) ThumbPush() int {
+	if c.options.ThumbPush <= 0 {
+		return 0
+	} else if c.options.ThumbPush > 100 {
+		return 100
+	}
+
+	return c.options.ThumbPush
+}
+
+// ThumbGif returns the thumbnail mode for animated GIFs, i.e. the first or middle frame, or an animated thumbnail.
+func (c *Config) ThumbGif() thumb.GifMode {
+	mode, _ := thumb.ParseGifMode(c.options.ThumbGif)
+	return mode
+}
+
+// ThumbReconcile returns the daily thumbnail reconciliation mode, i.e. "report" or "repair",
+// or an empty string if it is disabled.
+func (c *Config) ThumbReconcile() string {
+	switch s := strings.ToLower(strings.TrimSpace(c.options.ThumbReconcile)); s {
+	case "report", "repair":
+		return s
+	default:
+		return ""
+	}
+}
+
+// ThumbInvalid returns how images with zero or invalid dimensions are handled: "flag" marks the file
+// with an error, so that it can be filtered and is not decoded again, while "icon" only returns a placeholder.
+func (c *Config) ThumbInvalid() string {
+	if s := strings.ToLower(strings.TrimSpace(c.options.ThumbInvalid)); s == "icon" {
+		return s
+	}
+
+	return "flag"
+}
+
+// ThumbErrorIcons returns the names of placeholder icons by media type for files that cannot be displayed,
+// e.g. because they are corrupt or missing. Media types that are not listed get the default icon.
+func (c *Config) ThumbErrorIcons() map[media.Type]string {
+	result := make(map[media.Type]string)
+
+	for _, s := range strings.Split(strings.ToLower(c.options.ThumbErrorIcons), ",") {
+		k, v, ok := strings.Cut(s, "=")
+
+		if !ok {
+			continue
+		}
+
+		switch t, icon := media.Type(strings.TrimSpace(k)), strings.TrimSpace(v); t {
+		case media.Image, media.Raw, media.Animated, media.Live, media.Video, media.Vector:
+			if icon != "" {
+				result[t] = icon
+			}
+		}
+	}
+
+	return result
+}
+
+// thumbErrorIconsString returns the placeholder icons by media type as a sorted, comma-separated list.
+func thumbErrorIconsString(icons map[media.Type]string) string {
+	result := make([]string, 0, len(icons))
+
+	for t, icon := range icons {
+		result = append(result, string(t)+"="+icon)
+	}
+
+	sort.Strings(result)
+
+	return strings.Join(result, ",")
+}
+
+// ThumbIconFormat returns the format of placeholder icons, which may be PNG if SVG images are blocked
+// by a strict Content-Security-Policy, or SVG by default.
+func (c *Config) ThumbIconFormat() fs.Type {
+	if strings.ToLower(strings.TrimSpace(c.options.ThumbIconFormat)) == "png" {
+		return fs.ImagePNG
+	}
+
+	return fs.VectorSVG
+}
+
+// DownloadTemplate returns the download file name template, or an empty string if none is set or it is invalid.
+func (c *Config) DownloadTemplate() string {
+	tmpl := strings.TrimSpace(c.options.DownloadTemplate)
+
+	if tmpl == "" || entity.ValidDownloadTemplate(tmpl) != nil {
+		return ""
+	}
+
+	return tmpl
+}
+
+// ThumbXmpPreview checks if thumbnails should be created from edited previews embedded in XMP sidecar files.
+func (c *Config) ThumbXmpPreview() bool {
+	return c.options.ThumbXmpPreview
+}
+
+// ThumbEmbedded checks if small thumbnails should be created from the previews embedded in the EXIF data of JPEG files.
+func (c *Config) ThumbEmbedded() bool {
+	return c.options.ThumbEmbedded
+}
+
+// ThumbStackCover checks if the primary file thumbnail should be served for all files in a stack, e.g. bursts or RAW+JPEG.
+func (c *Config) ThumbStackCover() bool {
+	return c.options.ThumbStackCover
+}
+
+// ThumbPhotoUID checks if the UID of the photo should be added to thumbnail responses in the X-Photo-UID header.
+func (c *Config) ThumbPhotoUID() bool {
+	return c.options.ThumbPhotoUID
+}
+
+// ThumbGPS checks if the coordinates of the photo should be added to thumbnail responses in the X-GPS header.
+func (c *Config) ThumbGPS() bool {
+	return c.options.ThumbGPS
+}
+
+// ThumbFileFallback returns the maximum number of other image files of a photo that are tried if the original
+// of its preview image is missing, e.g. in stacks where the primary JPEG has been deleted (0-10).
+func (c *Config) ThumbFileFallback() int {
+	if c.options.ThumbFileFallback <= 0 {
+		return 0
+	} else if c.options.ThumbFileFallback > 10 {
+		return 10
+	}
+
+	return c.options.ThumbFileFallback
+}
+
+// ThumbHashOrder returns the order in which files with the same hash are selected, e.g. if the same image
+// is indexed in multiple folders, so that thumbnails are always created from the same file.
+func (c *Config) ThumbHashOrder() string {
+	order := strings.ToLower(strings.TrimSpace(c.options.ThumbHashOrder))
+
+	if _, ok := query.HashOrders[order]; !ok {
+		return query.HashOrderAvailable
+	}
+
+	return order
+}
+
+// ThumbAspect returns the strategy for thumbnails with a target aspect ratio, either crop or pad.
+func (c *Config) ThumbAspect() string {
+	if strings.ToLower(strings.TrimSpace(c.options.ThumbAspect)) == crop.AspectPad {
+		return crop.AspectPad
+	}
+
+	return crop.AspectCrop
+}
+
+// ThumbAspectLoss returns the maximum percentage of an image that may be cropped to reach a target aspect
+// ratio before it is padded instead (0-100).
+func (c *Config) ThumbAspectLoss() int {
+	if c.options.ThumbAspectLoss <= 0 {
+		return 0
+	} else if c.options.ThumbAspectLoss > 100 {
+		return 100
+	}
+
+	return c.options.ThumbAspectLoss
+}
+
+// ThumbCdnUrl returns the base URL of a CDN or object store that mirrors the thumbnail cache folder without
+// trailing slash, or an empty string if thumbnails should not be redirected to a CDN.
+func (c *Config) ThumbCdnUrl() string {
+	s := strings.TrimRight(strings.TrimSpace(c.options.ThumbCdnUrl), "/")
+
+	if s == "" {
+		return ""
+	} else if u, err := url.Parse(s); err != nil || u.Host == "" || u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+
+	return s
+}
+
+// ThumbCdnSecret returns the secret for signing thumbnail CDN redirects, if any.
+func (c *Config) ThumbCdnSecret() string {
+	return strings.TrimSpace(c.options.ThumbCdnSecret)
+}
+
+// ThumbCdnExpires returns the time until signed thumbnail CDN URLs expire (1 minute to 7 days).
+func (c *Config) ThumbCdnExpires() time.Duration {
+	if c.options.ThumbCdnExpires <= 0 {
+		return time.Hour
+	} else if c.options.ThumbCdnExpires < 60 {
+		return time.Minute
+	} else if c.options.ThumbCdnExpires > 604800 {
+		return 7 * 24 * time.Hour
+	}
+
+	return time.Duration(c.options.ThumbCdnExpires) * time.Second
+}
+
+// ThumbRemote checks if thumbnails of remote originals that are referenced by http(s) URL may be created.
+func (c *Config) ThumbRemote() bool {
+	return c.options.ThumbRemote
+}
+
+// ThumbRemoteLimit returns the maximum size of remote originals in megabytes.
+func (c *Config) ThumbRemoteLimit() int {
+	if c.options.ThumbRemoteLimit <= 0 {
+		return 100
+	}
+
+	return c.options.ThumbRemoteLimit
+}
+
+// ThumbLayout returns the thumbnail storage layout, see thumb.Layout. The sidecar layout requires
+// write access to the originals folder, so the central layout is used in read-only mode.
+func (c *Config) ThumbLayout() string {
+	if strings.ToLower(strings.TrimSpace(c.options.ThumbLayout)) != thumb.LayoutSidecar || c.ReadOnly() {
+		return thumb.LayoutCentral
+	}
+
+	return thumb.LayoutSidecar
+}
+
+// ThumbSharding returns the number of hash characters per subfolder level of the thumbnail cache, see thumb.Sharding.
+func (c *Config) ThumbSharding() []int {
+	if c.options.ThumbSharding == "" {
+		return thumb.DefaultSharding
+	} else if sharding, err := thumb.ParseSharding(c.options.ThumbSharding); err != nil {
+		log.Warnf("config: %s", err)
+		return thumb.DefaultSharding
+	} else {
+		return sharding
+	}
+}
+
+// ThumbDecodeLimit returns the maximum number of RAW images and videos that may be decoded at the same time.
+func (c *Config) ThumbDecodeLimit() int {
+	if c.options.ThumbDecodeLimit > 0 {
+		return c.options.ThumbDecodeLimit
+	} else if workers := c.Workers(); workers > 2 {
+		return workers / 2
+	}
+
+	return 1
+}
+
+// ThumbDecodeMem returns the estimated memory in megabytes that concurrent RAW image and video decoding may use,
+// which is a quarter of the total memory by default.
+func (c *Config) ThumbDecodeMem() int {
+	if c.options.ThumbDecodeMem > 0 {
+		return c.options.ThumbDecodeMem
+	} else if mem := int(TotalMem / Megabyte / 4); mem > 256 {
+		return mem
+	}
+
+	return 256
+}
+
+// ThumbMigrate checks if cached thumbnails that use a legacy naming scheme should be renamed when requested.
+func (c *Config) ThumbMigrate() bool {
+	return c.options.ThumbMigrate && !c.ReadOnly()
+}
+
+// ThumbBudget returns the maximum size of thumbnails in megabytes that may be served per preview token and day,
+// or 0 if it is unlimited.
+func (c *Config) ThumbBudget() int {
+	if c.options.ThumbBudget <= 0 {
+		return 0
+	}
+
+	return c.options.ThumbBudget
+}
+
+// ThumbNormalize checks if the orientation of rotated originals should be baked into upright copies when indexing.
+func (c *Config) ThumbNormalize() bool {
+	return c.options.ThumbNormalize
+}
+
+// ThumbMaxPixels returns the maximum number of megapixels of images from which thumbnails are created,
+// or 0 if it is unlimited.
+func (c *Config) ThumbMaxPixels() int {
+	if c.options.ThumbMaxPixels < 0 {
+		return 0
+	} else if c.options.ThumbMaxPixels == 0 {
+		return 200
+	}
+
+	return c.options.ThumbMaxPixels
+}
+
+// ThumbDownsize returns the number of requests after which originals that are served because the size exceeds
+// the limit are downsized in the background, or 0 if this is disabled.
+func (c *Config) ThumbDownsize() int {
+	if c.options.ThumbDownsize <= 0 {
+		return 0
+	}
+
+	return c.options.ThumbDownsize
+}
+
+// ThumbMapUrl returns the map tile server URL for thumbnails with location inset,
+// or an empty string if they are disabled.
+func (c *Config) ThumbMapUrl() string {
+	if c.DisablePlaces() {
+		return ""
+	} else if u := strings.TrimSpace(c.options.ThumbMapUrl); strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://") {
+		return u
+	}
+
+	return ""
+}
+
+// ThumbMapZoom returns the map zoom level of location insets.
+func (c *Config) ThumbMapZoom() int {
+	if c.options.ThumbMapZoom < 1 {
+		return 12
+	} else if c.options.ThumbMapZoom > 18 {
+		return 18
+	}
+
+	return c.options.ThumbMapZoom
+}
+
+// initThumbPins loads the list of pinned thumbnails, see thumb.Pin.
+func (c *Config) initThumbPins() {
+	if err := thumb.LoadPins(c.ThumbPinsYaml()); err != nil {
+		log.Warnf("config: %s", err)
+	}
+}
+
+// ThumbStream checks if large thumbnails should be sent to clients while they are being created.
+func (c *Config) ThumbStream() bool {
+	return c.options.ThumbStream
+}
+
+// initThumbQuality checks if the JPEG quality settings were changed, so that stale thumbnails are
+// refreshed in the background when requested, see thumb.CheckQuality.
+func (c *Config) initThumbQuality() {
+	if changed, err := thumb.CheckQuality(c.ThumbQualityStamp()); err != nil {
+		log.Warnf("config: %s", err)
+	} else if changed {
+		log.Infof("config: jpeg quality changed, existing thumbnails will be refreshed when requested")
+	}
+}
+
+// initThumbBudget restores the thumbnail bytes served per preview token today, see limiter.Thumbs.
+func (c *Config) initThumbBudget() {
+	if err := limiter.Thumbs.Load(c.ThumbBudgetYaml()); err != nil {
+		log.Warnf("config: %s", err)
+	}
+}
@@ -0,0 +1,488 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/media"
+)
+
+func TestConfig_ShareStripMetadata(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ShareStripMetadata())
+	c.options.ShareStripMetadata = true
+	assert.True(t, c.ShareStripMetadata())
+	c.options.ShareStripMetadata = false
+}
+
+func TestConfig_ShareStripOriginals(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ShareStripOriginals())
+	c.options.ShareStripOriginals = true
+	assert.True(t, c.ShareStripOriginals())
+	c.options.ShareStripOriginals = false
+}
+
+func TestConfig_ShareThumbSizes(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, []string{}, c.ShareThumbSizes())
+	c.options.ShareThumbSizes = "fit_720, TILE_500,foo,fit_720,fit_1280"
+	assert.Equal(t, []string{"fit_720", "tile_500", "fit_1280"}, c.ShareThumbSizes())
+	c.options.ShareThumbSizes = ""
+}
+
+func TestConfig_DownloadNotice(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.DownloadNotice())
+	c.options.DownloadNotice = true
+	assert.True(t, c.DownloadNotice())
+	c.options.DownloadNotice = false
+}
+
+func TestConfig_DownloadDpi(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 300, c.DownloadDpi())
+	c.options.DownloadDpi = 72
+	assert.Equal(t, 72, c.DownloadDpi())
+	c.options.DownloadDpi = -1
+	assert.Equal(t, 0, c.DownloadDpi())
+	c.options.DownloadDpi = 100000
+	assert.Equal(t, 2400, c.DownloadDpi())
+	c.options.DownloadDpi = 0
+}
+
+func TestConfig_DownloadArtist(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.DownloadArtist())
+	c.options.DownloadArtist = " Jens Mander "
+	assert.Equal(t, "Jens Mander", c.DownloadArtist())
+	c.options.DownloadArtist = ""
+}
+
+func TestConfig_DownloadCopyright(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.DownloadCopyright())
+	c.options.DownloadCopyright = "© 2023 PhotoPrism UG"
+	assert.Equal(t, "© 2023 PhotoPrism UG", c.DownloadCopyright())
+	c.options.DownloadCopyright = ""
+}
+
+func TestConfig_ThumbTimeout(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, time.Duration(0), c.ThumbTimeout())
+	c.options.ThumbTimeout = 30
+	assert.Equal(t, 30*time.Second, c.ThumbTimeout())
+	c.options.ThumbTimeout = -1
+	assert.Equal(t, time.Duration(0), c.ThumbTimeout())
+	c.options.ThumbTimeout = 0
+}
+
+func TestConfig_ThumbAsync(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, time.Duration(0), c.ThumbAsync())
+	c.options.ThumbAsync = 2
+	assert.Equal(t, 2*time.Second, c.ThumbAsync())
+	c.options.ThumbAsync = -1
+	assert.Equal(t, time.Duration(0), c.ThumbAsync())
+	c.options.ThumbAsync = 0
+}
+
+func TestConfig_ThumbWait(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, time.Duration(0), c.ThumbWait())
+	c.options.ThumbWait = 30
+	assert.Equal(t, 30*time.Second, c.ThumbWait())
+	c.options.ThumbWait = 1000
+	assert.Equal(t, 300*time.Second, c.ThumbWait())
+	c.options.ThumbWait = -1
+	assert.Equal(t, time.Duration(0), c.ThumbWait())
+	c.options.ThumbWait = 0
+}
+
+func TestConfig_ThumbPreload(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 0, c.ThumbPreload())
+	c.options.ThumbPreload = 500
+	assert.Equal(t, 500, c.ThumbPreload())
+	c.options.ThumbPreload = -1
+	assert.Equal(t, 0, c.ThumbPreload())
+	c.options.ThumbPreload = 0
+}
+
+func TestConfig_ThumbPreloadAge(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 24*time.Hour, c.ThumbPreloadAge())
+	c.options.ThumbPreloadAge = 48
+	assert.Equal(t, 48*time.Hour, c.ThumbPreloadAge())
+	c.options.ThumbPreloadAge = 0
+	assert.Equal(t, 24*time.Hour, c.ThumbPreloadAge())
+}
+
+func TestConfig_ThumbPush(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 0, c.ThumbPush())
+	c.options.ThumbPush = 24
+	assert.Equal(t, 24, c.ThumbPush())
+	c.options.ThumbPush = 1000
+	assert.Equal(t, 100, c.ThumbPush())
+	c.options.ThumbPush = 0
+}
+
+func TestConfig_ThumbGif(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, thumb.GifFirst, c.ThumbGif())
+	c.options.ThumbGif = "Animated"
+	assert.Equal(t, thumb.GifAnimated, c.ThumbGif())
+	c.options.ThumbGif = "invalid"
+	assert.Equal(t, thumb.GifFirst, c.ThumbGif())
+	c.options.ThumbGif = ""
+}
+
+func TestConfig_ThumbInvalid(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "flag", c.ThumbInvalid())
+	c.options.ThumbInvalid = " Icon"
+	assert.Equal(t, "icon", c.ThumbInvalid())
+	c.options.ThumbInvalid = "invalid"
+	assert.Equal(t, "flag", c.ThumbInvalid())
+	c.options.ThumbInvalid = ""
+}
+
+func TestConfig_ThumbReconcile(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.ThumbReconcile())
+	c.options.ThumbReconcile = " Repair"
+	assert.Equal(t, "repair", c.ThumbReconcile())
+	c.options.ThumbReconcile = "report"
+	assert.Equal(t, "report", c.ThumbReconcile())
+	c.options.ThumbReconcile = "delete"
+	assert.Equal(t, "", c.ThumbReconcile())
+	c.options.ThumbReconcile = ""
+}
+
+func TestConfig_ThumbErrorIcons(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, map[media.Type]string{}, c.ThumbErrorIcons())
+	c.options.ThumbErrorIcons = "Video=Video, raw = broken,foo=bar,image,live="
+	assert.Equal(t, map[media.Type]string{media.Video: "video", media.Raw: "broken"}, c.ThumbErrorIcons())
+	assert.Equal(t, "raw=broken,video=video", thumbErrorIconsString(c.ThumbErrorIcons()))
+	c.options.ThumbErrorIcons = ""
+}
+
+func TestConfig_ThumbIconFormat(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, fs.VectorSVG, c.ThumbIconFormat())
+	c.options.ThumbIconFormat = "PNG"
+	assert.Equal(t, fs.ImagePNG, c.ThumbIconFormat())
+	c.options.ThumbIconFormat = "gif"
+	assert.Equal(t, fs.VectorSVG, c.ThumbIconFormat())
+	c.options.ThumbIconFormat = ""
+}
+
+func TestConfig_DownloadTemplate(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.DownloadTemplate())
+	c.options.DownloadTemplate = " {date}-{title} "
+	assert.Equal(t, "{date}-{title}", c.DownloadTemplate())
+	c.options.DownloadTemplate = "{date}-{foo}"
+	assert.Equal(t, "", c.DownloadTemplate())
+	c.options.DownloadTemplate = ""
+}
+
+func TestConfig_ThumbXmpPreview(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ThumbXmpPreview())
+	c.options.ThumbXmpPreview = true
+	assert.True(t, c.ThumbXmpPreview())
+	c.options.ThumbXmpPreview = false
+}
+
+func TestConfig_ThumbEmbedded(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ThumbEmbedded())
+	c.options.ThumbEmbedded = true
+	assert.True(t, c.ThumbEmbedded())
+	c.options.ThumbEmbedded = false
+}
+
+func TestConfig_ThumbStackCover(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ThumbStackCover())
+	c.options.ThumbStackCover = true
+	assert.True(t, c.ThumbStackCover())
+	c.options.ThumbStackCover = false
+}
+
+func TestConfig_ThumbPhotoUID(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ThumbPhotoUID())
+	c.options.ThumbPhotoUID = true
+	assert.True(t, c.ThumbPhotoUID())
+	c.options.ThumbPhotoUID = false
+}
+
+func TestConfig_ThumbGPS(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ThumbGPS())
+	c.options.ThumbGPS = true
+	assert.True(t, c.ThumbGPS())
+	c.options.ThumbGPS = false
+}
+
+func TestConfig_ThumbFileFallback(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 0, c.ThumbFileFallback())
+	c.options.ThumbFileFallback = 3
+	assert.Equal(t, 3, c.ThumbFileFallback())
+	c.options.ThumbFileFallback = 100
+	assert.Equal(t, 10, c.ThumbFileFallback())
+	c.options.ThumbFileFallback = 0
+}
+
+func TestConfig_ThumbHashOrder(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "available", c.ThumbHashOrder())
+	c.options.ThumbHashOrder = " Primary"
+	assert.Equal(t, "primary", c.ThumbHashOrder())
+	c.options.ThumbHashOrder = "oldest"
+	assert.Equal(t, "oldest", c.ThumbHashOrder())
+	c.options.ThumbHashOrder = "random"
+	assert.Equal(t, "available", c.ThumbHashOrder())
+	c.options.ThumbHashOrder = ""
+}
+
+func TestConfig_ThumbAspect(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "crop", c.ThumbAspect())
+	c.options.ThumbAspect = "Pad"
+	assert.Equal(t, "pad", c.ThumbAspect())
+	c.options.ThumbAspect = "stretch"
+	assert.Equal(t, "crop", c.ThumbAspect())
+	c.options.ThumbAspect = ""
+}
+
+func TestConfig_ThumbAspectLoss(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 0, c.ThumbAspectLoss())
+	c.options.ThumbAspectLoss = 40
+	assert.Equal(t, 40, c.ThumbAspectLoss())
+	c.options.ThumbAspectLoss = 150
+	assert.Equal(t, 100, c.ThumbAspectLoss())
+	c.options.ThumbAspectLoss = 0
+}
+
+func TestConfig_ThumbCdnUrl(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.ThumbCdnUrl())
+	c.options.ThumbCdnUrl = "https://cdn.example.com/thumbs/"
+	assert.Equal(t, "https://cdn.example.com/thumbs", c.ThumbCdnUrl())
+	c.options.ThumbCdnUrl = "ftp://cdn.example.com/thumbs"
+	assert.Equal(t, "", c.ThumbCdnUrl())
+	c.options.ThumbCdnUrl = "/thumbs"
+	assert.Equal(t, "", c.ThumbCdnUrl())
+	c.options.ThumbCdnUrl = ""
+}
+
+func TestConfig_ThumbCdnSecret(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.ThumbCdnSecret())
+	c.options.ThumbCdnSecret = " foo "
+	assert.Equal(t, "foo", c.ThumbCdnSecret())
+	c.options.ThumbCdnSecret = ""
+}
+
+func TestConfig_ThumbCdnExpires(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, time.Hour, c.ThumbCdnExpires())
+	c.options.ThumbCdnExpires = 300
+	assert.Equal(t, 5*time.Minute, c.ThumbCdnExpires())
+	c.options.ThumbCdnExpires = 10
+	assert.Equal(t, time.Minute, c.ThumbCdnExpires())
+	c.options.ThumbCdnExpires = 10000000
+	assert.Equal(t, 7*24*time.Hour, c.ThumbCdnExpires())
+	c.options.ThumbCdnExpires = 0
+}
+
+func TestConfig_ThumbRemote(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ThumbRemote())
+	c.options.ThumbRemote = true
+	assert.True(t, c.ThumbRemote())
+	c.options.ThumbRemote = false
+}
+
+func TestConfig_ThumbRemoteLimit(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 100, c.ThumbRemoteLimit())
+	c.options.ThumbRemoteLimit = 20
+	assert.Equal(t, 20, c.ThumbRemoteLimit())
+	c.options.ThumbRemoteLimit = -1
+	assert.Equal(t, 100, c.ThumbRemoteLimit())
+}
+
+func TestConfig_ThumbLayout(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "central", c.ThumbLayout())
+	c.options.ThumbLayout = "Sidecar"
+	assert.Equal(t, "sidecar", c.ThumbLayout())
+	c.options.ReadOnly = true
+	assert.Equal(t, "central", c.ThumbLayout())
+	c.options.ReadOnly = false
+	c.options.ThumbLayout = "invalid"
+	assert.Equal(t, "central", c.ThumbLayout())
+	c.options.ThumbLayout = ""
+}
+
+func TestConfig_ThumbDecodeLimit(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.GreaterOrEqual(t, c.ThumbDecodeLimit(), 1)
+	assert.LessOrEqual(t, c.ThumbDecodeLimit(), c.Workers())
+	c.options.ThumbDecodeLimit = 3
+	assert.Equal(t, 3, c.ThumbDecodeLimit())
+	c.options.ThumbDecodeLimit = 0
+}
+
+func TestConfig_ThumbDecodeMem(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.GreaterOrEqual(t, c.ThumbDecodeMem(), 256)
+	c.options.ThumbDecodeMem = 512
+	assert.Equal(t, 512, c.ThumbDecodeMem())
+	c.options.ThumbDecodeMem = 0
+}
+
+func TestConfig_ThumbMigrate(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ThumbMigrate())
+	c.options.ThumbMigrate = true
+	assert.True(t, c.ThumbMigrate())
+	c.options.ReadOnly = true
+	assert.False(t, c.ThumbMigrate())
+	c.options.ReadOnly = false
+	c.options.ThumbMigrate = false
+	assert.False(t, c.ThumbMigrate())
+}
+
+func TestConfig_ThumbBudget(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 0, c.ThumbBudget())
+	c.options.ThumbBudget = 500
+	assert.Equal(t, 500, c.ThumbBudget())
+	c.options.ThumbBudget = -1
+	assert.Equal(t, 0, c.ThumbBudget())
+	c.options.ThumbBudget = 0
+}
+
+func TestConfig_ThumbNormalize(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ThumbNormalize())
+	c.options.ThumbNormalize = true
+	assert.True(t, c.ThumbNormalize())
+	c.options.ThumbNormalize = false
+}
+
+func TestConfig_ThumbMaxPixels(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 200, c.ThumbMaxPixels())
+	c.options.ThumbMaxPixels = 50
+	assert.Equal(t, 50, c.ThumbMaxPixels())
+	c.options.ThumbMaxPixels = -1
+	assert.Equal(t, 0, c.ThumbMaxPixels())
+	c.options.ThumbMaxPixels = 0
+}
+
+func TestConfig_ThumbDownsize(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 0, c.ThumbDownsize())
+	c.options.ThumbDownsize = 3
+	assert.Equal(t, 3, c.ThumbDownsize())
+	c.options.ThumbDownsize = -1
+	assert.Equal(t, 0, c.ThumbDownsize())
+	c.options.ThumbDownsize = 0
+}
+
+func TestConfig_ThumbStream(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.ThumbStream())
+	c.options.ThumbStream = true
+	assert.True(t, c.ThumbStream())
+	c.options.ThumbStream = false
+}
+
+func TestConfig_ThumbMapUrl(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.ThumbMapUrl())
+	c.options.ThumbMapUrl = "https://tile.example.com/{z}/{x}/{y}.png"
+	assert.Equal(t, "https://tile.example.com/{z}/{x}/{y}.png", c.ThumbMapUrl())
+	c.options.ThumbMapUrl = "file:///etc/passwd"
+	assert.Equal(t, "", c.ThumbMapUrl())
+	c.options.ThumbMapUrl = ""
+}
+
+func TestConfig_ThumbMapZoom(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, 12, c.ThumbMapZoom())
+	c.options.ThumbMapZoom = 15
+	assert.Equal(t, 15, c.ThumbMapZoom())
+	c.options.ThumbMapZoom = 25
+	assert.Equal(t, 18, c.ThumbMapZoom())
+	c.options.ThumbMapZoom = 0
+}
+
+func TestConfig_ThumbSharding(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, []int{1, 1, 1}, c.ThumbSharding())
+	c.options.ThumbSharding = "2,2"
+	assert.Equal(t, []int{2, 2}, c.ThumbSharding())
+	c.options.ThumbSharding = "invalid"
+	assert.Equal(t, []int{1, 1, 1}, c.ThumbSharding())
+	c.options.ThumbSharding = ""
+}
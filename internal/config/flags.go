@@ -394,6 +394,11 @@ var Flags = CliFlags{
 			Usage:  "stream videos over the specified CDN",
 			EnvVar: EnvVar("CDN_VIDEO"),
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "content-url",
+			Usage:  "external base `URL` or path of thumbnails and other content, e.g. if it is served behind a reverse proxy subpath or CDN (overrides cdn-url)",
+			EnvVar: EnvVar("CONTENT_URL"),
+		}}, {
 		Flag: cli.StringFlag{
 			Name:   "site-url, url",
 			Usage:  "public site `URL`",
@@ -671,6 +676,29 @@ var Flags = CliFlags{
 			Value:  "heif-convert",
 			EnvVar: EnvVar("HEIFCONVERT_BIN"),
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "cjpeg-bin",
+			Usage:  "libjpeg-turbo or mozjpeg JPEG encoder `COMMAND`",
+			EnvVar: EnvVar("CJPEG_BIN"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "cwebp-bin",
+			Usage:  "libwebp WebP encoder `COMMAND` for crops requested by clients that support WebP",
+			Value:  "cwebp",
+			EnvVar: EnvVar("CWEBP_BIN"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "avifenc-bin",
+			Usage:  "libavif AVIF encoder `COMMAND` for crops requested by clients that support AVIF",
+			Value:  "avifenc",
+			EnvVar: EnvVar("AVIFENC_BIN"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "format-quality",
+			Usage:  "WebP and AVIF quality `MODE` (fixed, auto), auto chooses the lowest quality up to the JPEG quality that preserves the details of each image",
+			Value:  "fixed",
+			EnvVar: EnvVar("FORMAT_QUALITY"),
+		}}, {
 		Flag: cli.StringFlag{
 			Name:   "download-token",
 			Usage:  "`DEFAULT` download URL token for originals (leave empty for a random value)",
@@ -681,6 +709,52 @@ var Flags = CliFlags{
 			Usage:  "`DEFAULT` thumbnail and video streaming URL token (leave empty for a random value)",
 			EnvVar: EnvVar("PREVIEW_TOKEN"),
 		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "preview-token-local",
+			Usage:  "allow thumbnail and video streaming requests without token from localhost and private networks",
+			EnvVar: EnvVar("PREVIEW_TOKEN_LOCAL"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "share-strip-metadata",
+			Usage:  "verify that thumbnails requested with a share token contain no Exif, XMP, or IPTC metadata, and remove it otherwise",
+			EnvVar: EnvVar("SHARE_STRIP_METADATA"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "share-strip-originals",
+			Usage:  "serve JPEG and PNG originals without metadata to share links and in public mode if the requested size exceeds the limit",
+			EnvVar: EnvVar("SHARE_STRIP_ORIGINALS"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "share-thumb-sizes",
+			Usage:  "comma-separated `SIZES` that may be requested with a share token, larger sizes are downgraded if possible (default: all)",
+			EnvVar: EnvVar("SHARE_THUMB_SIZES"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "download-notice",
+			Usage:  "embed copyright and creator notices in downloaded thumbnails",
+			EnvVar: EnvVar("DOWNLOAD_NOTICE"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "download-artist",
+			Usage:  "creator `NAME` embedded in downloaded thumbnails (leave blank to use photo metadata)",
+			EnvVar: EnvVar("DOWNLOAD_ARTIST"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "download-copyright",
+			Usage:  "copyright `NOTICE` embedded in downloaded thumbnails (leave blank to use photo metadata)",
+			EnvVar: EnvVar("DOWNLOAD_COPYRIGHT"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "download-template",
+			Usage:  "download file name `TEMPLATE`, e.g. {date}-{camera}-{title} (tokens: date, time, year, month, day, title, camera, name, uid, hash)",
+			EnvVar: EnvVar("DOWNLOAD_TEMPLATE"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "download-dpi",
+			Usage:  "default print resolution in `DPI` of downloaded thumbnails (-1 to disable)",
+			Value:  thumb.DefaultDpi,
+			EnvVar: EnvVar("DOWNLOAD_DPI"),
+		}}, {
 		Flag: cli.StringFlag{
 			Name:   "thumb-color",
 			Usage:  "standard color `PROFILE` for thumbnails (leave blank to disable)",
@@ -693,6 +767,26 @@ var Flags = CliFlags{
 			Value:  "lanczos",
 			EnvVar: EnvVar("THUMB_FILTER"),
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-levels",
+			Usage:  "thumbnail `SIZES` to apply conservative auto levels to, e.g. tile_500,fit_720 or all (improves flat images)",
+			EnvVar: EnvVar("THUMB_LEVELS"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-gray",
+			Usage:  "thumbnail `SIZES` to create in grayscale, e.g. tile_500,fit_720 or all (except color detection)",
+			EnvVar: EnvVar("THUMB_GRAY"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-steps",
+			Usage:  "thumbnail `SIZES` for which large images are downscaled in steps, e.g. tile_50,tile_100 or all (reduces aliasing)",
+			EnvVar: EnvVar("THUMB_STEPS"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-entropy",
+			Usage:  "thumbnail and crop `SIZES` that show the part with the most detail instead of the center, e.g. tile_224,wide_640",
+			EnvVar: EnvVar("THUMB_ENTROPY"),
+		}}, {
 		Flag: cli.IntFlag{
 			Name:   "thumb-size",
 			Usage:  "maximum size of thumbnails created during indexing in `PIXELS` (720-7680)",
@@ -705,17 +799,259 @@ var Flags = CliFlags{
 			Value:  7680,
 			EnvVar: EnvVar("THUMB_SIZE_UNCACHED"),
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-fallback",
+			Usage:  "ordered list of precached thumbnail `SIZES` to use if uncached sizes are disabled, e.g. fit_2048,fit_1920,fit_1280",
+			EnvVar: EnvVar("THUMB_FALLBACK"),
+		}}, {
 		Flag: cli.BoolFlag{
 			Name:   "thumb-uncached, u",
 			Usage:  "enable on-demand creation of missing thumbnails (high memory and cpu usage)",
 			EnvVar: EnvVar("THUMB_UNCACHED"),
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-uncached-ttl",
+			Usage:  "`DURATION` after which thumbnails of uncached sizes expire, optionally by size, e.g. 30m,fit_7680=10m (empty to disable)",
+			EnvVar: EnvVar("THUMB_UNCACHED_TTL"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-policy",
+			Usage:  "thumbnail creation `POLICY` by size, e.g. fit_2560=precache,fit_7680=never (precache, on-demand, never)",
+			EnvVar: EnvVar("THUMB_POLICY"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-accel",
+			Usage:  "hardware acceleration `BACKEND` for resizing JPEG thumbnails with FFmpeg, falls back to the CPU if unavailable (none, auto, cuda, vaapi)",
+			Value:  "none",
+			EnvVar: EnvVar("THUMB_ACCEL"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-timeout",
+			Usage:  "time in `SECONDS` until on-demand thumbnail creation is aborted and the file is flagged (0 to disable)",
+			EnvVar: EnvVar("THUMB_TIMEOUT"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-async",
+			Usage:  "time in `SECONDS` to wait for on-demand thumbnails before returning 202 Accepted so that clients can retry later (0 to wait until done)",
+			EnvVar: EnvVar("THUMB_ASYNC"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-wait",
+			Usage:  "maximum time in `SECONDS` that clients may wait for thumbnails with long polling before 202 Accepted is returned (0 to disable)",
+			Value:  30,
+			EnvVar: EnvVar("THUMB_WAIT"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-preload",
+			Usage:  "maximum `NUMBER` of recently created thumbnails to add to the memory cache on startup (0 to disable)",
+			EnvVar: EnvVar("THUMB_PRELOAD"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-preload-age",
+			Usage:  "maximum age in `HOURS` of thumbnails added to the memory cache on startup",
+			Value:  24,
+			EnvVar: EnvVar("THUMB_PRELOAD_AGE"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-push",
+			Usage:  "`NUMBER` of thumbnails to push with HTTP/2 or announce with 103 Early Hints when a shared album is opened (0 to disable)",
+			EnvVar: EnvVar("THUMB_PUSH"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-gif",
+			Usage:  "thumbnail `MODE` for animated GIFs (first, middle, animated)",
+			Value:  "first",
+			EnvVar: EnvVar("THUMB_GIF"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-reconcile",
+			Usage:  "daily thumbnail reconciliation `MODE` to find indexed files without thumbnails and orphaned thumbnails (report, repair)",
+			EnvVar: EnvVar("THUMB_RECONCILE"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-error-icons",
+			Usage:  "placeholder `ICONS` by media type for files that cannot be displayed, e.g. video=broken-video,raw=broken (icons: broken, broken-video, photo, video, raw, file)",
+			Value:  "video=broken-video",
+			EnvVar: EnvVar("THUMB_ERROR_ICONS"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-icon-format",
+			Usage:  "placeholder icon `FORMAT`, use png if SVG images are blocked by a strict Content-Security-Policy (svg, png)",
+			Value:  "svg",
+			EnvVar: EnvVar("THUMB_ICON_FORMAT"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-invalid",
+			Usage:  "`MODE` for images with zero or invalid dimensions, flag marks the file as broken so that it is not decoded again (flag, icon)",
+			Value:  "flag",
+			EnvVar: EnvVar("THUMB_INVALID"),
+		}}, {
+		Flag: cli.Float64Flag{
+			Name:   "thumb-document-ratio",
+			Usage:  "minimum aspect `RATIO` of documents whose tiles are padded instead of cropped",
+			Value:  thumb.DocumentRatio,
+			EnvVar: EnvVar("THUMB_DOCUMENT_RATIO"),
+		}}, {
+		Flag: cli.Float64Flag{
+			Name:   "thumb-document-edges",
+			Usage:  "minimum `SHARE` of edge pixels of documents whose tiles are padded instead of cropped (0-1, 0 to disable)",
+			Value:  thumb.DocumentEdges,
+			EnvVar: EnvVar("THUMB_DOCUMENT_EDGES"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "thumb-xmp-preview",
+			Usage:  "create thumbnails from edited previews embedded in XMP sidecar files, e.g. by Lightroom or Darktable",
+			EnvVar: EnvVar("THUMB_XMP_PREVIEW"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "thumb-embedded",
+			Usage:  "create small thumbnails from the previews embedded in JPEG files if they are large enough, which is faster but may reduce quality",
+			EnvVar: EnvVar("THUMB_EMBEDDED"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "thumb-stack-cover",
+			Usage:  "serve the thumbnail of the primary file when thumbnails of other files in a stack are requested",
+			EnvVar: EnvVar("THUMB_STACK_COVER"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "thumb-photo-uid",
+			Usage:  "add the UID of the photo to thumbnail responses so that clients don't need to look it up",
+			EnvVar: EnvVar("THUMB_PHOTO_UID"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "thumb-gps",
+			Usage:  "add the coordinates of the photo to thumbnail responses, e.g. for clustering them on a map",
+			EnvVar: EnvVar("THUMB_GPS"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-file-fallback",
+			Usage:  "maximum `NUMBER` of other image files of a photo to try if the original of its preview image is missing (0 to disable)",
+			Value:  3,
+			EnvVar: EnvVar("THUMB_FILE_FALLBACK"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-hash-order",
+			Usage:  "`ORDER` in which files with the same hash are selected for thumbnails: available, primary, or oldest",
+			Value:  "available",
+			EnvVar: EnvVar("THUMB_HASH_ORDER"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-aspect",
+			Usage:  "`STRATEGY` for thumbnails with a target aspect ratio: crop, or pad to show the whole image",
+			Value:  "crop",
+			EnvVar: EnvVar("THUMB_ASPECT"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-aspect-loss",
+			Usage:  "maximum `PERCENT` of an image that may be cropped to reach a target aspect ratio before it is padded instead",
+			Value:  40,
+			EnvVar: EnvVar("THUMB_ASPECT_LOSS"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-cdn-url",
+			Usage:  "base `URL` of a CDN or object store that mirrors the thumbnail cache folder, cached thumbnails are redirected to it if they exist there",
+			EnvVar: EnvVar("THUMB_CDN_URL"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-cdn-secret",
+			Usage:  "`SECRET` for signing thumbnail CDN redirects with HMAC-SHA256 (leave empty for unsigned URLs)",
+			EnvVar: EnvVar("THUMB_CDN_SECRET"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-cdn-expires",
+			Usage:  "time in `SECONDS` until signed thumbnail CDN URLs expire",
+			Value:  3600,
+			EnvVar: EnvVar("THUMB_CDN_EXPIRES"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "thumb-remote",
+			Usage:  "enable thumbnails of remote originals that are referenced by http(s) URL",
+			EnvVar: EnvVar("THUMB_REMOTE"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-remote-limit",
+			Usage:  "maximum size of remote originals in `MB`",
+			Value:  100,
+			EnvVar: EnvVar("THUMB_REMOTE_LIMIT"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-layout",
+			Usage:  "thumbnail storage `LAYOUT`: central, or sidecar to store thumbnails in hidden folders next to the originals",
+			Value:  "central",
+			EnvVar: EnvVar("THUMB_LAYOUT"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-sharding",
+			Usage:  "comma-separated number of hash `CHARS` per subfolder level of the thumbnail cache, e.g. 2,2 (run \"photoprism thumbs reshard\" after changing it)",
+			Value:  thumb.ShardingString(thumb.DefaultSharding),
+			EnvVar: EnvVar("THUMB_SHARDING"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-decode-limit",
+			Usage:  "maximum `NUMBER` of RAW images and videos decoded at the same time (0 for auto)",
+			EnvVar: EnvVar("THUMB_DECODE_LIMIT"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-decode-mem",
+			Usage:  "estimated memory `MB` that RAW image and video decoding may use at the same time (0 for auto)",
+			EnvVar: EnvVar("THUMB_DECODE_MEM"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "thumb-migrate",
+			Usage:  "rename cached thumbnails that use a legacy naming scheme when they are requested instead of creating them again",
+			EnvVar: EnvVar("THUMB_MIGRATE"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-budget",
+			Usage:  "maximum thumbnail `MB` served per preview token and day (0 for unlimited)",
+			EnvVar: EnvVar("THUMB_BUDGET"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "thumb-normalize",
+			Usage:  "bake the orientation of rotated originals into upright copies when indexing, so that thumbnails don't need to be rotated",
+			EnvVar: EnvVar("THUMB_NORMALIZE"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-max-pixels",
+			Usage:  "maximum `MEGAPIXELS` of images from which thumbnails are created, larger images are rejected (-1 for unlimited)",
+			Value:  200,
+			EnvVar: EnvVar("THUMB_MAX_PIXELS"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-downsize",
+			Usage:  "number of `REQUESTS` after which originals served because the size exceeds the limit are downsized in the background (0 to disable)",
+			Value:  5,
+			EnvVar: EnvVar("THUMB_DOWNSIZE"),
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "thumb-stream",
+			Usage:  "send large thumbnails to clients while they are being created to reduce latency",
+			EnvVar: EnvVar("THUMB_STREAM"),
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumb-map-url",
+			Usage:  "map tile server `URL` with {z}, {x}, and {y} placeholders for thumbnails with location inset (leave empty to disable)",
+			Value:  thumb.MapTileUrl,
+			EnvVar: EnvVar("THUMB_MAP_URL"),
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "thumb-map-zoom",
+			Usage:  "map `ZOOM` level of location insets (1-18)",
+			Value:  thumb.MapZoom,
+			EnvVar: EnvVar("THUMB_MAP_ZOOM"),
+		}}, {
 		Flag: cli.StringFlag{
 			Name:   "jpeg-quality, q",
 			Usage:  "a higher value increases the `QUALITY` and file size of JPEG images and thumbnails (25-100)",
 			Value:  thumb.JpegQuality.String(),
 			EnvVar: EnvVar("JPEG_QUALITY"),
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "jpeg-encoder",
+			Usage:  "JPEG `ENCODER` for thumbnails: stdlib, turbo for libjpeg-turbo, or mozjpeg for smaller files",
+			Value:  "stdlib",
+			EnvVar: EnvVar("JPEG_ENCODER"),
+		}}, {
 		Flag: cli.IntFlag{
 			Name:   "jpeg-size",
 			Usage:  "maximum size of created JPEG sidecar files in `PIXELS` (720-30000)",
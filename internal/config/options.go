@@ -95,6 +95,7 @@ type Options struct {
 	WallpaperUri          string        `yaml:"WallpaperUri" json:"WallpaperUri" flag:"wallpaper-uri"`
 	CdnUrl                string        `yaml:"CdnUrl" json:"CdnUrl" flag:"cdn-url"`
 	CdnVideo              bool          `yaml:"CdnVideo" json:"CdnVideo" flag:"cdn-video"`
+	ContentUrl            string        `yaml:"ContentUrl" json:"ContentUrl" flag:"content-url"`
 	SiteUrl               string        `yaml:"SiteUrl" json:"SiteUrl" flag:"site-url"`
 	SiteAuthor            string        `yaml:"SiteAuthor" json:"SiteAuthor" flag:"site-author"`
 	SiteTitle             string        `yaml:"SiteTitle" json:"SiteTitle" flag:"site-title"`
@@ -145,15 +146,76 @@ type Options struct {
 	ImageMagickBin        string        `yaml:"ImageMagickBin" json:"-" flag:"imagemagick-bin"`
 	ImageMagickBlacklist  string        `yaml:"ImageMagickBlacklist" json:"-" flag:"imagemagick-blacklist"`
 	HeifConvertBin        string        `yaml:"HeifConvertBin" json:"-" flag:"heifconvert-bin"`
+	CjpegBin              string        `yaml:"CjpegBin" json:"-" flag:"cjpeg-bin"`
+	CwebpBin              string        `yaml:"CwebpBin" json:"-" flag:"cwebp-bin"`
+	AvifencBin            string        `yaml:"AvifencBin" json:"-" flag:"avifenc-bin"`
+	FormatQuality         string        `yaml:"FormatQuality" json:"FormatQuality" flag:"format-quality"`
 	RsvgConvertBin        string        `yaml:"RsvgConvertBin" json:"-" flag:"rsvgconvert-bin"`
 	DownloadToken         string        `yaml:"DownloadToken" json:"-" flag:"download-token"`
 	PreviewToken          string        `yaml:"PreviewToken" json:"-" flag:"preview-token"`
+	PreviewTokenLocal     bool          `yaml:"PreviewTokenLocal" json:"-" flag:"preview-token-local"`
+	ShareStripMetadata    bool          `yaml:"ShareStripMetadata" json:"ShareStripMetadata" flag:"share-strip-metadata"`
+	ShareStripOriginals   bool          `yaml:"ShareStripOriginals" json:"ShareStripOriginals" flag:"share-strip-originals"`
+	ShareThumbSizes       string        `yaml:"ShareThumbSizes" json:"ShareThumbSizes" flag:"share-thumb-sizes"`
+	DownloadNotice        bool          `yaml:"DownloadNotice" json:"DownloadNotice" flag:"download-notice"`
+	DownloadArtist        string        `yaml:"DownloadArtist" json:"-" flag:"download-artist"`
+	DownloadCopyright     string        `yaml:"DownloadCopyright" json:"-" flag:"download-copyright"`
+	DownloadTemplate      string        `yaml:"DownloadTemplate" json:"DownloadTemplate" flag:"download-template"`
+	DownloadDpi           int           `yaml:"DownloadDpi" json:"DownloadDpi" flag:"download-dpi"`
 	ThumbColor            string        `yaml:"ThumbColor" json:"ThumbColor" flag:"thumb-color"`
 	ThumbFilter           string        `yaml:"ThumbFilter" json:"ThumbFilter" flag:"thumb-filter"`
+	ThumbLevels           string        `yaml:"ThumbLevels" json:"ThumbLevels" flag:"thumb-levels"`
+	ThumbGray             string        `yaml:"ThumbGray" json:"ThumbGray" flag:"thumb-gray"`
+	ThumbSteps            string        `yaml:"ThumbSteps" json:"ThumbSteps" flag:"thumb-steps"`
+	ThumbEntropy          string        `yaml:"ThumbEntropy" json:"ThumbEntropy" flag:"thumb-entropy"`
 	ThumbSize             int           `yaml:"ThumbSize" json:"ThumbSize" flag:"thumb-size"`
 	ThumbSizeUncached     int           `yaml:"ThumbSizeUncached" json:"ThumbSizeUncached" flag:"thumb-size-uncached"`
+	ThumbFallback         string        `yaml:"ThumbFallback" json:"ThumbFallback" flag:"thumb-fallback"`
 	ThumbUncached         bool          `yaml:"ThumbUncached" json:"ThumbUncached" flag:"thumb-uncached"`
+	ThumbUncachedTTL      string        `yaml:"ThumbUncachedTTL" json:"ThumbUncachedTTL" flag:"thumb-uncached-ttl"`
+	ThumbTimeout          int           `yaml:"ThumbTimeout" json:"ThumbTimeout" flag:"thumb-timeout"`
+	ThumbAsync            int           `yaml:"ThumbAsync" json:"ThumbAsync" flag:"thumb-async"`
+	ThumbWait             int           `yaml:"ThumbWait" json:"ThumbWait" flag:"thumb-wait"`
+	ThumbPreload          int           `yaml:"ThumbPreload" json:"ThumbPreload" flag:"thumb-preload"`
+	ThumbPreloadAge       int           `yaml:"ThumbPreloadAge" json:"ThumbPreloadAge" flag:"thumb-preload-age"`
+	ThumbPush             int           `yaml:"ThumbPush" json:"ThumbPush" flag:"thumb-push"`
+	ThumbGif              string        `yaml:"ThumbGif" json:"ThumbGif" flag:"thumb-gif"`
+	ThumbReconcile        string        `yaml:"ThumbReconcile" json:"ThumbReconcile" flag:"thumb-reconcile"`
+	ThumbErrorIcons       string        `yaml:"ThumbErrorIcons" json:"ThumbErrorIcons" flag:"thumb-error-icons"`
+	ThumbIconFormat       string        `yaml:"ThumbIconFormat" json:"ThumbIconFormat" flag:"thumb-icon-format"`
+	ThumbInvalid          string        `yaml:"ThumbInvalid" json:"ThumbInvalid" flag:"thumb-invalid"`
+	ThumbPolicy           string        `yaml:"ThumbPolicy" json:"ThumbPolicy" flag:"thumb-policy"`
+	ThumbAccel            string        `yaml:"ThumbAccel" json:"ThumbAccel" flag:"thumb-accel"`
+	ThumbDocumentRatio    float64       `yaml:"ThumbDocumentRatio" json:"ThumbDocumentRatio" flag:"thumb-document-ratio"`
+	ThumbDocumentEdges    float64       `yaml:"ThumbDocumentEdges" json:"ThumbDocumentEdges" flag:"thumb-document-edges"`
+	ThumbXmpPreview       bool          `yaml:"ThumbXmpPreview" json:"ThumbXmpPreview" flag:"thumb-xmp-preview"`
+	ThumbEmbedded         bool          `yaml:"ThumbEmbedded" json:"ThumbEmbedded" flag:"thumb-embedded"`
+	ThumbStackCover       bool          `yaml:"ThumbStackCover" json:"ThumbStackCover" flag:"thumb-stack-cover"`
+	ThumbPhotoUID         bool          `yaml:"ThumbPhotoUID" json:"ThumbPhotoUID" flag:"thumb-photo-uid"`
+	ThumbGPS              bool          `yaml:"ThumbGPS" json:"ThumbGPS" flag:"thumb-gps"`
+	ThumbFileFallback     int           `yaml:"ThumbFileFallback" json:"ThumbFileFallback" flag:"thumb-file-fallback"`
+	ThumbHashOrder        string        `yaml:"ThumbHashOrder" json:"ThumbHashOrder" flag:"thumb-hash-order"`
+	ThumbAspect           string        `yaml:"ThumbAspect" json:"ThumbAspect" flag:"thumb-aspect"`
+	ThumbAspectLoss       int           `yaml:"ThumbAspectLoss" json:"ThumbAspectLoss" flag:"thumb-aspect-loss"`
+	ThumbCdnUrl           string        `yaml:"ThumbCdnUrl" json:"ThumbCdnUrl" flag:"thumb-cdn-url"`
+	ThumbCdnSecret        string        `yaml:"ThumbCdnSecret" json:"-" flag:"thumb-cdn-secret"`
+	ThumbCdnExpires       int           `yaml:"ThumbCdnExpires" json:"ThumbCdnExpires" flag:"thumb-cdn-expires"`
+	ThumbRemote           bool          `yaml:"ThumbRemote" json:"ThumbRemote" flag:"thumb-remote"`
+	ThumbRemoteLimit      int           `yaml:"ThumbRemoteLimit" json:"ThumbRemoteLimit" flag:"thumb-remote-limit"`
+	ThumbLayout           string        `yaml:"ThumbLayout" json:"ThumbLayout" flag:"thumb-layout"`
+	ThumbSharding         string        `yaml:"ThumbSharding" json:"ThumbSharding" flag:"thumb-sharding"`
+	ThumbDecodeLimit      int           `yaml:"ThumbDecodeLimit" json:"ThumbDecodeLimit" flag:"thumb-decode-limit"`
+	ThumbDecodeMem        int           `yaml:"ThumbDecodeMem" json:"ThumbDecodeMem" flag:"thumb-decode-mem"`
+	ThumbMigrate          bool          `yaml:"ThumbMigrate" json:"ThumbMigrate" flag:"thumb-migrate"`
+	ThumbBudget           int           `yaml:"ThumbBudget" json:"ThumbBudget" flag:"thumb-budget"`
+	ThumbNormalize        bool          `yaml:"ThumbNormalize" json:"ThumbNormalize" flag:"thumb-normalize"`
+	ThumbMaxPixels        int           `yaml:"ThumbMaxPixels" json:"ThumbMaxPixels" flag:"thumb-max-pixels"`
+	ThumbDownsize         int           `yaml:"ThumbDownsize" json:"ThumbDownsize" flag:"thumb-downsize"`
+	ThumbStream           bool          `yaml:"ThumbStream" json:"ThumbStream" flag:"thumb-stream"`
+	ThumbMapUrl           string        `yaml:"ThumbMapUrl" json:"ThumbMapUrl" flag:"thumb-map-url"`
+	ThumbMapZoom          int           `yaml:"ThumbMapZoom" json:"ThumbMapZoom" flag:"thumb-map-zoom"`
 	JpegQuality           string        `yaml:"JpegQuality" json:"JpegQuality" flag:"jpeg-quality"`
+	JpegEncoder           string        `yaml:"JpegEncoder" json:"JpegEncoder" flag:"jpeg-encoder"`
 	JpegSize              int           `yaml:"JpegSize" json:"JpegSize" flag:"jpeg-size"`
 	PngSize               int           `yaml:"PngSize" json:"PngSize" flag:"png-size"`
 	FaceSize              int           `yaml:"-" json:"-" flag:"face-size"`
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 	"unicode/utf8"
+
+	"github.com/photoprism/photoprism/internal/thumb"
 )
 
 // Report returns global config values as a table for reporting.
@@ -129,6 +131,7 @@ func (c *Config) Report() (rows [][]string, cols []string) {
 		// Site Infos.
 		{"cdn-url", c.CdnUrl("/")},
 		{"cdn-video", fmt.Sprintf("%t", c.CdnVideo())},
+		{"content-url", c.ContentUrl()},
 		{"site-url", c.SiteUrl()},
 		{"site-https", fmt.Sprintf("%t", c.SiteHttps())},
 		{"site-domain", c.SiteDomain()},
@@ -198,18 +201,79 @@ func (c *Config) Report() (rows [][]string, cols []string) {
 		{"imagemagick-bin", c.ImageMagickBin()},
 		{"imagemagick-blacklist", c.ImageMagickBlacklist()},
 		{"heifconvert-bin", c.HeifConvertBin()},
+		{"cjpeg-bin", c.CjpegBin()},
+		{"cwebp-bin", c.CwebpBin()},
+		{"avifenc-bin", c.AvifencBin()},
+		{"format-quality", c.FormatQuality()},
 		{"rsvgconvert-bin", c.RsvgConvertBin()},
 		{"jpegxldecoder-bin", c.JpegXLDecoderBin()},
 
 		// Thumbnails.
 		{"download-token", c.DownloadToken()},
 		{"preview-token", c.PreviewToken()},
+		{"preview-token-local", fmt.Sprintf("%t", c.PreviewTokenLocal())},
+		{"share-strip-metadata", fmt.Sprintf("%t", c.ShareStripMetadata())},
+		{"share-strip-originals", fmt.Sprintf("%t", c.ShareStripOriginals())},
+		{"share-thumb-sizes", strings.Join(c.ShareThumbSizes(), ",")},
+		{"download-notice", fmt.Sprintf("%t", c.DownloadNotice())},
+		{"download-artist", c.DownloadArtist()},
+		{"download-copyright", c.DownloadCopyright()},
+		{"download-template", c.DownloadTemplate()},
+		{"download-dpi", fmt.Sprintf("%d", c.DownloadDpi())},
 		{"thumb-color", c.ThumbColor()},
 		{"thumb-filter", string(c.ThumbFilter())},
+		{"thumb-levels", strings.Join(c.ThumbLevels(), ",")},
+		{"thumb-gray", strings.Join(c.ThumbGray(), ",")},
+		{"thumb-steps", strings.Join(c.ThumbSteps(), ",")},
+		{"thumb-entropy", strings.Join(c.ThumbEntropy(), ",")},
 		{"thumb-size", fmt.Sprintf("%d", c.ThumbSizePrecached())},
 		{"thumb-size-uncached", fmt.Sprintf("%d", c.ThumbSizeUncached())},
+		{"thumb-fallback", strings.Join(c.ThumbFallback(), ",")},
 		{"thumb-uncached", fmt.Sprintf("%t", c.ThumbUncached())},
+		{"thumb-uncached-ttl", thumb.UncachedTTLString(c.ThumbUncachedTTL())},
+		{"thumb-policy", thumb.PoliciesString(c.ThumbPolicy())},
+		{"thumb-accel", c.ThumbAccel()},
+		{"thumb-timeout", fmt.Sprintf("%d", c.ThumbTimeout()/time.Second)},
+		{"thumb-async", fmt.Sprintf("%d", c.ThumbAsync()/time.Second)},
+		{"thumb-wait", fmt.Sprintf("%d", c.ThumbWait()/time.Second)},
+		{"thumb-preload", fmt.Sprintf("%d", c.ThumbPreload())},
+		{"thumb-preload-age", fmt.Sprintf("%d", c.ThumbPreloadAge()/time.Hour)},
+		{"thumb-push", fmt.Sprintf("%d", c.ThumbPush())},
+		{"thumb-gif", string(c.ThumbGif())},
+		{"thumb-reconcile", c.ThumbReconcile()},
+		{"thumb-error-icons", thumbErrorIconsString(c.ThumbErrorIcons())},
+		{"thumb-icon-format", c.ThumbIconFormat().String()},
+		{"thumb-invalid", c.ThumbInvalid()},
+		{"thumb-document-ratio", fmt.Sprintf("%.2f", c.ThumbDocumentRatio())},
+		{"thumb-document-edges", fmt.Sprintf("%.2f", c.ThumbDocumentEdges())},
+		{"thumb-xmp-preview", fmt.Sprintf("%t", c.ThumbXmpPreview())},
+		{"thumb-embedded", fmt.Sprintf("%t", c.ThumbEmbedded())},
+		{"thumb-stack-cover", fmt.Sprintf("%t", c.ThumbStackCover())},
+		{"thumb-photo-uid", fmt.Sprintf("%t", c.ThumbPhotoUID())},
+		{"thumb-gps", fmt.Sprintf("%t", c.ThumbGPS())},
+		{"thumb-file-fallback", fmt.Sprintf("%d", c.ThumbFileFallback())},
+		{"thumb-hash-order", c.ThumbHashOrder()},
+		{"thumb-aspect", c.ThumbAspect()},
+		{"thumb-aspect-loss", fmt.Sprintf("%d", c.ThumbAspectLoss())},
+		{"thumb-cdn-url", c.ThumbCdnUrl()},
+		{"thumb-cdn-secret", strings.Repeat("*", utf8.RuneCountInString(c.ThumbCdnSecret()))},
+		{"thumb-cdn-expires", c.ThumbCdnExpires().String()},
+		{"thumb-remote", fmt.Sprintf("%t", c.ThumbRemote())},
+		{"thumb-remote-limit", fmt.Sprintf("%d", c.ThumbRemoteLimit())},
+		{"thumb-layout", c.ThumbLayout()},
+		{"thumb-sharding", thumb.ShardingString(c.ThumbSharding())},
+		{"thumb-decode-limit", fmt.Sprintf("%d", c.ThumbDecodeLimit())},
+		{"thumb-decode-mem", fmt.Sprintf("%d", c.ThumbDecodeMem())},
+		{"thumb-migrate", fmt.Sprintf("%t", c.ThumbMigrate())},
+		{"thumb-budget", fmt.Sprintf("%d", c.ThumbBudget())},
+		{"thumb-normalize", fmt.Sprintf("%t", c.ThumbNormalize())},
+		{"thumb-max-pixels", fmt.Sprintf("%d", c.ThumbMaxPixels())},
+		{"thumb-downsize", fmt.Sprintf("%d", c.ThumbDownsize())},
+		{"thumb-stream", fmt.Sprintf("%t", c.ThumbStream())},
+		{"thumb-map-url", c.ThumbMapUrl()},
+		{"thumb-map-zoom", fmt.Sprintf("%d", c.ThumbMapZoom())},
 		{"jpeg-quality", fmt.Sprintf("%d", c.JpegQuality())},
+		{"jpeg-encoder", c.JpegEncoder()},
 		{"jpeg-size", fmt.Sprintf("%d", c.JpegSize())},
 		{"png-size", fmt.Sprintf("%d", c.PngSize())},
 
@@ -56,6 +56,53 @@ func (a Area) Bounds(img image.Image) (min, max image.Point, dim int) {
 	return min, max, dim
 }
 
+// RatioBounds returns absolute coordinates and dimension like Bounds, but expands the area around its
+// center to match the aspect ratio of the crop size. If the expanded area does not fit into the image,
+// it is shifted inwards and reduced to the image size as needed.
+func (a Area) RatioBounds(img image.Image, size Size) (min, max image.Point, dim int) {
+	min, max, dim = a.Bounds(img)
+
+	if r := fitRatio(image.Rectangle{Min: min, Max: max}, img.Bounds().Max, size.Ratio()); !r.Empty() {
+		return r.Min, r.Max, r.Dx()
+	}
+
+	return min, max, dim
+}
+
+// fitRatio expands the absolute area around its center to match the aspect ratio, and keeps
+// it within the image size. An empty rectangle is returned if the area or ratio is invalid.
+func fitRatio(area image.Rectangle, imgSize image.Point, ratio float64) image.Rectangle {
+	w, h := float64(area.Dx()), float64(area.Dy())
+
+	if ratio <= 0 || w <= 0 || h <= 0 {
+		return image.Rectangle{}
+	}
+
+	// Expand the shorter side to match the target aspect ratio.
+	if w/h < ratio {
+		w = h * ratio
+	} else {
+		h = w / ratio
+	}
+
+	// Reduce the area if it is larger than the image.
+	if w > float64(imgSize.X) {
+		w = float64(imgSize.X)
+		h = w / ratio
+	}
+
+	if h > float64(imgSize.Y) {
+		h = float64(imgSize.Y)
+		w = h * ratio
+	}
+
+	cx, cy := float64(area.Min.X+area.Max.X)/2, float64(area.Min.Y+area.Max.Y)/2
+	x := math.Max(0, math.Min(cx-w/2, float64(imgSize.X)-w))
+	y := math.Max(0, math.Min(cy-h/2, float64(imgSize.Y)-h))
+
+	return image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+w)), int(math.Round(y+h)))
+}
+
 // FileWidth returns the ideal file width based on the crop size.
 func (a Area) FileWidth(size Size) int {
 	return int(float32(size.Width) / a.W)
@@ -143,6 +190,38 @@ func NewArea(name string, x, y, w, h float32) Area {
 	}
 }
 
+// AreaCenter is the name of the largest centered square area, see CenterArea.
+const AreaCenter = "center"
+
+// CenterArea returns the largest centered square area of an image with the specified dimensions, e.g. for
+// avatar-style tiles. An empty area is returned if the dimensions are unknown.
+func CenterArea(width, height int) Area {
+	if width <= 0 || height <= 0 {
+		return Area{}
+	} else if width >= height {
+		w := float32(height) / float32(width)
+		return NewArea(AreaCenter, (1-w)/2, 0, w, 1)
+	}
+
+	h := float32(width) / float32(height)
+
+	return NewArea(AreaCenter, 0, (1-h)/2, 1, h)
+}
+
+// AreaFull is the name of the area that covers the whole image, see FullArea.
+const AreaFull = "full"
+
+// FullArea returns the area that covers the whole image, so that the part with the most detail can be
+// cropped when it is resampled to a size with the entropy method, see Size.Entropy.
+func FullArea() Area {
+	return NewArea(AreaFull, 0, 0, 1, 1)
+}
+
+// Full tests if the area covers the whole image, see FullArea.
+func (a Area) Full() bool {
+	return a.X <= 0 && a.Y <= 0 && a.X+a.W >= 1 && a.Y+a.H >= 1
+}
+
 // AreaFromString returns an image area.
 func AreaFromString(s string) Area {
 	if len(s) != 12 || !rnd.IsHex(s) {
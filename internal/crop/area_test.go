@@ -2,8 +2,11 @@ package crop
 
 import (
 	"fmt"
+	"image"
+	"image/color"
 	"testing"
 
+	"github.com/disintegration/imaging"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -82,6 +85,52 @@ func TestArea_FileWidth(t *testing.T) {
 	})
 }
 
+func TestArea_RatioBounds(t *testing.T) {
+	img := imaging.New(1000, 500, color.White)
+
+	t.Run("Wide640", func(t *testing.T) {
+		// Square area with 100px in the center of the image.
+		m := NewArea("face", 0.45, 0.4, 0.1, 0.2)
+		min, max, dim := m.RatioBounds(img, Sizes[Wide640])
+
+		assert.Equal(t, image.Point{X: 411, Y: 200}, min)
+		assert.Equal(t, image.Point{X: 589, Y: 300}, max)
+		assert.Equal(t, 178, dim)
+	})
+	t.Run("Std640", func(t *testing.T) {
+		m := NewArea("face", 0.45, 0.4, 0.1, 0.2)
+		min, max, dim := m.RatioBounds(img, Sizes[Std640])
+
+		assert.Equal(t, 134, dim)
+		assert.Equal(t, 100, max.Y-min.Y)
+	})
+	t.Run("Edge", func(t *testing.T) {
+		m := NewArea("face", 0, 0, 0.1, 0.2)
+		min, max, dim := m.RatioBounds(img, Sizes[Wide640])
+
+		assert.Equal(t, image.Point{X: 0, Y: 0}, min)
+		assert.Equal(t, 178, dim)
+		assert.Equal(t, 100, max.Y)
+	})
+	t.Run("ExceedsImage", func(t *testing.T) {
+		m := NewArea("face", 0, 0, 1, 1)
+		min, max, dim := m.RatioBounds(img, Sizes[Wide640])
+
+		assert.Equal(t, 888, dim)
+		assert.Equal(t, 500, max.Y-min.Y)
+		assert.Equal(t, 56, min.X)
+	})
+	t.Run("Square", func(t *testing.T) {
+		m := NewArea("face", 0.45, 0.4, 0.1, 0.2)
+		min, max, dim := m.RatioBounds(img, Sizes[Tile160])
+		bMin, bMax, bDim := m.Bounds(img)
+
+		assert.Equal(t, bMin, min)
+		assert.Equal(t, bMax, max)
+		assert.Equal(t, bDim, dim)
+	})
+}
+
 func TestAreaFromString(t *testing.T) {
 	t.Run("3e814d0011f4", func(t *testing.T) {
 		a := AreaFromString("3e814d0011f4")
@@ -192,3 +241,38 @@ func TestArea_OverlapPercent(t *testing.T) {
 	assert.Equal(t, 0, a1.OverlapPercent(a3))
 	assert.Equal(t, 96, a1.OverlapPercent(a4))
 }
+
+func TestCenterArea(t *testing.T) {
+	t.Run("Landscape", func(t *testing.T) {
+		a := CenterArea(1500, 1000)
+		assert.Equal(t, AreaCenter, a.Name)
+		assert.InDelta(t, 0.1667, a.X, 0.001)
+		assert.Equal(t, float32(0), a.Y)
+		assert.InDelta(t, 0.6667, a.W, 0.001)
+		assert.Equal(t, float32(1), a.H)
+		assert.Equal(t, "0a600029a3e8", a.String())
+	})
+	t.Run("Portrait", func(t *testing.T) {
+		a := CenterArea(500, 750)
+		assert.Equal(t, float32(0), a.X)
+		assert.InDelta(t, 0.1667, a.Y, 0.001)
+		assert.Equal(t, float32(1), a.W)
+		assert.InDelta(t, 0.6667, a.H, 0.001)
+	})
+	t.Run("Square", func(t *testing.T) {
+		assert.Equal(t, "0000003e83e8", CenterArea(100, 100).String())
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		assert.True(t, CenterArea(0, 100).Empty())
+		assert.Equal(t, "", CenterArea(100, 0).String())
+	})
+}
+
+func TestFullArea(t *testing.T) {
+	a := FullArea()
+
+	assert.True(t, a.Full())
+	assert.True(t, AreaFromString(a.String()).Full())
+	assert.False(t, CenterArea(200, 100).Full())
+	assert.False(t, Area{}.Full())
+}
@@ -0,0 +1,166 @@
+package crop
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Strategies for reaching a target aspect ratio, see FromAspect.
+const (
+	AspectCrop = "crop"
+	AspectPad  = "pad"
+)
+
+// AspectStrategy is the preferred strategy for reaching a target aspect ratio.
+var AspectStrategy = AspectCrop
+
+// AspectMaxLoss is the maximum share of the image that may be cut off to reach a target aspect ratio.
+// Images that would lose more are padded instead, so that they are shown as a whole.
+var AspectMaxLoss = 0.4
+
+// AspectPadColor is the background color of padded images.
+var AspectPadColor color.Color = color.Black
+
+// Limits of the supported aspect ratios, e.g. 4:1 for panoramas and 1:4 for tall banners.
+const (
+	AspectMin = 0.25
+	AspectMax = 4.0
+)
+
+// ParseAspect parses an aspect ratio like "16x9" or "16:9" and returns its width and height.
+func ParseAspect(s string) (w, h int, err error) {
+	parts := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == 'x' || r == ':' })
+
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("crop: invalid aspect ratio %s", clean.Log(s))
+	}
+
+	if w, err = strconv.Atoi(parts[0]); err != nil || w < 1 || w > 100 {
+		return 0, 0, fmt.Errorf("crop: invalid aspect ratio %s", clean.Log(s))
+	} else if h, err = strconv.Atoi(parts[1]); err != nil || h < 1 || h > 100 {
+		return 0, 0, fmt.Errorf("crop: invalid aspect ratio %s", clean.Log(s))
+	}
+
+	if r := float64(w) / float64(h); r < AspectMin || r > AspectMax {
+		return 0, 0, fmt.Errorf("crop: unsupported aspect ratio %s", clean.Log(s))
+	}
+
+	return w, h, nil
+}
+
+// AspectSize returns the largest size with the aspect ratio that fits into the bounds of a thumbnail size.
+func AspectSize(w, h int, bounds thumb.Size) Size {
+	size := Size{Name: Name(fmt.Sprintf("aspect_%dx%d", w, h)), Options: DefaultOptions}
+
+	if w < 1 || h < 1 || bounds.Width < 1 || bounds.Height < 1 {
+		return size
+	}
+
+	size.Width, size.Height = bounds.Width, bounds.Width*h/w
+
+	if size.Height > bounds.Height {
+		size.Width, size.Height = bounds.Height*w/h, bounds.Height
+	}
+
+	return size
+}
+
+// AspectLoss returns the share of an image with the specified dimensions that is cut off when it is
+// cropped to the aspect ratio, e.g. 0.25 if a 4:3 image is cropped to 1:1.
+func AspectLoss(imgWidth, imgHeight int, ratio float64) float64 {
+	if imgWidth <= 0 || imgHeight <= 0 || ratio <= 0 {
+		return 0
+	}
+
+	if imgRatio := float64(imgWidth) / float64(imgHeight); imgRatio > ratio {
+		return 1 - ratio/imgRatio
+	} else if imgRatio < ratio {
+		return 1 - imgRatio/ratio
+	}
+
+	return 0
+}
+
+// AspectMethod returns the strategy that is used to reach the aspect ratio of the size for an image
+// with the specified dimensions. Cropping falls back to padding if it would cut off more than AspectMaxLoss.
+func AspectMethod(imgWidth, imgHeight int, size Size) string {
+	if AspectStrategy == AspectPad {
+		return AspectPad
+	} else if AspectLoss(imgWidth, imgHeight, size.Ratio()) > AspectMaxLoss {
+		return AspectPad
+	}
+
+	return AspectCrop
+}
+
+// AspectFileName returns the file name of a thumbnail with a target aspect ratio. Hints are only
+// considered for crops, as padded images always show the whole image.
+func AspectFileName(hash, thumbPath string, size Size, method string, hints Areas) (string, error) {
+	if method != AspectCrop {
+		hints = nil
+	}
+
+	return hintsFileName(hash, thumbPath, size, "aspect_"+method, hints, fs.ExtJPEG)
+}
+
+// FromAspect returns the file name of a thumbnail with the aspect ratio of the size, and creates it from
+// the best fitting thumbnail if needed. The image is either cropped so that the subject stays centered,
+// using faces and the focus point passed as hints, or padded to show the whole image, see AspectMethod.
+func FromAspect(hash, thumbPath string, size Size, method string, hints Areas) (fileName string, err error) {
+	if method != AspectCrop && method != AspectPad {
+		return "", fmt.Errorf("crop: invalid aspect method %s", clean.Log(method))
+	} else if fileName, err = AspectFileName(hash, thumbPath, size, method, hints); err != nil {
+		return "", err
+	} else if fs.FileExists(fileName) {
+		return fileName, nil
+	}
+
+	longest := size.Width
+
+	if size.Height > longest {
+		longest = size.Height
+	}
+
+	thumbName := findIdealThumbFileName(hash, longest, thumb.Dir(hash, thumbPath))
+
+	if thumbName == "" {
+		return "", fmt.Errorf("crop: no thumbnail found for %s", clean.Log(hash))
+	}
+
+	img, err := imaging.Open(thumbName)
+
+	if err != nil {
+		return "", err
+	}
+
+	if method == AspectPad {
+		img = padImage(img, size)
+	} else {
+		img = ImageFromSelector(img, size, "", hints)
+	}
+
+	if err = thumb.SaveJpeg(img, fileName, thumb.JpegQuality); err != nil {
+		return "", err
+	}
+
+	log.Debugf("crop: saved %s", filepath.Base(fileName))
+
+	return fileName, nil
+}
+
+// padImage fits the whole image into the size and centers it on a background with AspectPadColor.
+func padImage(img image.Image, size Size) image.Image {
+	img = thumb.Resample(img, size.Width, size.Height, thumb.ResampleFit, thumb.ResampleDefault)
+
+	return imaging.PasteCenter(imaging.New(size.Width, size.Height, AspectPadColor), img)
+}
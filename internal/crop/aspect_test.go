@@ -0,0 +1,132 @@
+package crop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestParseAspect(t *testing.T) {
+	t.Run("Wide", func(t *testing.T) {
+		w, h, err := ParseAspect("16x9")
+		assert.NoError(t, err)
+		assert.Equal(t, 16, w)
+		assert.Equal(t, 9, h)
+	})
+	t.Run("Colon", func(t *testing.T) {
+		w, h, err := ParseAspect("4:5")
+		assert.NoError(t, err)
+		assert.Equal(t, 4, w)
+		assert.Equal(t, 5, h)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, _, err := ParseAspect("16")
+		assert.Error(t, err)
+		_, _, err = ParseAspect("0x9")
+		assert.Error(t, err)
+		_, _, err = ParseAspect("axb")
+		assert.Error(t, err)
+	})
+	t.Run("Unsupported", func(t *testing.T) {
+		_, _, err := ParseAspect("10x1")
+		assert.Error(t, err)
+	})
+}
+
+func TestAspectSize(t *testing.T) {
+	t.Run("Wide", func(t *testing.T) {
+		size := AspectSize(16, 9, thumb.Sizes[thumb.Fit720])
+		assert.Equal(t, 720, size.Width)
+		assert.Equal(t, 405, size.Height)
+	})
+	t.Run("Tall", func(t *testing.T) {
+		size := AspectSize(4, 5, thumb.Sizes[thumb.Fit1280])
+		assert.Equal(t, 819, size.Width)
+		assert.Equal(t, 1024, size.Height)
+	})
+}
+
+func TestAspectLoss(t *testing.T) {
+	assert.InDelta(t, 0.25, AspectLoss(800, 600, 1), 0.001)
+	assert.InDelta(t, 0.25, AspectLoss(600, 800, 1), 0.001)
+	assert.Equal(t, 0.0, AspectLoss(800, 800, 1))
+	assert.Equal(t, 0.0, AspectLoss(0, 800, 1))
+}
+
+func TestAspectMethod(t *testing.T) {
+	size := AspectSize(1, 1, thumb.Sizes[thumb.Fit720])
+
+	assert.Equal(t, AspectCrop, AspectMethod(800, 600, size))
+	assert.Equal(t, AspectPad, AspectMethod(2000, 500, size))
+
+	AspectStrategy = AspectPad
+	defer func() { AspectStrategy = AspectCrop }()
+
+	assert.Equal(t, AspectPad, AspectMethod(800, 600, size))
+}
+
+func TestFromAspect(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	thumbPath := t.TempDir()
+
+	data, err := os.ReadFile("testdata/b/c/c/" + hash + "_720x720_fit.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := thumb.Dir(hash, thumbPath)
+
+	if err = os.MkdirAll(dir, fs.ModeDir); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(filepath.Join(dir, hash+"_720x720_fit.jpg"), data, fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	size := AspectSize(4, 5, thumb.Sizes[thumb.Fit720])
+
+	t.Run("Crop", func(t *testing.T) {
+		fileName, err := FromAspect(hash, thumbPath, size, AspectCrop, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, hash+"_576x720_aspect_crop.jpg", filepath.Base(fileName))
+
+		img, err := imaging.Open(fileName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 576, img.Bounds().Dx())
+		assert.Equal(t, 720, img.Bounds().Dy())
+	})
+	t.Run("Pad", func(t *testing.T) {
+		fileName, err := FromAspect(hash, thumbPath, size, AspectPad, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, hash+"_576x720_aspect_pad.jpg", filepath.Base(fileName))
+
+		img, err := imaging.Open(fileName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 576, img.Bounds().Dx())
+		assert.Equal(t, 720, img.Bounds().Dy())
+	})
+	t.Run("InvalidMethod", func(t *testing.T) {
+		_, err := FromAspect(hash, thumbPath, size, "stretch", nil)
+		assert.Error(t, err)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := FromAspect("0000000000000000000000000000000000000000", thumbPath, size, AspectCrop, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,140 @@
+package crop
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Pixel budget limits for crops, see FromBudget.
+const (
+	BudgetMin = 32 * 32
+	BudgetMax = 4096 * 4096
+)
+
+// Budget represents the largest crop of an area that fits within a pixel budget, with its actual dimensions.
+type Budget struct {
+	Area     Area   `json:"area"`
+	Pixels   int    `json:"pixels"`
+	Width    int    `json:"w"`
+	Height   int    `json:"h"`
+	FileName string `json:"-"`
+}
+
+// BudgetFileName returns the file name of a crop of the area with the specified pixel budget.
+func BudgetFileName(hash, area string, pixels int, thumbPath string) (string, error) {
+	if len(hash) < 4 {
+		return "", fmt.Errorf("crop: invalid file hash %s", clean.Log(hash))
+	} else if len(thumbPath) < 1 {
+		return "", fmt.Errorf("crop: cache path missing")
+	} else if pixels < BudgetMin || pixels > BudgetMax {
+		return "", fmt.Errorf("crop: invalid pixel budget %d", pixels)
+	} else if AreaFromString(area).Empty() {
+		return "", fmt.Errorf("crop: invalid area %s", clean.Log(area))
+	}
+
+	return path.Join(thumb.Dir(hash, thumbPath), fmt.Sprintf("%s_budget%d_crop_%s%s", hash, pixels, area, fs.ExtJPEG)), nil
+}
+
+// FromBudget returns the largest crop of the area that fits within the pixel budget, and creates it from the
+// best fitting thumbnail if needed. Crops keep the aspect ratio of the area and are never upscaled, so their
+// dimensions may be smaller than the budget allows. Crops are cached by area and budget.
+func FromBudget(hash, area string, pixels int, thumbPath string) (result Budget, err error) {
+	a := AreaFromString(area)
+	result = Budget{Area: a, Pixels: pixels}
+
+	if result.FileName, err = BudgetFileName(hash, area, pixels, thumbPath); err != nil {
+		return result, err
+	} else if a.W <= 0 || a.H <= 0 {
+		return result, fmt.Errorf("crop: invalid area %s", clean.Log(area))
+	}
+
+	// Cached? Only the header needs to be decoded to get the dimensions.
+	if f, err := os.Open(result.FileName); err == nil {
+		cfg, _, err := image.DecodeConfig(f)
+		f.Close()
+
+		if err != nil {
+			log.Warnf("crop: %s while loading %s", err, filepath.Base(result.FileName))
+		} else {
+			result.Width, result.Height = cfg.Width, cfg.Height
+			return result, nil
+		}
+	}
+
+	// Find the smallest thumbnail that has enough pixels, assuming a square image.
+	thumbWidth := int(math.Ceil(math.Sqrt(float64(pixels) / float64(a.W*a.H))))
+	thumbName := findIdealThumbFileName(hash, thumbWidth, thumb.Dir(hash, thumbPath))
+
+	if thumbName == "" {
+		return result, fmt.Errorf("crop: no thumbnail found for %s", clean.Log(hash))
+	}
+
+	img, err := imaging.Open(thumbName)
+
+	if err != nil {
+		return result, err
+	}
+
+	min, max, _ := a.Bounds(img)
+	rect := image.Rectangle{Min: min, Max: max}.Add(img.Bounds().Min).Intersect(img.Bounds())
+
+	if rect.Empty() {
+		return result, fmt.Errorf("crop: area %s is outside of %s", clean.Log(area), filepath.Base(thumbName))
+	}
+
+	result.Width, result.Height = BudgetSize(rect.Dx(), rect.Dy(), pixels)
+
+	img = imaging.Crop(img, rect)
+
+	if result.Width != rect.Dx() || result.Height != rect.Dy() {
+		img = thumb.Resample(img, result.Width, result.Height, thumb.ResampleResize, thumb.ResampleDefault)
+	}
+
+	if err = thumb.SaveJpeg(img, result.FileName, thumb.JpegQuality); err != nil {
+		return result, err
+	}
+
+	log.Debugf("crop: saved %s", filepath.Base(result.FileName))
+
+	return result, nil
+}
+
+// BudgetSize returns the largest dimensions with the same aspect ratio that fit within the pixel budget,
+// or the dimensions as they are if they already fit.
+func BudgetSize(width, height, pixels int) (w, h int) {
+	if width <= 0 || height <= 0 || width*height <= pixels {
+		return width, height
+	}
+
+	scale := math.Sqrt(float64(pixels) / float64(width*height))
+	w, h = int(float64(width)*scale), int(float64(height)*scale)
+
+	if w < 1 {
+		w = 1
+	}
+
+	if h < 1 {
+		h = 1
+	}
+
+	// Compensate for rounding errors.
+	for w*h > pixels && w > 1 && h > 1 {
+		if w > h {
+			w--
+		} else {
+			h--
+		}
+	}
+
+	return w, h
+}
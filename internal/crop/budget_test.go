@@ -0,0 +1,96 @@
+package crop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestBudgetSize(t *testing.T) {
+	t.Run("Fits", func(t *testing.T) {
+		w, h := BudgetSize(100, 50, 10000)
+		assert.Equal(t, 100, w)
+		assert.Equal(t, 50, h)
+	})
+	t.Run("Square", func(t *testing.T) {
+		w, h := BudgetSize(400, 400, 160*160)
+		assert.Equal(t, 160, w)
+		assert.Equal(t, 160, h)
+	})
+	t.Run("Wide", func(t *testing.T) {
+		w, h := BudgetSize(300, 100, 1000)
+		assert.LessOrEqual(t, w*h, 1000)
+		assert.Equal(t, 54, w)
+		assert.Equal(t, 18, h)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		w, h := BudgetSize(0, 100, 1000)
+		assert.Equal(t, 0, w)
+		assert.Equal(t, 100, h)
+	})
+}
+
+func TestFromBudget(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	area := "0680bc31420d"
+	thumbPath := t.TempDir()
+
+	data, err := os.ReadFile("testdata/b/c/c/" + hash + "_720x720_fit.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := thumb.Dir(hash, thumbPath)
+
+	if err = os.MkdirAll(dir, fs.ModeDir); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(filepath.Join(dir, hash+"_720x720_fit.jpg"), data, fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Downscaled", func(t *testing.T) {
+		result, err := FromBudget(hash, area, 64*64, thumbPath)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, fs.FileExists(result.FileName))
+		assert.LessOrEqual(t, result.Width*result.Height, 64*64)
+		assert.Greater(t, result.Width*result.Height, 60*60)
+		assert.Equal(t, AreaFromString(area), result.Area)
+
+		cached, err := FromBudget(hash, area, 64*64, thumbPath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, cached)
+	})
+	t.Run("NotUpscaled", func(t *testing.T) {
+		result, err := FromBudget(hash, area, BudgetMax, thumbPath)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Less(t, result.Width*result.Height, BudgetMax)
+		assert.Greater(t, result.Width, 64)
+	})
+	t.Run("InvalidBudget", func(t *testing.T) {
+		_, err := FromBudget(hash, area, 1, thumbPath)
+		assert.Error(t, err)
+	})
+	t.Run("InvalidArea", func(t *testing.T) {
+		_, err := FromBudget(hash, "xyz", 64*64, thumbPath)
+		assert.Error(t, err)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := FromBudget("a0b1c2d3e4f5a0b1c2d3e4f5a0b1c2d3e4f5a0b1", area, 64*64, thumbPath)
+		assert.Error(t, err)
+	})
+}
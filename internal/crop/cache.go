@@ -6,13 +6,14 @@ import (
 
 	"path"
 
+	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
 
-// FromCache returns the crop file name if cached.
-func FromCache(hash, area string, size Size, thumbPath string) (fileName string, err error) {
-	fileName, err = FileName(hash, area, size.Width, size.Height, thumbPath)
+// FromCache returns the crop file name if cached in the specified format.
+func FromCache(hash, area string, size Size, thumbPath string, format fs.Type) (fileName string, err error) {
+	fileName, err = FormatFileName(hash, area, size.Width, size.Height, thumbPath, format)
 
 	if err != nil {
 		return fileName, err
@@ -25,8 +26,13 @@ func FromCache(hash, area string, size Size, thumbPath string) (fileName string,
 	return fileName, fmt.Errorf("%s not found", filepath.Base(fileName))
 }
 
-// FileName returns the crop file name based on cache path, size, and area.
+// FileName returns the JPEG crop file name based on cache path, size, and area.
 func FileName(hash, area string, width, height int, thumbPath string) (fileName string, err error) {
+	return FormatFileName(hash, area, width, height, thumbPath, fs.ImageJPEG)
+}
+
+// FormatFileName returns the crop file name based on cache path, size, area, and image format.
+func FormatFileName(hash, area string, width, height int, thumbPath string, format fs.Type) (fileName string, err error) {
 	if len(hash) < 4 {
 		return "", fmt.Errorf("crop: invalid file hash %s", clean.Log(hash))
 	}
@@ -39,7 +45,7 @@ func FileName(hash, area string, width, height int, thumbPath string) (fileName
 		return "", fmt.Errorf("crop: invalid size %dx%d", width, height)
 	}
 
-	fileName = path.Join(thumbPath, hash[0:1], hash[1:2], hash[2:3], fmt.Sprintf("%s_%dx%d_crop_%s%s", hash, width, height, area, fs.ExtJPEG))
+	fileName = path.Join(thumb.Dir(hash, thumbPath), fmt.Sprintf("%s_%dx%d_crop_%s%s", hash, width, height, area, format.DefaultExt()))
 
 	return fileName, nil
 }
@@ -1,9 +1,16 @@
 package crop
 
 import (
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
+	"github.com/disintegration/imaging"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
 )
 
 func TestFileName(t *testing.T) {
@@ -47,3 +54,115 @@ func TestFileName(t *testing.T) {
 		assert.Empty(t, result)
 	})
 }
+
+func TestFormatFileName(t *testing.T) {
+	t.Run("WebP", func(t *testing.T) {
+		result, err := FormatFileName("147da9f0261e2d81e9a52b266f1945556588bb78", "042008007010", 160, 160, "/example", fs.ImageWebP)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "/example/1/4/7/147da9f0261e2d81e9a52b266f1945556588bb78_160x160_crop_042008007010.webp", result)
+	})
+}
+
+func TestFromRequest(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	area := "042008007010"
+	size := Sizes[Tile160]
+
+	t.Run("FallbackToJpeg", func(t *testing.T) {
+		fileName, err := FromRequest(hash, area, size, "testdata", fs.ImageWebP)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(fileName)
+
+		if thumb.CwebpBin == "" {
+			assert.Equal(t, fs.ImageJPEG, fs.FileType(fileName))
+		} else {
+			assert.Equal(t, fs.ImageWebP, fs.FileType(fileName))
+		}
+	})
+}
+
+func TestFromRequest_Concurrent(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	area := "042008007010"
+	size := Sizes[Tile160]
+	thumbPath := t.TempDir()
+
+	data, err := os.ReadFile("testdata/b/c/c/" + hash + "_720x720_fit.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thumbDir := thumb.Dir(hash, thumbPath)
+
+	if err = os.MkdirAll(thumbDir, fs.ModeDir); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(filepath.Join(thumbDir, hash+"_720x720_fit.jpg"), data, fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 8
+	results := make([]string, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = FromRequest(hash, area, size, thumbPath, fs.ImageJPEG)
+		}(i)
+	}
+
+	wg.Wait()
+
+	expected, _ := FileName(hash, area, size.Width, size.Height, thumbPath)
+
+	for i := 0; i < n; i++ {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, expected, results[i])
+	}
+
+	assert.True(t, fs.FileExists(expected))
+
+	// No temporary files must be left behind.
+	if matches, err := filepath.Glob(filepath.Join(thumbDir, "*.tmp.jpg")); err != nil {
+		t.Fatal(err)
+	} else {
+		assert.Empty(t, matches)
+	}
+}
+
+func TestFromRequest_Entropy(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	area := FullArea().String()
+	size := Sizes[Wide640]
+	size.Options = []thumb.ResampleOption{thumb.ResampleFillEntropy, thumb.ResampleDefault}
+
+	fileName, err := FromRequest(hash, area, size, "testdata", fs.ImageJPEG)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(fileName)
+
+	img, err := imaging.Open(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 640, img.Bounds().Dx())
+	assert.Equal(t, 360, img.Bounds().Dy())
+}
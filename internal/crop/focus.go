@@ -0,0 +1,149 @@
+package crop
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// FocusName is the name of hint areas that mark a user-defined focus point, see NewFocus.
+const FocusName = "focus"
+
+// FocusMargin is the minimum distance of the focus point from the edges of a crop, relative to its size.
+var FocusMargin = 0.15
+
+// NewFocus returns a hint area that marks the focus point with the specified relative coordinates,
+// which all crops keep in frame, see SelectArea.
+func NewFocus(x, y float32) Area {
+	return Area{Name: FocusName, X: clipVal(x), Y: clipVal(y)}
+}
+
+// IsFocus tests if the area marks a focus point rather than a face.
+func (a Area) IsFocus() bool {
+	return a.Name == FocusName
+}
+
+// Focus returns the focus point passed as hint, if any.
+func (h Areas) Focus() (Area, bool) {
+	for _, a := range h {
+		if a.IsFocus() {
+			return a, true
+		}
+	}
+
+	return Area{}, false
+}
+
+// Faces returns the hints without focus points.
+func (h Areas) Faces() (faces Areas) {
+	for _, a := range h {
+		if !a.IsFocus() {
+			faces = append(faces, a)
+		}
+	}
+
+	return faces
+}
+
+// keepFocus shifts the area within the bounds as little as possible, so that the focus point is in
+// frame with a margin of FocusMargin, if the area is smaller than the bounds.
+func keepFocus(area, bounds image.Rectangle, focus Area) image.Rectangle {
+	if area.Empty() {
+		return area
+	}
+
+	px := float64(bounds.Min.X) + float64(focus.X)*float64(bounds.Dx())
+	py := float64(bounds.Min.Y) + float64(focus.Y)*float64(bounds.Dy())
+
+	dx := focusShift(px, float64(area.Min.X), float64(area.Dx()))
+	dy := focusShift(py, float64(area.Min.Y), float64(area.Dy()))
+
+	// Keep the area within the image bounds.
+	dx = clampInt(dx, bounds.Min.X-area.Min.X, bounds.Max.X-area.Max.X)
+	dy = clampInt(dy, bounds.Min.Y-area.Min.Y, bounds.Max.Y-area.Max.Y)
+
+	return area.Add(image.Pt(dx, dy))
+}
+
+// focusShift returns the distance by which a window must be moved so that the point is in it with a margin.
+func focusShift(point, pos, length float64) int {
+	margin := length * FocusMargin
+
+	if point < pos+margin {
+		return int(math.Floor(point - margin - pos))
+	} else if point > pos+length-margin {
+		return int(math.Ceil(point + margin - pos - length))
+	}
+
+	return 0
+}
+
+// RegenerateHinted removes the cached banner crops and crop variants of the file with the specified hash,
+// which depend on the faces and focus point, and creates them again in the same sizes with the new hints.
+// It returns the number of files created.
+func RegenerateHinted(hash, thumbPath string, hints Areas) (created int, err error) {
+	if len(hash) < 4 {
+		return 0, fmt.Errorf("crop: invalid file hash %s", hash)
+	} else if len(thumbPath) < 1 {
+		return 0, fmt.Errorf("crop: cache path missing")
+	}
+
+	cached, err := filepath.Glob(path.Join(thumb.Dir(hash, thumbPath), hash) + "_*x*_*")
+
+	if err != nil {
+		return 0, err
+	}
+
+	heroes := make(map[Name]Size)
+	variants := make(map[Name]Size)
+
+	for _, fileName := range cached {
+		var width, height int
+		var suffix string
+
+		if _, err = fmt.Sscanf(strings.TrimPrefix(filepath.Base(fileName), hash+"_"), "%dx%d_%s", &width, &height, &suffix); err != nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(suffix, SelectorHero):
+			if size, ok := HeroSizes.Find(width, height); ok {
+				heroes[size.Name] = size
+			}
+		case strings.HasPrefix(suffix, "variant"):
+			if size, ok := Sizes.Find(width, height); ok {
+				variants[size.Name] = size
+			}
+		default:
+			continue
+		}
+
+		if err = os.Remove(fileName); err != nil {
+			log.Warnf("crop: %s", err)
+		}
+	}
+
+	for _, size := range heroes {
+		if _, err = FromHero(hash, thumbPath, size, hints); err != nil {
+			return created, err
+		}
+
+		created++
+	}
+
+	for _, size := range variants {
+		if _, err = FromVariants(hash, thumbPath, size, hints); err != nil {
+			return created, err
+		}
+
+		created += len(VariantSelectors)
+	}
+
+	return created, nil
+}
@@ -0,0 +1,116 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestNewFocus(t *testing.T) {
+	focus := NewFocus(0.25, 1.5)
+
+	assert.True(t, focus.IsFocus())
+	assert.Equal(t, float32(0.25), focus.X)
+	assert.Equal(t, float32(1), focus.Y)
+	assert.False(t, NewArea("face", 0.1, 0.1, 0.2, 0.2).IsFocus())
+}
+
+func TestAreas_Focus(t *testing.T) {
+	face := NewArea("face", 0.1, 0.1, 0.2, 0.2)
+
+	t.Run("None", func(t *testing.T) {
+		_, ok := Areas{face}.Focus()
+		assert.False(t, ok)
+		assert.Equal(t, Areas{face}, Areas{face}.Faces())
+	})
+	t.Run("Found", func(t *testing.T) {
+		hints := Areas{face, NewFocus(0.8, 0.4)}
+		focus, ok := hints.Focus()
+		assert.True(t, ok)
+		assert.Equal(t, float32(0.8), focus.X)
+		assert.Equal(t, Areas{face}, hints.Faces())
+	})
+}
+
+func TestSelectArea_Focus(t *testing.T) {
+	img := imaging.New(900, 300, color.Gray{Y: 128})
+	size := Sizes[Tile224]
+
+	t.Run("Center", func(t *testing.T) {
+		area, used := SelectArea(img, size, SelectorCenter, nil)
+		assert.Equal(t, SelectorCenter, used)
+		assert.Equal(t, image.Rect(300, 0, 600, 300), area)
+	})
+	t.Run("Left", func(t *testing.T) {
+		area, used := SelectArea(img, size, SelectorCenter, Areas{NewFocus(0.1, 0.5)})
+		assert.Equal(t, SelectorCenter, used)
+		assert.Equal(t, 300, area.Dx())
+		assert.Equal(t, 45, area.Min.X)
+	})
+	t.Run("Edge", func(t *testing.T) {
+		area, _ := SelectArea(img, size, SelectorCenter, Areas{NewFocus(0.99, 0.5)})
+		assert.Equal(t, image.Rect(600, 0, 900, 300), area)
+	})
+	t.Run("InFrame", func(t *testing.T) {
+		area, _ := SelectArea(img, size, SelectorCenter, Areas{NewFocus(0.5, 0.5)})
+		assert.Equal(t, image.Rect(300, 0, 600, 300), area)
+	})
+	t.Run("FacesIgnoreFocus", func(t *testing.T) {
+		area, used := SelectArea(img, size, SelectorFace, Areas{NewFocus(0.5, 0.5)})
+		assert.NotEqual(t, SelectorFace, used)
+		assert.Equal(t, 300, area.Dx())
+	})
+}
+
+func TestRegenerateHinted(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	thumbPath := t.TempDir()
+
+	data, err := os.ReadFile("testdata/b/c/c/" + hash + "_720x720_fit.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := thumb.Dir(hash, thumbPath)
+
+	if err = os.MkdirAll(dir, fs.ModeDir); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(filepath.Join(dir, hash+"_720x720_fit.jpg"), data, fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	heroName, err := FromHero(hash, thumbPath, HeroSizes[Hero1500], nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = FromVariants(hash, thumbPath, Sizes[Tile224], nil); err != nil {
+		t.Fatal(err)
+	}
+
+	hints := Areas{NewFocus(0.2, 0.3)}
+	created, err := RegenerateHinted(hash, thumbPath, hints)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1+len(VariantSelectors), created)
+	assert.False(t, fs.FileExists(heroName))
+	assert.True(t, fs.FileExists(filepath.Join(dir, hash+"_720x720_fit.jpg")))
+
+	newName, err := HeroFileName(hash, thumbPath, HeroSizes[Hero1500], hints)
+
+	assert.NoError(t, err)
+	assert.True(t, fs.FileExists(newName))
+
+	_, err = RegenerateHinted("xx", thumbPath, hints)
+	assert.Error(t, err)
+}
@@ -0,0 +1,165 @@
+package crop
+
+import (
+	"fmt"
+	"image"
+	"path"
+	"path/filepath"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// HeroSelector selects a wide banner area that is vertically centered, horizontally placed on the part with
+// the most detail, and shifted so that faces passed as hints are not cut if possible. Focus points are kept
+// in frame by SelectArea.
+type HeroSelector struct{}
+
+// Name returns the selector name.
+func (HeroSelector) Name() string {
+	return SelectorHero
+}
+
+// Select returns the largest area matching the aspect ratio that keeps the subject and faces visible.
+func (HeroSelector) Select(img image.Image, size Size, hints Areas) (image.Rectangle, bool) {
+	ratio := size.Ratio()
+	b := img.Bounds()
+	imgW, imgH := b.Dx(), b.Dy()
+
+	if ratio <= 0 || imgW <= 0 || imgH <= 0 {
+		return image.Rectangle{}, false
+	}
+
+	// Find the largest area matching the aspect ratio.
+	w, h := imgW, int(float64(imgW)/ratio)
+
+	if h > imgH {
+		w, h = int(float64(imgH)*ratio), imgH
+	}
+
+	// Center vertically, and place horizontally on the part with the most detail, e.g. in panoramas.
+	x, y := (imgW-w)/2, (imgH-h)/2
+
+	if w < imgW {
+		detail := thumb.EntropyArea(img, size.Width, size.Height).Sub(b.Min)
+		x = (detail.Min.X+detail.Max.X)/2 - w/2
+	}
+
+	// Shift the area so that it contains all faces, or centers them if they do not fit.
+	var faces image.Rectangle
+
+	for _, a := range hints.Faces() {
+		if a.Empty() {
+			continue
+		}
+
+		min, max, _ := a.Bounds(img)
+		faces = faces.Union(image.Rectangle{Min: min, Max: max}.Sub(b.Min))
+	}
+
+	if !faces.Empty() {
+		x = heroShift(x, w, faces.Min.X, faces.Max.X)
+		y = heroShift(y, h, faces.Min.Y, faces.Max.Y)
+	}
+
+	// Keep the area within the image bounds.
+	x = clampInt(x, 0, imgW-w)
+	y = clampInt(y, 0, imgH-h)
+
+	return image.Rect(x, y, x+w, y+h).Add(b.Min), true
+}
+
+// heroShift returns the position of a window with the specified length, so that it contains the
+// range from min to max, or centers it on the range if it is too long.
+func heroShift(pos, length, min, max int) int {
+	if max-min > length {
+		return (min+max)/2 - length/2
+	} else if pos > min {
+		return min
+	} else if pos+length < max {
+		return max - length
+	}
+
+	return pos
+}
+
+// clampInt returns the value limited to the range from min to max.
+func clampInt(v, min, max int) int {
+	if v > max {
+		v = max
+	}
+
+	if v < min {
+		v = min
+	}
+
+	return v
+}
+
+// HeroFileName returns the file name of a banner crop. Since the area depends on the faces, their
+// version is part of the name if any, so that a new file is created when faces are added or moved.
+func HeroFileName(hash, thumbPath string, size Size, hints Areas) (string, error) {
+	return hintsFileName(hash, thumbPath, size, SelectorHero, hints, fs.ExtJPEG)
+}
+
+// hintsFileName returns the name of a cached file with the specified size, suffix, and extension,
+// which includes the version of the hints if any, see HeroFileName.
+func hintsFileName(hash, thumbPath string, size Size, suffix string, hints Areas, ext string) (string, error) {
+	if len(hash) < 4 {
+		return "", fmt.Errorf("crop: invalid file hash %s", clean.Log(hash))
+	} else if len(thumbPath) < 1 {
+		return "", fmt.Errorf("crop: cache path missing")
+	} else if size.Width < 1 || size.Height < 1 {
+		return "", fmt.Errorf("crop: invalid size %dx%d", size.Width, size.Height)
+	}
+
+	base := fmt.Sprintf("%s_%dx%d_%s", hash, size.Width, size.Height, suffix)
+
+	if len(hints) > 0 {
+		areas := make([]string, len(hints))
+
+		for i := range hints {
+			areas[i] = hints[i].String()
+		}
+
+		base += "_" + Version(areas)
+	}
+
+	return path.Join(thumb.Dir(hash, thumbPath), base+ext), nil
+}
+
+// FromHero returns the file name of a wide banner crop of the whole image, and creates it from the
+// best fitting thumbnail if needed, see HeroSelector. Face areas can be passed as hints.
+func FromHero(hash, thumbPath string, size Size, hints Areas) (fileName string, err error) {
+	if fileName, err = HeroFileName(hash, thumbPath, size, hints); err != nil {
+		return "", err
+	} else if fs.FileExists(fileName) {
+		return fileName, nil
+	}
+
+	thumbName := findIdealThumbFileName(hash, size.Width, thumb.Dir(hash, thumbPath))
+
+	if thumbName == "" {
+		return "", fmt.Errorf("crop: no thumbnail found for %s", clean.Log(hash))
+	}
+
+	img, err := imaging.Open(thumbName)
+
+	if err != nil {
+		return "", err
+	}
+
+	area, _ := SelectArea(img, size, SelectorHero, hints)
+	img = thumb.Resample(imaging.Crop(img, area), size.Width, size.Height, size.Options...)
+
+	if err = thumb.SaveJpeg(img, fileName, thumb.JpegQuality); err != nil {
+		return "", err
+	}
+
+	log.Debugf("crop: saved %s", filepath.Base(fileName))
+
+	return fileName, nil
+}
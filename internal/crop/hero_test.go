@@ -0,0 +1,107 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestHeroSelector_Select(t *testing.T) {
+	size := HeroSizes[Hero1500]
+
+	t.Run("Centered", func(t *testing.T) {
+		img := imaging.New(900, 600, color.Gray{Y: 128})
+		area, ok := HeroSelector{}.Select(img, size, nil)
+
+		assert.True(t, ok)
+		assert.Equal(t, image.Rect(0, 150, 900, 450), area)
+	})
+	t.Run("Faces", func(t *testing.T) {
+		img := imaging.New(900, 600, color.Gray{Y: 128})
+		faces := Areas{NewArea("face", 0.4, 0.05, 0.1, 0.15)}
+		area, ok := HeroSelector{}.Select(img, size, faces)
+
+		assert.True(t, ok)
+		assert.Equal(t, image.Rect(0, 30, 900, 330), area)
+	})
+	t.Run("LargeFaces", func(t *testing.T) {
+		img := imaging.New(900, 600, color.Gray{Y: 128})
+		faces := Areas{NewArea("face", 0.1, 0.0, 0.2, 0.3), NewArea("face", 0.6, 0.6, 0.2, 0.4)}
+		area, ok := HeroSelector{}.Select(img, size, faces)
+
+		assert.True(t, ok)
+		assert.Equal(t, 300, area.Dy())
+		assert.Equal(t, 150, area.Min.Y)
+	})
+	t.Run("Panorama", func(t *testing.T) {
+		img := imaging.New(2000, 300, color.Gray{Y: 128})
+		area, ok := HeroSelector{}.Select(img, size, nil)
+
+		assert.True(t, ok)
+		assert.Equal(t, 900, area.Dx())
+		assert.Equal(t, 300, area.Dy())
+	})
+}
+
+func TestHeroFileName(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	size := HeroSizes[Hero1500]
+
+	t.Run("NoFaces", func(t *testing.T) {
+		fileName, err := HeroFileName(hash, "testdata", size, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "testdata/b/c/c/"+hash+"_1500x500_hero.jpg", fileName)
+	})
+	t.Run("Faces", func(t *testing.T) {
+		fileName, err := HeroFileName(hash, "testdata", size, Areas{NewArea("face", 0.4, 0.05, 0.1, 0.15)})
+		assert.NoError(t, err)
+		assert.Regexp(t, `_1500x500_hero_[0-9a-f]{8}\.jpg$`, fileName)
+	})
+	t.Run("InvalidHash", func(t *testing.T) {
+		_, err := HeroFileName("xx", "testdata", size, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestFromHero(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	thumbPath := t.TempDir()
+	size := HeroSizes[Hero1500]
+
+	data, err := os.ReadFile("testdata/b/c/c/" + hash + "_720x720_fit.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := thumb.Dir(hash, thumbPath)
+
+	if err = os.MkdirAll(dir, fs.ModeDir); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(filepath.Join(dir, hash+"_720x720_fit.jpg"), data, fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	fileName, err := FromHero(hash, thumbPath, size, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := imaging.Open(fileName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1500, img.Bounds().Dx())
+	assert.Equal(t, 500, img.Bounds().Dy())
+
+	_, err = FromHero("0000000000000000000000000000000000000000", thumbPath, size, nil)
+	assert.Error(t, err)
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"image"
 	"os"
-	"path"
 	"path/filepath"
 	"strings"
 
@@ -71,6 +70,12 @@ func ImageFromThumb(thumbName string, area Area, size Size, cache bool) (img ima
 	// Get absolute crop coordinates and dimension.
 	min, max, dim := area.Bounds(img)
 
+	// Expand area to match the aspect ratio of non-square sizes, unless the part
+	// of the whole image with the most detail is cropped when it is resampled.
+	if !size.Square() && !(area.Full() && size.Entropy()) {
+		min, max, dim = area.RatioBounds(img, size)
+	}
+
 	if dim < size.Width {
 		log.Debugf("crop: %s is too small, upscaling %dpx to %dpx", filepath.Base(thumbName), dim, size.Width)
 	}
@@ -111,11 +116,11 @@ func ThumbFileName(hash string, area Area, size Size, thumbPath string) (string,
 		return "", fmt.Errorf("invalid crop size %d", size.Width)
 	}
 
-	filePath := path.Join(thumbPath, hash[0:1], hash[1:2], hash[2:3])
+	filePath := thumb.Dir(hash, thumbPath)
 	fileName := findIdealThumbFileName(hash, area.FileWidth(size), filePath)
 
 	if fileName == "" {
-		return "", fmt.Errorf("not found")
+		return "", ErrNotFound
 	}
 
 	// Resolve symlinks.
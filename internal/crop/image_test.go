@@ -52,6 +52,7 @@ func TestThumbFileName(t *testing.T) {
 			t.Fatal(err)
 		}
 		assert.Contains(t, err.Error(), "not found")
+		assert.ErrorIs(t, err, ErrNotFound)
 	})
 	t.Run("File exists", func(t *testing.T) {
 		a := NewArea("face", 1.000, 0.33333, 0.001, 0.5)
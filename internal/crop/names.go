@@ -10,12 +10,25 @@ func (n Name) Jpeg() string {
 	return string(n) + fs.ExtJPEG
 }
 
+// Format returns the crop name with the default file extension of the image format as string.
+func (n Name) Format(f fs.Type) string {
+	if f == fs.ImageJPEG || f == "" {
+		return n.Jpeg()
+	}
+
+	return string(n) + f.DefaultExt()
+}
+
 // Names of standard crop sizes.
 const (
-	Tile50  Name = "tile_50"
-	Tile100 Name = "tile_100"
-	Tile160 Name = "tile_160"
-	Tile224 Name = "tile_224"
-	Tile320 Name = "tile_320"
-	Tile500 Name = "tile_500"
+	Tile50   Name = "tile_50"
+	Tile100  Name = "tile_100"
+	Tile160  Name = "tile_160"
+	Tile224  Name = "tile_224"
+	Tile320  Name = "tile_320"
+	Tile500  Name = "tile_500"
+	Std640   Name = "std_640"
+	Wide640  Name = "wide_640"
+	Wide1280 Name = "wide_1280"
+	Hero1500 Name = "hero_1500"
 )
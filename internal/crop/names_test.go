@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
 )
 
 func TestName_Jpeg(t *testing.T) {
@@ -14,3 +16,12 @@ func TestName_Jpeg(t *testing.T) {
 		assert.Equal(t, "tile_50.jpg", Tile50.Jpeg())
 	})
 }
+
+func TestName_Format(t *testing.T) {
+	t.Run("WebP", func(t *testing.T) {
+		assert.Equal(t, "tile_320.webp", Tile320.Format(fs.ImageWebP))
+	})
+	t.Run("JPEG", func(t *testing.T) {
+		assert.Equal(t, "tile_50.jpg", Tile50.Format(fs.ImageJPEG))
+	})
+}
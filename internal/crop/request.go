@@ -6,18 +6,47 @@ import (
 	"image"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/disintegration/imaging"
+	"golang.org/x/sync/singleflight"
+
 	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
 
-// FromRequest returns the crop file name for an image hash, and creates it if needed.
-func FromRequest(hash, area string, size Size, thumbPath string) (fileName string, err error) {
-	if fileName, err = FromCache(hash, area, size, thumbPath); err == nil {
+// cropRequests coalesces concurrent requests for the same crop, so that it is only created once.
+var cropRequests singleflight.Group
+
+// FromRequest returns the crop file name for an image hash in the specified format, and creates it if needed.
+// Other formats are encoded from the JPEG crop, which is returned instead if encoding fails.
+// Concurrent requests for the same crop wait for the first one and share its result.
+func FromRequest(hash, area string, size Size, thumbPath string, format fs.Type) (fileName string, err error) {
+	if fileName, err = FromCache(hash, area, size, thumbPath, format); err == nil || fileName == "" {
 		return fileName, err
 	}
 
+	// The cache file name is unique for each hash, size, area, and format.
+	result, err, _ := cropRequests.Do(fileName, func() (interface{}, error) {
+		// Another request may have created the crop in the meantime.
+		if cached, err := FromCache(hash, area, size, thumbPath, format); err == nil {
+			return cached, nil
+		} else if format != fs.ImageJPEG {
+			return fromJpeg(hash, area, size, thumbPath, format)
+		}
+
+		return fromThumb(hash, area, size, thumbPath)
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return result.(string), nil
+}
+
+// fromThumb creates a JPEG crop from the best matching thumbnail and returns its file name.
+func fromThumb(hash, area string, size Size, thumbPath string) (fileName string, err error) {
 	a := AreaFromString(area)
 
 	thumbName, err := ThumbFileName(hash, a, size, thumbPath)
@@ -45,6 +74,12 @@ func FromRequest(hash, area string, size Size, thumbPath string) (fileName strin
 	// Get absolute crop coordinates and dimension.
 	min, max, dim := a.Bounds(img)
 
+	// Expand area to match the aspect ratio of non-square sizes, unless the part
+	// of the whole image with the most detail is cropped when it is resampled.
+	if !size.Square() && !(a.Full() && size.Entropy()) {
+		min, max, dim = a.RatioBounds(img, size)
+	}
+
 	if dim < size.Width {
 		log.Debugf("crop: %s is too small, upscaling %dpx to %dpx", filepath.Base(thumbName), dim, size.Width)
 	}
@@ -55,13 +90,48 @@ func FromRequest(hash, area string, size Size, thumbPath string) (fileName strin
 	// Resample crop area.
 	img = thumb.Resample(img, size.Width, size.Height, size.Options...)
 
-	// Save crop image.
-	if err := imaging.Save(img, cropName); err != nil {
+	// Save crop image to a temporary file first, so that incomplete files are never served.
+	tmpName := strings.TrimSuffix(cropName, fs.ExtJPEG) + ".tmp" + fs.ExtJPEG
+
+	if err := imaging.Save(img, tmpName); err != nil {
 		log.Errorf("failed saving %s - no permission or disk full?", filepath.Base(cropName))
 		log.Debug(err.Error())
+		_ = os.Remove(tmpName)
+	} else if err = os.Rename(tmpName, cropName); err != nil {
+		log.Errorf("failed saving %s - no permission or disk full?", filepath.Base(cropName))
+		log.Debug(err.Error())
+		_ = os.Remove(tmpName)
 	} else {
 		log.Debugf("saved %s", filepath.Base(cropName))
 	}
 
 	return cropName, nil
 }
+
+// fromJpeg encodes the JPEG crop in another format, and returns the JPEG crop file name if this fails.
+func fromJpeg(hash, area string, size Size, thumbPath string, format fs.Type) (fileName string, err error) {
+	jpegName, err := FromRequest(hash, area, size, thumbPath, fs.ImageJPEG)
+
+	if err != nil {
+		return "", err
+	}
+
+	if fileName, err = FormatFileName(hash, area, size.Width, size.Height, thumbPath, format); err != nil {
+		return "", err
+	}
+
+	img, err := imaging.Open(jpegName)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err = thumb.SaveFormat(img, fileName, format, thumb.JpegQuality); err != nil {
+		log.Debugf("crop: %s while saving %s, using jpeg", err, filepath.Base(fileName))
+		return jpegName, nil
+	}
+
+	log.Debugf("saved %s", filepath.Base(fileName))
+
+	return fileName, nil
+}
@@ -0,0 +1,200 @@
+package crop
+
+import (
+	"image"
+	"sync"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// Selector chooses the image area to crop for a target size, so that custom crop logic can be compiled in
+// and registered with RegisterSelector, without changing the crop package.
+//
+// Select is called with the decoded image, the target size, and optional hints such as face areas. It must
+// return an absolute rectangle within the image bounds that should have the aspect ratio of the target size,
+// as it is resized to the exact dimensions afterwards. If the selector cannot make a choice, e.g. because
+// it requires hints that are missing, it returns false and the next selector is consulted. Implementations
+// must be safe for concurrent use and must not modify the image.
+type Selector interface {
+	Name() string
+	Select(img image.Image, size Size, hints Areas) (area image.Rectangle, ok bool)
+}
+
+// DefaultSelector is the name of the selector that is consulted first if no other name is specified.
+var DefaultSelector = SelectorFace
+
+// Names of the built-in selectors.
+const (
+	SelectorCenter    = "center"
+	SelectorAttention = "attention"
+	SelectorFace      = "face"
+	SelectorHero      = "hero"
+)
+
+var (
+	selectors      []Selector
+	selectorsMutex sync.RWMutex
+)
+
+func init() {
+	RegisterSelector(CenterSelector{})
+	RegisterSelector(HeroSelector{})
+	RegisterSelector(AttentionSelector{})
+	RegisterSelector(FaceSelector{})
+}
+
+// RegisterSelector adds a selector, or replaces the registered selector with the same name.
+func RegisterSelector(s Selector) {
+	if s == nil || s.Name() == "" {
+		return
+	}
+
+	selectorsMutex.Lock()
+	defer selectorsMutex.Unlock()
+
+	for i := range selectors {
+		if selectors[i].Name() == s.Name() {
+			selectors[i] = s
+			return
+		}
+	}
+
+	selectors = append(selectors, s)
+}
+
+// FindSelector returns the registered selector with the specified name, if any.
+func FindSelector(name string) Selector {
+	selectorsMutex.RLock()
+	defer selectorsMutex.RUnlock()
+
+	for _, s := range selectors {
+		if s.Name() == name {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// SelectArea returns the crop area chosen by the named selector and the name of the selector that was used.
+// If the selector is unknown or cannot make a choice, the most recently registered selectors are consulted
+// first, so that custom selectors take precedence over the built-in ones. As a last resort, the center of
+// the image is used. If a focus point is passed as hint, the area is shifted to keep it in frame, see NewFocus.
+func SelectArea(img image.Image, size Size, name string, hints Areas) (image.Rectangle, string) {
+	area, used := selectArea(img, size, name, hints)
+
+	if focus, ok := hints.Focus(); ok {
+		area = keepFocus(area, img.Bounds(), focus)
+	}
+
+	return area, used
+}
+
+// selectArea returns the crop area chosen by the named selector or a fallback, see SelectArea.
+func selectArea(img image.Image, size Size, name string, hints Areas) (image.Rectangle, string) {
+	if name == "" {
+		name = DefaultSelector
+	}
+
+	if s := FindSelector(name); s != nil {
+		if area, ok := s.Select(img, size, hints); ok && !area.Empty() {
+			return area.Intersect(img.Bounds()), s.Name()
+		}
+	}
+
+	selectorsMutex.RLock()
+	list := make([]Selector, len(selectors))
+	copy(list, selectors)
+	selectorsMutex.RUnlock()
+
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].Name() == name {
+			continue
+		} else if area, ok := list[i].Select(img, size, hints); ok && !area.Empty() {
+			return area.Intersect(img.Bounds()), list[i].Name()
+		}
+	}
+
+	area, _ := CenterSelector{}.Select(img, size, hints)
+
+	return area, SelectorCenter
+}
+
+// ImageFromSelector crops the area chosen by the named selector and resamples it to the crop size.
+func ImageFromSelector(img image.Image, size Size, name string, hints Areas) image.Image {
+	area, _ := SelectArea(img, size, name, hints)
+
+	return thumb.Resample(imaging.Crop(img, area), size.Width, size.Height, size.Options...)
+}
+
+// CenterSelector selects the largest area in the center of the image.
+type CenterSelector struct{}
+
+// Name returns the selector name.
+func (CenterSelector) Name() string {
+	return SelectorCenter
+}
+
+// Select returns the largest area in the center of the image that matches the aspect ratio.
+func (CenterSelector) Select(img image.Image, size Size, _ Areas) (image.Rectangle, bool) {
+	b := img.Bounds()
+
+	if area := fitRatio(b.Sub(b.Min), b.Size(), size.Ratio()); !area.Empty() {
+		return area.Add(b.Min), true
+	}
+
+	return b, true
+}
+
+// AttentionSelector selects the image area with the most detail, see thumb.EntropyArea.
+type AttentionSelector struct{}
+
+// Name returns the selector name.
+func (AttentionSelector) Name() string {
+	return SelectorAttention
+}
+
+// Select returns the area with the most detail, preferring the center of the image.
+func (AttentionSelector) Select(img image.Image, size Size, _ Areas) (image.Rectangle, bool) {
+	if size.Width <= 0 || size.Height <= 0 {
+		return image.Rectangle{}, false
+	}
+
+	return thumb.EntropyArea(img, size.Width, size.Height), true
+}
+
+// FaceSelector selects the area that contains all faces passed as hints.
+type FaceSelector struct{}
+
+// Name returns the selector name.
+func (FaceSelector) Name() string {
+	return SelectorFace
+}
+
+// Select returns the smallest area containing all hints, expanded to match the aspect ratio.
+func (FaceSelector) Select(img image.Image, size Size, hints Areas) (image.Rectangle, bool) {
+	faces := hints.Faces()
+
+	if len(faces) == 0 {
+		return image.Rectangle{}, false
+	}
+
+	var union image.Rectangle
+
+	for _, a := range faces {
+		if a.Empty() {
+			continue
+		}
+
+		min, max, _ := a.Bounds(img)
+		union = union.Union(image.Rectangle{Min: min, Max: max})
+	}
+
+	if area := fitRatio(union, img.Bounds().Max, size.Ratio()); !area.Empty() {
+		return area, true
+	}
+
+	return image.Rectangle{}, false
+}
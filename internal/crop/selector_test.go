@@ -0,0 +1,95 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// testSelector is a custom selector that always selects the top left corner.
+type testSelector struct{}
+
+func (testSelector) Name() string {
+	return "test"
+}
+
+func (testSelector) Select(img image.Image, size Size, hints Areas) (image.Rectangle, bool) {
+	return image.Rect(0, 0, size.Width, size.Height), true
+}
+
+func TestSelectArea(t *testing.T) {
+	img := imaging.New(1000, 500, color.Gray{Y: 128})
+
+	t.Run("Center", func(t *testing.T) {
+		area, name := SelectArea(img, Sizes[Tile160], SelectorCenter, nil)
+
+		assert.Equal(t, SelectorCenter, name)
+		assert.Equal(t, image.Rect(250, 0, 750, 500), area)
+	})
+	t.Run("CenterWide", func(t *testing.T) {
+		area, name := SelectArea(img, Sizes[Wide640], SelectorCenter, nil)
+
+		assert.Equal(t, SelectorCenter, name)
+		assert.Equal(t, image.Rect(56, 0, 944, 500), area)
+	})
+	t.Run("Face", func(t *testing.T) {
+		faces := Areas{NewArea("face", 0.1, 0.1, 0.1, 0.2), NewArea("face", 0.3, 0.1, 0.1, 0.2)}
+		area, name := SelectArea(img, Sizes[Tile160], SelectorFace, faces)
+
+		assert.Equal(t, SelectorFace, name)
+		assert.Equal(t, image.Rect(100, 0, 400, 300), area)
+	})
+	t.Run("NoFaces", func(t *testing.T) {
+		area, name := SelectArea(img, Sizes[Tile160], "", nil)
+
+		assert.Equal(t, SelectorAttention, name)
+		assert.Equal(t, 500, area.Dx())
+		assert.Equal(t, 500, area.Dy())
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		_, name := SelectArea(img, Sizes[Tile160], "foo", nil)
+
+		assert.Equal(t, SelectorAttention, name)
+	})
+	t.Run("Custom", func(t *testing.T) {
+		RegisterSelector(testSelector{})
+		defer func() {
+			selectorsMutex.Lock()
+			selectors = selectors[:len(selectors)-1]
+			selectorsMutex.Unlock()
+		}()
+
+		assert.NotNil(t, FindSelector("test"))
+
+		area, name := SelectArea(img, Sizes[Tile160], "test", nil)
+		assert.Equal(t, "test", name)
+		assert.Equal(t, image.Rect(0, 0, 160, 160), area)
+
+		// Custom selectors take precedence if the requested one cannot make a choice.
+		_, name = SelectArea(img, Sizes[Tile160], SelectorFace, nil)
+		assert.Equal(t, "test", name)
+	})
+}
+
+func TestRegisterSelector(t *testing.T) {
+	n := len(selectors)
+
+	RegisterSelector(nil)
+	RegisterSelector(CenterSelector{})
+
+	assert.Len(t, selectors, n)
+	assert.Nil(t, FindSelector("foo"))
+	assert.Equal(t, SelectorCenter, FindSelector(SelectorCenter).Name())
+}
+
+func TestImageFromSelector(t *testing.T) {
+	img := imaging.New(1000, 500, color.Gray{Y: 128})
+
+	result := ImageFromSelector(img, Sizes[Wide640], SelectorCenter, nil)
+
+	assert.Equal(t, 640, result.Bounds().Dx())
+	assert.Equal(t, 360, result.Bounds().Dy())
+}
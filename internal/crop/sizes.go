@@ -19,10 +19,71 @@ type SizeMap map[Name]Size
 
 // Sizes contains the properties of all thumbnail sizes.
 var Sizes = SizeMap{
-	Tile50:  {Tile50, Tile320, "Lists", 50, 50, DefaultOptions},
-	Tile100: {Tile100, Tile320, "Maps", 100, 100, DefaultOptions},
-	Tile160: {Tile160, Tile320, "FaceNet", 160, 160, DefaultOptions},
-	Tile224: {Tile224, Tile320, "TensorFlow, Mosaic", 224, 224, DefaultOptions},
-	Tile320: {Tile320, "", "UI", 320, 320, DefaultOptions},
-	Tile500: {Tile500, "", "FaceNet", 500, 500, DefaultOptions},
+	Tile50:   {Tile50, Tile320, "Lists", 50, 50, DefaultOptions},
+	Tile100:  {Tile100, Tile320, "Maps", 100, 100, DefaultOptions},
+	Tile160:  {Tile160, Tile320, "FaceNet", 160, 160, DefaultOptions},
+	Tile224:  {Tile224, Tile320, "TensorFlow, Mosaic", 224, 224, DefaultOptions},
+	Tile320:  {Tile320, "", "UI", 320, 320, DefaultOptions},
+	Tile500:  {Tile500, "", "FaceNet", 500, 500, DefaultOptions},
+	Std640:   {Std640, "", "Cards, 4:3", 640, 480, DefaultOptions},
+	Wide640:  {Wide640, "", "Banners, 16:9", 640, 360, DefaultOptions},
+	Wide1280: {Wide1280, "", "Hero Images, 16:9", 1280, 720, DefaultOptions},
+}
+
+// HeroSizes contains the properties of wide banner sizes, which are cropped from the whole image
+// rather than a specific area, see FromHero.
+var HeroSizes = SizeMap{
+	Hero1500: {Hero1500, "", "Album Headers, 3:1", 1500, 500, DefaultOptions},
+}
+
+// SetEntropy crops the specified sizes to the area with the most detail if no area is stored, and restores
+// center crops for all other sizes, see thumb.FillEntropy.
+func SetEntropy(names []Name) {
+	entropy := make(map[Name]bool, len(names))
+
+	for _, name := range names {
+		entropy[name] = true
+	}
+
+	for name, size := range Sizes {
+		if entropy[name] {
+			size.Options = []thumb.ResampleOption{thumb.ResampleFillEntropy, thumb.ResampleDefault}
+		} else {
+			size.Options = DefaultOptions
+		}
+
+		Sizes[name] = size
+	}
+}
+
+// Find returns the size with the specified dimensions, if any.
+func (m SizeMap) Find(width, height int) (size Size, ok bool) {
+	for _, s := range m {
+		if s.Width == width && s.Height == height {
+			return s, true
+		}
+	}
+
+	return size, false
+}
+
+// Ratio returns the aspect ratio of the crop size.
+func (s Size) Ratio() float64 {
+	if s.Height <= 0 {
+		return 0
+	}
+
+	return float64(s.Width) / float64(s.Height)
+}
+
+// Entropy tests if images without a stored area are cropped to the area with the most detail, see SetEntropy.
+func (s Size) Entropy() bool {
+	method, _, _ := thumb.ResampleOptions(s.Options...)
+
+	return method == thumb.ResampleFillEntropy
+}
+
+// Square tests if the crop size has the same width and height.
+func (s Size) Square() bool {
+	return s.Width == s.Height
 }
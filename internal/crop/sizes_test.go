@@ -0,0 +1,34 @@
+package crop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSize_Ratio(t *testing.T) {
+	assert.Equal(t, 1.0, Sizes[Tile160].Ratio())
+	assert.InDelta(t, 16.0/9, Sizes[Wide640].Ratio(), 0.001)
+	assert.InDelta(t, 16.0/9, Sizes[Wide1280].Ratio(), 0.001)
+	assert.InDelta(t, 4.0/3, Sizes[Std640].Ratio(), 0.001)
+	assert.Equal(t, 0.0, Size{Width: 100}.Ratio())
+}
+
+func TestSize_Square(t *testing.T) {
+	assert.True(t, Sizes[Tile500].Square())
+	assert.False(t, Sizes[Wide640].Square())
+}
+
+func TestSetEntropy(t *testing.T) {
+	defer SetEntropy(nil)
+
+	SetEntropy([]Name{Wide640})
+
+	assert.True(t, Sizes[Wide640].Entropy())
+	assert.False(t, Sizes[Tile160].Entropy())
+
+	SetEntropy(nil)
+
+	assert.False(t, Sizes[Wide640].Entropy())
+	assert.Equal(t, DefaultOptions, Sizes[Wide640].Options)
+}
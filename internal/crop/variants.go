@@ -0,0 +1,149 @@
+package crop
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// VariantSelectors contains the names of the selectors whose crops are suggested to users, see FromVariants.
+var VariantSelectors = []string{SelectorFace, SelectorCenter, SelectorAttention}
+
+// Variant represents a suggested crop of the whole image with the selector that was requested, the selector
+// that was used if it had to fall back, e.g. because there are no faces, and the relative area that was cropped.
+type Variant struct {
+	Selector string `json:"selector"`
+	Used     string `json:"used"`
+	Area     Area   `json:"area"`
+	FileName string `json:"-"`
+}
+
+// VariantFileName returns the file name of a crop variant. Since the area may depend on the faces, their
+// version is part of the name if any, so that a new file is created when faces are added or moved.
+func VariantFileName(hash, thumbPath string, size Size, selector string, hints Areas) (string, error) {
+	return hintsFileName(hash, thumbPath, size, "variant_"+selector, hints, fs.ExtJPEG)
+}
+
+// FromVariants returns the crop variants of all VariantSelectors for the specified size, and creates them
+// from the best fitting thumbnail if needed. Face areas can be passed as hints. The variants are cached
+// together with the areas used, so that the thumbnail only has to be decoded once.
+func FromVariants(hash, thumbPath string, size Size, hints Areas) (variants []Variant, err error) {
+	indexName, err := hintsFileName(hash, thumbPath, size, "variants", hints, fs.ExtJSON)
+
+	if err != nil {
+		return nil, err
+	} else if variants, err = cachedVariants(indexName, hash, thumbPath, size, hints); err == nil {
+		return variants, nil
+	}
+
+	thumbName := findIdealThumbFileName(hash, size.Width, thumb.Dir(hash, thumbPath))
+
+	if thumbName == "" {
+		return nil, fmt.Errorf("crop: no thumbnail found for %s", clean.Log(hash))
+	}
+
+	img, err := imaging.Open(thumbName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	variants = make([]Variant, 0, len(VariantSelectors))
+
+	for _, name := range VariantSelectors {
+		v := Variant{Selector: name}
+
+		if v.FileName, err = VariantFileName(hash, thumbPath, size, name, hints); err != nil {
+			return nil, err
+		}
+
+		area, used := SelectArea(img, size, name, hints)
+
+		v.Used = used
+		v.Area = relativeArea(img, area)
+
+		if err = thumb.SaveJpeg(thumb.Resample(imaging.Crop(img, area), size.Width, size.Height, size.Options...), v.FileName, thumb.JpegQuality); err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, v)
+	}
+
+	if data, err := json.Marshal(variants); err != nil {
+		log.Warnf("crop: %s", err)
+	} else if err = os.WriteFile(indexName, data, fs.ModeFile); err != nil {
+		log.Warnf("crop: %s", err)
+	}
+
+	log.Debugf("crop: saved %d variants of %s", len(variants), clean.Log(hash))
+
+	return variants, nil
+}
+
+// FromVariant returns the file name of the crop variant of the named selector, see FromVariants.
+func FromVariant(hash, thumbPath string, size Size, selector string, hints Areas) (fileName string, err error) {
+	variants, err := FromVariants(hash, thumbPath, size, hints)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range variants {
+		if v.Selector == selector {
+			return v.FileName, nil
+		}
+	}
+
+	return "", ErrNotFound
+}
+
+// cachedVariants returns the cached crop variants if the index and all files exist.
+func cachedVariants(indexName, hash, thumbPath string, size Size, hints Areas) (variants []Variant, err error) {
+	data, err := os.ReadFile(indexName)
+
+	if err != nil {
+		return nil, err
+	} else if err = json.Unmarshal(data, &variants); err != nil {
+		return nil, err
+	} else if len(variants) != len(VariantSelectors) {
+		return nil, ErrNotFound
+	}
+
+	for i := range variants {
+		if variants[i].Selector != VariantSelectors[i] {
+			return nil, ErrNotFound
+		} else if variants[i].FileName, err = VariantFileName(hash, thumbPath, size, variants[i].Selector, hints); err != nil {
+			return nil, err
+		} else if !fs.FileExists(variants[i].FileName) {
+			return nil, ErrNotFound
+		}
+	}
+
+	return variants, nil
+}
+
+// relativeArea returns the absolute rectangle as area relative to the image bounds.
+func relativeArea(img image.Image, area image.Rectangle) Area {
+	b := img.Bounds()
+
+	if b.Dx() <= 0 || b.Dy() <= 0 {
+		return Area{}
+	}
+
+	area = area.Sub(b.Min)
+	w, h := float32(b.Dx()), float32(b.Dy())
+
+	return Area{
+		X: float32(area.Min.X) / w,
+		Y: float32(area.Min.Y) / h,
+		W: float32(area.Dx()) / w,
+		H: float32(area.Dy()) / h,
+	}
+}
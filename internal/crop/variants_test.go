@@ -0,0 +1,109 @@
+package crop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestVariantFileName(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	size := Sizes[Tile224]
+
+	t.Run("NoFaces", func(t *testing.T) {
+		fileName, err := VariantFileName(hash, "testdata", size, SelectorCenter, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "testdata/b/c/c/"+hash+"_224x224_variant_center.jpg", fileName)
+	})
+	t.Run("Faces", func(t *testing.T) {
+		fileName, err := VariantFileName(hash, "testdata", size, SelectorFace, Areas{NewArea("face", 0.4, 0.05, 0.1, 0.15)})
+		assert.NoError(t, err)
+		assert.Regexp(t, `_224x224_variant_face_[0-9a-f]{8}\.jpg$`, fileName)
+	})
+	t.Run("InvalidHash", func(t *testing.T) {
+		_, err := VariantFileName("xx", "testdata", size, SelectorCenter, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestFromVariants(t *testing.T) {
+	hash := "bccfeaa526a36e19b555fd4ca5e8f767d5604289"
+	thumbPath := t.TempDir()
+	size := Sizes[Wide640]
+
+	data, err := os.ReadFile("testdata/b/c/c/" + hash + "_720x720_fit.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := thumb.Dir(hash, thumbPath)
+
+	if err = os.MkdirAll(dir, fs.ModeDir); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(filepath.Join(dir, hash+"_720x720_fit.jpg"), data, fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("NoFaces", func(t *testing.T) {
+		variants, err := FromVariants(hash, thumbPath, size, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, variants, len(VariantSelectors))
+
+		for i, v := range variants {
+			assert.Equal(t, VariantSelectors[i], v.Selector)
+			assert.True(t, fs.FileExists(v.FileName))
+			assert.False(t, v.Area.Empty())
+
+			img, err := imaging.Open(v.FileName)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 640, img.Bounds().Dx())
+			assert.Equal(t, 360, img.Bounds().Dy())
+		}
+
+		// Without faces, the face selector falls back to another selector.
+		assert.NotEqual(t, SelectorFace, variants[0].Used)
+		assert.Equal(t, SelectorCenter, variants[1].Used)
+		assert.InDelta(t, 1.0, variants[1].Area.W, 0.01)
+
+		// Cached variants are returned with the same areas.
+		cached, err := FromVariants(hash, thumbPath, size, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, variants, cached)
+	})
+	t.Run("Faces", func(t *testing.T) {
+		faces := Areas{NewArea("face", 0.4, 0.1, 0.2, 0.2)}
+		fileName, err := FromVariant(hash, thumbPath, size, SelectorFace, faces)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Regexp(t, `_640x360_variant_face_[0-9a-f]{8}\.jpg$`, fileName)
+
+		variants, err := FromVariants(hash, thumbPath, size, faces)
+
+		assert.NoError(t, err)
+		assert.Equal(t, SelectorFace, variants[0].Used)
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := FromVariant(hash, thumbPath, size, "foo", nil)
+		assert.Equal(t, ErrNotFound, err)
+	})
+	t.Run("NoThumb", func(t *testing.T) {
+		_, err := FromVariants("0000000000000000000000000000000000000000", thumbPath, size, nil)
+		assert.Error(t, err)
+	})
+}
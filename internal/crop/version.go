@@ -0,0 +1,110 @@
+package crop
+
+import (
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// RegenerateSizes contains the crop sizes that are created if no crops of a file have been cached yet.
+var RegenerateSizes = []Name{Tile160}
+
+// Version returns a short checksum of the crop areas, which changes when faces are added, moved, or removed.
+func Version(areas []string) string {
+	sorted := make([]string, len(areas))
+	copy(sorted, areas)
+	sort.Strings(sorted)
+
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(strings.Join(sorted, ","))))
+}
+
+// VersionFileName returns the name of the file that stores the crop version next to the cached crops.
+func VersionFileName(hash, thumbPath string) string {
+	return path.Join(thumb.Dir(hash, thumbPath), hash+"_crop.version")
+}
+
+// CachedVersion returns the version of the cached crops, or an empty string if unknown.
+func CachedVersion(hash, thumbPath string) string {
+	if data, err := os.ReadFile(VersionFileName(hash, thumbPath)); err != nil {
+		return ""
+	} else {
+		return strings.TrimSpace(string(data))
+	}
+}
+
+// Cached returns the file names of all cached crops of the file with the specified hash, in any format.
+func Cached(hash, thumbPath string) ([]string, error) {
+	if len(hash) < 4 {
+		return nil, fmt.Errorf("crop: invalid file hash %s", clean.Log(hash))
+	} else if len(thumbPath) < 1 {
+		return nil, fmt.Errorf("crop: cache path missing")
+	}
+
+	return filepath.Glob(path.Join(thumb.Dir(hash, thumbPath), hash) + "_*_crop_*")
+}
+
+// Regenerate removes all cached crops of the file with the specified hash and creates new crops of the areas,
+// in the sizes that were cached before, or RegenerateSizes if there were none. The version is stored afterwards,
+// so that unchanged areas can be skipped next time. It returns the number of crops created.
+func Regenerate(hash, thumbPath string, areas []string, version string) (created int, err error) {
+	cached, err := Cached(hash, thumbPath)
+
+	if err != nil {
+		return 0, err
+	}
+
+	sizes := make(map[Name]Size)
+
+	for _, fileName := range cached {
+		if size, ok := cachedSize(hash, fileName); ok {
+			sizes[size.Name] = size
+		}
+
+		if err = os.Remove(fileName); err != nil {
+			log.Warnf("crop: %s", err)
+		}
+	}
+
+	if len(sizes) == 0 {
+		for _, name := range RegenerateSizes {
+			if size, ok := Sizes[name]; ok {
+				sizes[name] = size
+			}
+		}
+	}
+
+	for _, area := range areas {
+		for _, size := range sizes {
+			if _, err = FromRequest(hash, area, size, thumbPath, fs.ImageJPEG); err != nil {
+				return created, err
+			}
+
+			created++
+		}
+	}
+
+	if err = os.WriteFile(VersionFileName(hash, thumbPath), []byte(version), fs.ModeFile); err != nil {
+		return created, err
+	}
+
+	return created, nil
+}
+
+// cachedSize returns the crop size based on the dimensions in the file name of a cached crop.
+func cachedSize(hash, fileName string) (size Size, ok bool) {
+	var width, height int
+
+	if _, err := fmt.Sscanf(strings.TrimPrefix(filepath.Base(fileName), hash+"_"), "%dx%d_crop_", &width, &height); err != nil {
+		return size, false
+	}
+
+	return Sizes.Find(width, height)
+}
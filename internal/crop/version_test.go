@@ -0,0 +1,76 @@
+package crop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestVersion(t *testing.T) {
+	v := Version([]string{"046045043065", "021022023024"})
+
+	assert.Len(t, v, 8)
+	assert.Equal(t, v, Version([]string{"021022023024", "046045043065"}))
+	assert.NotEqual(t, v, Version([]string{"021022023024"}))
+	assert.Equal(t, Version(nil), Version([]string{}))
+}
+
+func TestCachedVersion(t *testing.T) {
+	thumbPath := t.TempDir()
+	hash := "a2d5be6b0b9b0ef1cfe9b5d0ad20a8b22a8cc7ef"
+
+	assert.Equal(t, "", CachedVersion(hash, thumbPath))
+
+	if err := os.MkdirAll(thumb.Dir(hash, thumbPath), fs.ModeDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(VersionFileName(hash, thumbPath), []byte("0badc0de\n"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "0badc0de", CachedVersion(hash, thumbPath))
+}
+
+func TestRegenerate(t *testing.T) {
+	thumbPath := t.TempDir()
+	hash := "a2d5be6b0b9b0ef1cfe9b5d0ad20a8b22a8cc7ef"
+	dir := thumb.Dir(hash, thumbPath)
+
+	if err := os.MkdirAll(dir, fs.ModeDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.Copy("testdata/b/c/c/bccfeaa526a36e19b555fd4ca5e8f767d5604289_720x720_fit.jpg", filepath.Join(dir, hash+"_720x720_fit.jpg")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stale crop of a face that has moved.
+	stale := filepath.Join(dir, hash+"_100x100_crop_046045043065.jpg")
+
+	if err := os.WriteFile(stale, []byte("stale"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	areas := []string{"021022023024"}
+	created, err := Regenerate(hash, thumbPath, areas, Version(areas))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, created)
+	assert.NoFileExists(t, stale)
+	assert.FileExists(t, filepath.Join(dir, hash+"_100x100_crop_021022023024.jpg"))
+	assert.Equal(t, Version(areas), CachedVersion(hash, thumbPath))
+
+	cached, err := Cached(hash, thumbPath)
+
+	assert.NoError(t, err)
+	assert.Len(t, cached, 1)
+}
@@ -6,6 +6,7 @@ const (
 	DownloadNameFile     DownloadName = "file"
 	DownloadNameOriginal DownloadName = "original"
 	DownloadNameShare    DownloadName = "share"
+	DownloadNameTemplate DownloadName = "template"
 )
 
 var DownloadNameDefault = DownloadNameFile
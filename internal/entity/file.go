@@ -52,6 +52,7 @@ type File struct {
 	FileRoot           string        `gorm:"type:VARBINARY(16);default:'/';unique_index:idx_files_name_root;" json:"Root" yaml:"Root,omitempty"`
 	OriginalName       string        `gorm:"type:VARBINARY(755);" json:"OriginalName" yaml:"OriginalName,omitempty"`
 	FileHash           string        `gorm:"type:VARBINARY(128);index" json:"Hash" yaml:"Hash,omitempty"`
+	FileHashBlake3     string        `gorm:"column:file_hash_blake3;type:VARBINARY(128);index" json:"HashBlake3,omitempty" yaml:"HashBlake3,omitempty"`
 	FileSize           int64         `json:"Size" yaml:"Size,omitempty"`
 	FileCodec          string        `gorm:"type:VARBINARY(32)" json:"Codec" yaml:"Codec,omitempty"`
 	FileType           string        `gorm:"type:VARBINARY(16)" json:"FileType" yaml:"FileType,omitempty"`
@@ -69,6 +70,9 @@ type File struct {
 	FileHeight         int           `gorm:"column:file_height;" json:"Height" yaml:"Height,omitempty"`
 	FileOrientation    int           `gorm:"column:file_orientation;" json:"Orientation" yaml:"Orientation,omitempty"`
 	FileOrientationSrc string        `gorm:"column:file_orientation_src;type:VARBINARY(8);default:'';" json:"OrientationSrc" yaml:"OrientationSrc,omitempty"`
+	FileAngle          float32       `gorm:"column:file_angle;type:FLOAT;" json:"Angle,omitempty" yaml:"Angle,omitempty"`
+	FileFocusX         float32       `gorm:"column:file_focus_x;type:FLOAT;" json:"FocusX,omitempty" yaml:"FocusX,omitempty"`
+	FileFocusY         float32       `gorm:"column:file_focus_y;type:FLOAT;" json:"FocusY,omitempty" yaml:"FocusY,omitempty"`
 	FileProjection     string        `gorm:"column:file_projection;type:VARBINARY(64);" json:"Projection,omitempty" yaml:"Projection,omitempty"`
 	FileAspectRatio    float32       `gorm:"column:file_aspect_ratio;type:FLOAT;" json:"AspectRatio" yaml:"AspectRatio,omitempty"`
 	FileHDR            bool          `gorm:"column:file_hdr;"  json:"HDR" yaml:"HDR,omitempty"`
@@ -79,6 +83,7 @@ type File struct {
 	FileLuminance      string        `gorm:"type:VARBINARY(18);" json:"Luminance" yaml:"Luminance,omitempty"`
 	FileDiff           int           `json:"Diff" yaml:"Diff,omitempty"`
 	FileChroma         int16         `json:"Chroma" yaml:"Chroma,omitempty"`
+	FileAHash          string        `gorm:"column:file_ahash;type:VARBINARY(16);index" json:"AHash,omitempty" yaml:"AHash,omitempty"`
 	FileSoftware       string        `gorm:"type:VARCHAR(64)" json:"Software" yaml:"Software,omitempty"`
 	FileError          string        `gorm:"type:VARBINARY(512)" json:"Error" yaml:"Error,omitempty"`
 	ModTime            int64         `json:"ModTime" yaml:"-"`
@@ -201,6 +206,8 @@ func (m *File) DownloadName(n customize.DownloadName, seq int) string {
 		return m.Base(seq)
 	case customize.DownloadNameOriginal:
 		return m.OriginalBase(seq)
+	case customize.DownloadNameTemplate:
+		return m.TemplateBase(DownloadTemplate, seq)
 	default:
 		return m.ShareBase(seq)
 	}
@@ -845,3 +852,40 @@ func (m *File) SetOrientation(val int, src string) *File {
 
 	return m
 }
+
+// Focus returns the user-defined focus point relative to the image dimensions, if any.
+func (m *File) Focus() (x, y float32, ok bool) {
+	if m.FileFocusX <= 0 && m.FileFocusY <= 0 {
+		return 0, 0, false
+	}
+
+	return m.FileFocusX, m.FileFocusY, true
+}
+
+// SetFocus sets the focus point relative to the image dimensions, which all crops should keep in frame.
+func (m *File) SetFocus(x, y float32) error {
+	if x < 0 || x > 1 || y < 0 || y > 1 {
+		return fmt.Errorf("invalid focus point %.3f,%.3f", x, y)
+	} else if x == 0 && y == 0 {
+		return m.ClearFocus()
+	}
+
+	if err := m.Updates(map[string]interface{}{"FileFocusX": x, "FileFocusY": y}); err != nil {
+		return err
+	}
+
+	m.FileFocusX, m.FileFocusY = x, y
+
+	return nil
+}
+
+// ClearFocus removes the focus point, so that crops are chosen automatically again.
+func (m *File) ClearFocus() error {
+	if err := m.Updates(map[string]interface{}{"FileFocusX": 0, "FileFocusY": 0}); err != nil {
+		return err
+	}
+
+	m.FileFocusX, m.FileFocusY = 0, 0
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package entity
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// DownloadTemplate is the optional download file name template, e.g. "{date}-{camera}-{title}".
+var DownloadTemplate = ""
+
+// DownloadTemplateTokens maps the supported template tokens to their default time layout, if any.
+var DownloadTemplateTokens = map[string]string{
+	"date":   "2006-01-02",
+	"time":   "150405",
+	"year":   "2006",
+	"month":  "01",
+	"day":    "02",
+	"title":  "",
+	"camera": "",
+	"name":   "",
+	"uid":    "",
+	"hash":   "",
+}
+
+var downloadTemplateRegexp = regexp.MustCompile(`\{([^{}]*)}`)
+var downloadSeparatorRegexp = regexp.MustCompile(`([-_ .])[-_ .]+`)
+
+// ValidDownloadTemplate returns an error if the download file name template is invalid.
+func ValidDownloadTemplate(tmpl string) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return fmt.Errorf("template is empty")
+	}
+
+	for _, m := range downloadTemplateRegexp.FindAllStringSubmatch(tmpl, -1) {
+		name, layout, hasLayout := strings.Cut(m[1], ":")
+
+		if defaultLayout, ok := DownloadTemplateTokens[name]; !ok {
+			return fmt.Errorf("unknown token {%s}", name)
+		} else if hasLayout && (defaultLayout == "" || layout == "") {
+			return fmt.Errorf("token {%s} has no date format", name)
+		}
+	}
+
+	if rest := downloadTemplateRegexp.ReplaceAllString(tmpl, ""); strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("unbalanced braces")
+	}
+
+	return nil
+}
+
+// TemplateBase returns a file name for downloads based on the specified template,
+// or the share name if the template is empty or invalid.
+func (m *File) TemplateBase(tmpl string, seq int) string {
+	if ValidDownloadTemplate(tmpl) != nil {
+		return m.ShareBase(seq)
+	}
+
+	photo := m.RelatedPhoto()
+
+	if photo == nil {
+		return m.ShareBase(seq)
+	}
+
+	name := downloadTemplateRegexp.ReplaceAllStringFunc(tmpl, func(token string) string {
+		token = strings.Trim(token, "{}")
+		token, layout, hasLayout := strings.Cut(token, ":")
+
+		if !hasLayout {
+			layout = DownloadTemplateTokens[token]
+		}
+
+		switch token {
+		case "date", "time", "year", "month", "day":
+			if photo.TakenAtLocal.IsZero() {
+				return ""
+			}
+
+			return photo.TakenAtLocal.Format(layout)
+		case "title":
+			return photo.PhotoTitle
+		case "camera":
+			return m.downloadCamera(photo)
+		case "name":
+			return fs.StripExt(filepath.Base(m.OriginalBase(0)))
+		case "uid":
+			return photo.PhotoUID
+		case "hash":
+			return m.FileHash
+		default:
+			return ""
+		}
+	})
+
+	if name = SanitizeDownloadName(name); name == "" {
+		return m.ShareBase(seq)
+	}
+
+	if seq > 0 {
+		return fmt.Sprintf("%s (%d).%s", name, seq, m.FileType)
+	}
+
+	return fmt.Sprintf("%s.%s", name, m.FileType)
+}
+
+// downloadCamera returns the camera name of the related photo, if known.
+func (m *File) downloadCamera(photo *Photo) string {
+	if photo.UnknownCamera() {
+		return ""
+	} else if photo.Camera != nil {
+		return photo.Camera.CameraName
+	}
+
+	camera := Camera{}
+
+	if err := Db().Where("id = ?", photo.CameraID).First(&camera).Error; err != nil {
+		return ""
+	}
+
+	return camera.CameraName
+}
+
+// SanitizeDownloadName replaces characters that are not safe to use in file names
+// and collapses repeated separators, e.g. if a token value is empty.
+func SanitizeDownloadName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if !unicode.IsPrint(r) {
+			return -1
+		}
+
+		switch r {
+		case '~', '/', '\\', ':', '|', '"', '?', '*', '<', '>', '{', '}':
+			return '-'
+		default:
+			return r
+		}
+	}, s)
+
+	s = downloadSeparatorRegexp.ReplaceAllString(s, "$1")
+	s = strings.Trim(s, "-_ .")
+
+	return txt.Clip(s, txt.ClipLongName)
+}
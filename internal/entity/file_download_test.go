@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/customize"
+)
+
+func TestValidDownloadTemplate(t *testing.T) {
+	assert.NoError(t, ValidDownloadTemplate("{date}-{camera}-{title}"))
+	assert.NoError(t, ValidDownloadTemplate("{date:02.01.2006} {title}"))
+	assert.NoError(t, ValidDownloadTemplate("photo"))
+	assert.EqualError(t, ValidDownloadTemplate(" "), "template is empty")
+	assert.EqualError(t, ValidDownloadTemplate("{date}-{foo}"), "unknown token {foo}")
+	assert.EqualError(t, ValidDownloadTemplate("{title:2006}"), "token {title} has no date format")
+	assert.EqualError(t, ValidDownloadTemplate("{date:}"), "token {date} has no date format")
+	assert.EqualError(t, ValidDownloadTemplate("{date}-{title"), "unbalanced braces")
+	assert.EqualError(t, ValidDownloadTemplate("{date}}"), "unbalanced braces")
+}
+
+func TestSanitizeDownloadName(t *testing.T) {
+	assert.Equal(t, "Lake 2790", SanitizeDownloadName("Lake / 2790"))
+	assert.Equal(t, "2019-01-15_Mood", SanitizeDownloadName("2019-01-15__Mood"))
+	assert.Equal(t, "12-00", SanitizeDownloadName(" 12:00 "))
+	assert.Equal(t, "foo-bar", SanitizeDownloadName("../foo\\bar.."))
+	assert.Equal(t, "", SanitizeDownloadName("///"))
+}
+
+func TestFile_TemplateBase(t *testing.T) {
+	photo := &Photo{PhotoUID: "pt9jtdre2lvl0yh7", TakenAtLocal: time.Date(2019, 01, 15, 8, 30, 0, 0, time.UTC), PhotoTitle: "Berlin / Morning Mood", Camera: &Camera{CameraName: "Canon EOS 6D"}, CameraID: 1000000}
+	file := &File{Photo: photo, FileType: "jpg", FileHash: "e98eb86480a72bd585d228a709f0622f90e86cbc", OriginalName: "Vacation/IMG_1234.jpg", FileName: "2019/01/filename.jpg"}
+
+	t.Run("Default", func(t *testing.T) {
+		assert.Equal(t, "2019-01-15-Canon EOS 6D-Berlin Morning Mood.jpg", file.TemplateBase("{date}-{camera}-{title}", 0))
+	})
+	t.Run("DateFormat", func(t *testing.T) {
+		assert.Equal(t, "15.01.2019 IMG_1234 (2).jpg", file.TemplateBase("{date:02.01.2006} {name}", 2))
+	})
+	t.Run("Time", func(t *testing.T) {
+		assert.Equal(t, "20190115_083000_pt9jtdre2lvl0yh7.jpg", file.TemplateBase("{year}{month}{day}_{time}_{uid}", 0))
+	})
+	t.Run("UnknownCamera", func(t *testing.T) {
+		unknown := &File{Photo: &Photo{TakenAtLocal: photo.TakenAtLocal, PhotoTitle: "Mood", CameraID: UnknownCamera.ID}, FileType: "jpg", FileHash: file.FileHash}
+		assert.Equal(t, "2019-01-15-Mood.jpg", unknown.TemplateBase("{date}-{camera}-{title}", 0))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		assert.Equal(t, file.ShareBase(0), file.TemplateBase("{foo}", 0))
+	})
+	t.Run("Empty", func(t *testing.T) {
+		assert.Equal(t, file.ShareBase(0), file.TemplateBase("{hash:2006}", 0))
+		assert.Equal(t, file.ShareBase(1), file.TemplateBase(" / ", 1))
+	})
+	t.Run("DownloadName", func(t *testing.T) {
+		DownloadTemplate = "{title}"
+		defer func() { DownloadTemplate = "" }()
+
+		assert.Equal(t, "Berlin Morning Mood.jpg", file.DownloadName(customize.DownloadNameTemplate, 0))
+	})
+}
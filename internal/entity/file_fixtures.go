@@ -37,6 +37,7 @@ var FileFixtures = FileMap{
 		FileRoot:        RootOriginals,
 		OriginalName:    "Vacation/exampleFileNameOriginal.jpg",
 		FileHash:        "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818",
+		FileHashBlake3:  "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262",
 		FileSize:        4278906,
 		FileCodec:       "jpeg",
 		FileType:        "jpg",
@@ -57,6 +58,7 @@ var FileFixtures = FileMap{
 		FileLuminance:   "8836BD496",
 		FileDiff:        968,
 		FileChroma:      25,
+		FileAHash:       "ff81818181c3e7ff",
 		FileError:       "",
 		ModTime:         time.Date(2020, 3, 6, 2, 6, 51, 0, time.UTC).Unix(),
 		Share: []FileShare{
@@ -43,6 +43,7 @@ func (m *File) MarshalJSON() ([]byte, error) {
 		Luminance      string        `json:",omitempty"`
 		Diff           int           `json:",omitempty"`
 		Chroma         int16         `json:",omitempty"`
+		AHash          string        `json:",omitempty"`
 		HDR            bool          `json:",omitempty"`
 		Watermark      bool          `json:",omitempty"`
 		Software       string        `json:",omitempty"`
@@ -90,6 +91,7 @@ func (m *File) MarshalJSON() ([]byte, error) {
 		Luminance:      m.FileLuminance,
 		Diff:           m.FileDiff,
 		Chroma:         m.FileChroma,
+		AHash:          m.FileAHash,
 		HDR:            m.FileHDR,
 		Watermark:      m.FileWatermark,
 		Software:       m.FileSoftware,
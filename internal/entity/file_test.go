@@ -859,3 +859,39 @@ func TestFile_SetOrientation(t *testing.T) {
 		assert.Equal(t, "", m.FileOrientationSrc)
 	})
 }
+
+func TestFile_Focus(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		m := File{}
+		_, _, ok := m.Focus()
+		assert.False(t, ok)
+	})
+	t.Run("Set", func(t *testing.T) {
+		m := File{FileFocusX: 0.25, FileFocusY: 0}
+		x, y, ok := m.Focus()
+		assert.True(t, ok)
+		assert.Equal(t, float32(0.25), x)
+		assert.Equal(t, float32(0), y)
+	})
+}
+
+func TestFile_SetFocus(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		m := FileFixtures.Get("Quality1FavoriteTrue.jpg")
+
+		assert.NoError(t, m.SetFocus(0.3, 0.6))
+		x, y, ok := m.Focus()
+		assert.True(t, ok)
+		assert.Equal(t, float32(0.3), x)
+		assert.Equal(t, float32(0.6), y)
+
+		assert.NoError(t, m.ClearFocus())
+		_, _, ok = m.Focus()
+		assert.False(t, ok)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		m := File{}
+		assert.Error(t, m.SetFocus(1.5, 0.5))
+		assert.Error(t, m.SetFocus(0.5, -0.1))
+	})
+}
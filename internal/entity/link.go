@@ -2,6 +2,8 @@ package entity
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/jinzhu/gorm"
@@ -32,6 +34,8 @@ type Link struct {
 	HasPassword bool      `json:"HasPassword" yaml:"HasPassword,omitempty"`
 	Comment     string    `gorm:"size:512;" json:"Comment,omitempty" yaml:"Comment,omitempty"`
 	Perm        uint      `json:"Perm,omitempty" yaml:"Perm,omitempty"`
+	BlurFaces   bool      `json:"BlurFaces" yaml:"BlurFaces,omitempty"`
+	Headers     string    `gorm:"size:2048;" json:"Headers,omitempty" yaml:"Headers,omitempty"`
 	RefID       string    `gorm:"type:VARBINARY(16);" json:"-" yaml:"-"`
 	CreatedBy   string    `gorm:"type:VARBINARY(42);index" json:"CreatedBy,omitempty" yaml:"CreatedBy,omitempty"`
 	CreatedAt   time.Time `deepcopier:"skip" json:"CreatedAt" yaml:"CreatedAt"`
@@ -119,6 +123,48 @@ func (m *Link) SetSlug(s string) {
 	m.ShareSlug = txt.Slug(s)
 }
 
+// SetHeaders sets the custom response headers of shared thumbnails, so that they can be embedded in other
+// sites. They are stored as one "Name: value" pair per line, sorted by name. Headers with an empty value are
+// removed. Which headers are allowed is checked by the API, see api.ShareHeaders.
+func (m *Link) SetHeaders(headers map[string]string) error {
+	var lines []string
+
+	for name, value := range headers {
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+
+		if name == "" || strings.ContainsAny(name, ":\r\n") || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid header %s", clean.Log(name))
+		} else if value != "" {
+			lines = append(lines, name+": "+value)
+		}
+	}
+
+	sort.Strings(lines)
+
+	result := strings.Join(lines, "\n")
+
+	if len(result) > 2048 {
+		return fmt.Errorf("headers too long")
+	}
+
+	m.Headers = result
+
+	return nil
+}
+
+// ResponseHeaders returns the custom response headers of shared thumbnails by name, see SetHeaders.
+func (m *Link) ResponseHeaders() map[string]string {
+	result := make(map[string]string)
+
+	for _, line := range strings.Split(m.Headers, "\n") {
+		if name, value, found := strings.Cut(line, ":"); found {
+			result[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+
+	return result
+}
+
 // SetPassword sets the password required to use the share link.
 func (m *Link) SetPassword(password string) error {
 	pw := NewPassword(m.LinkUID, password, false)
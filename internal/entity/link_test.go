@@ -68,6 +68,30 @@ func TestLink_SetSlug(t *testing.T) {
 	assert.Equal(t, "test-slug", link.ShareSlug)
 }
 
+func TestLink_SetHeaders(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		link := Link{}
+		err := link.SetHeaders(map[string]string{"Referrer-Policy": "no-referrer ", "Access-Control-Allow-Origin": "https://example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Access-Control-Allow-Origin: https://example.com\nReferrer-Policy: no-referrer", link.Headers)
+		assert.Equal(t, map[string]string{
+			"Access-Control-Allow-Origin": "https://example.com",
+			"Referrer-Policy":             "no-referrer",
+		}, link.ResponseHeaders())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		link := Link{Headers: "Referrer-Policy: no-referrer"}
+		assert.Error(t, link.SetHeaders(map[string]string{"Referrer-Policy": "no-referrer\r\nSet-Cookie: foo=bar"}))
+		assert.Equal(t, "Referrer-Policy: no-referrer", link.Headers)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		link := Link{Headers: "Referrer-Policy: no-referrer"}
+		assert.NoError(t, link.SetHeaders(nil))
+		assert.Equal(t, "", link.Headers)
+		assert.Empty(t, link.ResponseHeaders())
+	})
+}
+
 func TestLink_SetPassword(t *testing.T) {
 	link := Link{LinkUID: "dftjdfkvh"}
 	assert.Equal(t, false, link.HasPassword)
@@ -8,7 +8,10 @@ import (
 
 // File represents a file edit form.
 type File struct {
-	FileOrientation int `json:"Orientation"`
+	FileOrientation int     `json:"Orientation"`
+	FileAngle       float32 `json:"Angle"`
+	FileFocusX      float32 `json:"FocusX"`
+	FileFocusY      float32 `json:"FocusY"`
 }
 
 // Orientation returns the Exif orientation value within a valid range or 0 if it is invalid.
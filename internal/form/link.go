@@ -9,4 +9,6 @@ type Link struct {
 	MaxViews    uint   `json:"MaxViews"`
 	CanComment  bool   `json:"CanComment"`
 	CanEdit     bool   `json:"CanEdit"`
+	BlurFaces   bool   `json:"BlurFaces"`
+	Headers     string `json:"Headers"`
 }
@@ -54,6 +54,8 @@ type SearchPhotos struct {
 	Fmax      float32   `form:"fmax" notes:"F-number (max)"`
 	Chroma    int16     `form:"chroma" example:"chroma:70" notes:"Chroma (0-100)"`
 	Diff      uint32    `form:"diff" notes:"Differential Perceptual Hash (000000-FFFFFF)"`
+	AHash     string    `form:"ahash" example:"ahash:ff81818181c3e7ff" notes:"Finds near-duplicates by Average Hash (16 hex digits)"`
+	AHashDist int       `form:"ahashdist" example:"ahashdist:8" notes:"Maximum Average Hash Distance (0-64), 5 by default"`
 	Mono      bool      `form:"mono" notes:"Finds pictures with few or no colors"`
 	Geo       string    `form:"geo" example:"geo:yes" notes:"Finds pictures with or without coordinates"`
 	Keywords  string    `form:"keywords" example:"keywords:\"sand&water\"" notes:"Keywords (combinable with & and |)"`
@@ -7,6 +7,7 @@ var (
 	ShareWorker  = Activity{}
 	MetaWorker   = Activity{}
 	FacesWorker  = Activity{}
+	ThumbsWorker = Activity{}
 	UpdatePeople = Activity{}
 )
 
@@ -18,6 +19,7 @@ func CancelAll() {
 	ShareWorker.Cancel()
 	MetaWorker.Cancel()
 	FacesWorker.Cancel()
+	ThumbsWorker.Cancel()
 }
 
 // IndexWorkersRunning checks if a worker is currently running.
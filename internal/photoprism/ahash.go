@@ -0,0 +1,25 @@
+package photoprism
+
+import (
+	"fmt"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// AverageHash returns the average hash of an image computed from its smallest uncropped thumbnail,
+// see thumb.AverageHash.
+func (m *MediaFile) AverageHash(thumbPath string) (h thumb.AHash, err error) {
+	if !m.IsPreviewImage() {
+		return h, fmt.Errorf("%s is not a jpeg", clean.Log(m.BaseName()))
+	}
+
+	img, err := m.Resample(thumbPath, thumb.Fit720)
+
+	if err != nil {
+		log.Debugf("ahash: %s in %s (resample)", err, clean.Log(m.BaseName()))
+		return h, err
+	}
+
+	return thumb.AverageHash(img), nil
+}
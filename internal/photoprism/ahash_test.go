@@ -0,0 +1,38 @@
+package photoprism
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestMediaFile_AverageHash(t *testing.T) {
+	conf := config.TestConfig()
+
+	t.Run("cat_brown.jpg", func(t *testing.T) {
+		mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/cat_brown.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		h, err := mediaFile.AverageHash(conf.ThumbCachePath())
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, thumb.AHash(0), h)
+		assert.Len(t, h.String(), 16)
+	})
+	t.Run("Random.docx", func(t *testing.T) {
+		mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/Random.docx")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = mediaFile.AverageHash(conf.ThumbCachePath())
+		assert.Error(t, err)
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/fastwalk"
 	"github.com/photoprism/photoprism/pkg/fs"
@@ -182,6 +183,8 @@ func (w *CleanUp) Cache(opt CleanUpOptions) (deleted int, err error) {
 				// Do nothing.
 			} else if ok = thumbHashes[hash]; ok {
 				// Do nothing.
+			} else if thumb.PinnedHash(hash) {
+				log.Debugf("cleanup: %s is pinned", logName)
 			} else if opt.Dry {
 				deleted++
 				log.Debugf("cleanup: %s would be deleted", logName)
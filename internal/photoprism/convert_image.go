@@ -81,7 +81,7 @@ func (c *Convert) ToImage(f *MediaFile, force bool) (*MediaFile, error) {
 
 	start := time.Now()
 
-	// PNG, GIF, BMP, TIFF, and WebP can be handled natively.
+	// PNG, GIF, BMP, TIFF, WebP, DICOM, and PSD can be handled natively.
 	if f.IsImageOther() {
 		log.Infof("convert: converting %s to %s (%s)", clean.Log(filepath.Base(fileName)), clean.Log(filepath.Base(imageName)), f.FileType())
 
@@ -99,13 +99,24 @@ func (c *Convert) ToImage(f *MediaFile, force bool) (*MediaFile, error) {
 		if err == nil {
 			log.Infof("convert: %s created in %s (%s)", clean.Log(filepath.Base(imageName)), time.Since(start), f.FileType())
 			return NewMediaFile(imageName)
-		} else if !f.IsTIFF() && !f.IsWebP() {
+		} else if !f.IsTIFF() && !f.IsWebP() && !f.IsPSD() {
 			// See https://github.com/photoprism/photoprism/issues/1612
-			// for TIFF file format compatibility.
+			// for TIFF file format compatibility, PSD files without
+			// a supported composite or preview need ImageMagick.
 			return nil, err
 		}
 	}
 
+	// Use the poster embedded in videos, if any, instead of extracting a keyframe.
+	if f.IsVideo() && fs.LowerExt(imageName) == fs.ExtJPEG {
+		if _, err = thumb.PosterJpeg(f.FileName(), imageName, f.Orientation()); err == nil {
+			log.Infof("convert: %s created in %s (embedded poster)", clean.Log(filepath.Base(imageName)), time.Since(start))
+			return NewMediaFile(imageName)
+		} else if !errors.Is(err, thumb.ErrNoPoster) {
+			log.Debugf("convert: %s in %s (embedded poster)", err, clean.Log(f.RootRelName()))
+		}
+	}
+
 	// Run external commands for other formats.
 	var cmds []*exec.Cmd
 	var useMutex bool
@@ -139,6 +150,11 @@ func (c *Convert) ToImage(f *MediaFile, force bool) (*MediaFile, error) {
 		return NewMediaFile(imageName)
 	}
 
+	// Limit the number of RAW images and videos decoded at the same time.
+	if f.IsRaw() || f.IsVideo() {
+		defer thumb.AcquireDecode(f.FileName(), thumb.DecodeMemSize(f.Width(), f.Height(), f.FileSize()))()
+	}
+
 	// Try compatible converters.
 	for _, cmd := range cmds {
 		// Fetch command output.
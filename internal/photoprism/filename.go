@@ -7,11 +7,17 @@ import (
 	"strings"
 
 	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
 
 // FileName returns the full file name based on the root folder type.
 func FileName(fileRoot, fileName string) string {
+	// Remote originals are referenced by URL.
+	if thumb.IsRemote(fileName) {
+		return fileName
+	}
+
 	switch fileRoot {
 	case entity.RootSidecar:
 		return path.Join(Config().SidecarPath(), fileName)
@@ -20,6 +20,9 @@ func TestFileName(t *testing.T) {
 	t.Run("examples", func(t *testing.T) {
 		assert.Equal(t, c.ExamplesPath()+"/test.jpg", FileName("examples", "test.jpg"))
 	})
+	t.Run("remote", func(t *testing.T) {
+		assert.Equal(t, "https://example.com/test.jpg", FileName("/", "https://example.com/test.jpg"))
+	})
 
 }
 
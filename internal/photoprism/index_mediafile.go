@@ -231,6 +231,9 @@ func (ind *Index) UserMediaFile(m *MediaFile, o IndexOptions, originalName, phot
 		log.Errorf("index: %s in %s (purge duplicate)", err, m.RootRelName())
 	}
 
+	// Keep straightening the thumbnails if an angle was set.
+	m.SetAngle(float64(file.FileAngle))
+
 	// Create default thumbnails if needed.
 	if err := m.CreateThumbnails(ind.thumbPath(), false); err != nil {
 		result.Status = IndexFailed
@@ -382,6 +385,13 @@ func (ind *Index) UserMediaFile(m *MediaFile, o IndexOptions, originalName, phot
 			}
 		}
 
+		// Update average hash for quick grouping of near-duplicates.
+		if h, hashErr := m.AverageHash(Config().ThumbCachePath()); hashErr != nil {
+			log.Debugf("%s while computing average hash", hashErr.Error())
+		} else {
+			file.FileAHash = h.String()
+		}
+
 		// Update resolution and aspect ratio.
 		if m.Width() > 0 && m.Height() > 0 {
 			file.FileWidth = m.Width()
@@ -27,6 +27,7 @@ import (
 
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/meta"
+	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/photoprism/photoprism/pkg/media"
@@ -53,6 +54,7 @@ type MediaFile struct {
 	colorProfile     string
 	width            int
 	height           int
+	angle            float64
 	metaData         meta.Data
 	metaOnce         sync.Once
 	videoInfo        video.Info
@@ -739,6 +741,24 @@ func (m *MediaFile) IsWebP() bool {
 	return m.MimeType() == fs.MimeTypeWebP
 }
 
+// IsDICOM checks if the file is a DICOM medical image file with a supported file type extension.
+func (m *MediaFile) IsDICOM() bool {
+	if fs.FileType(m.fileName) != fs.ImageDICOM {
+		return false
+	}
+
+	return m.MimeType() == fs.MimeTypeDICOM
+}
+
+// IsPSD checks if the file is an Adobe Photoshop document with a supported file type extension.
+func (m *MediaFile) IsPSD() bool {
+	if fs.FileType(m.fileName) != fs.ImagePSD {
+		return false
+	}
+
+	return m.MimeType() == fs.MimeTypePSD
+}
+
 // Duration returns the duration is the media content is playable.
 func (m *MediaFile) Duration() time.Duration {
 	return m.MetaData().Duration
@@ -870,10 +890,10 @@ func (m *MediaFile) SkipTranscoding() bool {
 	return !m.NeedsTranscoding()
 }
 
-// IsImageOther returns true if this is a PNG, GIF, BMP, TIFF, or WebP file.
+// IsImageOther returns true if this is a PNG, GIF, BMP, TIFF, WebP, DICOM, or PSD file.
 func (m *MediaFile) IsImageOther() bool {
 	switch {
-	case m.IsPNG(), m.IsGIF(), m.IsTIFF(), m.IsBMP(), m.IsWebP():
+	case m.IsPNG(), m.IsGIF(), m.IsTIFF(), m.IsBMP(), m.IsWebP(), m.IsDICOM(), m.IsPSD():
 		return true
 	default:
 		return false
@@ -1164,6 +1184,16 @@ func (m *MediaFile) Orientation() int {
 	return 1
 }
 
+// Angle returns the angle in degrees by which thumbnails are straightened.
+func (m *MediaFile) Angle() float64 {
+	return m.angle
+}
+
+// SetAngle sets the angle in degrees by which thumbnails are straightened.
+func (m *MediaFile) SetAngle(angle float64) {
+	m.angle = thumb.NormalizeAngle(angle)
+}
+
 // RenameSidecarFiles moves related sidecar files.
 func (m *MediaFile) RenameSidecarFiles(oldFileName string) (renamed map[string]string, err error) {
 	renamed = make(map[string]string)
@@ -1351,6 +1351,78 @@ func TestMediaFile_IsImageOther(t *testing.T) {
 	})
 }
 
+func TestMediaFile_IsDICOM(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "series.dcm")
+
+		// DICOM files start with a 128-byte preamble followed by the "DICM" prefix.
+		if err := os.WriteFile(fileName, append(make([]byte, 128), []byte("DICM\x02\x00\x00\x00UL\x04\x00")...), fs.ModeFile); err != nil {
+			t.Fatal(err)
+		}
+
+		mediaFile, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, mediaFile.IsDICOM())
+		assert.True(t, mediaFile.IsImageOther())
+		assert.Equal(t, fs.ImageDICOM, mediaFile.FileType())
+	})
+	t.Run("WrongContent", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "series.dcm")
+
+		if err := os.WriteFile(fileName, []byte("not a dicom file"), fs.ModeFile); err != nil {
+			t.Fatal(err)
+		}
+
+		mediaFile, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.False(t, mediaFile.IsDICOM())
+	})
+}
+
+func TestMediaFile_IsPSD(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "design.psd")
+
+		// PSD files start with the "8BPS" signature followed by the version number.
+		if err := os.WriteFile(fileName, append([]byte("8BPS\x00\x01"), make([]byte, 64)...), fs.ModeFile); err != nil {
+			t.Fatal(err)
+		}
+
+		mediaFile, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, mediaFile.IsPSD())
+		assert.True(t, mediaFile.IsImageOther())
+		assert.Equal(t, fs.ImagePSD, mediaFile.FileType())
+	})
+	t.Run("WrongContent", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "design.psd")
+
+		if err := os.WriteFile(fileName, []byte("not a photoshop file"), fs.ModeFile); err != nil {
+			t.Fatal(err)
+		}
+
+		mediaFile, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.False(t, mediaFile.IsPSD())
+	})
+}
+
 func TestMediaFile_IsSidecar(t *testing.T) {
 	conf := config.TestConfig()
 
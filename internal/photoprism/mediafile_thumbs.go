@@ -33,13 +33,16 @@ func (m *MediaFile) Thumbnail(path string, sizeName thumb.Name) (filename string
 		return "", fmt.Errorf("media: invalid type %s", sizeName)
 	}
 
+	// Use a hidden folder next to the file with the sidecar layout.
+	path = thumb.Path(path, m.FileName())
+
 	// Choose the smallest fitting size if the original image is smaller.
 	if size.Fit && m.Bounds().In(size.Bounds()) {
 		size = thumb.FitBounds(m.Bounds())
 		log.Tracef("media: smallest fitting size for %s is %s (width %d, height %d)", clean.Log(m.RootRelName()), size.Name, size.Width, size.Height)
 	}
 
-	thumbName, err := size.FromFile(m.FileName(), m.Hash(), path, m.Orientation())
+	thumbName, err := size.FromFileAngle(m.FileName(), m.Hash(), path, m.Orientation(), m.Angle())
 
 	if err != nil {
 		err = fmt.Errorf("media: failed creating thumbnail for %s (%s)", clean.Log(m.BaseName()), err)
@@ -71,8 +74,11 @@ func (m *MediaFile) CreateThumbnails(thumbPath string, force bool) (err error) {
 
 	count := 0
 	start := time.Now()
+	thumbPath = thumb.Path(thumbPath, m.FileName())
 
 	defer func() {
+		thumb.AddStats(thumb.SourceFormat(m.FileName()), count, time.Since(start), err != nil)
+
 		switch count {
 		case 0:
 			log.Debug(capture.Time(start, fmt.Sprintf("media: created no new thumbnails for %s", clean.Log(m.RootRelName()))))
@@ -82,81 +88,94 @@ func (m *MediaFile) CreateThumbnails(thumbPath string, force bool) (err error) {
 	}()
 
 	hash := m.Hash()
+	srcFile, orientation := m.FileName(), m.Orientation()
+
+	// Use the edited preview embedded in the XMP sidecar file instead, if any, see thumb.XmpPreview.
+	if preview, ok := thumb.FromXmp(srcFile, hash, thumbPath); ok {
+		srcFile, orientation = preview, thumb.OrientationNormal
+	}
 
-	var original image.Image
+	// Bake the orientation into an upright copy once, so that thumbnails don't need to be rotated?
+	if thumb.Normalize && orientation > thumb.OrientationNormal {
+		if upright, created, uprightErr := thumb.Upright(srcFile, hash, thumbPath, orientation, force); uprightErr != nil {
+			log.Warnf("media: %s in %s (normalize orientation)", uprightErr, clean.Log(m.RootRelName()))
+		} else {
+			// Existing thumbnails must be created again from the new upright copy.
+			if created {
+				force = true
+			}
 
-	var srcImg image.Image
-	var srcName thumb.Name
+			srcFile, orientation = upright, thumb.OrientationNormal
+		}
+	}
+
+	var pending thumb.SizeList
 
 	for _, name := range thumb.Names {
 		var size thumb.Size
 		var fileName string
 
 		if size = thumb.Sizes[name]; size.Uncached() {
-			// Skip, exceeds pre-cached size limit.
+			// Skip, not pre-cached, see thumb.Policies.
 			continue
 		} else if fileName, err = size.FileName(hash, thumbPath); err != nil {
 			log.Errorf("media: failed creating %s (%s)", clean.Log(string(name)), err)
 			return err
 		} else if force || !fs.FileExists(fileName) {
-			// Open original if needed.
-			if original == nil {
-				img, imgErr := thumb.Open(m.FileName(), m.Orientation())
-
-				// Try to fix broken JPEGs if possible, fail otherwise.
-				if imgErr != nil {
-					msg := imgErr.Error()
-
-					// Fixable file error?
-					if !(strings.HasPrefix(msg, "EOF") || strings.HasPrefix(msg, "invalid JPEG")) {
-						log.Debugf("media: %s in %s", msg, clean.Log(m.RootRelName()))
-						return imgErr
-					}
-
-					// Try to fix image.
-					if fixed, fixErr := NewConvert(conf).FixJpeg(m, false); fixErr != nil {
-						return fixErr
-					} else if fixedImg, openErr := thumb.Open(fixed.FileName(), m.Orientation()); openErr != nil {
-						return openErr
-					} else {
-						img = fixedImg
-					}
-				}
-
-				original = img
-
-				log.Debugf("media: opened %s [%s]", clean.Log(m.RootRelName()), thumb.MemSize(original).String())
-			}
+			pending = append(pending, size)
+		}
+	}
 
-			// Thumb size too large
-			// for the original image?
-			if size.Skip(original) {
-				continue
-			}
+	if len(pending) == 0 {
+		return nil
+	}
 
-			// Reuse existing thumb to improve performance
-			// and reduce server load?
-			if size.Source != "" {
-				if size.Source == srcName && srcImg != nil {
-					_, err = size.Create(srcImg, fileName)
-				} else {
-					_, err = size.Create(original, fileName)
-				}
-			} else {
-				srcImg, err = size.Create(original, fileName)
-				srcName = name
-			}
+	// Open original.
+	img, imgErr := thumb.Open(srcFile, orientation)
 
-			// Failed?
-			if err != nil {
-				log.Errorf("media: failed creating %s (%s)", name.String(), err)
-				return err
-			}
+	// Try to fix broken JPEGs if possible, fail otherwise.
+	if imgErr != nil {
+		msg := imgErr.Error()
+
+		// Fixable file error?
+		if !(strings.HasPrefix(msg, "EOF") || strings.HasPrefix(msg, "invalid JPEG")) {
+			log.Debugf("media: %s in %s", msg, clean.Log(m.RootRelName()))
+			return imgErr
+		}
+
+		// Try to fix image.
+		if fixed, fixErr := NewConvert(conf).FixJpeg(m, false); fixErr != nil {
+			return fixErr
+		} else if fixedImg, openErr := thumb.Open(fixed.FileName(), m.Orientation()); openErr != nil {
+			return openErr
+		} else {
+			img = fixedImg
+		}
+	}
 
-			count++
+	original := thumb.Straighten(img, m.Angle())
+
+	log.Debugf("media: opened %s [%s]", clean.Log(m.RootRelName()), thumb.MemSize(original).String())
+
+	sizes := make(thumb.SizeList, 0, len(pending))
+
+	for _, size := range pending {
+		// Thumb size too large for the original image?
+		if !size.Skip(original) {
+			sizes = append(sizes, size)
 		}
 	}
 
+	// Decode the original only once, and resample smaller sizes from larger thumbnails, see thumb.CreateMulti.
+	created, err := thumb.CreateMulti(original, hash, thumbPath, sizes)
+	count = len(created)
+
+	// Failed?
+	if err != nil {
+		log.Errorf("media: failed creating thumbnails (%s)", err)
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,156 @@
+package photoprism
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+
+	"github.com/karrick/godirwalk"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ThumbsCheck represents the result of checking if thumbnails can be created for a media file.
+type ThumbsCheck struct {
+	FileName string
+	FileType string
+	Method   string
+	Error    error
+}
+
+// ThumbsChecks represents a list of thumbnail check results.
+type ThumbsChecks []ThumbsCheck
+
+// Failed returns the number of files for which thumbnails cannot be created.
+func (r ThumbsChecks) Failed() (n int) {
+	for _, c := range r {
+		if c.Error != nil {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Check tests if thumbnails can be created for the media files in an originals subfolder without writing
+// any files. If types are specified, only files with a matching type or extension are checked.
+func (w *Thumbs) Check(dir string, types []string) (results ThumbsChecks, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("thumbs: %s (panic)\nstack: %s", r, debug.Stack())
+			log.Error(err)
+		}
+	}()
+
+	originalsPath := w.conf.OriginalsPath()
+	originalsDir := filepath.Join(originalsPath, dir)
+
+	// Valid path provided?
+	if !fs.PathExists(originalsDir) {
+		return results, fmt.Errorf("thumbs: directory %s not found", clean.Log(originalsDir))
+	}
+
+	allowed := make(map[string]bool, len(types))
+
+	for _, t := range types {
+		if t = strings.ToLower(strings.Trim(strings.TrimSpace(t), ".")); t != "" {
+			allowed[t] = true
+		}
+	}
+
+	conv := NewConvert(w.conf)
+	done := make(fs.Done)
+	ignore := fs.NewIgnoreList(fs.IgnoreFile, true, false)
+
+	handler := func(fileName string, info *godirwalk.Dirent) error {
+		isDir, _ := info.IsDirOrSymlinkToDir()
+		isSymlink := info.IsSymlink()
+
+		if skip, result := fs.SkipWalk(fileName, isDir, isSymlink, done, ignore); skip {
+			return result
+		}
+
+		done[fileName] = fs.Processed
+
+		m, err := NewMediaFile(fileName)
+
+		if err != nil || m.Empty() || !m.IsMedia() {
+			return nil
+		}
+
+		if len(allowed) > 0 && !allowed[m.FileType().String()] && !allowed[strings.Trim(m.Extension(), ".")] {
+			return nil
+		}
+
+		results = append(results, w.CheckFile(conv, m))
+
+		return nil
+	}
+
+	err = godirwalk.Walk(originalsDir, &godirwalk.Options{
+		ErrorCallback: func(fileName string, err error) godirwalk.ErrorAction {
+			return godirwalk.SkipNode
+		},
+		Callback:            handler,
+		Unsorted:            false,
+		FollowSymbolicLinks: true,
+	})
+
+	return results, err
+}
+
+// CheckFile tests if thumbnails can be created for a media file without writing any files.
+// Files that require conversion are not converted, it is only checked that a suitable converter is available.
+func (w *Thumbs) CheckFile(conv *Convert, m *MediaFile) (result ThumbsCheck) {
+	result = ThumbsCheck{FileName: m.RootRelName(), FileType: m.FileType().String()}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result.Error = fmt.Errorf("%s (panic)", r)
+		}
+	}()
+
+	if m.IsImageNative() {
+		result.Method = "decode"
+		result.Error = checkThumbDecode(m.FileName(), m.Orientation())
+	} else if preview, err := m.PreviewImage(); err == nil {
+		result.Method = "preview"
+		result.Error = checkThumbDecode(preview.FileName(), preview.Orientation())
+	} else if cmds, _, err := conv.JpegConvertCommands(m, filepath.Join(os.TempDir(), m.BasePrefix(false)+fs.ExtJPEG), ""); err != nil {
+		result.Error = err
+	} else {
+		result.Error = fmt.Errorf("no converter found for %s files", m.FileType())
+
+		// Use the first converter that is actually installed.
+		for _, cmd := range cmds {
+			if cmd.Err == nil {
+				result.Method = "convert with " + filepath.Base(cmd.Path)
+				result.Error = nil
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// checkThumbDecode decodes an image and resamples it in memory.
+func checkThumbDecode(fileName string, orientation int) error {
+	img, err := thumb.Open(fileName, orientation)
+
+	if err != nil {
+		return err
+	}
+
+	size := thumb.Sizes[thumb.Tile50]
+
+	if thumb.Resample(img, size.Width, size.Height, size.Options...) == nil {
+		return fmt.Errorf("failed to resample %s", clean.Log(filepath.Base(fileName)))
+	}
+
+	return nil
+}
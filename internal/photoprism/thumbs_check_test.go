@@ -0,0 +1,79 @@
+package photoprism
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestThumbs_Check(t *testing.T) {
+	conf := config.TestConfig()
+	w := NewThumbs(conf)
+
+	t.Run("NotFound", func(t *testing.T) {
+		results, err := w.Check("xxx-not-found", nil)
+
+		assert.Error(t, err)
+		assert.Empty(t, results)
+	})
+	t.Run("Success", func(t *testing.T) {
+		results, err := w.Check("", []string{"jpg"})
+
+		assert.NoError(t, err)
+
+		for _, r := range results {
+			assert.Equal(t, "jpg", r.FileType)
+		}
+	})
+}
+
+func TestThumbs_CheckFile(t *testing.T) {
+	conf := config.TestConfig()
+	w := NewThumbs(conf)
+	conv := NewConvert(conf)
+
+	t.Run("Jpeg", func(t *testing.T) {
+		m, err := NewMediaFile(filepath.Join(conf.ExamplesPath(), "elephants.jpg"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := w.CheckFile(conv, m)
+
+		assert.NoError(t, result.Error)
+		assert.Equal(t, "decode", result.Method)
+		assert.Equal(t, "jpg", result.FileType)
+	})
+	t.Run("Corrupt", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "corrupt.jpg")
+
+		if err := os.WriteFile(fileName, []byte("\xff\xd8\xff\xe0 not a jpeg"), fs.ModeFile); err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := NewMediaFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := w.CheckFile(conv, m)
+
+		assert.Error(t, result.Error)
+	})
+}
+
+func TestThumbsChecks_Failed(t *testing.T) {
+	results := ThumbsChecks{
+		{FileName: "a.jpg", Method: "decode"},
+		{FileName: "b.cr2", Error: os.ErrNotExist},
+	}
+
+	assert.Equal(t, 1, results.Failed())
+}
@@ -0,0 +1,236 @@
+package photoprism
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/media"
+)
+
+// ThumbsExportIndex is the file name of the JSON index in thumbnail export folders.
+const ThumbsExportIndex = "index.json"
+
+// ThumbsExportOptions specifies the sizes and formats of exported thumbnails, see Thumbs.Export.
+type ThumbsExportOptions struct {
+	Sizes   []thumb.Name
+	Formats []fs.Type
+	Force   bool
+}
+
+// ThumbsExportFile represents the thumbnails of an exported file in the JSON index.
+type ThumbsExportFile struct {
+	Hash     string              `json:"hash"`
+	PhotoUID string              `json:"photo_uid"`
+	Title    string              `json:"title,omitempty"`
+	TakenAt  time.Time           `json:"taken_at"`
+	Width    int                 `json:"width"`
+	Height   int                 `json:"height"`
+	Thumbs   map[string][]string `json:"thumbs"`
+}
+
+// ThumbsExport represents the JSON index of a thumbnail export folder.
+type ThumbsExport struct {
+	Sizes     []string           `json:"sizes"`
+	Formats   []string           `json:"formats"`
+	Files     []ThumbsExportFile `json:"files"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	Created   int                `json:"-"`
+	Skipped   int                `json:"-"`
+}
+
+// ThumbsExportName returns the predictable name of an exported thumbnail, e.g. "[hash]_tile_500.jpg".
+func ThumbsExportName(hash string, name thumb.Name, format fs.Type) string {
+	return fmt.Sprintf("%s_%s%s", hash, name, format.DefaultExt())
+}
+
+// Export renders thumbnails of the selected photos into a flat folder with predictable file names and a
+// JSON index, e.g. for publishing a read-only gallery with static hosting. Existing thumbnails are skipped
+// unless force is true, so that an export can be resumed.
+func (w *Thumbs) Export(dir string, sel form.Selection, opt ThumbsExportOptions) (result ThumbsExport, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("thumbs: %s (panic)\nstack: %s", r, debug.Stack())
+			log.Error(err)
+		}
+	}()
+
+	if dir == "" {
+		return result, errors.New("thumbs: export folder is empty")
+	} else if len(opt.Sizes) == 0 {
+		return result, errors.New("thumbs: no sizes to export")
+	} else if len(opt.Formats) == 0 {
+		opt.Formats = []fs.Type{fs.ImageJPEG}
+	}
+
+	for _, name := range opt.Sizes {
+		if _, ok := thumb.Sizes[name]; !ok {
+			return result, fmt.Errorf("thumbs: invalid size %s", clean.Log(name.String()))
+		}
+
+		result.Sizes = append(result.Sizes, name.String())
+	}
+
+	for _, format := range opt.Formats {
+		if !exportFormat(format) {
+			return result, fmt.Errorf("thumbs: cannot encode %s images", clean.Log(format.String()))
+		}
+
+		result.Formats = append(result.Formats, format.String())
+	}
+
+	if err = mutex.MainWorker.Start(); err != nil {
+		return result, err
+	}
+
+	defer mutex.MainWorker.Stop()
+
+	files, err := query.SelectedFiles(sel, query.FileSelection{Primary: true, Types: media.PreviewFileTypes})
+
+	if err != nil {
+		return result, err
+	} else if err = os.MkdirAll(dir, fs.ModeDir); err != nil {
+		return result, err
+	}
+
+	result.Files = make([]ThumbsExportFile, 0, len(files))
+
+	for i := range files {
+		if mutex.MainWorker.Canceled() {
+			return result, errors.New("thumbs: export canceled")
+		}
+
+		file, exportErr := w.exportFile(&files[i], dir, opt, &result)
+
+		if exportErr != nil {
+			log.Errorf("thumbs: %s in %s (export)", exportErr, clean.Log(files[i].FileName))
+			continue
+		}
+
+		result.Files = append(result.Files, file)
+	}
+
+	result.UpdatedAt = entity.TimeStamp()
+
+	return result, w.saveExportIndex(dir, result)
+}
+
+// exportFile renders the thumbnails of a file into the export folder.
+func (w *Thumbs) exportFile(f *entity.File, dir string, opt ThumbsExportOptions, result *ThumbsExport) (file ThumbsExportFile, err error) {
+	p := f.RelatedPhoto()
+
+	file = ThumbsExportFile{
+		Hash:     f.FileHash,
+		PhotoUID: f.PhotoUID,
+		Title:    p.PhotoTitle,
+		TakenAt:  p.TakenAt,
+		Width:    f.FileWidth,
+		Height:   f.FileHeight,
+		Thumbs:   make(map[string][]string, len(opt.Sizes)),
+	}
+
+	fileName := FileName(f.FileRoot, f.FileName)
+	thumbPath := thumb.Path(w.conf.ThumbCachePath(), fileName)
+
+	for _, name := range opt.Sizes {
+		size := thumb.Sizes[name]
+
+		// Choose the smallest fitting size if the original image is smaller.
+		if size.Fit && f.Bounds().In(size.Bounds()) {
+			size = thumb.FitBounds(f.Bounds())
+		}
+
+		var thumbName string
+
+		for _, format := range opt.Formats {
+			exportName := ThumbsExportName(f.FileHash, name, format)
+			exportFile := filepath.Join(dir, exportName)
+
+			// Skip thumbnails that have been exported before, so that an export can be resumed.
+			if !opt.Force && fs.FileExists(exportFile) {
+				file.Thumbs[name.String()] = append(file.Thumbs[name.String()], exportName)
+				result.Skipped++
+				continue
+			}
+
+			if thumbName == "" {
+				if thumbName, err = size.FromFileAngle(fileName, f.FileHash, thumbPath, f.FileOrientation, float64(f.FileAngle)); err != nil {
+					return file, err
+				}
+			}
+
+			if err = exportThumb(thumbName, exportFile, format, size); err != nil {
+				return file, err
+			}
+
+			file.Thumbs[name.String()] = append(file.Thumbs[name.String()], exportName)
+			result.Created++
+		}
+	}
+
+	return file, nil
+}
+
+// exportFormat checks if thumbnails can currently be exported in the specified format, see thumb.Formats.
+func exportFormat(format fs.Type) bool {
+	for _, f := range thumb.Formats() {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exportThumb saves a copy of the thumbnail in the specified format. Files are written to a temporary
+// file first, so that incomplete files are never skipped when an export is resumed.
+func exportThumb(thumbName, exportFile string, format fs.Type, size thumb.Size) (err error) {
+	tmpFile := filepath.Join(filepath.Dir(exportFile), "."+filepath.Base(exportFile))
+
+	defer os.Remove(tmpFile)
+
+	if format == fs.ImageJPEG {
+		err = fs.Copy(thumbName, tmpFile)
+	} else if img, openErr := imaging.Open(thumbName); openErr != nil {
+		err = openErr
+	} else {
+		err = thumb.SaveFormat(img, tmpFile, format, thumb.SizeQuality(size.Width, size.Height))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, exportFile)
+}
+
+// saveExportIndex saves the JSON index of an export folder.
+func (w *Thumbs) saveExportIndex(dir string, index ThumbsExport) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	indexFile := filepath.Join(dir, ThumbsExportIndex)
+	tmpFile := filepath.Join(dir, "."+ThumbsExportIndex)
+
+	if err = os.WriteFile(tmpFile, data, fs.ModeFile); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, indexFile)
+}
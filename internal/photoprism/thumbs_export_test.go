@@ -0,0 +1,50 @@
+package photoprism
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestThumbsExportName(t *testing.T) {
+	assert.Equal(t, "ca7d4e9c7ad1ab9ca8a0b9b8e4b3c3e0d4c5b6a7_tile_500.jpg", ThumbsExportName("ca7d4e9c7ad1ab9ca8a0b9b8e4b3c3e0d4c5b6a7", thumb.Tile500, fs.ImageJPEG))
+	assert.Equal(t, "ca7d4e9c7ad1ab9ca8a0b9b8e4b3c3e0d4c5b6a7_fit_1920.webp", ThumbsExportName("ca7d4e9c7ad1ab9ca8a0b9b8e4b3c3e0d4c5b6a7", thumb.Fit1920, fs.ImageWebP))
+}
+
+func TestThumbs_Export(t *testing.T) {
+	conf := config.TestConfig()
+
+	w := NewThumbs(conf)
+
+	t.Run("Index", func(t *testing.T) {
+		dir := t.TempDir()
+
+		result, err := w.Export(dir, form.Selection{Photos: []string{"pt9jtdre2lvl0yh7"}}, ThumbsExportOptions{Sizes: []thumb.Name{thumb.Tile224}})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"tile_224"}, result.Sizes)
+		assert.Equal(t, []string{"jpg"}, result.Formats)
+		assert.FileExists(t, filepath.Join(dir, ThumbsExportIndex))
+	})
+	t.Run("NoSizes", func(t *testing.T) {
+		_, err := w.Export(t.TempDir(), form.Selection{Photos: []string{"pt9jtdre2lvl0yh7"}}, ThumbsExportOptions{})
+		assert.Error(t, err)
+	})
+	t.Run("InvalidSize", func(t *testing.T) {
+		_, err := w.Export(t.TempDir(), form.Selection{Photos: []string{"pt9jtdre2lvl0yh7"}}, ThumbsExportOptions{Sizes: []thumb.Name{"foo_123"}})
+		assert.Error(t, err)
+	})
+	t.Run("InvalidFormat", func(t *testing.T) {
+		_, err := w.Export(t.TempDir(), form.Selection{Photos: []string{"pt9jtdre2lvl0yh7"}}, ThumbsExportOptions{Sizes: []thumb.Name{thumb.Tile224}, Formats: []fs.Type{fs.ImageGIF}})
+		assert.Error(t, err)
+	})
+}
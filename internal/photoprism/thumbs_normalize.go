@@ -0,0 +1,80 @@
+package photoprism
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Normalize bakes the orientation of indexed files in the specified originals subfolder into upright copies
+// and creates their thumbnails again, e.g. after normalization has been enabled. Originals remain untouched.
+// It returns the number of files that have been normalized.
+func (w *Thumbs) Normalize(dir string) (count int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("thumbs: %s (panic)\nstack: %s", r, debug.Stack())
+			log.Error(err)
+		}
+	}()
+
+	if !thumb.Normalize {
+		return 0, errors.New("thumbs: orientation normalization is disabled")
+	}
+
+	if err = mutex.MainWorker.Start(); err != nil {
+		return 0, err
+	}
+
+	defer mutex.MainWorker.Stop()
+
+	thumbPath := w.conf.ThumbCachePath()
+	limit := 1000
+	offset := 0
+
+	for {
+		files, err := query.Files(limit, offset, dir, false)
+
+		if err != nil {
+			return count, err
+		} else if len(files) == 0 {
+			break
+		}
+
+		for _, file := range files {
+			if mutex.MainWorker.Canceled() {
+				return count, errors.New("thumbs: normalization canceled")
+			} else if file.FileOrientation <= thumb.OrientationNormal || file.FileSidecar {
+				continue
+			}
+
+			fileName := FileName(file.FileRoot, file.FileName)
+
+			if !fs.FileExists(fileName) {
+				continue
+			}
+
+			m, err := NewMediaFile(fileName)
+
+			if err != nil || !m.IsPreviewImage() {
+				continue
+			}
+
+			if err = m.CreateThumbnails(thumbPath, true); err != nil {
+				log.Errorf("thumbs: %s in %s (normalize)", err, clean.Log(file.FileName))
+				continue
+			}
+
+			count++
+		}
+
+		offset += limit
+	}
+
+	return count, nil
+}
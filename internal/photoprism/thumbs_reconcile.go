@@ -0,0 +1,184 @@
+package photoprism
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ThumbsDrift represents the differences between the index and the thumbnail cache, i.e. indexed files
+// without thumbnails and thumbnails of files that are not indexed.
+type ThumbsDrift struct {
+	Files    int
+	Missing  []string
+	Orphans  []string
+	Repaired int
+	Removed  int
+}
+
+// Found returns the number of differences found.
+func (d ThumbsDrift) Found() int {
+	return len(d.Missing) + len(d.Orphans)
+}
+
+// Reconcile compares the indexed files with the thumbnail cache. It finds files whose thumbnails are all
+// missing, and thumbnails whose file hash is not indexed anymore. If repair is true, missing thumbnails
+// are created and orphaned thumbnails are removed.
+func (w *Thumbs) Reconcile(repair bool) (result ThumbsDrift, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("thumbs: %s (panic)\nstack: %s", r, debug.Stack())
+			log.Error(err)
+		}
+	}()
+
+	if err = w.reconcileMissing(&result, repair); err != nil {
+		return result, err
+	}
+
+	err = w.reconcileOrphans(&result, repair)
+
+	return result, err
+}
+
+// reconcileMissing finds indexed files whose thumbnails are all missing, and creates them if repair is true.
+func (w *Thumbs) reconcileMissing(result *ThumbsDrift, repair bool) error {
+	cachePath := w.conf.ThumbCachePath()
+	limit := 1000
+	offset := 0
+
+	for {
+		files, err := query.ThumbSourceFiles(limit, offset)
+
+		if err != nil {
+			return err
+		} else if len(files) == 0 {
+			return nil
+		}
+
+		for _, f := range files {
+			if mutex.ThumbsWorker.Canceled() {
+				return errors.New("thumbs: reconciliation canceled")
+			}
+
+			result.Files++
+
+			fileName := FileName(f.FileRoot, f.FileName)
+
+			if !thumbsMissing(f.FileHash, thumb.Path(cachePath, fileName)) {
+				continue
+			}
+
+			result.Missing = append(result.Missing, f.FileName)
+
+			if repair && reconcileFile(f, fileName, cachePath) {
+				result.Repaired++
+			}
+		}
+
+		offset += limit
+	}
+}
+
+// reconcileOrphans finds thumbnails whose file hash is not indexed, and removes them if repair is true.
+func (w *Thumbs) reconcileOrphans(result *ThumbsDrift, repair bool) error {
+	fileHashes, err := query.FileHashMap()
+
+	if err != nil {
+		return err
+	}
+
+	thumbHashes, err := query.ThumbHashMap()
+
+	if err != nil {
+		return err
+	}
+
+	// Do not report all thumbnails as orphans if the index is empty.
+	if len(fileHashes) == 0 {
+		return nil
+	}
+
+	cachePath := w.conf.ThumbCachePath()
+	originalsPath := w.conf.OriginalsPath()
+
+	return thumb.Walk(cachePath, originalsPath, func(fileName string, d os.DirEntry) error {
+		base := d.Name()
+
+		if strings.HasPrefix(base, ".") {
+			return nil
+		}
+
+		// Example: 01244519acf35c62a5fea7a5a7dcefdbec4fb2f5_3x3_resize.png
+		i := strings.Index(base, "_")
+
+		if i < 40 {
+			return nil
+		}
+
+		hash := base[:i]
+
+		if fileHashes[hash] || thumbHashes[hash] || thumb.PinnedHash(hash) {
+			return nil
+		}
+
+		// Thumbnails in sidecar folders are reported relative to the originals folder.
+		if relName := fs.RelName(fileName, cachePath); relName != fileName {
+			result.Orphans = append(result.Orphans, relName)
+		} else {
+			result.Orphans = append(result.Orphans, fs.RelName(fileName, originalsPath))
+		}
+
+		if !repair {
+			return nil
+		} else if err := os.Remove(fileName); err != nil {
+			log.Warnf("thumbs: %s while removing orphan %s", err, clean.Log(base))
+		} else {
+			result.Removed++
+		}
+
+		return nil
+	})
+}
+
+// thumbsMissing checks if none of the pre-cached thumbnails of the file hash exist.
+func thumbsMissing(hash, thumbPath string) bool {
+	for _, name := range thumb.Names {
+		if size := thumb.Sizes[name]; size.Uncached() {
+			continue
+		} else if fileName, err := size.FileName(hash, thumbPath); err == nil && fs.FileExists(fileName) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reconcileFile creates the missing thumbnails of an indexed file, and returns true if successful.
+func reconcileFile(f entity.File, fileName, cachePath string) bool {
+	if thumb.IsRemote(fileName) {
+		log.Debugf("thumbs: skipped remote original %s", clean.Log(f.FileName))
+		return false
+	}
+
+	m, err := NewMediaFile(fileName)
+
+	if err != nil {
+		log.Warnf("thumbs: %s while repairing %s", err, clean.Log(f.FileName))
+		return false
+	} else if err = m.CreateThumbnails(cachePath, false); err != nil {
+		log.Warnf("thumbs: %s while repairing %s", err, clean.Log(f.FileName))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,53 @@
+package photoprism
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestThumbsDrift_Found(t *testing.T) {
+	assert.Equal(t, 0, ThumbsDrift{Files: 5}.Found())
+	assert.Equal(t, 3, ThumbsDrift{Missing: []string{"a.jpg"}, Orphans: []string{"b.jpg", "c.jpg"}}.Found())
+}
+
+func TestThumbs_Reconcile(t *testing.T) {
+	conf := config.TestConfig()
+
+	w := NewThumbs(conf)
+
+	result, err := w.Reconcile(false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 0, result.Repaired)
+	assert.Equal(t, 0, result.Removed)
+}
+
+func TestThumbsMissing(t *testing.T) {
+	thumbPath := t.TempDir()
+	hash := "ca7d4e9c7ad1ab9ca8a0b9b8e4b3c3e0d4c5b6a7"
+
+	assert.True(t, thumbsMissing(hash, thumbPath))
+
+	fileName, err := thumb.Sizes[thumb.Tile50].FileName(hash, thumbPath)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(fileName, []byte("jpeg"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, thumbsMissing(hash, thumbPath))
+}
@@ -104,6 +104,24 @@ func AlbumCoverByUID(uid string, public bool) (file entity.File, err error) {
 	return file, nil
 }
 
+// AlbumHasPhoto checks if the photo is a visible entry of the album, e.g. to verify that a share link includes it.
+func AlbumHasPhoto(albumUid, photoUid string) bool {
+	if albumUid == "" || photoUid == "" {
+		return false
+	}
+
+	var count int
+
+	if err := UnscopedDb().Table(entity.PhotoAlbum{}.TableName()).
+		Where("album_uid = ? AND photo_uid = ? AND hidden = 0 AND missing = 0", albumUid, photoUid).
+		Count(&count).Error; err != nil {
+		log.Debugf("query: %s", err)
+		return false
+	}
+
+	return count > 0
+}
+
 // UpdateAlbumDates updates the year, month and day of the album based on the indexed photo metadata.
 func UpdateAlbumDates() error {
 	mutex.Index.Lock()
@@ -28,6 +28,12 @@ func TestAlbumByUID(t *testing.T) {
 	})
 }
 
+func TestAlbumHasPhoto(t *testing.T) {
+	assert.True(t, AlbumHasPhoto("at9lxuqxpogaaba8", "pt9jtdre2lvl0yh7"))
+	assert.False(t, AlbumHasPhoto("at9lxuqxpogaaba8", "pt9jtdre2lvl0y11"))
+	assert.False(t, AlbumHasPhoto("", "pt9jtdre2lvl0yh7"))
+}
+
 func TestAlbumCoverByUID(t *testing.T) {
 	t.Run("existing uid default album", func(t *testing.T) {
 		file, err := AlbumCoverByUID("at9lxuqxpogaaba8", true)
@@ -76,6 +76,21 @@ func FileByPhotoUID(photoUID string) (*entity.File, error) {
 	return &f, err
 }
 
+// PreviewFilesByPhotoUID finds the JPEG and PNG files of a photo, starting with the primary file and
+// followed by the other image files in descending order of their resolution.
+func PreviewFilesByPhotoUID(photoUID string, limit int) (files entity.Files, err error) {
+	if photoUID == "" {
+		return files, fmt.Errorf("photo uid required")
+	}
+
+	err = Db().Where("photo_uid = ? AND file_missing = 0 AND file_error = ''", photoUID).
+		Where("file_type IN (?)", media.PreviewExpr).
+		Order("file_primary DESC, file_width DESC, file_hdr DESC, id").
+		Limit(limit).Preload("Photo").Find(&files).Error
+
+	return files, err
+}
+
 // VideoByPhotoUID finds a video for the given photo UID.
 func VideoByPhotoUID(photoUID string) (*entity.File, error) {
 	f := entity.File{}
@@ -105,7 +120,34 @@ func FileByUID(fileUID string) (*entity.File, error) {
 	return &f, err
 }
 
-// FileByHash finds a file with a given hash string.
+// FileHashColumns maps supported hash types to the file table columns they are stored in.
+var FileHashColumns = map[fs.HashType]string{
+	fs.HashSHA1:   "file_hash",
+	fs.HashBlake3: "file_hash_blake3",
+}
+
+// Orders in which files with the same hash are selected, e.g. if the same image is indexed in multiple folders.
+const (
+	HashOrderAvailable = "available"
+	HashOrderPrimary   = "primary"
+	HashOrderOldest    = "oldest"
+)
+
+// HashOrders maps the supported selection orders to SQL sort clauses. The file id is always used as the
+// last criterion, so that the same file is returned for every request.
+var HashOrders = map[string]string{
+	HashOrderAvailable: "file_missing, file_primary DESC, file_sidecar, id",
+	HashOrderPrimary:   "file_primary DESC, file_missing, file_sidecar, id",
+	HashOrderOldest:    "id",
+}
+
+// HashOrder specifies which file FileByHash returns if multiple files have the same hash, see HashOrders.
+var HashOrder = HashOrderAvailable
+
+// FileByHash finds a file with a given hash string, optionally prefixed with the hash type, e.g. "blake3:...".
+// If multiple files have the same hash, the first file in HashOrder is returned: by default, files whose
+// original exists are preferred over missing ones, then primary files, then non-sidecar files, then the
+// file that was indexed first.
 func FileByHash(fileHash string) (*entity.File, error) {
 	f := entity.File{}
 
@@ -113,7 +155,25 @@ func FileByHash(fileHash string) (*entity.File, error) {
 		return &f, fmt.Errorf("file hash required")
 	}
 
-	err := Db().Where("file_hash = ?", fileHash).Preload("Photo").First(&f).Error
+	hashType, hash := fs.ParseHash(fileHash)
+
+	if hash == "" {
+		return &f, fmt.Errorf("file hash required")
+	}
+
+	col, ok := FileHashColumns[hashType]
+
+	if !ok {
+		return &f, fmt.Errorf("unsupported hash type %s", hashType)
+	}
+
+	order, ok := HashOrders[HashOrder]
+
+	if !ok {
+		order = HashOrders[HashOrderAvailable]
+	}
+
+	err := Db().Where(col+" = ?", hash).Order(order).Preload("Photo").First(&f).Error
 
 	return &f, err
 }
@@ -218,3 +278,50 @@ func OrphanFiles() (files entity.Files, err error) {
 
 	return files, err
 }
+
+// ThumbSourceFiles returns indexed JPEG and PNG files, from which thumbnails are created, in the range
+// of limit and offset sorted by id.
+func ThumbSourceFiles(limit, offset int) (files entity.Files, err error) {
+	err = Db().
+		Where("file_missing = 0 AND file_error = ''").
+		Where("file_hash IS NOT NULL AND file_hash <> ''").
+		Where("file_type IN (?)", []string{fs.ImageJPEG.String(), fs.ImagePNG.String()}).
+		Order("id").Limit(limit).Offset(offset).
+		Find(&files).Error
+
+	return files, err
+}
+
+// FilesByLabel returns the indexed files of photos with the label matching the slug or UID, sorted by id.
+func FilesByLabel(label string) (files entity.Files, err error) {
+	if label == "" {
+		return files, fmt.Errorf("label missing")
+	}
+
+	err = Db().
+		Joins("JOIN photos_labels ON photos_labels.photo_id = files.photo_id AND photos_labels.uncertainty < 100").
+		Joins("JOIN labels ON labels.id = photos_labels.label_id").
+		Where("labels.label_slug = ? OR labels.custom_slug = ? OR labels.label_uid = ?", label, label, label).
+		Where("files.file_missing = 0 AND files.file_hash <> ''").
+		Order("files.id").
+		Find(&files).Error
+
+	return files, err
+}
+
+// FilesByKeyword returns the indexed files of photos with the specified keyword, sorted by id.
+func FilesByKeyword(keyword string) (files entity.Files, err error) {
+	if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword == "" {
+		return files, fmt.Errorf("keyword missing")
+	}
+
+	err = Db().
+		Joins("JOIN photos_keywords ON photos_keywords.photo_id = files.photo_id").
+		Joins("JOIN keywords ON keywords.id = photos_keywords.keyword_id").
+		Where("keywords.keyword = ?", keyword).
+		Where("files.file_missing = 0 AND files.file_hash <> ''").
+		Order("files.id").
+		Find(&files).Error
+
+	return files, err
+}
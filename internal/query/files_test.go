@@ -134,6 +134,44 @@ func TestFileByPhotoUID(t *testing.T) {
 	})
 }
 
+func TestPreviewFilesByPhotoUID(t *testing.T) {
+	t.Run("MultipleFiles", func(t *testing.T) {
+		files, err := PreviewFilesByPhotoUID("pt9jtdre2lvl0yh0", 5)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, files, 2) {
+			assert.Equal(t, "1990/04/bridge2.jpg", files[0].FileName)
+			assert.True(t, files[0].FilePrimary)
+			assert.Equal(t, "London/bridge3.jpg", files[1].FileName)
+		}
+	})
+	t.Run("Limit", func(t *testing.T) {
+		files, err := PreviewFilesByPhotoUID("pt9jtdre2lvl0yh0", 1)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, files, 1) {
+			assert.Equal(t, "1990/04/bridge2.jpg", files[0].FileName)
+		}
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		files, err := PreviewFilesByPhotoUID("111", 5)
+
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+	t.Run("EmptyUID", func(t *testing.T) {
+		_, err := PreviewFilesByPhotoUID("", 5)
+
+		assert.Error(t, err)
+	})
+}
+
 func TestVideoByPhotoUID(t *testing.T) {
 	t.Run("files found", func(t *testing.T) {
 		file, err := VideoByPhotoUID("pt9jtdre2lvl0yh0")
@@ -191,6 +229,81 @@ func TestFileByHash(t *testing.T) {
 		assert.Error(t, err, "record not found")
 		t.Log(file)
 	})
+	t.Run("sha1 prefix", func(t *testing.T) {
+		file, err := FileByHash("sha1:2cad9168fa6acc5c5c2965ddf6ec465ca42fd818")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "2790/07/27900704_070228_D6D51B6C.jpg", file.FileName)
+	})
+	t.Run("blake3", func(t *testing.T) {
+		file, err := FileByHash("blake3:af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "2cad9168fa6acc5c5c2965ddf6ec465ca42fd818", file.FileHash)
+	})
+	t.Run("blake3 not found", func(t *testing.T) {
+		_, err := FileByHash("blake3:2cad9168fa6acc5c5c2965ddf6ec465ca42fd818")
+
+		assert.Error(t, err, "record not found")
+	})
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := FileByHash("md5:2cad9168fa6acc5c5c2965ddf6ec465c")
+
+		assert.EqualError(t, err, "unsupported hash type md5")
+	})
+	t.Run("duplicate prefer available", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			file, err := FileByHash("acad9168fa6acc5c5c2965ddf6ec465ca42fd819")
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, "ft4es39w45bnlqdw", file.FileUID)
+			assert.False(t, file.FileMissing)
+		}
+	})
+	t.Run("duplicate prefer primary", func(t *testing.T) {
+		HashOrder = HashOrderPrimary
+		defer func() { HashOrder = HashOrderAvailable }()
+
+		file, err := FileByHash("pcad9a68fa6acc5c5ba965adf6ec465ca42fd918")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "ft2es49qhhinlplp", file.FileUID)
+		assert.True(t, file.FilePrimary)
+	})
+	t.Run("duplicate oldest", func(t *testing.T) {
+		HashOrder = HashOrderOldest
+		defer func() { HashOrder = HashOrderAvailable }()
+
+		file, err := FileByHash("acad9168fa6acc5c5c2965ddf6ec465ca42fd819")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, uint(1000005), file.ID)
+	})
+	t.Run("invalid order", func(t *testing.T) {
+		HashOrder = "random"
+		defer func() { HashOrder = HashOrderAvailable }()
+
+		file, err := FileByHash("acad9168fa6acc5c5c2965ddf6ec465ca42fd819")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "ft4es39w45bnlqdw", file.FileUID)
+	})
 }
 
 func TestSetPhotoPrimary(t *testing.T) {
@@ -287,3 +400,64 @@ func TestOrphanFiles(t *testing.T) {
 
 	assert.IsType(t, entity.Files{}, files)
 }
+
+func TestThumbSourceFiles(t *testing.T) {
+	files, err := ThumbSourceFiles(100, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.LessOrEqual(t, len(files), 100)
+
+	for _, f := range files {
+		assert.Contains(t, []string{"jpg", "png"}, f.FileType)
+		assert.False(t, f.FileMissing)
+		assert.NotEmpty(t, f.FileHash)
+	}
+}
+
+func TestFilesByLabel(t *testing.T) {
+	t.Run("Slug", func(t *testing.T) {
+		files, err := FilesByLabel("landscape")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotEmpty(t, files)
+
+		for _, f := range files {
+			assert.NotEmpty(t, f.FileHash)
+		}
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		files, err := FilesByLabel("xxx-not-a-label")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Empty(t, files)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		_, err := FilesByLabel("")
+		assert.Error(t, err)
+	})
+}
+
+func TestFilesByKeyword(t *testing.T) {
+	t.Run("Bridge", func(t *testing.T) {
+		files, err := FilesByKeyword("Bridge")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotEmpty(t, files)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		_, err := FilesByKeyword(" ")
+		assert.Error(t, err)
+	})
+}
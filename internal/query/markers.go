@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jinzhu/gorm"
@@ -76,6 +77,23 @@ func FaceMarkers(limit, offset int) (result entity.Markers, err error) {
 	return result, err
 }
 
+// FaceMarker returns the valid face marker that best represents a face cluster, i.e. the one closest to its center.
+func FaceMarker(faceId string) (*entity.Marker, error) {
+	result := entity.Marker{}
+
+	if faceId == "" {
+		return &result, fmt.Errorf("face id required")
+	}
+
+	err := Db().
+		Where("face_id = ? AND marker_type = ?", strings.ToUpper(faceId), entity.MarkerFace).
+		Where("marker_invalid = 0 AND thumb <> ''").
+		Order("face_dist, q DESC, marker_uid").
+		First(&result).Error
+
+	return &result, err
+}
+
 // Embeddings returns existing face embeddings.
 func Embeddings(single, unclustered bool, size, score int) (result face.Embeddings, err error) {
 	var col []string
@@ -109,6 +109,30 @@ func TestFaceMarkers(t *testing.T) {
 	})
 }
 
+func TestFaceMarker(t *testing.T) {
+	t.Run("Found", func(t *testing.T) {
+		faceId := entity.FaceFixtures.Get("actress-1").ID
+		result, err := FaceMarker(faceId)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, faceId, result.FaceID)
+		assert.Equal(t, entity.MarkerFace, result.MarkerType)
+		assert.False(t, result.MarkerInvalid)
+		assert.NotEmpty(t, result.Thumb)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := FaceMarker("XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX")
+		assert.Error(t, err)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		_, err := FaceMarker("")
+		assert.Error(t, err)
+	})
+}
+
 func TestEmbeddings(t *testing.T) {
 	t.Run("all", func(t *testing.T) {
 		results, err := Embeddings(false, false, 0, 0)
@@ -665,6 +665,18 @@ func searchPhotos(f form.SearchPhotos, sess *entity.Session, resultCols string)
 		s = s.Where("files.file_diff = ?", f.Diff)
 	}
 
+	// Filter by average hash distance.
+	if txt.NotEmpty(f.AHash) {
+		if ids, hashErr := AHashFileIDs(f.AHash, f.AHashDist); hashErr != nil {
+			log.Debugf("search: %s", hashErr)
+			return PhotoResults{}, 0, ErrBadFilter
+		} else if len(ids) == 0 {
+			return PhotoResults{}, 0, nil
+		} else {
+			s = s.Where("files.id IN (?)", ids)
+		}
+	}
+
 	if f.Fmin > 0 {
 		s = s.Where("photos.photo_f_number >= ?", f.Fmin)
 	}
@@ -0,0 +1,81 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// AHashFileIDs returns the IDs of files with an average hash that differs from the specified hash
+// in at most dist bits, or thumb.AHashDist bits if dist is zero or negative, see thumb.AHash.
+// The distance is computed in Go since not all supported databases can count bits, so the candidates are
+// narrowed down in the database first, see aHashBuckets.
+func AHashFileIDs(hash string, dist int) (ids []uint, err error) {
+	h, err := thumb.ParseAHash(hash)
+
+	if err != nil {
+		return ids, err
+	}
+
+	if dist <= 0 {
+		dist = thumb.AHashDist
+	}
+
+	var rows []struct {
+		ID        uint
+		FileAHash string `gorm:"column:file_ahash"`
+	}
+
+	stmt := UnscopedDb().Table("files").Select("id, file_ahash").
+		Where("file_ahash <> '' AND file_missing = 0 AND deleted_at IS NULL")
+
+	if cond, values := aHashBuckets(h, dist); cond != "" {
+		stmt = stmt.Where(cond, values...)
+	}
+
+	if err = stmt.Scan(&rows).Error; err != nil {
+		return ids, err
+	}
+
+	for _, row := range rows {
+		if other, parseErr := thumb.ParseAHash(row.FileAHash); parseErr != nil {
+			continue
+		} else if h.Distance(other) <= dist {
+			ids = append(ids, row.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// aHashBuckets returns a condition that matches all hashes which differ in at most dist bits. The hex string
+// is split into dist+1 parts, of which at least one must be equal, as each differing bit can only change one
+// part. An empty condition is returned if the parts would be shorter than a single hex digit.
+func aHashBuckets(h thumb.AHash, dist int) (cond string, values []interface{}) {
+	const digits = 16
+
+	parts := dist + 1
+
+	if parts > digits {
+		return "", nil
+	}
+
+	hex := h.String()
+	where := make([]string, 0, parts)
+	values = make([]interface{}, 0, parts)
+
+	for i, start := 0, 0; i < parts; i++ {
+		n := digits / parts
+
+		if i < digits%parts {
+			n++
+		}
+
+		where = append(where, fmt.Sprintf("SUBSTR(file_ahash, %d, %d) = ?", start+1, n))
+		values = append(values, hex[start:start+n])
+		start += n
+	}
+
+	return "(" + strings.Join(where, " OR ") + ")", values
+}
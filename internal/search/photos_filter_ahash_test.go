@@ -0,0 +1,119 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+func TestPhotosFilterAHash(t *testing.T) {
+	t.Run("Exact", func(t *testing.T) {
+		var f form.SearchPhotos
+
+		f.AHash = "ff81818181c3e7ff"
+		f.Merged = true
+
+		photos, _, err := Photos(f)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+	})
+	t.Run("Similar", func(t *testing.T) {
+		var f form.SearchPhotos
+
+		f.AHash = "ff81818181c3e7f0"
+		f.Merged = true
+
+		photos, _, err := Photos(f)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+	})
+	t.Run("Distance", func(t *testing.T) {
+		var f form.SearchPhotos
+
+		f.AHash = "ff81818181c3e7f0"
+		f.AHashDist = 2
+		f.Merged = true
+
+		photos, _, err := Photos(f)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 0)
+	})
+	t.Run("QueryString", func(t *testing.T) {
+		var f form.SearchPhotos
+
+		f.Query = "ahash:ff81818181c3e7fe ahashdist:1"
+		f.Merged = true
+
+		photos, _, err := Photos(f)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, photos, 1)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		var f form.SearchPhotos
+
+		f.AHash = "xyz"
+		f.Merged = true
+
+		_, _, err := Photos(f)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAHashFileIDs(t *testing.T) {
+	ids, err := AHashFileIDs("ff81818181c3e7ff", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1000000}, ids)
+
+	ids, err = AHashFileIDs("007e7e7e7e3c1800", 0)
+
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestAHashBuckets(t *testing.T) {
+	h, err := thumb.ParseAHash("ff81818181c3e7ff")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		cond, values := aHashBuckets(h, thumb.AHashDist)
+
+		assert.Equal(t, "(SUBSTR(file_ahash, 1, 3) = ? OR SUBSTR(file_ahash, 4, 3) = ? OR SUBSTR(file_ahash, 7, 3) = ? OR SUBSTR(file_ahash, 10, 3) = ? OR SUBSTR(file_ahash, 13, 2) = ? OR SUBSTR(file_ahash, 15, 2) = ?)", cond)
+		assert.Equal(t, []interface{}{"ff8", "181", "818", "1c3", "e7", "ff"}, values)
+	})
+	t.Run("Exact", func(t *testing.T) {
+		cond, values := aHashBuckets(h, 0)
+
+		assert.Equal(t, "(SUBSTR(file_ahash, 1, 16) = ?)", cond)
+		assert.Equal(t, []interface{}{"ff81818181c3e7ff"}, values)
+	})
+	t.Run("Large", func(t *testing.T) {
+		cond, values := aHashBuckets(h, 20)
+
+		assert.Equal(t, "", cond)
+		assert.Nil(t, values)
+	})
+}
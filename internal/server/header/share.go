@@ -0,0 +1,45 @@
+package header
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	AccessControlExposeHeaders = "Access-Control-Expose-Headers" // https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Access-Control-Expose-Headers
+	CrossOriginResourcePolicy  = "Cross-Origin-Resource-Policy"  // https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Cross-Origin-Resource-Policy
+	TimingAllowOrigin          = "Timing-Allow-Origin"           // https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Timing-Allow-Origin
+	RobotsTag                  = "X-Robots-Tag"                  // https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/X-Robots-Tag
+)
+
+// ShareHeaders contains the response headers that may be configured per share link,
+// so that shared content can be embedded in other sites.
+var ShareHeaders = map[string]bool{
+	AccessControlAllowOrigin:   true,
+	AccessControlExposeHeaders: true,
+	ContentSecurityPolicy:      true,
+	CrossOriginResourcePolicy:  true,
+	FrameOptions:               true,
+	ReferrerPolicy:             true,
+	RobotsTag:                  true,
+	TimingAllowOrigin:          true,
+}
+
+// ShareHeader returns the canonical name of a response header that may be configured per share link,
+// and false if it is not allowed or the value is invalid.
+func ShareHeader(name, value string) (string, bool) {
+	name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+
+	if !ShareHeaders[name] {
+		return name, false
+	}
+
+	// Control characters could be used to inject other headers.
+	for _, r := range value {
+		if r < 0x20 && r != '\t' || r == 0x7f {
+			return name, false
+		}
+	}
+
+	return name, true
+}
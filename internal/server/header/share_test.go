@@ -0,0 +1,24 @@
+package header
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareHeader(t *testing.T) {
+	t.Run("Allowed", func(t *testing.T) {
+		name, ok := ShareHeader(" access-control-allow-origin", "https://example.com")
+		assert.True(t, ok)
+		assert.Equal(t, AccessControlAllowOrigin, name)
+	})
+	t.Run("NotAllowed", func(t *testing.T) {
+		name, ok := ShareHeader("set-cookie", "foo=bar")
+		assert.False(t, ok)
+		assert.Equal(t, "Set-Cookie", name)
+	})
+	t.Run("InvalidValue", func(t *testing.T) {
+		_, ok := ShareHeader("Referrer-Policy", "no-referrer\r\nSet-Cookie: foo=bar")
+		assert.False(t, ok)
+	})
+}
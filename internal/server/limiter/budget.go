@@ -0,0 +1,173 @@
+package limiter
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// DefaultBudgetSaveInterval is the minimum time between saving the budget usage to a file.
+const DefaultBudgetSaveInterval = 5 * time.Minute
+
+// Thumbs limits the number of thumbnail bytes served per preview token and day.
+var Thumbs = NewBudget(0)
+
+// BudgetUsage represents the approximate number of bytes served per key on a day.
+type BudgetUsage struct {
+	Day   string           `json:"day" yaml:"Day"`
+	Limit int64            `json:"limit" yaml:"-"`
+	Bytes map[string]int64 `json:"bytes" yaml:"Bytes"`
+}
+
+// Budget represents a daily limit of bytes served per key, e.g. per preview token.
+// The usage is kept in memory and periodically saved to a file if one was set.
+type Budget struct {
+	mu       sync.Mutex
+	limit    int64
+	day      string
+	bytes    map[string]int64
+	fileName string
+	saved    time.Time
+}
+
+// NewBudget returns a new Budget with the specified daily limit in bytes, or no limit if it is 0.
+func NewBudget(limit int64) *Budget {
+	return &Budget{
+		limit: limit,
+		day:   budgetDay(),
+		bytes: make(map[string]int64),
+	}
+}
+
+// budgetDay returns the current day, after which the usage is reset.
+func budgetDay() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
+
+// SetLimit changes the daily limit in bytes, or disables it if it is 0.
+func (b *Budget) SetLimit(limit int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.limit = limit
+}
+
+// Enabled checks if a daily limit is set.
+func (b *Budget) Enabled() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.limit > 0
+}
+
+// Exceeded checks if the daily limit has been exceeded for the key.
+func (b *Budget) Exceeded(key string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reset()
+
+	return b.limit > 0 && b.bytes[key] >= b.limit
+}
+
+// Add adds the number of bytes served to the usage of the key.
+func (b *Budget) Add(key string, n int64) {
+	if n <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reset()
+	b.bytes[key] += n
+
+	// Save usage in the background if the last time was long enough ago.
+	if b.fileName != "" && time.Since(b.saved) > DefaultBudgetSaveInterval {
+		b.saved = time.Now()
+		go func() { _ = b.Save() }()
+	}
+}
+
+// Usage returns a copy of the current usage.
+func (b *Budget) Usage() BudgetUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reset()
+
+	return b.usage()
+}
+
+// Load restores the usage from a YAML file, which is then used to periodically save it.
+func (b *Budget) Load(fileName string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.fileName = fileName
+	b.saved = time.Now()
+
+	data, err := os.ReadFile(fileName)
+
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var u BudgetUsage
+
+	if err = yaml.Unmarshal(data, &u); err != nil {
+		return err
+	}
+
+	if u.Day == budgetDay() && u.Bytes != nil {
+		b.day = u.Day
+		b.bytes = u.Bytes
+	}
+
+	return nil
+}
+
+// Save writes the current usage to the YAML file, if any.
+func (b *Budget) Save() error {
+	b.mu.Lock()
+	fileName := b.fileName
+	u := b.usage()
+	b.mu.Unlock()
+
+	if fileName == "" {
+		return nil
+	}
+
+	data, err := yaml.Marshal(u)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(fileName, data, fs.ModeFile)
+}
+
+// reset clears the usage if the day has changed. The caller must hold the lock.
+func (b *Budget) reset() {
+	if day := budgetDay(); day != b.day {
+		b.day = day
+		b.bytes = make(map[string]int64)
+	}
+}
+
+// usage returns a copy of the current usage. The caller must hold the lock.
+func (b *Budget) usage() BudgetUsage {
+	u := BudgetUsage{Day: b.day, Limit: b.limit, Bytes: make(map[string]int64, len(b.bytes))}
+
+	for key, n := range b.bytes {
+		u.Bytes[key] = n
+	}
+
+	return u
+}
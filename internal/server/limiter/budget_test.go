@@ -0,0 +1,63 @@
+package limiter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	t.Run("Unlimited", func(t *testing.T) {
+		b := NewBudget(0)
+		b.Add("token", 1000)
+		assert.False(t, b.Enabled())
+		assert.False(t, b.Exceeded("token"))
+	})
+	t.Run("Exceeded", func(t *testing.T) {
+		b := NewBudget(100)
+		assert.True(t, b.Enabled())
+		b.Add("token", 60)
+		assert.False(t, b.Exceeded("token"))
+		b.Add("token", 40)
+		assert.True(t, b.Exceeded("token"))
+		assert.False(t, b.Exceeded("other"))
+	})
+	t.Run("NewDay", func(t *testing.T) {
+		b := NewBudget(100)
+		b.Add("token", 200)
+		b.day = "2000-01-01"
+		assert.False(t, b.Exceeded("token"))
+		assert.Equal(t, budgetDay(), b.Usage().Day)
+	})
+	t.Run("Usage", func(t *testing.T) {
+		b := NewBudget(100)
+		b.Add("token", 42)
+		b.Add("token", -1)
+		u := b.Usage()
+		assert.Equal(t, int64(100), u.Limit)
+		assert.Equal(t, map[string]int64{"token": 42}, u.Bytes)
+	})
+	t.Run("SaveAndLoad", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "budget.yml")
+		b := NewBudget(100)
+
+		if err := b.Load(fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		b.Add("token", 80)
+
+		if err := b.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		restored := NewBudget(100)
+
+		if err := restored.Load(fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, int64(80), restored.Usage().Bytes["token"])
+	})
+}
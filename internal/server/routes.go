@@ -61,6 +61,23 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 
 	// Thumbnail Images.
 	api.GetThumb(APIv1)
+	api.GetThumbVariants(APIv1)
+	api.GetThumbVariant(APIv1)
+	api.GetCropBudget(APIv1)
+	api.GetFaceThumb(APIv1)
+	api.GetAspectThumb(APIv1)
+	api.HeadThumb(APIv1)
+	api.GetThumbStats(APIv1)
+	api.GetThumbSelfTest(APIv1)
+	api.GetThumbStripSelfTest(APIv1)
+	api.CompareThumb(APIv1)
+	api.RegenerateThumb(APIv1)
+	api.InvalidateThumbs(APIv1)
+	api.GetThumbBudget(APIv1)
+	api.GetThumbsMissing(APIv1)
+	api.GetThumbPins(APIv1)
+	api.PinThumb(APIv1)
+	api.UnpinThumb(APIv1)
 
 	// Video Streaming.
 	api.GetVideo(APIv1)
@@ -95,13 +112,19 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 	api.UpdatePhotoLabel(APIv1)
 	api.GetMomentsTime(APIv1)
 	api.GetFile(APIv1)
+	api.GetFileFaces(APIv1)
+	api.GetFileAHash(APIv1)
 	api.DeleteFile(APIv1)
 	api.ChangeFileOrientation(APIv1)
+	api.ChangeFileAngle(APIv1)
+	api.ChangeFileFocus(APIv1)
+	api.ClearFileFocus(APIv1)
 	api.CreateMarker(APIv1)
 	api.UpdateMarker(APIv1)
 	api.ClearMarkerSubject(APIv1)
 	api.PhotoPrimary(APIv1)
 	api.PhotoUnstack(APIv1)
+	api.RegeneratePhotoCrops(APIv1)
 
 	// Photo Albums.
 	api.SearchAlbums(APIv1)
@@ -120,6 +143,7 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 	api.CloneAlbums(APIv1)
 	api.AddPhotosToAlbum(APIv1)
 	api.RemovePhotosFromAlbum(APIv1)
+	api.RegenerateAlbumCrops(APIv1)
 
 	// Photo Labels.
 	api.SearchLabels(APIv1)
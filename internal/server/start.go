@@ -13,6 +13,7 @@ import (
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
 
+	"github.com/photoprism/photoprism/internal/api"
 	"github.com/photoprism/photoprism/internal/config"
 )
 
@@ -72,6 +73,15 @@ func Start(ctx context.Context, conf *config.Config) {
 	// Register HTTP route handlers.
 	registerRoutes(router, conf)
 
+	// Preload thumbnail file names so that the first requests after a restart hit the memory cache.
+	if limit := conf.ThumbPreload(); limit > 0 {
+		go func() {
+			if _, err := api.PreloadThumbCache(conf.ThumbCachePath(), conf.OriginalsPath(), limit, conf.ThumbPreloadAge()); err != nil {
+				log.Warnf("thumbs: %s while preloading cache", err)
+			}
+		}()
+	}
+
 	var tlsErr error
 	var tlsManager *autocert.Manager
 	var server *http.Server
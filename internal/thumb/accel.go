@@ -0,0 +1,187 @@
+package thumb
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Hardware acceleration backends for resizing and encoding thumbnails with FFmpeg.
+const (
+	AccelNone  = "none"
+	AccelAuto  = "auto"
+	AccelCuda  = "cuda"
+	AccelVaapi = "vaapi"
+)
+
+var (
+	Accel       = AccelNone
+	AccelBin    = ""
+	AccelDevice = "/dev/dri/renderD128"
+)
+
+var (
+	accelDetected = make(map[string]string)
+	accelMutex    = sync.Mutex{}
+)
+
+// ParseAccel returns the hardware acceleration backend matching the config value, or AccelNone if it is unknown.
+func ParseAccel(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case AccelAuto:
+		return AccelAuto
+	case AccelCuda, "nvidia", "nvenc":
+		return AccelCuda
+	case AccelVaapi, "intel", "amd":
+		return AccelVaapi
+	default:
+		return AccelNone
+	}
+}
+
+// DetectAccel returns the first hardware acceleration backend supported by the FFmpeg binary, or AccelNone.
+// The result is cached, as it does not change while the app is running.
+func DetectAccel(bin string) string {
+	if bin == "" {
+		return AccelNone
+	}
+
+	accelMutex.Lock()
+	defer accelMutex.Unlock()
+
+	if result, ok := accelDetected[bin]; ok {
+		return result
+	}
+
+	result := AccelNone
+
+	if out, err := exec.Command(bin, "-hide_banner", "-hwaccels").Output(); err == nil {
+		for _, backend := range []string{AccelCuda, AccelVaapi} {
+			if accelListed(string(out), backend) {
+				result = backend
+				break
+			}
+		}
+	}
+
+	accelDetected[bin] = result
+
+	return result
+}
+
+// accelListed checks if the backend is listed in the output of "ffmpeg -hwaccels".
+func accelListed(out, backend string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == backend {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ActiveAccel returns the hardware acceleration backend that is currently used to create thumbnails.
+func ActiveAccel() string {
+	if AccelBin == "" || Accel == AccelAuto {
+		return AccelNone
+	}
+
+	return Accel
+}
+
+// AccelSupported checks if a thumbnail with the specified options can be created from the file with hardware
+// acceleration. This is the case for fit sizes of local JPEG files that do not need to be rotated or adjusted,
+// so that the result is the same as with the CPU, apart from resampling differences.
+func AccelSupported(imageFilename string, width, height, orientation int, opts ...ResampleOption) bool {
+	if ActiveAccel() == AccelNone || orientation > OrientationNormal || IsRemote(imageFilename) {
+		return false
+	} else if fs.FileType(imageFilename) != fs.ImageJPEG {
+		return false
+	} else if method, _, format := ResampleOptions(opts...); method != ResampleFit || format != fs.ImageJPEG {
+		return false
+	} else if IsGray(opts...) || UseLevels(width, height, opts...) || IsCMYK(imageFilename) {
+		return false
+	}
+
+	// The largest image is used if the file contains multiple images, see OpenLargest.
+	if UseLargest {
+		if images, err := ContainerImages(imageFilename); err != nil || len(images) > 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FromAccel creates a thumbnail with hardware acceleration if it is supported, see AccelSupported, and returns
+// false if the thumbnail must be created with the CPU instead, e.g. because the device is not available.
+func FromAccel(imageFilename, fileName string, width, height, orientation int, opts ...ResampleOption) bool {
+	if !AccelSupported(imageFilename, width, height, orientation, opts...) {
+		return false
+	} else if err := CheckPixels(imageFilename); err != nil {
+		return false
+	}
+
+	if err := resizeAccel(imageFilename, fileName, width, height, SizeQuality(width, height)); err != nil {
+		log.Debugf("thumb: %s while resizing %s with %s, using cpu", err, clean.Log(filepath.Base(imageFilename)), ActiveAccel())
+		return false
+	}
+
+	return true
+}
+
+// AccelArgs returns the FFmpeg arguments to resize the image to fit the specified size without enlarging it,
+// and save it as JPEG with the specified quality.
+func AccelArgs(backend, srcName, destName string, width, height int, quality Quality) []string {
+	scale := fmt.Sprintf("w='min(iw,%d)':h='min(ih,%d)':force_original_aspect_ratio=decrease", width, height)
+
+	switch backend {
+	case AccelCuda:
+		// Scale with CUDA, and encode on the CPU, as FFmpeg has no NVJPEG encoder.
+		qscale := 2 + (100-int(quality))*29/100
+
+		return []string{"-hide_banner", "-loglevel", "error", "-init_hw_device", "cuda=gpu", "-filter_hw_device", "gpu",
+			"-i", srcName, "-vf", fmt.Sprintf("format=nv12,hwupload,scale_cuda=%s,hwdownload,format=nv12", scale),
+			"-frames:v", "1", "-q:v", fmt.Sprintf("%d", qscale), "-y", destName}
+	case AccelVaapi:
+		// Scale and encode with VA-API.
+		return []string{"-hide_banner", "-loglevel", "error", "-vaapi_device", AccelDevice,
+			"-i", srcName, "-vf", fmt.Sprintf("format=nv12,hwupload,scale_vaapi=%s", scale),
+			"-c:v", "mjpeg_vaapi", "-global_quality", quality.String(), "-frames:v", "1", "-y", destName}
+	default:
+		return nil
+	}
+}
+
+// resizeAccel resizes and encodes the image with the active hardware acceleration backend.
+func resizeAccel(srcName, destName string, width, height int, quality Quality) error {
+	args := AccelArgs(ActiveAccel(), srcName, destName, width, height, quality)
+
+	if len(args) == 0 {
+		return fmt.Errorf("unsupported backend %s", clean.Log(ActiveAccel()))
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(AccelBin, args...)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		_ = os.Remove(destName)
+
+		if s := strings.TrimSpace(stderr.String()); s != "" {
+			return fmt.Errorf("%s (%s)", s, filepath.Base(AccelBin))
+		}
+
+		return fmt.Errorf("%s (%s)", err, filepath.Base(AccelBin))
+	}
+
+	return nil
+}
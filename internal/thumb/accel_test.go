@@ -0,0 +1,70 @@
+package thumb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestParseAccel(t *testing.T) {
+	assert.Equal(t, AccelNone, ParseAccel(""))
+	assert.Equal(t, AccelNone, ParseAccel("invalid"))
+	assert.Equal(t, AccelAuto, ParseAccel(" Auto"))
+	assert.Equal(t, AccelCuda, ParseAccel("CUDA"))
+	assert.Equal(t, AccelCuda, ParseAccel("nvidia"))
+	assert.Equal(t, AccelVaapi, ParseAccel("vaapi"))
+}
+
+func TestActiveAccel(t *testing.T) {
+	defer func() { Accel, AccelBin = AccelNone, "" }()
+
+	assert.Equal(t, AccelNone, ActiveAccel())
+	Accel = AccelCuda
+	assert.Equal(t, AccelNone, ActiveAccel())
+	AccelBin = "/usr/bin/ffmpeg"
+	assert.Equal(t, AccelCuda, ActiveAccel())
+	Accel = AccelAuto
+	assert.Equal(t, AccelNone, ActiveAccel())
+}
+
+func TestDetectAccel(t *testing.T) {
+	assert.Equal(t, AccelNone, DetectAccel(""))
+	assert.Equal(t, AccelNone, DetectAccel("/bin/false"))
+	assert.True(t, accelListed("Hardware acceleration methods:\nvdpau\ncuda\n", AccelCuda))
+	assert.False(t, accelListed("Hardware acceleration methods:\nvdpau\n", AccelCuda))
+}
+
+func TestAccelSupported(t *testing.T) {
+	defer func() { Accel, AccelBin = AccelNone, "" }()
+
+	assert.False(t, AccelSupported("testdata/example.jpg", 720, 720, 1, ResampleFit))
+
+	Accel, AccelBin = AccelVaapi, "/usr/bin/ffmpeg"
+
+	assert.True(t, AccelSupported("testdata/example.jpg", 720, 720, 1, ResampleFit))
+	assert.False(t, AccelSupported("testdata/example.jpg", 720, 720, 6, ResampleFit))
+	assert.False(t, AccelSupported("testdata/example.jpg", 224, 224, 1, ResampleFillCenter))
+	assert.False(t, AccelSupported("testdata/example.jpg", 720, 720, 1, ResampleFit, ResamplePng))
+	assert.False(t, AccelSupported("testdata/example.png", 720, 720, 1, ResampleFit))
+}
+
+func TestAccelArgs(t *testing.T) {
+	assert.Nil(t, AccelArgs(AccelNone, "in.jpg", "out.jpg", 720, 720, QualityDefault))
+	assert.Contains(t, AccelArgs(AccelCuda, "in.jpg", "out.jpg", 720, 720, QualityDefault), "out.jpg")
+	assert.Contains(t, AccelArgs(AccelVaapi, "in.jpg", "out.jpg", 720, 720, QualityDefault), "mjpeg_vaapi")
+}
+
+func TestFromAccel(t *testing.T) {
+	defer func() { Accel, AccelBin = AccelNone, "" }()
+
+	// Falls back to the CPU if FFmpeg fails, e.g. because the device is not available.
+	Accel, AccelBin = AccelCuda, "/bin/false"
+
+	cachePath := t.TempDir()
+	fileName, err := FromFile("testdata/example.jpg", "ac5c8e4d0bd0d8f5bda1d6a1e93f5d9e4f3c1a2b", cachePath, 720, 720, 1, ResampleFit)
+
+	assert.NoError(t, err)
+	assert.True(t, fs.FileExists(fileName))
+}
@@ -0,0 +1,78 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// AHash represents a 64-bit average image hash, which sets the bits of all pixels in an 8x8 grayscale
+// version of the image that are brighter than the mean. It is much cheaper to compute and compare than
+// PHash, so it is suited for quick grouping of near-duplicates, but it also changes more with brightness,
+// contrast, and color adjustments, so matches should be verified with PHash if accuracy matters.
+type AHash uint64
+
+// AHashDist is the default maximum number of different bits for images to be considered near-duplicates.
+const AHashDist = 5
+
+// ahashSize is the width and height of the grayscale image the hash is computed from.
+const ahashSize = 8
+
+// String returns the hash as hex string.
+func (h AHash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// Distance returns the number of different bits, where 0 means the images are likely identical.
+func (h AHash) Distance(other AHash) int {
+	return bits.OnesCount64(uint64(h ^ other))
+}
+
+// ParseAHash returns the average hash encoded as hex string.
+func ParseAHash(s string) (AHash, error) {
+	s = strings.TrimSpace(s)
+
+	if s == "" || len(s) > 16 {
+		return 0, fmt.Errorf("invalid average hash %s", s)
+	}
+
+	h, err := strconv.ParseUint(s, 16, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid average hash %s", s)
+	}
+
+	return AHash(h), nil
+}
+
+// AverageHash returns the average hash of the image, which should already be small, e.g. a thumbnail,
+// as it is reduced to 8x8 pixels regardless of its aspect ratio.
+func AverageHash(img image.Image) AHash {
+	gray := imaging.Grayscale(imaging.Resize(img, ahashSize, ahashSize, imaging.Box))
+
+	values := make([]int, 0, ahashSize*ahashSize)
+	sum := 0
+
+	for y := 0; y < ahashSize; y++ {
+		for x := 0; x < ahashSize; x++ {
+			v := int(gray.Pix[y*gray.Stride+x*4])
+			values = append(values, v)
+			sum += v
+		}
+	}
+
+	var h AHash
+
+	// Compare the scaled values so that the mean does not need to be rounded.
+	for i, v := range values {
+		if v*len(values) > sum {
+			h |= 1 << uint(i)
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,63 @@
+package thumb
+
+import (
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAverageHash(t *testing.T) {
+	img, err := imaging.Open("testdata/example.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := AverageHash(img)
+
+	assert.Len(t, h.String(), 16)
+	assert.NotEqual(t, AHash(0), h)
+
+	t.Run("Resized", func(t *testing.T) {
+		resized := AverageHash(imaging.Resize(img, 300, 0, imaging.Lanczos))
+		assert.LessOrEqual(t, h.Distance(resized), AHashDist)
+	})
+	t.Run("Different", func(t *testing.T) {
+		other, err := imaging.Open("selftest.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Greater(t, h.Distance(AverageHash(other)), AHashDist)
+	})
+}
+
+func TestParseAHash(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		h, err := ParseAHash("00ff00ff00ff00ff")
+		assert.NoError(t, err)
+		assert.Equal(t, AHash(0x00ff00ff00ff00ff), h)
+		assert.Equal(t, "00ff00ff00ff00ff", h.String())
+	})
+	t.Run("Short", func(t *testing.T) {
+		h, err := ParseAHash("ff")
+		assert.NoError(t, err)
+		assert.Equal(t, "00000000000000ff", h.String())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ParseAHash("xyz")
+		assert.Error(t, err)
+		_, err = ParseAHash("")
+		assert.Error(t, err)
+		_, err = ParseAHash("00ff00ff00ff00ff00")
+		assert.Error(t, err)
+	})
+}
+
+func TestAHash_Distance(t *testing.T) {
+	assert.Equal(t, 0, AHash(0xff).Distance(0xff))
+	assert.Equal(t, 8, AHash(0xff).Distance(0))
+	assert.Equal(t, 64, AHash(0).Distance(^AHash(0)))
+}
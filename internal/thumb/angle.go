@@ -0,0 +1,62 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// AngleBackground is the color used to fill the corners of straightened images.
+var AngleBackground color.Color = color.Black
+
+// NormalizeAngle returns the rotation angle in degrees within the range (-180, 180], rounded to 1/100 degree.
+func NormalizeAngle(angle float64) float64 {
+	if math.IsNaN(angle) || math.IsInf(angle, 0) {
+		return 0
+	}
+
+	angle = math.Mod(angle, 360)
+
+	if angle > 180 {
+		angle -= 360
+	} else if angle <= -180 {
+		angle += 360
+	}
+
+	return math.Round(angle*100) / 100
+}
+
+// ParseAngle parses a rotation angle in degrees and returns false if the string is empty or invalid.
+func ParseAngle(s string) (angle float64, ok bool) {
+	if s = strings.TrimSpace(s); s == "" {
+		return 0, false
+	} else if f, err := strconv.ParseFloat(s, 64); err != nil {
+		return 0, false
+	} else {
+		return NormalizeAngle(f), true
+	}
+}
+
+// AngleHash returns the hash prefix of thumbnails rotated by an arbitrary angle.
+func AngleHash(hash string, angle float64) string {
+	if angle = NormalizeAngle(angle); angle == 0 {
+		return hash
+	}
+
+	return fmt.Sprintf("%s_a%s", hash, strconv.FormatFloat(angle, 'f', -1, 64))
+}
+
+// Straighten rotates an image counter-clockwise by an arbitrary angle in degrees,
+// expanding the image bounds to fit and filling the uncovered corners with AngleBackground.
+func Straighten(img image.Image, angle float64) image.Image {
+	if angle = NormalizeAngle(angle); angle == 0 || img == nil {
+		return img
+	}
+
+	return imaging.Rotate(img, angle, AngleBackground)
+}
@@ -0,0 +1,59 @@
+package thumb
+
+import (
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAngle(t *testing.T) {
+	assert.Equal(t, 0.0, NormalizeAngle(0))
+	assert.Equal(t, 2.5, NormalizeAngle(2.5))
+	assert.Equal(t, -2.5, NormalizeAngle(357.5))
+	assert.Equal(t, 180.0, NormalizeAngle(-180))
+	assert.Equal(t, 1.23, NormalizeAngle(1.2345))
+	assert.Equal(t, 0.0, NormalizeAngle(720))
+}
+
+func TestParseAngle(t *testing.T) {
+	angle, ok := ParseAngle("-1.5")
+	assert.True(t, ok)
+	assert.Equal(t, -1.5, angle)
+
+	angle, ok = ParseAngle("")
+	assert.False(t, ok)
+	assert.Equal(t, 0.0, angle)
+
+	_, ok = ParseAngle("foo")
+	assert.False(t, ok)
+}
+
+func TestAngleHash(t *testing.T) {
+	hash := "ca1ee11578d4e4cfed3338fe3a9e7078a491bb54"
+
+	assert.Equal(t, hash, AngleHash(hash, 0))
+	assert.Equal(t, hash+"_a2.5", AngleHash(hash, 2.5))
+	assert.Equal(t, hash+"_a-10", AngleHash(hash, -10))
+}
+
+func TestStraighten(t *testing.T) {
+	img, err := imaging.Open("testdata/example.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Zero", func(t *testing.T) {
+		assert.Equal(t, img, Straighten(img, 0))
+	})
+	t.Run("Bounds", func(t *testing.T) {
+		result := Straighten(img, 90)
+		assert.Equal(t, img.Bounds().Dx(), result.Bounds().Dy())
+		assert.Equal(t, img.Bounds().Dy(), result.Bounds().Dx())
+
+		result = Straighten(img, 5)
+		assert.Greater(t, result.Bounds().Dx(), img.Bounds().Dx())
+		assert.Greater(t, result.Bounds().Dy(), img.Bounds().Dy())
+	})
+}
@@ -0,0 +1,134 @@
+package thumb
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ArchiveEntryLimit is the maximum uncompressed size of archive entries in bytes that are decoded,
+// so that decompression bombs cannot exhaust memory.
+var ArchiveEntryLimit int64 = 50 * 1024 * 1024
+
+// ArchiveExtensions contains the file extensions of archives that may contain a cover image, e.g. comic books.
+var ArchiveExtensions = map[string]bool{
+	".zip": true,
+	".cbz": true,
+}
+
+// IsArchive checks if the file is a ZIP archive based on its extension.
+func IsArchive(fileName string) bool {
+	return ArchiveExtensions[fs.LowerExt(fileName)]
+}
+
+// ArchiveCover returns the name and content of the cover image in a ZIP archive, which is the first image
+// with a base name starting with "cover", or else the first image in alphabetical order, like the pages of
+// a comic book. It returns ErrNoPreview if the archive contains no images within the size limit.
+func ArchiveCover(fileName string) (name string, data []byte, err error) {
+	r, err := zip.OpenReader(fileName)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	defer r.Close()
+
+	var images []*zip.File
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !archiveImage(f.Name) {
+			continue
+		} else if f.UncompressedSize64 > uint64(ArchiveEntryLimit) {
+			log.Debugf("thumb: skipping %s in archive, size exceeds limit", f.Name)
+			continue
+		}
+
+		images = append(images, f)
+	}
+
+	if len(images) == 0 {
+		return "", nil, ErrNoPreview
+	}
+
+	sort.SliceStable(images, func(i, j int) bool {
+		ci, cj := archiveCover(images[i].Name), archiveCover(images[j].Name)
+
+		if ci != cj {
+			return ci
+		}
+
+		return images[i].Name < images[j].Name
+	})
+
+	entry := images[0]
+	rc, err := entry.Open()
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	defer rc.Close()
+
+	// Never trust the size in the header, it may have been manipulated.
+	if data, err = io.ReadAll(io.LimitReader(rc, ArchiveEntryLimit+1)); err != nil {
+		return "", nil, err
+	} else if int64(len(data)) > ArchiveEntryLimit {
+		return "", nil, fmt.Errorf("thumb: %s in archive exceeds size limit", entry.Name)
+	}
+
+	return entry.Name, data, nil
+}
+
+// OpenArchive decodes the cover image of a ZIP archive and rotates it if necessary.
+func OpenArchive(fileName string, orientation int) (image.Image, error) {
+	name, data, err := ArchiveCover(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = CheckPixelsBytes(data); err != nil {
+		return nil, fmt.Errorf("thumb: %w in archive", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, fmt.Errorf("thumb: %s while decoding %s in archive", err, name)
+	}
+
+	if orientation > 1 {
+		img = Rotate(img, orientation)
+	}
+
+	return img, nil
+}
+
+// archiveImage checks if the archive entry is an image that can be decoded natively.
+func archiveImage(name string) bool {
+	base := path.Base(name)
+
+	// Skip hidden files and macOS resource forks.
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(name, "__MACOSX/") {
+		return false
+	}
+
+	switch fs.FileType(name) {
+	case fs.ImageJPEG, fs.ImagePNG, fs.ImageGIF, fs.ImageBMP, fs.ImageWebP, fs.ImageTIFF:
+		return true
+	default:
+		return false
+	}
+}
+
+// archiveCover checks if the base name of the archive entry indicates a cover image.
+func archiveCover(name string) bool {
+	return strings.HasPrefix(strings.ToLower(path.Base(name)), "cover")
+}
@@ -0,0 +1,117 @@
+package thumb
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testArchive creates a ZIP archive with the specified entries, whose content is read from the files in testdata.
+func testArchive(t *testing.T, name string, entries map[string]string) string {
+	fileName := filepath.Join(t.TempDir(), name)
+
+	f, err := os.Create(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := zip.NewWriter(f)
+
+	for entry, src := range entries {
+		data, err := os.ReadFile(src)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ew, err := w.Create(entry); err != nil {
+			t.Fatal(err)
+		} else if _, err = ew.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+func TestIsArchive(t *testing.T) {
+	assert.True(t, IsArchive("comic.cbz"))
+	assert.True(t, IsArchive("/photos/ARCHIVE.ZIP"))
+	assert.False(t, IsArchive("example.jpg"))
+}
+
+func TestArchiveCover(t *testing.T) {
+	t.Run("Cover", func(t *testing.T) {
+		fileName := testArchive(t, "comic.cbz", map[string]string{
+			"pages/001.jpg":   "testdata/example.jpg",
+			"pages/Cover.png": "testdata/example.png",
+			"readme.txt":      "testdata/example.bmp",
+		})
+
+		name, data, err := ArchiveCover(fileName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "pages/Cover.png", name)
+		assert.NotEmpty(t, data)
+	})
+	t.Run("FirstPage", func(t *testing.T) {
+		fileName := testArchive(t, "comic.cbz", map[string]string{
+			"pages/002.jpg":          "testdata/example.jpg",
+			"pages/001.png":          "testdata/example.png",
+			"__MACOSX/pages/000.jpg": "testdata/example.jpg",
+		})
+
+		name, _, err := ArchiveCover(fileName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "pages/001.png", name)
+	})
+	t.Run("NoImages", func(t *testing.T) {
+		fileName := testArchive(t, "docs.zip", map[string]string{
+			"readme.txt": "testdata/example.bmp",
+		})
+
+		_, _, err := ArchiveCover(fileName)
+
+		assert.ErrorIs(t, err, ErrNoPreview)
+	})
+	t.Run("SizeLimit", func(t *testing.T) {
+		limit := ArchiveEntryLimit
+		ArchiveEntryLimit = 100
+		defer func() { ArchiveEntryLimit = limit }()
+
+		fileName := testArchive(t, "comic.cbz", map[string]string{
+			"001.jpg": "testdata/example.jpg",
+		})
+
+		_, _, err := ArchiveCover(fileName)
+
+		assert.ErrorIs(t, err, ErrNoPreview)
+	})
+}
+
+func TestFromFile_Archive(t *testing.T) {
+	fileName := testArchive(t, "comic.cbz", map[string]string{
+		"001.jpg": "testdata/example.jpg",
+	})
+
+	result, err := FromFile(fileName, "3ab2c4c6e10b3ae1e6e6b7d9f1ed5e8a7b1c4d2f", t.TempDir(), 100, 100, 1, ResampleFit)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.FileExists(t, result)
+}
@@ -0,0 +1,68 @@
+package thumb
+
+import (
+	"sync"
+	"time"
+)
+
+// AsyncRetry is the number of seconds after which clients should check again if a thumbnail is still pending.
+var AsyncRetry = 2
+
+// asyncJob represents a thumbnail that is being created in the background.
+type asyncJob struct {
+	done     chan struct{}
+	fileName string
+	err      error
+}
+
+var (
+	asyncJobs  = make(map[string]*asyncJob)
+	asyncMutex = sync.Mutex{}
+)
+
+// Async calls the create function in the background and waits up to the given duration for it to finish.
+// If a thumbnail with the same key is already being created, it waits for the running job instead of
+// starting another one. ErrPending is returned if it is not done by then, so that clients can check again
+// later instead of blocking, see AsyncRetry.
+func Async(key string, wait time.Duration, create func() (string, error)) (fileName string, err error) {
+	asyncMutex.Lock()
+
+	job, running := asyncJobs[key]
+
+	if !running {
+		job = &asyncJob{done: make(chan struct{})}
+		asyncJobs[key] = job
+
+		go func() {
+			job.fileName, job.err = create()
+
+			asyncMutex.Lock()
+			delete(asyncJobs, key)
+			asyncMutex.Unlock()
+
+			close(job.done)
+		}()
+	}
+
+	asyncMutex.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-job.done:
+		return job.fileName, job.err
+	case <-timer.C:
+		return "", ErrPending
+	}
+}
+
+// Pending checks if a thumbnail with the specified key is being created in the background, see Async.
+func Pending(key string) bool {
+	asyncMutex.Lock()
+	defer asyncMutex.Unlock()
+
+	_, running := asyncJobs[key]
+
+	return running
+}
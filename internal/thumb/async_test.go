@@ -0,0 +1,69 @@
+package thumb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsync(t *testing.T) {
+	t.Run("InTime", func(t *testing.T) {
+		fileName, err := Async("async-in-time", time.Second, func() (string, error) {
+			return "foo.jpg", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "foo.jpg", fileName)
+		assert.False(t, Pending("async-in-time"))
+	})
+	t.Run("Error", func(t *testing.T) {
+		fileName, err := Async("async-error", time.Second, func() (string, error) {
+			return "", errors.New("failed")
+		})
+
+		assert.EqualError(t, err, "failed")
+		assert.Equal(t, "", fileName)
+	})
+	t.Run("Pending", func(t *testing.T) {
+		release := make(chan struct{})
+		done := make(chan struct{})
+
+		fileName, err := Async("async-pending", 10*time.Millisecond, func() (string, error) {
+			defer close(done)
+			<-release
+			return "bar.jpg", nil
+		})
+
+		assert.ErrorIs(t, err, ErrPending)
+		assert.Equal(t, "", fileName)
+		assert.True(t, Pending("async-pending"))
+
+		// Requests for the same thumbnail must not start another generation.
+		_, err = Async("async-pending", 10*time.Millisecond, func() (string, error) {
+			t.Error("create must not be called while pending")
+			return "", nil
+		})
+
+		assert.ErrorIs(t, err, ErrPending)
+
+		// Long polling requests wait for the running generation to finish.
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+		}()
+
+		fileName, err = Async("async-pending", time.Second, func() (string, error) {
+			t.Error("create must not be called while pending")
+			return "", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "bar.jpg", fileName)
+
+		<-done
+
+		assert.Eventually(t, func() bool { return !Pending("async-pending") }, time.Second, 5*time.Millisecond)
+	})
+}
@@ -0,0 +1,121 @@
+package thumb
+
+import (
+	"fmt"
+	"hash/crc32"
+	"image"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// BlurPadding is the share by which face regions are enlarged before blurring, so that hair and ears are covered.
+var BlurPadding = 0.2
+
+// BlurName returns the file name of a thumbnail with the regions blurred. The name depends on the
+// regions, so that a new file is created when faces are added or moved.
+func BlurName(thumbName string, regions []Region) string {
+	ext := filepath.Ext(thumbName)
+	keys := make([]string, len(regions))
+
+	for i, r := range regions {
+		keys[i] = r.String()
+	}
+
+	return fmt.Sprintf("%s_blur%08x%s", strings.TrimSuffix(thumbName, ext), crc32.ChecksumIEEE([]byte(strings.Join(keys, ";"))), ext)
+}
+
+// FromBlur returns the file name of the thumbnail with the regions blurred, and creates it if needed.
+// Blurred thumbnails are stored separately, so that the original thumbnail remains unchanged.
+func FromBlur(thumbName string, regions []Region) (fileName string, err error) {
+	if len(regions) == 0 {
+		return thumbName, nil
+	}
+
+	fileName = BlurName(thumbName, regions)
+
+	if fs.FileExists(fileName) {
+		return fileName, nil
+	}
+
+	img, err := imaging.Open(thumbName)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err = SaveJpeg(BlurRegions(img, regions), fileName, JpegQuality); err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
+
+// BlurRegions blurs the regions of the image, e.g. to hide faces. The blur strength scales with the region size.
+func BlurRegions(img image.Image, regions []Region) *image.NRGBA {
+	dst := imaging.Clone(img)
+	bounds := dst.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+
+	for _, r := range regions {
+		if r.Empty() {
+			continue
+		}
+
+		padX, padY := r.W*BlurPadding, r.H*BlurPadding
+
+		area := image.Rect(
+			int(math.Floor((r.X-padX)*w)),
+			int(math.Floor((r.Y-padY)*h)),
+			int(math.Ceil((r.X+r.W+padX)*w)),
+			int(math.Ceil((r.Y+r.H+padY)*h)),
+		).Add(bounds.Min).Intersect(bounds)
+
+		if area.Empty() {
+			continue
+		}
+
+		sigma := math.Max(2, math.Max(float64(area.Dx()), float64(area.Dy()))/6)
+		dst = imaging.Paste(dst, imaging.Blur(imaging.Crop(dst, area), sigma), area.Min)
+	}
+
+	return dst
+}
+
+// FillRegion returns the part of an image with the specified source dimensions that remains visible
+// in a thumbnail of the specified size, which is centered and cropped unless the size is a fit size.
+func FillRegion(srcWidth, srcHeight int, size Size) Region {
+	if size.Fit || srcWidth <= 0 || srcHeight <= 0 || size.Width <= 0 || size.Height <= 0 {
+		return Region{W: 1, H: 1}
+	}
+
+	srcRatio := float64(srcWidth) / float64(srcHeight)
+	dstRatio := float64(size.Width) / float64(size.Height)
+
+	if srcRatio > dstRatio {
+		w := dstRatio / srcRatio
+		return Region{X: (1 - w) / 2, W: w, H: 1}
+	}
+
+	h := srcRatio / dstRatio
+
+	return Region{Y: (1 - h) / 2, W: 1, H: h}
+}
+
+// Within returns the region relative to the visible part of the image, see FillRegion.
+func (r Region) Within(visible Region) Region {
+	if visible.Empty() {
+		return r
+	}
+
+	return Region{
+		X: (r.X - visible.X) / visible.W,
+		Y: (r.Y - visible.Y) / visible.H,
+		W: r.W / visible.W,
+		H: r.H / visible.H,
+	}
+}
@@ -0,0 +1,79 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlurName(t *testing.T) {
+	regions := []Region{{X: 0.25, Y: 0.25, W: 0.5, H: 0.5}}
+	moved := []Region{{X: 0.3, Y: 0.25, W: 0.5, H: 0.5}}
+
+	name := BlurName("/cache/a/b/c/abc_224x224_center.jpg", regions)
+
+	assert.Regexp(t, `^/cache/a/b/c/abc_224x224_center_blur[0-9a-f]{8}\.jpg$`, name)
+	assert.Equal(t, name, BlurName("/cache/a/b/c/abc_224x224_center.jpg", regions))
+	assert.NotEqual(t, name, BlurName("/cache/a/b/c/abc_224x224_center.jpg", moved))
+}
+
+func TestBlurRegions(t *testing.T) {
+	// Left half black, right half white.
+	src := imaging.New(100, 100, color.White)
+	src = imaging.Paste(src, imaging.New(50, 100, color.Black), image.Point{})
+
+	img := BlurRegions(src, []Region{{X: 0.4, Y: 0.4, W: 0.2, H: 0.2}})
+
+	assert.Equal(t, src.Bounds(), img.Bounds())
+
+	// The edge within the region is blurred, while pixels outside remain unchanged.
+	assert.NotEqual(t, uint8(0), img.NRGBAAt(49, 50).R)
+	assert.NotEqual(t, uint8(255), img.NRGBAAt(50, 50).R)
+	assert.Equal(t, uint8(0), img.NRGBAAt(49, 5).R)
+	assert.Equal(t, uint8(255), img.NRGBAAt(50, 5).R)
+}
+
+func TestFromBlur(t *testing.T) {
+	thumbName := filepath.Join(t.TempDir(), "abc_224x224_center.jpg")
+
+	if err := imaging.Save(imaging.New(224, 224, color.White), thumbName); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("NoRegions", func(t *testing.T) {
+		fileName, err := FromBlur(thumbName, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, thumbName, fileName)
+	})
+	t.Run("Regions", func(t *testing.T) {
+		fileName, err := FromBlur(thumbName, []Region{{X: 0.1, Y: 0.1, W: 0.2, H: 0.2}})
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, thumbName, fileName)
+		assert.FileExists(t, fileName)
+	})
+}
+
+func TestFillRegion(t *testing.T) {
+	t.Run("Fit", func(t *testing.T) {
+		assert.Equal(t, Region{W: 1, H: 1}, FillRegion(400, 200, Sizes[Fit720]))
+	})
+	t.Run("Landscape", func(t *testing.T) {
+		assert.Equal(t, Region{X: 0.25, W: 0.5, H: 1}, FillRegion(400, 200, Sizes[Tile224]))
+	})
+	t.Run("Portrait", func(t *testing.T) {
+		assert.Equal(t, Region{Y: 0.25, W: 1, H: 0.5}, FillRegion(200, 400, Sizes[Tile224]))
+	})
+}
+
+func TestRegion_Within(t *testing.T) {
+	r := Region{X: 0.5, Y: 0.25, W: 0.25, H: 0.5}
+
+	assert.Equal(t, r, r.Within(Region{W: 1, H: 1}))
+	assert.Equal(t, Region{X: 0.5, Y: 0.25, W: 0.5, H: 0.5}, r.Within(Region{X: 0.25, W: 0.5, H: 1}))
+}
@@ -0,0 +1,40 @@
+package thumb
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+
+	"github.com/photoprism/photoprism/pkg/colors"
+)
+
+// FromCMYK converts the colors of CMYK images, e.g. JPEG files for print, to sRGB, see colors.CMYKToSRGB.
+// Other images are returned as they are.
+func FromCMYK(img image.Image, logName string) image.Image {
+	cmyk, ok := img.(*image.CMYK)
+
+	if !ok {
+		return img
+	}
+
+	log.Tracef("thumb: converting %s from cmyk to srgb", logName)
+
+	return colors.CMYKToSRGB(cmyk)
+}
+
+// IsCMYK checks if the file is a JPEG with CMYK colors.
+func IsCMYK(fileName string) bool {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return false
+	}
+
+	defer f.Close()
+
+	cfg, err := jpeg.DecodeConfig(bufio.NewReader(f))
+
+	return err == nil && cfg.ColorModel == color.CMYKModel
+}
@@ -0,0 +1,91 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestFromCMYK(t *testing.T) {
+	t.Run("CMYK", func(t *testing.T) {
+		img := image.NewCMYK(image.Rect(0, 0, 1, 1))
+		img.SetCMYK(0, 0, color.CMYK{K: 255})
+
+		result := FromCMYK(img, "black.jpg")
+
+		assert.IsType(t, &image.NRGBA{}, result)
+		r, g, b, _ := result.At(0, 0).RGBA()
+		assert.Less(t, r>>8, uint32(60))
+		assert.Less(t, g>>8, uint32(60))
+		assert.Less(t, b>>8, uint32(60))
+	})
+	t.Run("RGB", func(t *testing.T) {
+		img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+		assert.Same(t, img, FromCMYK(img, "rgb.png"))
+	})
+}
+
+func TestIsCMYK(t *testing.T) {
+	assert.True(t, IsCMYK("testdata/cmyk.jpg"))
+	assert.False(t, IsCMYK("testdata/example.jpg"))
+	assert.False(t, IsCMYK("testdata/example.png"))
+	assert.False(t, IsCMYK("testdata/missing.jpg"))
+}
+
+func TestOpen_CMYK(t *testing.T) {
+	// The left half of the file is cyan, and the right half has no ink.
+	assertColors := func(t *testing.T, img image.Image) {
+		cyan := color.NRGBAModel.Convert(img.At(4, 4)).(color.NRGBA)
+		white := color.NRGBAModel.Convert(img.At(12, 4)).(color.NRGBA)
+
+		assert.Less(t, cyan.R, uint8(30))
+		assert.InDelta(t, 174, int(cyan.G), 20)
+		assert.Greater(t, cyan.B, uint8(220))
+		assert.Greater(t, white.R, uint8(245))
+		assert.Greater(t, white.G, uint8(245))
+		assert.Greater(t, white.B, uint8(245))
+	}
+
+	t.Run("OpenJpeg", func(t *testing.T) {
+		img, err := OpenJpeg("testdata/cmyk.jpg", 1)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertColors(t, img)
+	})
+	t.Run("NoStandardRGB", func(t *testing.T) {
+		StandardRGB = false
+		defer func() { StandardRGB = true }()
+
+		img, err := Open("testdata/cmyk.jpg", 1)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertColors(t, img)
+	})
+	t.Run("FromFile", func(t *testing.T) {
+		fileName, err := FromFile("testdata/cmyk.jpg", "c3e0f1b0d2a4e6c8b0a2c4e6f8a0b2c4d6e8f0a2", t.TempDir(), 100, 100, 1, ResampleFit, ResamplePng)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, fs.ImagePNG, fs.FileType(fileName))
+
+		img, err := Open(fileName, 1)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertColors(t, img)
+	})
+}
@@ -0,0 +1,170 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// MaxPSNR is the PSNR in dB that is reported for identical images, whose PSNR is infinite.
+const MaxPSNR = 100.0
+
+// CompareResult represents the quality of a thumbnail compared to a reference without compression loss.
+type CompareResult struct {
+	Size    Name    `json:"size"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	Quality Quality `json:"quality"`
+	Encoder string  `json:"encoder"`
+	Bytes   int64   `json:"bytes"`
+	SSIM    float64 `json:"ssim"`
+	PSNR    float64 `json:"psnr"`
+	Ms      int64   `json:"ms"`
+}
+
+// Compare renders a thumbnail of the image with the specified size, saves it with the specified JPEG quality in
+// a temporary file, and compares it to the rendered image before compression, so that quality settings can be
+// chosen objectively. The hash is used to cache image properties like the document check, as for thumbnails.
+func Compare(img image.Image, hash, tempPath string, size Size, quality Quality) (result CompareResult, err error) {
+	if img == nil {
+		return result, fmt.Errorf("thumb: image missing")
+	} else if InvalidSize(size.Width) || InvalidSize(size.Height) {
+		return result, fmt.Errorf("thumb: invalid size %s", size.Name)
+	} else if quality < 1 || quality > 100 {
+		return result, fmt.Errorf("thumb: invalid quality %d", quality)
+	}
+
+	start := time.Now()
+	reference := Render(img, hash+"_compare", size.Width, size.Height, size.Options...)
+
+	if err = os.MkdirAll(tempPath, fs.ModeDir); err != nil {
+		return result, err
+	}
+
+	tmp, err := os.CreateTemp(tempPath, "compare-*"+fs.ExtJPEG)
+
+	if err != nil {
+		return result, err
+	}
+
+	fileName := tmp.Name()
+	_ = tmp.Close()
+
+	defer os.Remove(fileName)
+
+	if err = SaveJpeg(reference, fileName, quality); err != nil {
+		return result, err
+	}
+
+	info, err := os.Stat(fileName)
+
+	if err != nil {
+		return result, err
+	}
+
+	encoded, err := imaging.Open(fileName)
+
+	if err != nil {
+		return result, fmt.Errorf("thumb: %s while decoding %s", err, filepath.Base(fileName))
+	}
+
+	result = CompareResult{
+		Size:    size.Name,
+		Width:   reference.Bounds().Dx(),
+		Height:  reference.Bounds().Dy(),
+		Quality: quality,
+		Encoder: ActiveEncoder(),
+		Bytes:   info.Size(),
+		SSIM:    math.Round(SSIM(reference, encoded)*10000) / 10000,
+		PSNR:    math.Round(PSNR(reference, encoded)*100) / 100,
+		Ms:      time.Since(start).Milliseconds(),
+	}
+
+	return result, nil
+}
+
+// PSNR returns the peak signal-to-noise ratio in dB of two images with the same dimensions, based on the
+// mean squared error of all color channels, or MaxPSNR if they are identical.
+func PSNR(a, b image.Image) float64 {
+	na, nb := imaging.Clone(a), imaging.Clone(b)
+
+	if na.Bounds().Size() != nb.Bounds().Size() || len(na.Pix) == 0 {
+		return 0
+	}
+
+	var sum float64
+	var n int
+
+	for i := 0; i < len(na.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			d := float64(na.Pix[i+c]) - float64(nb.Pix[i+c])
+			sum += d * d
+			n++
+		}
+	}
+
+	if sum == 0 {
+		return MaxPSNR
+	}
+
+	return math.Min(MaxPSNR, 10*math.Log10(255*255/(sum/float64(n))))
+}
+
+// SSIM returns the mean structural similarity index of the luminance of two images with the same dimensions,
+// computed in non-overlapping windows of 8x8 pixels. It is 1 if the images are identical.
+func SSIM(a, b image.Image) float64 {
+	ga, gb := imaging.Grayscale(a), imaging.Grayscale(b)
+
+	if ga.Bounds().Size() != gb.Bounds().Size() {
+		return 0
+	}
+
+	const window = 8
+	const c1 = (0.01 * 255) * (0.01 * 255)
+	const c2 = (0.03 * 255) * (0.03 * 255)
+
+	w, h := ga.Bounds().Dx(), ga.Bounds().Dy()
+
+	var total float64
+	var windows int
+
+	for y0 := 0; y0 < h; y0 += window {
+		for x0 := 0; x0 < w; x0 += window {
+			var sumA, sumB, sumAA, sumBB, sumAB float64
+			var n float64
+
+			for y := y0; y < y0+window && y < h; y++ {
+				for x := x0; x < x0+window && x < w; x++ {
+					i := y*ga.Stride + x*4
+					va, vb := float64(ga.Pix[i]), float64(gb.Pix[i])
+					sumA += va
+					sumB += vb
+					sumAA += va * va
+					sumBB += vb * vb
+					sumAB += va * vb
+					n++
+				}
+			}
+
+			meanA, meanB := sumA/n, sumB/n
+			varA, varB := sumAA/n-meanA*meanA, sumBB/n-meanB*meanB
+			cov := sumAB/n - meanA*meanB
+
+			total += ((2*meanA*meanB + c1) * (2*cov + c2)) / ((meanA*meanA + meanB*meanB + c1) * (varA + varB + c2))
+			windows++
+		}
+	}
+
+	if windows == 0 {
+		return 0
+	}
+
+	return total / float64(windows)
+}
@@ -0,0 +1,68 @@
+package thumb
+
+import (
+	"image"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	img, err := imaging.Open("testdata/example.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := "1234567890abcdef1234567890abcdef12345678"
+
+	t.Run("Success", func(t *testing.T) {
+		low, err := Compare(img, hash, t.TempDir(), Sizes[Tile224], QualityWorst)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		high, err := Compare(img, hash, t.TempDir(), Sizes[Tile224], QualityBest)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 224, high.Width)
+		assert.Equal(t, 224, high.Height)
+		assert.Equal(t, QualityBest, high.Quality)
+		assert.Greater(t, high.Bytes, low.Bytes)
+		assert.Greater(t, high.SSIM, low.SSIM)
+		assert.Greater(t, high.PSNR, low.PSNR)
+		assert.LessOrEqual(t, high.SSIM, 1.0)
+		assert.Greater(t, low.SSIM, 0.8)
+	})
+	t.Run("InvalidQuality", func(t *testing.T) {
+		_, err := Compare(img, hash, t.TempDir(), Sizes[Tile224], 0)
+		assert.Error(t, err)
+	})
+	t.Run("NoImage", func(t *testing.T) {
+		_, err := Compare(nil, hash, t.TempDir(), Sizes[Tile224], QualityDefault)
+		assert.Error(t, err)
+	})
+}
+
+func TestSSIM(t *testing.T) {
+	a := imaging.New(16, 16, image.White.C)
+	b := imaging.New(16, 16, image.Black.C)
+
+	assert.Equal(t, 1.0, SSIM(a, a))
+	assert.Less(t, SSIM(a, b), 0.01)
+	assert.Equal(t, 0.0, SSIM(a, imaging.New(8, 8, image.White.C)))
+}
+
+func TestPSNR(t *testing.T) {
+	a := imaging.New(16, 16, image.White.C)
+	b := imaging.New(16, 16, image.Black.C)
+
+	assert.Equal(t, MaxPSNR, PSNR(a, a))
+	assert.Equal(t, 0.0, PSNR(a, b))
+	assert.Equal(t, 0.0, PSNR(a, imaging.New(8, 8, image.White.C)))
+}
@@ -6,7 +6,6 @@ import (
 	"image"
 	"image/png"
 	"os"
-	"path"
 	"path/filepath"
 
 	"github.com/disintegration/imaging"
@@ -19,7 +18,11 @@ import (
 func Suffix(width, height int, opts ...ResampleOption) (result string) {
 	method, _, format := ResampleOptions(opts...)
 
-	result = fmt.Sprintf("%dx%d_%s.%s", width, height, ResampleMethods[method], format)
+	if IsGray(opts...) {
+		result = fmt.Sprintf("%dx%d_%s_gray%s.%s", width, height, ResampleMethods[method], gifSuffix(opts...), format)
+	} else {
+		result = fmt.Sprintf("%dx%d_%s%s.%s", width, height, ResampleMethods[method], gifSuffix(opts...), format)
+	}
 
 	return result
 }
@@ -43,7 +46,7 @@ func FileName(hash, thumbPath string, width, height int, opts ...ResampleOption)
 	}
 
 	suffix := Suffix(width, height, opts...)
-	p := path.Join(thumbPath, hash[0:1], hash[1:2], hash[2:3])
+	p := Dir(hash, thumbPath)
 
 	if err := os.MkdirAll(p, fs.ModeDir); err != nil {
 		return "", err
@@ -51,6 +54,11 @@ func FileName(hash, thumbPath string, width, height int, opts ...ResampleOption)
 
 	fileName = fmt.Sprintf("%s/%s_%s", p, hash, suffix)
 
+	// Rename existing thumbnail with legacy file name, if enabled.
+	if MigrateLegacy {
+		migrateLegacy(fileName, LegacyFileName(hash, thumbPath, width, height, opts...))
+	}
+
 	return fileName, nil
 }
 
@@ -73,6 +81,9 @@ func FromCache(imageFilename, hash, thumbPath string, width, height int, opts ..
 		return "", fmt.Errorf("thumb: invalid file name %s", clean.Log(imageFilename))
 	}
 
+	// Thumbnails of GIF sources are cached by mode, see GifOptions.
+	opts = GifOptions(imageFilename, opts...)
+
 	if fileName, err = FileName(hash, thumbPath, width, height, opts...); err != nil {
 		log.Debugf("thumb: %s in %s (get filename)", err, clean.Log(imageFilename))
 		return "", err
@@ -87,6 +98,13 @@ func FromCache(imageFilename, hash, thumbPath string, width, height int, opts ..
 
 // FromFile creates a new thumbnail with the specified size if it was not found in the cache, and returns the filename.
 func FromFile(imageFilename, hash, thumbPath string, width, height, orientation int, opts ...ResampleOption) (fileName string, err error) {
+	return FromFileAngle(imageFilename, hash, thumbPath, width, height, orientation, 0, opts...)
+}
+
+// FromFileAngle creates a new thumbnail like FromFile, but straightens the image by an arbitrary angle in degrees first.
+func FromFileAngle(imageFilename, hash, thumbPath string, width, height, orientation int, angle float64, opts ...ResampleOption) (fileName string, err error) {
+	opts = GifOptions(imageFilename, opts...)
+
 	if fileName, err = FromCache(imageFilename, hash, thumbPath, width, height, opts...); err == nil {
 		return fileName, err
 	} else if err != ErrNotCached {
@@ -101,11 +119,29 @@ func FromFile(imageFilename, hash, thumbPath string, width, height, orientation
 		return "", err
 	}
 
-	// Load image from storage.
-	img, err := Open(imageFilename, orientation)
+	// Create the thumbnail from the middle frame of a GIF, or keep it animated?
+	if mode, _ := GifModeOf(opts...); mode != GifFirst && angle == 0 {
+		return fromGif(imageFilename, fileName, thumbPath, width, height, mode, opts...)
+	}
 
-	if err != nil {
-		log.Debugf("thumb: %s in %s", err, clean.Log(filepath.Base(imageFilename)))
+	var img image.Image
+
+	// Use the edited preview embedded in the XMP sidecar file instead of the original, if any.
+	if preview, ok := FromXmp(imageFilename, hash, thumbPath); ok {
+		imageFilename, orientation = preview, OrientationNormal
+	}
+
+	// Use the preview embedded in the EXIF data if it is large enough, or load the image from storage otherwise.
+	if preview, ok := FromEmbedded(imageFilename, orientation, width, height, opts...); ok {
+		log.Tracef("thumb: using embedded preview of %s", clean.Log(filepath.Base(imageFilename)))
+
+		if img = preview; angle != 0 {
+			img = Straighten(img, angle)
+		}
+	} else if angle == 0 && FromAccel(imageFilename, fileName, width, height, orientation, opts...) {
+		// Resized and saved with hardware acceleration, see ActiveAccel.
+		return fileName, nil
+	} else if img, err = openSource(imageFilename, hash, thumbPath, orientation, angle); err != nil {
 		return "", err
 	}
 
@@ -117,6 +153,67 @@ func FromFile(imageFilename, hash, thumbPath string, width, height, orientation
 	return fileName, nil
 }
 
+// fromGif creates a thumbnail of a GIF source with the specified mode, see GifMode.
+func fromGif(imageFilename, fileName, thumbPath string, width, height int, mode GifMode, opts ...ResampleOption) (string, error) {
+	var err error
+
+	// Download remote original to the cache folder first?
+	if IsRemote(imageFilename) {
+		if imageFilename, err = RemoteFile(imageFilename, thumbPath); err != nil {
+			return "", err
+		}
+	} else if imageFilename, err = fs.Resolve(imageFilename); err != nil {
+		return "", err
+	}
+
+	// Reject images with huge dimensions before allocating memory for their frames.
+	if err = CheckPixels(imageFilename); err != nil {
+		return "", err
+	}
+
+	if mode == GifAnimated {
+		err = CreateAnimated(imageFilename, fileName, width, height, opts...)
+	} else if img, frameErr := GifFrame(imageFilename, mode); frameErr != nil {
+		err = frameErr
+	} else {
+		_, err = Create(img, fileName, width, height, opts...)
+	}
+
+	if err != nil {
+		log.Debugf("thumb: %s in %s (%s gif)", err, clean.Log(filepath.Base(imageFilename)), mode)
+		return "", err
+	}
+
+	return fileName, nil
+}
+
+// openSource opens the image from which thumbnails are created, and straightens it by angle degrees if not 0.
+func openSource(imageFilename, hash, thumbPath string, orientation int, angle float64) (img image.Image, err error) {
+	// Download remote original to the cache folder first?
+	if IsRemote(imageFilename) {
+		if imageFilename, err = RemoteFile(imageFilename, thumbPath); err != nil {
+			log.Debugf("thumb: %s", err)
+			return nil, err
+		}
+	}
+
+	// Use upright copy of rotated image, if available.
+	imageFilename, orientation = FromUpright(imageFilename, hash, thumbPath, orientation)
+
+	// Load image from storage.
+	if img, err = Open(imageFilename, orientation); err != nil {
+		log.Debugf("thumb: %s in %s", err, clean.Log(filepath.Base(imageFilename)))
+		return nil, err
+	}
+
+	// Straighten image?
+	if angle != 0 {
+		img = Straighten(img, angle)
+	}
+
+	return img, nil
+}
+
 // Create creates an image thumbnail.
 func Create(img image.Image, fileName string, width, height int, opts ...ResampleOption) (result image.Image, err error) {
 	if InvalidSize(width) {
@@ -127,20 +224,14 @@ func Create(img image.Image, fileName string, width, height int, opts ...Resampl
 		return img, fmt.Errorf("thumb: height has an invalid value (%d)", height)
 	}
 
-	result = Resample(img, width, height, opts...)
-
-	var quality imaging.EncodeOption
+	result = Render(img, fileName, width, height, opts...)
 
 	if filepath.Ext(fileName) == "."+string(fs.ImagePNG) {
-		quality = imaging.PNGCompressionLevel(png.DefaultCompression)
-	} else if width <= 150 && height <= 150 {
-		quality = JpegQualitySmall.EncodeOption()
+		err = imaging.Save(result, fileName, imaging.PNGCompressionLevel(png.DefaultCompression))
 	} else {
-		quality = JpegQuality.EncodeOption()
+		err = SaveJpeg(result, fileName, SizeQuality(width, height))
 	}
 
-	err = imaging.Save(result, fileName, quality)
-
 	if err != nil {
 		log.Debugf("thumb: failed to save %s", clean.Log(filepath.Base(fileName)))
 		return result, err
@@ -148,3 +239,60 @@ func Create(img image.Image, fileName string, width, height int, opts ...Resampl
 
 	return result, nil
 }
+
+// SizeQuality returns the JPEG quality of thumbnails with the specified size.
+func SizeQuality(width, height int) Quality {
+	if width <= 150 && height <= 150 {
+		return JpegQualitySmall
+	}
+
+	return JpegQuality
+}
+
+// Render resamples the image to the specified size and applies the configured adjustments, without saving it.
+// The file name is used to store the crop region of entropy-based crops.
+func Render(img image.Image, fileName string, width, height int, opts ...ResampleOption) (result image.Image) {
+	method, filter, _ := ResampleOptions(opts...)
+
+	// Pad document-like images to keep their text readable in square tiles.
+	pad := PadDocument(img, fileName, width, height, opts...)
+	entropy := !pad && method == ResampleFillEntropy && width > 0 && height > 0
+
+	// Crop the area with the most detail before the image is downscaled, so that the
+	// crop region is detected in and stored relative to the full image.
+	if entropy {
+		area := EntropyArea(img, width, height)
+
+		// Remember the computed crop region so that it can be returned to clients.
+		if area != img.Bounds() {
+			SetCropRegion(fileName, NewRegion(img.Bounds(), area))
+		}
+
+		img = imaging.Crop(img, area)
+	}
+
+	// Downscale large images in steps to reduce aliasing?
+	if UseSteps(width, height, opts...) {
+		img = DownscaleSteps(img, width, height, pad || method == ResampleFit)
+	}
+
+	if pad {
+		result = FitPadded(img, width, height, opts...)
+	} else if entropy {
+		result = imaging.Resize(img, width, height, filter)
+	} else {
+		result = Resample(img, width, height, opts...)
+	}
+
+	// Improve flat images with conservative auto levels?
+	if UseLevels(width, height, opts...) {
+		result = Levels(result)
+	}
+
+	// Convert to grayscale?
+	if IsGray(opts...) {
+		result = imaging.Grayscale(result)
+	}
+
+	return result
+}
@@ -0,0 +1,138 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"sort"
+)
+
+// FromFileMulti creates thumbnails with the specified sizes from the image file if they are not cached yet,
+// and returns their file names by size. Unlike calling FromFile for each size, the image is decoded only once,
+// see CreateMulti. Animated GIF thumbnails are created separately, as they are not resampled from a single frame.
+func FromFileMulti(imageFilename, hash, thumbPath string, orientation int, sizes SizeList) (fileNames map[Name]string, err error) {
+	fileNames = make(map[Name]string, len(sizes))
+
+	var pending SizeList
+
+	for _, s := range sizes {
+		if mode, _ := GifModeOf(GifOptions(imageFilename, s.Options...)...); mode != GifFirst {
+			if fileNames[s.Name], err = s.FromFile(imageFilename, hash, thumbPath, orientation); err != nil {
+				return fileNames, err
+			}
+		} else if fileName, cacheErr := s.FromCache(imageFilename, hash, thumbPath); cacheErr == nil {
+			fileNames[s.Name] = fileName
+		} else if cacheErr != ErrNotCached {
+			return fileNames, cacheErr
+		} else {
+			pending = append(pending, s)
+		}
+	}
+
+	if len(pending) == 0 {
+		return fileNames, nil
+	}
+
+	img, err := openSource(imageFilename, hash, thumbPath, orientation, 0)
+
+	if err != nil {
+		return fileNames, err
+	}
+
+	created, err := CreateMulti(img, hash, thumbPath, pending)
+
+	for name, fileName := range created {
+		fileNames[name] = fileName
+	}
+
+	return fileNames, err
+}
+
+// CreateMulti creates thumbnails with the specified sizes from an image, and returns their file names by size.
+// Sizes are created from the largest to the smallest, so that each one can be resampled from a smaller image
+// than the original: either its Source size, or the smallest fit thumbnail that is still large enough, see
+// multiSource. This avoids downscaling large originals repeatedly, while the method and adjustments of each
+// size, e.g. crop or fit, are preserved.
+func CreateMulti(img image.Image, hash, thumbPath string, sizes SizeList) (fileNames map[Name]string, err error) {
+	fileNames = make(map[Name]string, len(sizes))
+
+	sorted := make(SizeList, len(sizes))
+	copy(sorted, sizes)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Width*sorted[i].Height > sorted[j].Width*sorted[j].Height
+	})
+
+	// Keep rendered images only if they are the Source of other sizes.
+	sourceOf := make(map[Name]bool, len(sorted))
+
+	for _, s := range sorted {
+		if s.Source != "" {
+			sourceOf[s.Source] = true
+		}
+	}
+
+	rendered := make(map[Name]image.Image, len(sourceOf))
+
+	// Fit thumbnails without adjustments, from large to small.
+	var sources []image.Image
+
+	for _, s := range sorted {
+		fileName, err := s.FileName(hash, thumbPath)
+
+		if err != nil {
+			return fileNames, err
+		}
+
+		src := img
+
+		if s.Source != "" && rendered[s.Source] != nil {
+			src = rendered[s.Source]
+		} else if source, ok := multiSource(sources, s); ok {
+			src = source
+		}
+
+		result, err := s.Create(src, fileName)
+
+		if err != nil {
+			return fileNames, fmt.Errorf("%s while creating %s", err, s.Name)
+		}
+
+		fileNames[s.Name] = fileName
+
+		if sourceOf[s.Name] {
+			rendered[s.Name] = result
+		}
+
+		if multiPlain(s) && result.Bounds().Dx() < src.Bounds().Dx() {
+			sources = append(sources, result)
+		}
+	}
+
+	return fileNames, nil
+}
+
+// multiPlain checks if thumbnails with the size are resampled to fit without adjustments, so that other
+// sizes can be created from them as if they were created from the original.
+func multiPlain(s Size) bool {
+	method, _, _ := ResampleOptions(s.Options...)
+	return method == ResampleFit && !IsGray(s.Options...) && !UseLevels(s.Width, s.Height, s.Options...)
+}
+
+// multiSource returns the smallest image from which a thumbnail with the size can be created without
+// upscaling, so that the result matches a thumbnail created from the original.
+func multiSource(sources []image.Image, s Size) (image.Image, bool) {
+	method, _, _ := ResampleOptions(s.Options...)
+
+	for i := len(sources) - 1; i >= 0; i-- {
+		w, h := sources[i].Bounds().Dx(), sources[i].Bounds().Dy()
+
+		// Fit sizes are never enlarged, so one side must be scaled down, while crops need both sides.
+		if method == ResampleFit && (s.Width <= w || s.Height <= h) {
+			return sources[i], true
+		} else if method != ResampleFit && s.Width <= w && s.Height <= h {
+			return sources[i], true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,161 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestFromFileMulti(t *testing.T) {
+	hash := "5e3d7f1a9b2c4e6f8a0b1c2d3e4f5a6b7c8d9e0f"
+	thumbPath := t.TempDir()
+	sizes := SizeList{Sizes[Tile50], Sizes[Fit720], Sizes[Tile224], Sizes[Tile500], Sizes[Left224], Sizes[Colors]}
+
+	fileNames, err := FromFileMulti("testdata/example.jpg", hash, thumbPath, 1, sizes)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, fileNames, len(sizes))
+
+	for _, s := range sizes {
+		fileName := fileNames[s.Name]
+		assert.True(t, fs.FileExists(fileName), s.Name)
+
+		img, err := imaging.Open(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if s.Fit {
+			assert.Equal(t, image.Rect(0, 0, 720, 480), img.Bounds(), s.Name)
+		} else {
+			assert.Equal(t, s.Bounds(), img.Bounds(), s.Name)
+		}
+	}
+
+	// Cached thumbnails are returned as they are.
+	cached, err := FromFileMulti("testdata/example.jpg", hash, thumbPath, 1, sizes)
+
+	assert.NoError(t, err)
+	assert.Equal(t, fileNames, cached)
+}
+
+func TestCreateMulti(t *testing.T) {
+	img := imaging.New(3000, 2000, color.NRGBA{R: 120, G: 140, B: 160, A: 255})
+	hash := "6f4e8a2b0c1d3e5f7a9b8c6d4e2f0a1b3c5d7e9f"
+	sizes := SizeList{Sizes[Tile224], Sizes[Fit1920], Sizes[Fit720], Sizes[Tile50]}
+
+	fileNames, err := CreateMulti(img, hash, t.TempDir(), sizes)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[Name]image.Rectangle{
+		Fit1920: image.Rect(0, 0, 1800, 1200),
+		Fit720:  image.Rect(0, 0, 720, 480),
+		Tile224: image.Rect(0, 0, 224, 224),
+		Tile50:  image.Rect(0, 0, 50, 50),
+	}
+
+	for name, bounds := range expected {
+		result, err := imaging.Open(fileNames[name])
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, bounds, result.Bounds(), name)
+	}
+}
+
+func TestMultiSource(t *testing.T) {
+	sources := []image.Image{
+		imaging.New(1920, 1280, color.White),
+		imaging.New(720, 480, color.White),
+	}
+
+	t.Run("Fit", func(t *testing.T) {
+		result, ok := multiSource(sources, Sizes[Fit720])
+		assert.True(t, ok)
+		assert.Equal(t, 720, result.Bounds().Dx())
+
+		result, ok = multiSource(sources, Sizes[Fit1280])
+		assert.True(t, ok)
+		assert.Equal(t, 1920, result.Bounds().Dx())
+	})
+	t.Run("Crop", func(t *testing.T) {
+		result, ok := multiSource(sources, Sizes[Tile224])
+		assert.True(t, ok)
+		assert.Equal(t, 720, result.Bounds().Dx())
+
+		result, ok = multiSource(sources, Sizes[Tile500])
+		assert.True(t, ok)
+		assert.Equal(t, 1920, result.Bounds().Dx())
+	})
+	t.Run("TooSmall", func(t *testing.T) {
+		_, ok := multiSource(sources[1:], Sizes[Tile500])
+		assert.False(t, ok)
+		_, ok = multiSource(nil, Sizes[Fit720])
+		assert.False(t, ok)
+	})
+}
+
+func TestMultiPlain(t *testing.T) {
+	assert.True(t, multiPlain(Sizes[Fit720]))
+	assert.False(t, multiPlain(Sizes[Tile500]))
+	assert.False(t, multiPlain(Sizes[Colors]))
+}
+
+// BenchmarkFromFileMulti compares creating the default thumbnail sizes of a large image with a single decode
+// to creating each size separately.
+func BenchmarkFromFileMulti(b *testing.B) {
+	img := imaging.New(6000, 4000, color.NRGBA{R: 120, G: 140, B: 160, A: 255})
+	srcName := filepath.Join(b.TempDir(), "large.jpg")
+
+	if err := imaging.Save(img, srcName); err != nil {
+		b.Fatal(err)
+	}
+
+	hash := "7a5c3e1f9b8d6f4a2c0e1b3d5f7a9c8e6b4d2f0a"
+	sizes := SizeList{Sizes[Fit2048], Sizes[Fit1920], Sizes[Fit1280], Sizes[Fit720], Sizes[Tile500], Sizes[Tile224], Sizes[Tile100], Sizes[Tile50]}
+
+	b.Run("Multi", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			thumbPath := b.TempDir()
+
+			if _, err := FromFileMulti(srcName, hash, thumbPath, 1, sizes); err != nil {
+				b.Fatal(err)
+			}
+
+			b.StopTimer()
+			_ = os.RemoveAll(thumbPath)
+			b.StartTimer()
+		}
+	})
+	b.Run("Single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			thumbPath := b.TempDir()
+
+			for _, s := range sizes {
+				if _, err := s.FromFile(srcName, hash, thumbPath, 1); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.StopTimer()
+			_ = os.RemoveAll(thumbPath)
+			b.StartTimer()
+		}
+	})
+}
@@ -35,6 +35,22 @@ func TestResampleOptions(t *testing.T) {
 	})
 }
 
+func TestResampleWith(t *testing.T) {
+	img, err := imaging.Open("testdata/example.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	box := ResampleWith(img, 100, 100, ResampleBox, ResampleFillCenter)
+	lanczos := ResampleWith(img, 100, 100, ResampleLanczos, ResampleFillCenter)
+
+	assert.Equal(t, 100, box.Bounds().Dx())
+	assert.Equal(t, 100, box.Bounds().Dy())
+	assert.Equal(t, lanczos.Bounds(), box.Bounds())
+	assert.NotEqual(t, box, lanczos)
+}
+
 func TestResample(t *testing.T) {
 	t.Run("tile50 options", func(t *testing.T) {
 		tile50 := Sizes[Tile50]
@@ -345,6 +361,25 @@ func TestFromFile(t *testing.T) {
 	})
 }
 
+func TestFromFileAngle(t *testing.T) {
+	t.Run("Straightened", func(t *testing.T) {
+		tile := Sizes[Tile50]
+		src := "testdata/example.jpg"
+		dst := "testdata/1/2/3/123456789098765432_a2.5_50x50_center.jpg"
+
+		assert.FileExists(t, src)
+
+		fileName, err := FromFileAngle(src, AngleHash("123456789098765432", 2.5), "testdata", tile.Width, tile.Height, OrientationNormal, 2.5, tile.Options...)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, dst, fileName)
+		assert.FileExists(t, dst)
+	})
+}
+
 func TestFromCache(t *testing.T) {
 	t.Run("missing thumb", func(t *testing.T) {
 		tile50 := Sizes[Tile50]
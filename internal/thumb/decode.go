@@ -0,0 +1,104 @@
+package thumb
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/media"
+)
+
+var (
+	DecodeLimit          = 1
+	DecodeMemLimit Bytes = 1 * GB
+)
+
+// decodeState keeps track of the RAW image and video decodes that are currently running.
+var decodeState = struct {
+	sync.Mutex
+	cond    *sync.Cond
+	running int
+	mem     Bytes
+}{}
+
+func init() {
+	decodeState.cond = sync.NewCond(&decodeState.Mutex)
+}
+
+// IsHeavy checks if decoding the file requires a lot of memory, which is the case for RAW images and videos.
+func IsHeavy(fileName string) bool {
+	switch media.Formats[fs.FileType(fileName)] {
+	case media.Raw, media.Video:
+		return true
+	default:
+		return false
+	}
+}
+
+// DecodeMemSize returns the estimated peak memory usage when decoding a RAW image or video with
+// the specified dimensions in pixels, based on the file size if the dimensions are unknown.
+func DecodeMemSize(width, height int, fileSize int64) Bytes {
+	if width > 0 && height > 0 {
+		// Demosaicing and conversion typically use 16-bit RGBA buffers as well as a copy of the result.
+		return Bytes(width) * Bytes(height) * 8 * 2
+	} else if fileSize > 0 {
+		return Bytes(fileSize) * 8
+	}
+
+	return 0
+}
+
+// AcquireDecode waits until a RAW image or video with the estimated memory usage may be decoded, separately
+// from other thumbnail workers, and returns a function that must be called once decoding is done. A single
+// decode is always allowed, so that files exceeding the memory limit cannot block each other forever.
+func AcquireDecode(fileName string, mem Bytes) (release func()) {
+	start := time.Now()
+	waited := false
+
+	decodeState.Lock()
+
+	for !decodeAllowed(mem) {
+		if !waited {
+			log.Infof("thumb: %s waits for decoding, %d running using %s", clean.Log(filepath.Base(fileName)), decodeState.running, decodeState.mem)
+			waited = true
+		}
+
+		decodeState.cond.Wait()
+	}
+
+	decodeState.running++
+	decodeState.mem += mem
+
+	decodeState.Unlock()
+
+	if waited {
+		log.Infof("thumb: decoding %s after waiting %s", clean.Log(filepath.Base(fileName)), time.Since(start).Round(time.Millisecond))
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			decodeState.Lock()
+			decodeState.running--
+			decodeState.mem -= mem
+			decodeState.Unlock()
+			decodeState.cond.Broadcast()
+		})
+	}
+}
+
+// decodeAllowed checks if another decode may start. The caller must hold the lock.
+func decodeAllowed(mem Bytes) bool {
+	if decodeState.running == 0 {
+		return true
+	} else if DecodeLimit > 0 && decodeState.running >= DecodeLimit {
+		return false
+	} else if DecodeMemLimit > 0 && decodeState.mem+mem > DecodeMemLimit {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,82 @@
+package thumb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsHeavy(t *testing.T) {
+	assert.True(t, IsHeavy("IMG_1234.CR2"))
+	assert.True(t, IsHeavy("VID_1234.mp4"))
+	assert.False(t, IsHeavy("IMG_1234.jpg"))
+	assert.False(t, IsHeavy("IMG_1234.png"))
+}
+
+func TestDecodeMemSize(t *testing.T) {
+	assert.Equal(t, Bytes(6000*4000*16), DecodeMemSize(6000, 4000, 25*MB))
+	assert.Equal(t, Bytes(200*MB), DecodeMemSize(0, 0, 25*MB))
+	assert.Equal(t, Bytes(0), DecodeMemSize(0, 0, 0))
+}
+
+// waitForDecode acquires a decode slot in the background and returns a channel that receives the release function.
+func waitForDecode(mem Bytes) chan func() {
+	acquired := make(chan func(), 1)
+
+	go func() {
+		acquired <- AcquireDecode("IMG_1234.CR2", mem)
+	}()
+
+	return acquired
+}
+
+func TestAcquireDecode(t *testing.T) {
+	t.Run("Limit", func(t *testing.T) {
+		DecodeLimit = 1
+		defer func() { DecodeLimit = 1 }()
+
+		release := AcquireDecode("IMG_1234.CR2", 0)
+		acquired := waitForDecode(0)
+
+		select {
+		case <-acquired:
+			t.Fatal("decode must wait")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		release()
+		release()
+
+		select {
+		case next := <-acquired:
+			next()
+		case <-time.After(time.Second):
+			t.Fatal("decode must not wait after release")
+		}
+	})
+	t.Run("Memory", func(t *testing.T) {
+		DecodeLimit = 4
+		DecodeMemLimit = 100 * MB
+		defer func() { DecodeLimit = 1; DecodeMemLimit = 1 * GB }()
+
+		// A single decode is always allowed, even if it exceeds the limit.
+		release := AcquireDecode("IMG_1234.CR2", 150*MB)
+		acquired := waitForDecode(10 * MB)
+
+		select {
+		case <-acquired:
+			t.Fatal("decode must wait")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		release()
+
+		select {
+		case next := <-acquired:
+			next()
+		case <-time.After(time.Second):
+			t.Fatal("decode must not wait after release")
+		}
+	})
+}
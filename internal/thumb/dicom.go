@@ -0,0 +1,464 @@
+package thumb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DICOM transfer syntaxes with uncompressed pixel data.
+const (
+	dicomImplicitLittle = "1.2.840.10008.1.2"
+	dicomExplicitLittle = "1.2.840.10008.1.2.1"
+	dicomExplicitBig    = "1.2.840.10008.1.2.2"
+)
+
+// dicomMaxFrameSize is the maximum size of a single frame in bytes.
+const dicomMaxFrameSize = 512 * 1024 * 1024
+
+// dicomUndefined is the value length of elements with undefined length, e.g. sequences.
+const dicomUndefined = 0xFFFFFFFF
+
+// DICOM tags that are required to decode a representative frame.
+const (
+	dicomTransferSyntax  = 0x00020010
+	dicomSamples         = 0x00280002
+	dicomPhotometric     = 0x00280004
+	dicomFrames          = 0x00280008
+	dicomPlanar          = 0x00280006
+	dicomRows            = 0x00280010
+	dicomColumns         = 0x00280011
+	dicomBitsAllocated   = 0x00280100
+	dicomBitsStored      = 0x00280101
+	dicomPixelRepr       = 0x00280103
+	dicomWindowCenter    = 0x00281050
+	dicomWindowWidth     = 0x00281051
+	dicomRescaleInt      = 0x00281052
+	dicomRescaleSlope    = 0x00281053
+	dicomPixelData       = 0x7FE00010
+	dicomItem            = 0xFFFEE000
+	dicomItemDelimiter   = 0xFFFEE00D
+	dicomSequenceDelimit = 0xFFFEE0DD
+)
+
+// dicomLongVR contains the value representations with a 4-byte length in explicit VR encoding.
+var dicomLongVR = map[string]bool{
+	"OB": true, "OD": true, "OF": true, "OL": true, "OV": true, "OW": true,
+	"SQ": true, "SV": true, "UC": true, "UN": true, "UR": true, "UT": true, "UV": true,
+}
+
+func init() {
+	image.RegisterFormat("dicom", strings.Repeat("?", 128)+"DICM", DecodeDicom, DecodeDicomConfig)
+}
+
+// dicomHeader contains the DICOM attributes needed to decode the pixel data.
+type dicomHeader struct {
+	order         binary.ByteOrder
+	explicit      bool
+	syntax        string
+	rows          int
+	columns       int
+	frames        int
+	samples       int
+	planar        int
+	bitsAllocated int
+	bitsStored    int
+	pixelRepr     int
+	photometric   string
+	windowCenter  float64
+	windowWidth   float64
+	slope         float64
+	intercept     float64
+	pixelLength   uint32
+}
+
+// frameSize returns the size of a single frame in bytes.
+func (h *dicomHeader) frameSize() int {
+	return h.rows * h.columns * h.samples * (h.bitsAllocated / 8)
+}
+
+// DecodeDicom decodes the middle frame of a DICOM image and returns it as 8-bit image,
+// applying the stored window/level for grayscale images.
+func DecodeDicom(r io.Reader) (image.Image, error) {
+	br := bufio.NewReader(r)
+
+	h, err := readDicomHeader(br)
+
+	if err != nil {
+		return nil, err
+	}
+
+	frameSize := h.frameSize()
+
+	if int64(frameSize)*int64(h.frames) > int64(h.pixelLength) {
+		return nil, fmt.Errorf("dicom: pixel data is incomplete")
+	}
+
+	// Use the middle frame of multi-frame images as representative frame.
+	if skip := int64(frameSize) * int64(h.frames/2); skip > 0 {
+		if _, err = io.CopyN(io.Discard, br, skip); err != nil {
+			return nil, fmt.Errorf("dicom: %s", err)
+		}
+	}
+
+	data := make([]byte, frameSize)
+
+	if _, err = io.ReadFull(br, data); err != nil {
+		return nil, fmt.Errorf("dicom: %s", err)
+	}
+
+	if h.samples == 3 {
+		return dicomRGB(h, data), nil
+	}
+
+	return dicomGray(h, data), nil
+}
+
+// DecodeDicomConfig returns the color model and dimensions of a DICOM image without decoding the pixel data.
+func DecodeDicomConfig(r io.Reader) (image.Config, error) {
+	h, err := readDicomHeader(bufio.NewReader(r))
+
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	if h.samples == 3 {
+		return image.Config{ColorModel: color.NRGBAModel, Width: h.columns, Height: h.rows}, nil
+	}
+
+	return image.Config{ColorModel: color.GrayModel, Width: h.columns, Height: h.rows}, nil
+}
+
+// readDicomHeader reads the DICOM attributes up to the start of the pixel data.
+func readDicomHeader(r *bufio.Reader) (*dicomHeader, error) {
+	preamble := make([]byte, 132)
+
+	if _, err := io.ReadFull(r, preamble); err != nil {
+		return nil, fmt.Errorf("dicom: %s", err)
+	} else if string(preamble[128:]) != "DICM" {
+		return nil, errors.New("dicom: invalid file header")
+	}
+
+	// The file meta information is always encoded with explicit VR little endian.
+	h := &dicomHeader{order: binary.LittleEndian, explicit: true, frames: 1, samples: 1, slope: 1}
+	meta := true
+
+	for {
+		if meta {
+			if group, err := r.Peek(2); err != nil {
+				return nil, fmt.Errorf("dicom: %s", err)
+			} else if binary.LittleEndian.Uint16(group) != 0x0002 {
+				// Switch to the transfer syntax of the data set.
+				meta = false
+
+				switch h.syntax {
+				case dicomImplicitLittle:
+					h.explicit = false
+				case dicomExplicitLittle:
+				case dicomExplicitBig:
+					h.order = binary.BigEndian
+				default:
+					return nil, fmt.Errorf("dicom: transfer syntax %s is not supported", h.syntax)
+				}
+			}
+		}
+
+		tag, vr, length, err := h.readElement(r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if tag == dicomPixelData {
+			if length == dicomUndefined {
+				return nil, errors.New("dicom: encapsulated pixel data is not supported")
+			}
+
+			h.pixelLength = length
+
+			return h, h.validate()
+		}
+
+		if length == dicomUndefined {
+			if err = h.skipUndefined(r); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		switch tag {
+		case dicomTransferSyntax, dicomPhotometric, dicomFrames, dicomWindowCenter, dicomWindowWidth, dicomRescaleInt, dicomRescaleSlope:
+			s, err := readDicomString(r, length)
+
+			if err != nil {
+				return nil, err
+			}
+
+			h.setString(tag, s)
+		case dicomSamples, dicomPlanar, dicomRows, dicomColumns, dicomBitsAllocated, dicomBitsStored, dicomPixelRepr:
+			if length != 2 || vr != "US" && vr != "" {
+				return nil, fmt.Errorf("dicom: invalid value of tag %08X", tag)
+			}
+
+			v := make([]byte, 2)
+
+			if _, err = io.ReadFull(r, v); err != nil {
+				return nil, fmt.Errorf("dicom: %s", err)
+			}
+
+			h.setInt(tag, int(h.order.Uint16(v)))
+		default:
+			if _, err = io.CopyN(io.Discard, r, int64(length)); err != nil {
+				return nil, fmt.Errorf("dicom: %s", err)
+			}
+		}
+	}
+}
+
+// readElement reads the tag, value representation, and value length of the next data element.
+func (h *dicomHeader) readElement(r *bufio.Reader) (tag uint32, vr string, length uint32, err error) {
+	b := make([]byte, 8)
+
+	if _, err = io.ReadFull(r, b[:4]); err != nil {
+		return 0, "", 0, fmt.Errorf("dicom: %s", err)
+	}
+
+	tag = uint32(h.order.Uint16(b[0:2]))<<16 | uint32(h.order.Uint16(b[2:4]))
+
+	// Items and delimiters never have a value representation.
+	if !h.explicit || tag>>16 == 0xFFFE {
+		if _, err = io.ReadFull(r, b[:4]); err != nil {
+			return 0, "", 0, fmt.Errorf("dicom: %s", err)
+		}
+
+		return tag, "", h.order.Uint32(b[:4]), nil
+	}
+
+	if _, err = io.ReadFull(r, b[:4]); err != nil {
+		return 0, "", 0, fmt.Errorf("dicom: %s", err)
+	}
+
+	vr = string(b[0:2])
+
+	if !dicomLongVR[vr] {
+		return tag, vr, uint32(h.order.Uint16(b[2:4])), nil
+	}
+
+	if _, err = io.ReadFull(r, b[:4]); err != nil {
+		return 0, "", 0, fmt.Errorf("dicom: %s", err)
+	}
+
+	return tag, vr, h.order.Uint32(b[:4]), nil
+}
+
+// skipUndefined skips the items of a sequence with undefined length, including nested sequences.
+func (h *dicomHeader) skipUndefined(r *bufio.Reader) error {
+	for {
+		tag, _, length, err := h.readElement(r)
+
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case tag == dicomSequenceDelimit || tag == dicomItemDelimiter:
+			return nil
+		case length == dicomUndefined:
+			if err = h.skipUndefined(r); err != nil {
+				return err
+			}
+		case tag == dicomItem:
+			// Skip items with defined length as a whole.
+			fallthrough
+		default:
+			if _, err = io.CopyN(io.Discard, r, int64(length)); err != nil {
+				return fmt.Errorf("dicom: %s", err)
+			}
+		}
+	}
+}
+
+// setString sets a header value from a string attribute.
+func (h *dicomHeader) setString(tag uint32, s string) {
+	// Use the first value of multi-valued attributes.
+	if i := strings.IndexByte(s, '\\'); i >= 0 {
+		s = s[:i]
+	}
+
+	s = strings.TrimSpace(s)
+
+	switch tag {
+	case dicomTransferSyntax:
+		h.syntax = s
+	case dicomPhotometric:
+		h.photometric = strings.ToUpper(s)
+	case dicomFrames:
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			h.frames = n
+		}
+	case dicomWindowCenter:
+		h.windowCenter, _ = strconv.ParseFloat(s, 64)
+	case dicomWindowWidth:
+		h.windowWidth, _ = strconv.ParseFloat(s, 64)
+	case dicomRescaleInt:
+		h.intercept, _ = strconv.ParseFloat(s, 64)
+	case dicomRescaleSlope:
+		if v, err := strconv.ParseFloat(s, 64); err == nil && v != 0 {
+			h.slope = v
+		}
+	}
+}
+
+// setInt sets a header value from an unsigned short attribute.
+func (h *dicomHeader) setInt(tag uint32, v int) {
+	switch tag {
+	case dicomSamples:
+		h.samples = v
+	case dicomPlanar:
+		h.planar = v
+	case dicomRows:
+		h.rows = v
+	case dicomColumns:
+		h.columns = v
+	case dicomBitsAllocated:
+		h.bitsAllocated = v
+	case dicomBitsStored:
+		h.bitsStored = v
+	case dicomPixelRepr:
+		h.pixelRepr = v
+	}
+}
+
+// validate checks if the pixel data can be decoded.
+func (h *dicomHeader) validate() error {
+	if h.rows <= 0 || h.columns <= 0 {
+		return errors.New("dicom: image has no dimensions")
+	} else if h.bitsAllocated != 8 && h.bitsAllocated != 16 {
+		return fmt.Errorf("dicom: %d bits per sample are not supported", h.bitsAllocated)
+	} else if h.frameSize() > dicomMaxFrameSize {
+		return errors.New("dicom: frame size exceeds limit")
+	}
+
+	if h.bitsStored <= 0 || h.bitsStored > h.bitsAllocated {
+		h.bitsStored = h.bitsAllocated
+	}
+
+	switch {
+	case h.samples == 1 && (h.photometric == "MONOCHROME1" || h.photometric == "MONOCHROME2" || h.photometric == ""):
+		return nil
+	case h.samples == 3 && h.photometric == "RGB" && h.bitsAllocated == 8:
+		return nil
+	default:
+		return fmt.Errorf("dicom: photometric interpretation %s is not supported", h.photometric)
+	}
+}
+
+// readDicomString reads a string value.
+func readDicomString(r *bufio.Reader, length uint32) (string, error) {
+	if length > 1024 {
+		return "", fmt.Errorf("dicom: string value too long (%d bytes)", length)
+	}
+
+	b := make([]byte, length)
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", fmt.Errorf("dicom: %s", err)
+	}
+
+	return strings.TrimRight(string(b), "\x00 "), nil
+}
+
+// dicomGray applies the modality and window/level transformations to grayscale pixel data.
+func dicomGray(h *dicomHeader, data []byte) *image.Gray {
+	n := h.rows * h.columns
+	values := make([]float64, n)
+	mask := uint32(1)<<uint(h.bitsStored) - 1
+	sign := uint32(1) << uint(h.bitsStored-1)
+
+	for i := 0; i < n; i++ {
+		var raw uint32
+
+		if h.bitsAllocated == 8 {
+			raw = uint32(data[i])
+		} else {
+			raw = uint32(h.order.Uint16(data[i*2:]))
+		}
+
+		raw &= mask
+
+		v := float64(raw)
+
+		// Signed pixel values use two's complement.
+		if h.pixelRepr == 1 && raw&sign != 0 {
+			v = float64(int64(raw) - int64(mask) - 1)
+		}
+
+		values[i] = v*h.slope + h.intercept
+	}
+
+	center, width := h.windowCenter, h.windowWidth
+
+	// Use the full value range if no window is stored.
+	if width < 1 {
+		lo, hi := math.Inf(1), math.Inf(-1)
+
+		for _, v := range values {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+
+		center, width = (lo+hi)/2+0.5, math.Max(hi-lo+1, 1)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, h.columns, h.rows))
+	invert := h.photometric == "MONOCHROME1"
+
+	for i, v := range values {
+		// Linear window function, see DICOM PS3.3 C.11.2.1.2.
+		var y float64
+
+		switch {
+		case v <= center-0.5-(width-1)/2:
+			y = 0
+		case v > center-0.5+(width-1)/2:
+			y = 255
+		case width <= 1:
+			y = 255
+		default:
+			y = ((v-(center-0.5))/(width-1) + 0.5) * 255
+		}
+
+		if invert {
+			y = 255 - y
+		}
+
+		img.Pix[i] = uint8(math.Round(math.Max(0, math.Min(255, y))))
+	}
+
+	return img
+}
+
+// dicomRGB converts interleaved or planar 8-bit RGB pixel data.
+func dicomRGB(h *dicomHeader, data []byte) *image.NRGBA {
+	n := h.rows * h.columns
+	img := image.NewNRGBA(image.Rect(0, 0, h.columns, h.rows))
+
+	for i := 0; i < n; i++ {
+		if h.planar == 1 {
+			img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2] = data[i], data[n+i], data[2*n+i]
+		} else {
+			img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2] = data[i*3], data[i*3+1], data[i*3+2]
+		}
+
+		img.Pix[i*4+3] = 255
+	}
+
+	return img
+}
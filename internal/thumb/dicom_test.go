@@ -0,0 +1,208 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testDicomWriter creates DICOM test files with explicit or implicit VR little endian encoding.
+type testDicomWriter struct {
+	bytes.Buffer
+	explicit bool
+}
+
+func (w *testDicomWriter) tag(tag uint32) {
+	_ = binary.Write(w, binary.LittleEndian, uint16(tag>>16))
+	_ = binary.Write(w, binary.LittleEndian, uint16(tag))
+}
+
+func (w *testDicomWriter) element(tag uint32, vr string, value []byte, explicit bool) {
+	w.tag(tag)
+
+	if !explicit {
+		_ = binary.Write(w, binary.LittleEndian, uint32(len(value)))
+	} else if dicomLongVR[vr] {
+		w.WriteString(vr)
+		w.Write([]byte{0, 0})
+		_ = binary.Write(w, binary.LittleEndian, uint32(len(value)))
+	} else {
+		w.WriteString(vr)
+		_ = binary.Write(w, binary.LittleEndian, uint16(len(value)))
+	}
+
+	w.Write(value)
+}
+
+func (w *testDicomWriter) str(tag uint32, vr, s string) {
+	if len(s)%2 != 0 {
+		s += " "
+	}
+
+	w.element(tag, vr, []byte(s), w.explicit)
+}
+
+func (w *testDicomWriter) us(tag uint32, v uint16) {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, v)
+	w.element(tag, "US", b, w.explicit)
+}
+
+// testDicom returns a 16-bit grayscale DICOM image with three frames of 4x2 pixels,
+// each filled with the values 0, 100, 200, and 300 plus the frame number times 1000.
+func testDicom(explicit bool, window string, withSequence bool) []byte {
+	w := &testDicomWriter{explicit: explicit}
+	w.Write(make([]byte, 128))
+	w.WriteString("DICM")
+
+	syntax := dicomImplicitLittle
+
+	if explicit {
+		syntax = dicomExplicitLittle
+	}
+
+	uid := syntax + "\x00"
+
+	if len(uid)%2 != 0 {
+		uid += "\x00"
+	}
+
+	w.element(dicomTransferSyntax, "UI", []byte(uid), true)
+
+	if withSequence {
+		// Sequence with undefined length that contains an item with undefined length.
+		w.tag(0x00081115)
+
+		if explicit {
+			w.WriteString("SQ")
+			w.Write([]byte{0, 0})
+		}
+
+		_ = binary.Write(w, binary.LittleEndian, uint32(dicomUndefined))
+		w.tag(dicomItem)
+		_ = binary.Write(w, binary.LittleEndian, uint32(dicomUndefined))
+		w.str(0x00081150, "UI", "1.2.3.4")
+		w.tag(dicomItemDelimiter)
+		_ = binary.Write(w, binary.LittleEndian, uint32(0))
+		w.tag(dicomSequenceDelimit)
+		_ = binary.Write(w, binary.LittleEndian, uint32(0))
+	}
+
+	w.us(dicomSamples, 1)
+	w.str(dicomPhotometric, "CS", "MONOCHROME2")
+	w.str(dicomFrames, "IS", "3")
+	w.us(dicomRows, 2)
+	w.us(dicomColumns, 4)
+	w.us(dicomBitsAllocated, 16)
+	w.us(dicomBitsStored, 16)
+	w.us(dicomPixelRepr, 0)
+
+	if window != "" {
+		w.str(dicomWindowCenter, "DS", window)
+		w.str(dicomWindowWidth, "DS", "301\\100")
+	}
+
+	var pixels []byte
+
+	for frame := 0; frame < 3; frame++ {
+		for i := 0; i < 8; i++ {
+			pixels = binary.LittleEndian.AppendUint16(pixels, uint16(frame*1000+(i%4)*100))
+		}
+	}
+
+	w.element(dicomPixelData, "OW", pixels, explicit)
+
+	return w.Bytes()
+}
+
+func TestDecodeDicom(t *testing.T) {
+	t.Run("ExplicitWindow", func(t *testing.T) {
+		img, err := DecodeDicom(bytes.NewReader(testDicom(true, "1150", false)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gray, ok := img.(*image.Gray)
+
+		assert.True(t, ok)
+		assert.Equal(t, image.Rect(0, 0, 4, 2), img.Bounds())
+
+		// The middle frame contains the values 1000 to 1300, the window ranges from 1000 to 1300.
+		assert.Equal(t, []uint8{0, 85, 170, 255}, gray.Pix[0:4])
+	})
+	t.Run("ImplicitFullRange", func(t *testing.T) {
+		img, err := DecodeDicom(bytes.NewReader(testDicom(false, "", false)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gray := img.(*image.Gray)
+
+		assert.Equal(t, uint8(0), gray.Pix[0])
+		assert.Equal(t, uint8(255), gray.Pix[3])
+	})
+	t.Run("Sequence", func(t *testing.T) {
+		img, err := DecodeDicom(bytes.NewReader(testDicom(true, "1150", true)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, image.Rect(0, 0, 4, 2), img.Bounds())
+	})
+	t.Run("InvalidHeader", func(t *testing.T) {
+		_, err := DecodeDicom(bytes.NewReader(make([]byte, 200)))
+
+		assert.Error(t, err)
+	})
+	t.Run("Compressed", func(t *testing.T) {
+		data := testDicom(true, "", false)
+		data = bytes.Replace(data, []byte(dicomExplicitLittle+"\x00"), []byte("1.2.840.10008.1.2.5\x00"), 1)
+
+		_, err := DecodeDicom(bytes.NewReader(data))
+
+		assert.ErrorContains(t, err, "not supported")
+	})
+}
+
+func TestDecodeDicomConfig(t *testing.T) {
+	cfg, err := DecodeDicomConfig(bytes.NewReader(testDicom(true, "", true)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 4, cfg.Width)
+	assert.Equal(t, 2, cfg.Height)
+}
+
+func TestOpen_Dicom(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "series.dcm")
+
+	if err := os.WriteFile(fileName, testDicom(true, "1150", false), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := Open(fileName, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 4, img.Bounds().Dx())
+
+	dst := filepath.Join(t.TempDir(), "series.jpg")
+
+	if _, err = Jpeg(fileName, dst, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.FileExists(t, dst)
+}
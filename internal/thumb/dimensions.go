@@ -0,0 +1,33 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"strings"
+)
+
+// CheckDimensions reads the image dimensions from the file header without decoding the image, and returns
+// ErrInvalidDimensions if the width or height is zero or negative, as reported by some corrupt files.
+// Unknown formats are not rejected.
+func CheckDimensions(fileName string) error {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+
+	switch {
+	case err == nil && (cfg.Width <= 0 || cfg.Height <= 0):
+		return fmt.Errorf("%w (%dx%d)", ErrInvalidDimensions, cfg.Width, cfg.Height)
+	case err != nil && strings.Contains(err.Error(), "dimension"):
+		// Some decoders, e.g. for PNG, reject non-positive dimensions when reading the header.
+		return fmt.Errorf("%w (%s)", ErrInvalidDimensions, err)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package thumb
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDimensions(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, CheckDimensions("testdata/example.jpg"))
+		assert.NoError(t, CheckDimensions("testdata/example.png"))
+	})
+	t.Run("ZeroHeightJpeg", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 16)), nil); err != nil {
+			t.Fatal(err)
+		}
+
+		data := buf.Bytes()
+
+		// Set the height in the SOF0 header to zero.
+		i := bytes.Index(data, []byte{0xFF, 0xC0})
+
+		if i < 0 {
+			t.Fatal("sof0 marker not found")
+		}
+
+		data[i+5], data[i+6] = 0, 0
+		fileName := filepath.Join(t.TempDir(), "zero.jpg")
+
+		if err := os.WriteFile(fileName, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, errors.Is(CheckDimensions(fileName), ErrInvalidDimensions))
+	})
+	t.Run("ZeroWidthPng", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/example.png")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Set the width in the IHDR chunk to zero.
+		copy(data[16:20], []byte{0, 0, 0, 0})
+		fileName := filepath.Join(t.TempDir(), "zero.png")
+
+		if err = os.WriteFile(fileName, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, errors.Is(CheckDimensions(fileName), ErrInvalidDimensions))
+	})
+	t.Run("UnknownFormat", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "unknown.dat")
+
+		if err := os.WriteFile(fileName, []byte("not an image"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NoError(t, CheckDimensions(fileName))
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		assert.Error(t, CheckDimensions("testdata/xxx.jpg"))
+	})
+}
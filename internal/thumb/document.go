@@ -0,0 +1,119 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+	gc "github.com/patrickmn/go-cache"
+)
+
+var (
+	DocumentRatio                  = 1.3
+	DocumentEdges                  = 0.1
+	DocumentBackground color.Color = color.White
+)
+
+// documentCache caches the document detection results by file hash.
+var documentCache = gc.New(time.Hour, 10*time.Minute)
+
+// documentAnalysisSize is the maximum size in pixels of the image copy used to detect documents.
+const documentAnalysisSize = 256
+
+// documentEdgeContrast is the minimum brightness difference of neighboring pixels to count as edge.
+const documentEdgeContrast = 64
+
+// IsDocument checks if the image looks like a document with text, based on its aspect ratio and edge density.
+func IsDocument(img image.Image) bool {
+	if DocumentEdges <= 0 || img == nil {
+		return false
+	}
+
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+
+	if w <= 0 || h <= 0 || math.Max(w, h)/math.Min(w, h) < DocumentRatio {
+		return false
+	}
+
+	return EdgeDensity(img) >= DocumentEdges
+}
+
+// EdgeDensity returns the share of pixels with a high contrast to their right or bottom neighbor.
+func EdgeDensity(img image.Image) float64 {
+	b := img.Bounds()
+
+	if b.Dx() < 2 || b.Dy() < 2 {
+		return 0
+	}
+
+	small := img
+
+	if b.Dx() > documentAnalysisSize || b.Dy() > documentAnalysisSize {
+		small = imaging.Fit(img, documentAnalysisSize, documentAnalysisSize, imaging.Box)
+	}
+
+	gray := imaging.Grayscale(small)
+	r := gray.Bounds()
+
+	var edges int
+
+	for y := r.Min.Y; y < r.Max.Y-1; y++ {
+		for x := r.Min.X; x < r.Max.X-1; x++ {
+			v := int(gray.Pix[gray.PixOffset(x, y)])
+			dx := int(gray.Pix[gray.PixOffset(x+1, y)]) - v
+			dy := int(gray.Pix[gray.PixOffset(x, y+1)]) - v
+
+			if dx < 0 {
+				dx = -dx
+			}
+
+			if dy < 0 {
+				dy = -dy
+			}
+
+			if dx+dy >= documentEdgeContrast {
+				edges++
+			}
+		}
+	}
+
+	return float64(edges) / float64((r.Dx()-1)*(r.Dy()-1))
+}
+
+// PadDocument checks if a tile should be padded instead of cropped to keep the text of documents readable.
+// The result is cached by the file hash, which is taken from the thumbnail file name.
+func PadDocument(img image.Image, fileName string, width, height int, opts ...ResampleOption) bool {
+	if DocumentEdges <= 0 || width != height {
+		return false
+	} else if method, _, _ := ResampleOptions(opts...); method != ResampleFillCenter {
+		return false
+	}
+
+	cacheKey, _, _ := strings.Cut(filepath.Base(fileName), "_")
+
+	if cached, ok := documentCache.Get(cacheKey); ok {
+		return cached.(bool)
+	}
+
+	result := IsDocument(img)
+
+	documentCache.SetDefault(cacheKey, result)
+
+	if result {
+		log.Debugf("thumb: padding tiles of document %s", cacheKey)
+	}
+
+	return result
+}
+
+// FitPadded downscales the image to fit the dimensions and pads it with DocumentBackground.
+func FitPadded(img image.Image, width, height int, opts ...ResampleOption) image.Image {
+	_, filter, _ := ResampleOptions(opts...)
+
+	return imaging.PasteCenter(imaging.New(width, height, DocumentBackground), imaging.Fit(img, width, height, filter))
+}
@@ -0,0 +1,109 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// testDocument returns a white image with rows of dark dashes resembling text lines.
+func testDocument(width, height int) *image.NRGBA {
+	img := imaging.New(width, height, color.White)
+
+	for y := 0; y < height; y++ {
+		if y%6 > 2 {
+			continue
+		}
+
+		for x := 0; x < width; x++ {
+			if x%4 < 2 {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	return img
+}
+
+// testGradient returns a smooth gray gradient without edges.
+func testGradient(width, height int) *image.NRGBA {
+	img := imaging.New(width, height, color.White)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.Gray{Y: uint8(y * 255 / height)})
+		}
+	}
+
+	return img
+}
+
+func TestIsDocument(t *testing.T) {
+	t.Run("Document", func(t *testing.T) {
+		assert.True(t, IsDocument(testDocument(100, 200)))
+	})
+	t.Run("Square", func(t *testing.T) {
+		assert.False(t, IsDocument(testDocument(200, 200)))
+	})
+	t.Run("Gradient", func(t *testing.T) {
+		assert.False(t, IsDocument(testGradient(100, 200)))
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		edges := DocumentEdges
+		DocumentEdges = 0
+		defer func() { DocumentEdges = edges }()
+
+		assert.False(t, IsDocument(testDocument(100, 200)))
+	})
+}
+
+func TestEdgeDensity(t *testing.T) {
+	assert.Greater(t, EdgeDensity(testDocument(100, 200)), 0.3)
+	assert.Equal(t, 0.0, EdgeDensity(testGradient(100, 200)))
+	assert.Equal(t, 0.0, EdgeDensity(imaging.New(1, 1, color.White)))
+}
+
+func TestPadDocument(t *testing.T) {
+	img := testDocument(100, 200)
+
+	t.Run("Tile", func(t *testing.T) {
+		assert.True(t, PadDocument(img, "/foo/fa6acc5c5c2965ddf6ec465ca42fd8181_50x50_center.jpg", 50, 50, ResampleFillCenter))
+	})
+	t.Run("Cached", func(t *testing.T) {
+		assert.True(t, PadDocument(testGradient(100, 200), "/foo/fa6acc5c5c2965ddf6ec465ca42fd8181_100x100_center.jpg", 100, 100, ResampleFillCenter))
+	})
+	t.Run("Fit", func(t *testing.T) {
+		assert.False(t, PadDocument(img, "/foo/ba6acc5c5c2965ddf6ec465ca42fd8181_720x720_fit.jpg", 720, 720, ResampleFit))
+	})
+	t.Run("NotSquare", func(t *testing.T) {
+		assert.False(t, PadDocument(img, "/foo/ca6acc5c5c2965ddf6ec465ca42fd8181_200x100_center.jpg", 200, 100, ResampleFillCenter))
+	})
+}
+
+func TestFitPadded(t *testing.T) {
+	result := FitPadded(testDocument(100, 200), 50, 50, ResampleDefault)
+
+	assert.Equal(t, image.Rect(0, 0, 50, 50), result.Bounds())
+
+	r, g, b, _ := result.At(0, 25).RGBA()
+	assert.Equal(t, []uint32{0xffff, 0xffff, 0xffff}, []uint32{r, g, b})
+}
+
+func TestCreate_Document(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "da6acc5c5c2965ddf6ec465ca42fd8181_50x50_center.jpg")
+
+	result, err := Create(testDocument(100, 200), fileName, 50, 50, ResampleFillCenter, ResampleDefault)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, image.Rect(0, 0, 50, 50), result.Bounds())
+
+	r, g, b, _ := result.At(0, 25).RGBA()
+	assert.Equal(t, []uint32{0xffff, 0xffff, 0xffff}, []uint32{r, g, b})
+}
@@ -0,0 +1,129 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// DownsizeThreshold is the number of times an original must be served because the requested size exceeds
+// the limit, before a capped derivative is created in the background (0 to disable), see Downsize.
+var DownsizeThreshold = 0
+
+// DownsizeJob represents a frequently requested original that should be downsized.
+type DownsizeJob struct {
+	FileName    string
+	Hash        string
+	ThumbPath   string
+	Orientation int
+	Size        Size
+	Requests    int
+}
+
+var (
+	downsizeJobs  = make(map[string]*DownsizeJob)
+	downsizeMutex = sync.Mutex{}
+)
+
+// DownsizeName returns the file name of the capped derivative of an original with the specified size.
+func DownsizeName(hash, thumbPath string, size Size) string {
+	return path.Join(Dir(hash, thumbPath), fmt.Sprintf("%s_%dx%d_downsized%s", hash, size.Width, size.Height, fs.ExtJPEG))
+}
+
+// FromDownsized returns the file name of the capped derivative if it exists.
+func FromDownsized(hash, thumbPath string, size Size) (fileName string, ok bool) {
+	if len(hash) < 4 || thumbPath == "" {
+		return "", false
+	} else if fileName = DownsizeName(hash, thumbPath, size); fs.FileExists(fileName) {
+		return fileName, true
+	}
+
+	return "", false
+}
+
+// CountOversized counts a request for which the original is served because the size exceeds the limit.
+func CountOversized(fileName, hash, thumbPath string, orientation int, size Size) {
+	if DownsizeThreshold <= 0 || len(hash) < 4 || thumbPath == "" {
+		return
+	}
+
+	key := hash + "_" + size.Name.String()
+
+	downsizeMutex.Lock()
+	defer downsizeMutex.Unlock()
+
+	if job, ok := downsizeJobs[key]; ok {
+		job.Requests++
+		return
+	}
+
+	downsizeJobs[key] = &DownsizeJob{
+		FileName:    fileName,
+		Hash:        hash,
+		ThumbPath:   thumbPath,
+		Orientation: orientation,
+		Size:        size,
+		Requests:    1,
+	}
+}
+
+// DownsizeJobs removes the originals that have been requested at least DownsizeThreshold times
+// from the access counters and returns them.
+func DownsizeJobs() (jobs []DownsizeJob) {
+	downsizeMutex.Lock()
+	defer downsizeMutex.Unlock()
+
+	if DownsizeThreshold <= 0 {
+		return jobs
+	}
+
+	for key, job := range downsizeJobs {
+		if job.Requests >= DownsizeThreshold {
+			jobs = append(jobs, *job)
+			delete(downsizeJobs, key)
+		}
+	}
+
+	return jobs
+}
+
+// ResetDownsize resets the access counters of oversized requests.
+func ResetDownsize() {
+	downsizeMutex.Lock()
+	defer downsizeMutex.Unlock()
+
+	downsizeJobs = make(map[string]*DownsizeJob)
+}
+
+// Downsize creates a derivative of the original that fits the requested size, so that it can be served
+// instead of reading the full original again. Existing derivatives are not replaced.
+func Downsize(job DownsizeJob) (fileName string, err error) {
+	if fileName, ok := FromDownsized(job.Hash, job.ThumbPath, job.Size); ok {
+		return fileName, nil
+	}
+
+	fileName = DownsizeName(job.Hash, job.ThumbPath, job.Size)
+
+	img, err := Open(job.FileName, job.Orientation)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(fileName), fs.ModeDir); err != nil {
+		return "", err
+	}
+
+	if err = SaveJpeg(Resample(img, job.Size.Width, job.Size.Height, ResampleFit, ResampleDefault), fileName, JpegQuality); err != nil {
+		return "", err
+	}
+
+	log.Debugf("thumb: created %s derivative of %s", job.Size.Name, clean.Log(filepath.Base(job.FileName)))
+
+	return fileName, nil
+}
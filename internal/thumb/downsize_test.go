@@ -0,0 +1,79 @@
+package thumb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownsizeName(t *testing.T) {
+	assert.Equal(t, "testdata/d/0/w/d0wn5123456789_7680x4320_downsized.jpg", DownsizeName("d0wn5123456789", "testdata", Sizes[Fit7680]))
+}
+
+func TestCountOversized(t *testing.T) {
+	defer func(n int) { DownsizeThreshold = n }(DownsizeThreshold)
+	defer ResetDownsize()
+
+	hash := "d0wn5123456789"
+	size := Sizes[Fit7680]
+
+	t.Run("Disabled", func(t *testing.T) {
+		DownsizeThreshold = 0
+		CountOversized("testdata/example.jpg", hash, "testdata", OrientationNormal, size)
+		assert.Empty(t, DownsizeJobs())
+	})
+	t.Run("Threshold", func(t *testing.T) {
+		DownsizeThreshold = 2
+		CountOversized("testdata/example.jpg", hash, "testdata", OrientationNormal, size)
+		assert.Empty(t, DownsizeJobs())
+
+		CountOversized("testdata/example.jpg", hash, "testdata", OrientationNormal, size)
+		jobs := DownsizeJobs()
+
+		if assert.Len(t, jobs, 1) {
+			assert.Equal(t, hash, jobs[0].Hash)
+			assert.Equal(t, 2, jobs[0].Requests)
+		}
+
+		// Jobs are only returned once.
+		assert.Empty(t, DownsizeJobs())
+	})
+}
+
+func TestDownsize(t *testing.T) {
+	hash := "d0wn5123456789"
+	size := Sizes[Fit720]
+
+	_, ok := FromDownsized(hash, "testdata", size)
+	assert.False(t, ok)
+
+	fileName, err := Downsize(DownsizeJob{
+		FileName:    "testdata/example.jpg",
+		Hash:        hash,
+		ThumbPath:   "testdata",
+		Orientation: OrientationRotate90,
+		Size:        size,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll("testdata/d")
+
+	cached, ok := FromDownsized(hash, "testdata", size)
+	assert.True(t, ok)
+	assert.Equal(t, fileName, cached)
+
+	img, err := imaging.Open(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The orientation is applied, so that the derivative is portrait.
+	assert.Equal(t, 480, img.Bounds().Dx())
+	assert.Equal(t, 720, img.Bounds().Dy())
+}
@@ -0,0 +1,127 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultDpi is the default print resolution of downloaded thumbnails.
+const DefaultDpi = 300
+
+// MaxDpi is the maximum print resolution that can be set.
+const MaxDpi = 2400
+
+// jfifIdentifier is the identifier that precedes JFIF data in JPEG APP0 segments.
+const jfifIdentifier = "JFIF\x00"
+
+// SetDpi returns a copy of the JPEG data with the resolution in the JFIF header set to the specified DPI,
+// so that layout tools use the intended print size instead of 72 DPI. A JFIF header is added if missing.
+func SetDpi(jpeg []byte, dpi int) ([]byte, error) {
+	if dpi <= 0 {
+		return jpeg, nil
+	} else if dpi > MaxDpi {
+		dpi = MaxDpi
+	}
+
+	if len(jpeg) < 4 || jpeg[0] != 0xFF || jpeg[1] != 0xD8 {
+		return jpeg, fmt.Errorf("thumb: invalid jpeg data")
+	}
+
+	// Update the existing JFIF header, if any.
+	if len(jpeg) >= 18 && jpeg[2] == 0xFF && jpeg[3] == 0xE0 && string(jpeg[6:11]) == jfifIdentifier {
+		result := make([]byte, len(jpeg))
+		copy(result, jpeg)
+
+		// Units are dots per inch.
+		result[13] = 1
+		binary.BigEndian.PutUint16(result[14:16], uint16(dpi))
+		binary.BigEndian.PutUint16(result[16:18], uint16(dpi))
+
+		return result, nil
+	}
+
+	// Otherwise, insert a JFIF 1.1 header without thumbnail after SOI.
+	segment := make([]byte, 18)
+	segment[0], segment[1] = 0xFF, 0xE0
+	binary.BigEndian.PutUint16(segment[2:4], 16)
+	copy(segment[4:9], jfifIdentifier)
+	segment[9], segment[10] = 1, 1
+	segment[11] = 1
+	binary.BigEndian.PutUint16(segment[12:14], uint16(dpi))
+	binary.BigEndian.PutUint16(segment[14:16], uint16(dpi))
+
+	var buf bytes.Buffer
+
+	buf.Grow(len(jpeg) + len(segment))
+	buf.Write(jpeg[:2])
+	buf.Write(segment)
+	buf.Write(jpeg[2:])
+
+	return buf.Bytes(), nil
+}
+
+// Dpi returns the resolution stored in the JFIF header of the JPEG data, or 0 if unknown.
+func Dpi(jpeg []byte) int {
+	if len(jpeg) < 18 || jpeg[2] != 0xFF || jpeg[3] != 0xE0 || string(jpeg[6:11]) != jfifIdentifier {
+		return 0
+	}
+
+	density := int(binary.BigEndian.Uint16(jpeg[14:16]))
+
+	switch jpeg[13] {
+	case 1:
+		return density
+	case 2:
+		// Dots per centimeter.
+		return int(math.Round(float64(density) * 2.54))
+	default:
+		return 0
+	}
+}
+
+// ParsePrintSize parses a print length with unit, e.g. "20cm", "200mm", or "8in", and returns it in inches.
+// Values without unit are considered to be inches.
+func ParsePrintSize(s string) (inches float64, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	factor := 1.0
+
+	switch {
+	case strings.HasSuffix(s, "mm"):
+		factor, s = 1/25.4, strings.TrimSuffix(s, "mm")
+	case strings.HasSuffix(s, "cm"):
+		factor, s = 1/2.54, strings.TrimSuffix(s, "cm")
+	case strings.HasSuffix(s, "in"):
+		s = strings.TrimSuffix(s, "in")
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+
+	if err != nil || v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("thumb: invalid print size")
+	}
+
+	return v * factor, nil
+}
+
+// PrintDpi returns the resolution at which an image with the specified dimensions is printed with the
+// specified length of its longer side in inches.
+func PrintDpi(width, height int, inches float64) int {
+	if width <= 0 || height <= 0 || inches <= 0 {
+		return 0
+	}
+
+	dpi := int(math.Round(math.Max(float64(width), float64(height)) / inches))
+
+	if dpi < 1 {
+		return 1
+	} else if dpi > MaxDpi {
+		return MaxDpi
+	}
+
+	return dpi
+}
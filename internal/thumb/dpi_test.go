@@ -0,0 +1,91 @@
+package thumb
+
+import (
+	"bytes"
+	"image/jpeg"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDpi(t *testing.T) {
+	t.Run("Insert", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/example.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := SetDpi(data, 300)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 300, Dpi(result))
+
+		// Must still be a valid image.
+		_, err = jpeg.Decode(bytes.NewReader(result))
+		assert.NoError(t, err)
+
+		// Update existing header.
+		updated, err := SetDpi(result, 150)
+
+		assert.NoError(t, err)
+		assert.Equal(t, len(result), len(updated))
+		assert.Equal(t, 150, Dpi(updated))
+		assert.Equal(t, 300, Dpi(result))
+	})
+	t.Run("MaxDpi", func(t *testing.T) {
+		result, err := SetDpi([]byte{0xFF, 0xD8, 0xFF, 0xD9}, 10000)
+		assert.NoError(t, err)
+		assert.Equal(t, MaxDpi, Dpi(result))
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		data := []byte{0xFF, 0xD8, 0xFF, 0xD9}
+		result, err := SetDpi(data, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, data, result)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := SetDpi([]byte("foo"), 300)
+		assert.Error(t, err)
+	})
+}
+
+func TestParsePrintSize(t *testing.T) {
+	t.Run("Inches", func(t *testing.T) {
+		inches, err := ParsePrintSize("8in")
+		assert.NoError(t, err)
+		assert.Equal(t, 8.0, inches)
+	})
+	t.Run("NoUnit", func(t *testing.T) {
+		inches, err := ParsePrintSize(" 10 ")
+		assert.NoError(t, err)
+		assert.Equal(t, 10.0, inches)
+	})
+	t.Run("Centimeters", func(t *testing.T) {
+		inches, err := ParsePrintSize("25.4cm")
+		assert.NoError(t, err)
+		assert.InDelta(t, 10.0, inches, 0.0001)
+	})
+	t.Run("Millimeters", func(t *testing.T) {
+		inches, err := ParsePrintSize("254MM")
+		assert.NoError(t, err)
+		assert.InDelta(t, 10.0, inches, 0.0001)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ParsePrintSize("-5cm")
+		assert.Error(t, err)
+		_, err = ParsePrintSize("foo")
+		assert.Error(t, err)
+	})
+}
+
+func TestPrintDpi(t *testing.T) {
+	assert.Equal(t, 240, PrintDpi(1920, 1080, 8))
+	assert.Equal(t, 240, PrintDpi(1080, 1920, 8))
+	assert.Equal(t, MaxDpi, PrintDpi(1920, 1080, 0.1))
+	assert.Equal(t, 0, PrintDpi(0, 1080, 8))
+}
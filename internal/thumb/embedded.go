@@ -0,0 +1,258 @@
+package thumb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"math"
+	"os"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// UseEmbedded enables creating small thumbnails from the preview images embedded in the EXIF data of JPEG files,
+// which is much faster than decoding the full image, see FromEmbedded. It is disabled by default, as the previews
+// have a lower quality and would change existing thumbnails, e.g. those used for color detection.
+var UseEmbedded = false
+
+// EmbeddedMaxRatioDiff is the maximum relative difference between the aspect ratios of the embedded preview
+// and the image, so that letterboxed previews of images with another aspect ratio are not used.
+var EmbeddedMaxRatioDiff = 0.02
+
+// Exif tags that contain the offset and length of embedded JPEG previews.
+const (
+	exifTagJpegOffset = 0x0201
+	exifTagJpegLength = 0x0202
+)
+
+// exifHeader is the identifier that precedes Exif data in JPEG APP1 segments.
+const exifHeader = "Exif\x00\x00"
+
+// EmbeddedPreview returns the largest JPEG preview embedded in the EXIF data of a JPEG file, as stored without
+// applying the orientation, its dimensions, and the dimensions of the file itself. It returns ErrNoPreview if
+// there is none.
+func EmbeddedPreview(fileName string) (data []byte, preview image.Config, width, height int, err error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, preview, 0, 0, err
+	}
+
+	defer f.Close()
+
+	return readEmbeddedPreview(f)
+}
+
+// readEmbeddedPreview returns the largest JPEG preview embedded in the EXIF data of JPEG data, see EmbeddedPreview.
+func readEmbeddedPreview(f io.ReadSeeker) (data []byte, preview image.Config, width, height int, err error) {
+	exif, err := readExifSegment(bufio.NewReader(f))
+
+	if err != nil {
+		return nil, preview, 0, 0, err
+	}
+
+	// The image dimensions follow the EXIF data in the SOF segment.
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return nil, preview, 0, 0, err
+	}
+
+	cfg, err := jpeg.DecodeConfig(bufio.NewReader(f))
+
+	if err != nil {
+		return nil, preview, 0, 0, err
+	}
+
+	for _, p := range exifPreviews(exif) {
+		if previewCfg, err := jpeg.DecodeConfig(bytes.NewReader(p)); err != nil {
+			continue
+		} else if previewCfg.Width*previewCfg.Height > preview.Width*preview.Height {
+			preview = previewCfg
+			data = p
+		}
+	}
+
+	if data == nil {
+		return nil, preview, 0, 0, ErrNoPreview
+	}
+
+	return data, preview, cfg.Width, cfg.Height, nil
+}
+
+// FromEmbedded returns the preview image embedded in the EXIF data of a JPEG file, rotated according to the
+// orientation, if it is large enough for a thumbnail with the specified size and has the same aspect ratio.
+func FromEmbedded(fileName string, orientation, width, height int, opts ...ResampleOption) (img image.Image, ok bool) {
+	if !UseEmbedded || IsRemote(fileName) || fs.FileType(fileName) != fs.ImageJPEG {
+		return nil, false
+	}
+
+	data, cfg, srcWidth, srcHeight, err := EmbeddedPreview(fileName)
+
+	if err != nil {
+		return nil, false
+	}
+
+	// Check the dimensions before decoding the preview, as it is often too small.
+	if !EmbeddedAdequate(cfg.Width, cfg.Height, srcWidth, srcHeight, orientation, width, height, opts...) {
+		return nil, false
+	}
+
+	if img, err = jpeg.Decode(bytes.NewReader(data)); err != nil {
+		log.Debugf("thumb: %s while decoding embedded preview", err)
+		return nil, false
+	}
+
+	if orientation > 1 {
+		img = Rotate(img, orientation)
+	}
+
+	return img, true
+}
+
+// EmbeddedAdequate checks if a preview with the specified dimensions has the same aspect ratio as the image, and
+// is at least as large as a thumbnail with the specified size created from the image. All dimensions except the
+// thumbnail size are without orientation applied.
+func EmbeddedAdequate(previewWidth, previewHeight, srcWidth, srcHeight, orientation, width, height int, opts ...ResampleOption) bool {
+	if previewWidth <= 0 || previewHeight <= 0 || srcWidth <= 0 || srcHeight <= 0 || width <= 0 || height <= 0 {
+		return false
+	}
+
+	srcRatio := float64(srcWidth) / float64(srcHeight)
+	previewRatio := float64(previewWidth) / float64(previewHeight)
+
+	if math.Abs(previewRatio-srcRatio)/srcRatio > EmbeddedMaxRatioDiff {
+		return false
+	}
+
+	// Orientations 5 to 8 swap width and height.
+	if orientation >= 5 && orientation <= 8 {
+		width, height = height, width
+	}
+
+	scaleX, scaleY := float64(width)/float64(srcWidth), float64(height)/float64(srcHeight)
+
+	// Images are scaled to fit into fit sizes, and to cover the size otherwise.
+	var scale float64
+
+	if method, _, _ := ResampleOptions(opts...); method == ResampleFit {
+		scale = math.Min(scaleX, scaleY)
+	} else {
+		scale = math.Max(scaleX, scaleY)
+	}
+
+	// Images are not enlarged.
+	if scale > 1 {
+		scale = 1
+	}
+
+	return previewWidth >= int(math.Round(float64(srcWidth)*scale)) && previewHeight >= int(math.Round(float64(srcHeight)*scale))
+}
+
+// readExifSegment reads the EXIF data from the APP1 segment of a JPEG file.
+func readExifSegment(r *bufio.Reader) ([]byte, error) {
+	var marker [2]byte
+
+	if _, err := io.ReadFull(r, marker[:]); err != nil {
+		return nil, err
+	} else if marker[0] != 0xFF || marker[1] != 0xD8 {
+		return nil, errors.New("thumb: invalid jpeg data")
+	}
+
+	for {
+		if _, err := io.ReadFull(r, marker[:]); err != nil {
+			return nil, err
+		} else if marker[0] != 0xFF {
+			return nil, errors.New("thumb: invalid jpeg data")
+		}
+
+		// EXIF data precedes the image data.
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return nil, ErrNoPreview
+		}
+
+		var size [2]byte
+
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return nil, err
+		}
+
+		n := int(binary.BigEndian.Uint16(size[:])) - 2
+
+		if n < 0 {
+			return nil, errors.New("thumb: invalid jpeg data")
+		}
+
+		if marker[1] != 0xE1 {
+			if _, err := r.Discard(n); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		segment := make([]byte, n)
+
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return nil, err
+		} else if bytes.HasPrefix(segment, []byte(exifHeader)) {
+			return segment[len(exifHeader):], nil
+		}
+	}
+}
+
+// exifPreviews returns the JPEG previews referenced by the IFD chain of the EXIF data.
+func exifPreviews(exif []byte) (previews [][]byte) {
+	if len(exif) < 8 {
+		return previews
+	}
+
+	var order binary.ByteOrder
+
+	switch string(exif[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return previews
+	}
+
+	offset := int(order.Uint32(exif[4:8]))
+	visited := make(map[int]bool)
+
+	// Limit the number of IFDs to prevent endless loops.
+	for i := 0; i < 8 && offset >= 8 && offset+2 <= len(exif) && !visited[offset]; i++ {
+		visited[offset] = true
+
+		count := int(order.Uint16(exif[offset : offset+2]))
+		entries := offset + 2
+
+		if entries+count*12+4 > len(exif) {
+			return previews
+		}
+
+		var start, length int
+
+		for j := 0; j < count; j++ {
+			entry := exif[entries+j*12 : entries+j*12+12]
+
+			switch order.Uint16(entry[0:2]) {
+			case exifTagJpegOffset:
+				start = int(order.Uint32(entry[8:12]))
+			case exifTagJpegLength:
+				length = int(order.Uint32(entry[8:12]))
+			}
+		}
+
+		if start > 0 && length > 4 && start+length <= len(exif) && exif[start] == 0xFF && exif[start+1] == 0xD8 {
+			previews = append(previews, exif[start:start+length])
+		}
+
+		offset = int(order.Uint32(exif[entries+count*12 : entries+count*12+4]))
+	}
+
+	return previews
+}
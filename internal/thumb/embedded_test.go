@@ -0,0 +1,79 @@
+package thumb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const embeddedExample = "../../assets/examples/6720px_white.jpg"
+
+func TestEmbeddedPreview(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		data, preview, width, height, err := EmbeddedPreview(embeddedExample)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotEmpty(t, data)
+		assert.Equal(t, 160, preview.Width)
+		assert.Equal(t, 107, preview.Height)
+		assert.Equal(t, 6720, width)
+		assert.Equal(t, 4480, height)
+	})
+	t.Run("NoPreview", func(t *testing.T) {
+		_, _, _, _, err := EmbeddedPreview("testdata/fixed.jpg")
+		assert.Error(t, err)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, _, _, _, err := EmbeddedPreview("testdata/missing.jpg")
+		assert.Error(t, err)
+	})
+}
+
+func TestFromEmbedded(t *testing.T) {
+	UseEmbedded = true
+	defer func() { UseEmbedded = false }()
+
+	t.Run("Tile100", func(t *testing.T) {
+		img, ok := FromEmbedded(embeddedExample, 1, 100, 100, ResampleFillCenter)
+
+		assert.True(t, ok)
+		assert.Equal(t, 160, img.Bounds().Dx())
+	})
+	t.Run("Rotated", func(t *testing.T) {
+		img, ok := FromEmbedded(embeddedExample, 6, 100, 100, ResampleFillCenter)
+
+		assert.True(t, ok)
+		assert.Equal(t, 160, img.Bounds().Dy())
+	})
+	t.Run("TooSmall", func(t *testing.T) {
+		_, ok := FromEmbedded(embeddedExample, 1, 224, 224, ResampleFillCenter)
+		assert.False(t, ok)
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		UseEmbedded = false
+		defer func() { UseEmbedded = true }()
+
+		_, ok := FromEmbedded(embeddedExample, 1, 100, 100, ResampleFillCenter)
+		assert.False(t, ok)
+	})
+}
+
+func TestEmbeddedAdequate(t *testing.T) {
+	assert.True(t, EmbeddedAdequate(160, 107, 6720, 4480, 1, 100, 100, ResampleFillCenter))
+	assert.True(t, EmbeddedAdequate(160, 107, 6720, 4480, 1, 160, 160, ResampleFit))
+	assert.False(t, EmbeddedAdequate(160, 107, 6720, 4480, 1, 224, 224, ResampleFillCenter))
+
+	// Letterboxed preview.
+	assert.False(t, EmbeddedAdequate(160, 120, 6720, 4480, 1, 50, 50, ResampleFillCenter))
+
+	// Swapped dimensions.
+	assert.True(t, EmbeddedAdequate(160, 107, 6720, 4480, 6, 107, 160, ResampleFit))
+	assert.False(t, EmbeddedAdequate(160, 107, 6720, 4480, 1, 107, 160, ResampleFillCenter))
+
+	// Small images are not enlarged.
+	assert.True(t, EmbeddedAdequate(160, 107, 160, 107, 1, 720, 720, ResampleFit))
+	assert.False(t, EmbeddedAdequate(0, 0, 6720, 4480, 1, 100, 100, ResampleFit))
+}
@@ -0,0 +1,102 @@
+package thumb
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// JPEG encoder backends.
+const (
+	EncoderStd     = "stdlib"
+	EncoderTurbo   = "turbo"
+	EncoderMozjpeg = "mozjpeg"
+)
+
+var (
+	JpegEncoder = EncoderStd
+	CjpegBin    = ""
+)
+
+// ParseEncoder returns the JPEG encoder backend matching the config value, or EncoderStd if it is unknown.
+func ParseEncoder(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case EncoderTurbo, "libjpeg-turbo", "libjpeg":
+		return EncoderTurbo
+	case EncoderMozjpeg, "moz":
+		return EncoderMozjpeg
+	default:
+		return EncoderStd
+	}
+}
+
+// ActiveEncoder returns the JPEG encoder backend that is currently used to save thumbnails.
+func ActiveEncoder() string {
+	if CjpegBin == "" {
+		return EncoderStd
+	}
+
+	return JpegEncoder
+}
+
+// SaveJpeg saves the image as JPEG file with the configured encoder backend. The standard library
+// encoder is used as fallback if the native encoder is not available or fails.
+func SaveJpeg(img image.Image, fileName string, quality Quality) error {
+	if ActiveEncoder() != EncoderStd {
+		if err := saveCjpeg(img, fileName, quality); err == nil {
+			return nil
+		} else {
+			log.Debugf("thumb: %s while saving %s with %s, using %s", err, clean.Log(filepath.Base(fileName)), JpegEncoder, EncoderStd)
+		}
+	}
+
+	return imaging.Save(img, fileName, quality.EncodeOption())
+}
+
+// saveCjpeg saves the image as JPEG file with the libjpeg-turbo or mozjpeg cjpeg command,
+// which reads the image in binary PPM format from stdin.
+func saveCjpeg(img image.Image, fileName string, quality Quality) error {
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+
+	var ppm bytes.Buffer
+
+	ppm.Grow(b.Dx()*b.Dy()*3 + 32)
+	w := bufio.NewWriter(&ppm)
+
+	_, _ = fmt.Fprintf(w, "P6\n%d %d\n255\n", b.Dx(), b.Dy())
+
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		_, _ = w.Write(rgba.Pix[i : i+3])
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(CjpegBin, "-quality", quality.String(), "-optimize", "-outfile", fileName)
+	cmd.Stdin = &ppm
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if s := strings.TrimSpace(stderr.String()); s != "" {
+			return fmt.Errorf("%s (%s)", s, filepath.Base(CjpegBin))
+		}
+
+		return fmt.Errorf("%s (%s)", err, filepath.Base(CjpegBin))
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestParseEncoder(t *testing.T) {
+	assert.Equal(t, EncoderStd, ParseEncoder(""))
+	assert.Equal(t, EncoderStd, ParseEncoder("invalid"))
+	assert.Equal(t, EncoderTurbo, ParseEncoder("turbo"))
+	assert.Equal(t, EncoderTurbo, ParseEncoder(" libjpeg-turbo"))
+	assert.Equal(t, EncoderMozjpeg, ParseEncoder("MozJPEG"))
+}
+
+func TestActiveEncoder(t *testing.T) {
+	defer func() { JpegEncoder, CjpegBin = EncoderStd, "" }()
+
+	assert.Equal(t, EncoderStd, ActiveEncoder())
+	JpegEncoder = EncoderMozjpeg
+	assert.Equal(t, EncoderStd, ActiveEncoder())
+	CjpegBin = "/usr/bin/cjpeg"
+	assert.Equal(t, EncoderMozjpeg, ActiveEncoder())
+}
+
+func TestSaveJpeg(t *testing.T) {
+	img := imaging.New(64, 48, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+
+	t.Run("Stdlib", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "stdlib.jpg")
+
+		if err := SaveJpeg(img, fileName, QualityDefault); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := imaging.Open(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, image.Rect(0, 0, 64, 48), result.Bounds())
+	})
+	t.Run("Cjpeg", func(t *testing.T) {
+		dir := t.TempDir()
+		fileName := filepath.Join(dir, "cjpeg.ppm")
+
+		// Fake encoder that writes the PPM image it receives to the output file.
+		bin := filepath.Join(dir, "cjpeg")
+		script := "#!/bin/sh\nwhile [ $# -gt 0 ]; do [ \"$1\" = \"-outfile\" ] && out=\"$2\"; shift; done\ncat > \"$out\"\n"
+
+		if err := os.WriteFile(bin, []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		JpegEncoder, CjpegBin = EncoderTurbo, bin
+		defer func() { JpegEncoder, CjpegBin = EncoderStd, "" }()
+
+		if err := SaveJpeg(img, fileName, QualityDefault); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "P6\n64 48\n255\n", string(data[:13]))
+		assert.Len(t, data, 13+64*48*3)
+		assert.Equal(t, []byte{200, 100, 50}, data[13:16])
+	})
+	t.Run("Fallback", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "fallback.jpg")
+
+		JpegEncoder, CjpegBin = EncoderMozjpeg, "/nonexistent/cjpeg"
+		defer func() { JpegEncoder, CjpegBin = EncoderStd, "" }()
+
+		if err := SaveJpeg(img, fileName, QualityDefault); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, fs.ImageJPEG, fs.FileType(fileName))
+		assert.True(t, fs.FileExists(fileName))
+
+		_, err := imaging.Open(fileName)
+		assert.NoError(t, err)
+	})
+}
+
+// BenchmarkSaveJpeg compares the available JPEG encoder backends, e.g. with CJPEG_BIN=/opt/mozjpeg/bin/cjpeg.
+func BenchmarkSaveJpeg(b *testing.B) {
+	img, err := imaging.Open("testdata/example.jpg")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	encoders := map[string]string{EncoderStd: ""}
+
+	if bin := os.Getenv("CJPEG_BIN"); bin != "" {
+		encoders[EncoderTurbo] = bin
+	}
+
+	for encoder, bin := range encoders {
+		b.Run(encoder, func(b *testing.B) {
+			JpegEncoder, CjpegBin = encoder, bin
+			defer func() { JpegEncoder, CjpegBin = EncoderStd, "" }()
+
+			fileName := filepath.Join(b.TempDir(), "benchmark.jpg")
+
+			for i := 0; i < b.N; i++ {
+				if err := SaveJpeg(img, fileName, QualityDefault); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			if info, err := os.Stat(fileName); err == nil {
+				b.ReportMetric(float64(info.Size()), "bytes")
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package thumb
+
+import (
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// EntropyCenterBias specifies how strongly entropy crops favor the image center, from 0 (not at all) to 1.
+var EntropyCenterBias = 0.25
+
+// entropyAnalysisSize is the maximum size in pixels of the image copy used to find the crop area.
+const entropyAnalysisSize = 256
+
+// entropySteps is the number of crop positions compared along the free axis.
+const entropySteps = 16
+
+// FillEntropy crops the image area with the most detail, preferring the center, and resizes it
+// to the specified dimensions. It is a middle ground between a center crop and full saliency detection.
+func FillEntropy(img image.Image, width, height int, filter imaging.ResampleFilter) image.Image {
+	if width <= 0 || height <= 0 || img.Bounds().Empty() {
+		return imaging.Fill(img, width, height, imaging.Center, filter)
+	}
+
+	return imaging.Resize(imaging.Crop(img, EntropyArea(img, width, height)), width, height, filter)
+}
+
+// SetEntropy crops the specified thumbnail sizes to the area with the most detail instead of the center,
+// and restores center crops for all other sizes. Sizes that are not center or entropy crops are not changed.
+func SetEntropy(names []Name) {
+	entropy := make(map[Name]bool, len(names))
+
+	for _, name := range names {
+		entropy[name] = true
+	}
+
+	for name, size := range Sizes {
+		if method, _, _ := ResampleOptions(size.Options...); method != ResampleFillCenter && method != ResampleFillEntropy {
+			continue
+		}
+
+		opts := make([]ResampleOption, 0, len(size.Options))
+
+		for _, option := range size.Options {
+			if option != ResampleFillCenter && option != ResampleFillEntropy {
+				opts = append(opts, option)
+			} else if entropy[name] {
+				opts = append(opts, ResampleFillEntropy)
+			} else {
+				opts = append(opts, ResampleFillCenter)
+			}
+		}
+
+		size.Options = opts
+		Sizes[name] = size
+	}
+}
+
+// EntropyArea returns the absolute image area that FillEntropy crops for the specified dimensions.
+func EntropyArea(img image.Image, width, height int) image.Rectangle {
+	b := img.Bounds()
+	srcW, srcH := b.Dx(), b.Dy()
+
+	if width <= 0 || height <= 0 || srcW <= 0 || srcH <= 0 {
+		return b
+	}
+
+	// Find the largest crop area matching the target aspect ratio.
+	cropW, cropH := fillArea(srcW, srcH, width, height)
+
+	if cropW == srcW && cropH == srcH {
+		return b
+	}
+
+	// Analyze a small grayscale copy to keep it fast.
+	scale := 1.0
+
+	if srcW > entropyAnalysisSize || srcH > entropyAnalysisSize {
+		scale = entropyAnalysisSize / math.Max(float64(srcW), float64(srcH))
+	}
+
+	smallW := int(math.Max(1, math.Round(float64(srcW)*scale)))
+	smallH := int(math.Max(1, math.Round(float64(srcH)*scale)))
+	small := imaging.Grayscale(imaging.Resize(img, smallW, smallH, imaging.Box))
+
+	winW, winH := fillArea(smallW, smallH, width, height)
+	freeX, freeY := smallW-winW, smallH-winH
+
+	// Compare the entropy of evenly spaced crop positions, weighted by their distance from the center.
+	bestScore := -1.0
+	bestPos := 0.5
+
+	for i := 0; i <= entropySteps; i++ {
+		pos := float64(i) / entropySteps
+		x, y := int(math.Round(pos*float64(freeX))), int(math.Round(pos*float64(freeY)))
+		weight := 1 - EntropyCenterBias*math.Abs(pos-0.5)*2
+		score := entropy(small, image.Rect(x, y, x+winW, y+winH)) * weight
+
+		if score > bestScore {
+			bestScore = score
+			bestPos = pos
+		}
+	}
+
+	// Return the best area in original image coordinates.
+	x := b.Min.X + int(math.Round(bestPos*float64(srcW-cropW)))
+	y := b.Min.Y + int(math.Round(bestPos*float64(srcH-cropH)))
+
+	return image.Rect(x, y, x+cropW, y+cropH)
+}
+
+// fillArea returns the largest area within the source dimensions that has the target aspect ratio.
+func fillArea(srcW, srcH, width, height int) (w, h int) {
+	w, h = srcW, srcH
+
+	if srcW*height > srcH*width {
+		w = int(math.Max(1, math.Round(float64(srcH)*float64(width)/float64(height))))
+	} else {
+		h = int(math.Max(1, math.Round(float64(srcW)*float64(height)/float64(width))))
+	}
+
+	return w, h
+}
+
+// entropy returns the Shannon entropy of the pixel values in the specified area of a grayscale image.
+func entropy(img *image.NRGBA, r image.Rectangle) float64 {
+	r = r.Intersect(img.Bounds())
+
+	var hist [256]int
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			hist[img.Pix[img.PixOffset(x, y)]]++
+		}
+	}
+
+	n := float64(r.Dx() * r.Dy())
+
+	if n == 0 {
+		return 0
+	}
+
+	var result float64
+
+	for _, c := range hist {
+		if c > 0 {
+			p := float64(c) / n
+			result -= p * math.Log2(p)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,156 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillEntropy(t *testing.T) {
+	t.Run("DetailRight", func(t *testing.T) {
+		img := imaging.New(300, 100, color.Gray{Y: 128})
+
+		// Add a high-contrast pattern to the right third of the image.
+		for y := 0; y < 100; y++ {
+			for x := 200; x < 300; x++ {
+				img.Set(x, y, color.Gray{Y: uint8((x*7 + y*13) % 256)})
+			}
+		}
+
+		result := FillEntropy(img, 50, 50, imaging.NearestNeighbor)
+
+		assert.Equal(t, image.Rect(0, 0, 50, 50), result.Bounds())
+		assert.NotEqual(t, result.At(0, 0), result.At(1, 0))
+	})
+	t.Run("Flat", func(t *testing.T) {
+		img := imaging.New(100, 300, color.Gray{Y: 128})
+
+		result := FillEntropy(img, 50, 50, imaging.Lanczos)
+
+		assert.Equal(t, image.Rect(0, 0, 50, 50), result.Bounds())
+	})
+	t.Run("SameAspectRatio", func(t *testing.T) {
+		img := imaging.New(200, 100, color.Gray{Y: 128})
+
+		result := FillEntropy(img, 100, 50, imaging.Lanczos)
+
+		assert.Equal(t, image.Rect(0, 0, 100, 50), result.Bounds())
+	})
+}
+
+func TestSetEntropy(t *testing.T) {
+	defer SetEntropy(nil)
+
+	SetEntropy([]Name{Tile224, Fit720})
+
+	assert.Equal(t, []ResampleOption{ResampleFillEntropy, ResampleDefault}, Sizes[Tile224].Options)
+	assert.Equal(t, "224x224_entropy.jpg", Suffix(224, 224, Sizes[Tile224].Options...))
+	assert.Equal(t, []ResampleOption{ResampleFillCenter, ResampleDefault}, Sizes[Tile500].Options)
+
+	// Sizes that are not center crops are not changed.
+	method, _, _ := ResampleOptions(Sizes[Fit720].Options...)
+	assert.Equal(t, ResampleFit, method)
+
+	SetEntropy(nil)
+
+	assert.Equal(t, []ResampleOption{ResampleFillCenter, ResampleDefault}, Sizes[Tile224].Options)
+}
+
+func TestResample_Entropy(t *testing.T) {
+	img := imaging.New(400, 200, color.White)
+
+	result := Resample(img, 100, 100, ResampleFillEntropy, ResampleDefault)
+
+	assert.Equal(t, image.Rect(0, 0, 100, 100), result.Bounds())
+	assert.Equal(t, "100x100_entropy.jpg", Suffix(100, 100, ResampleFillEntropy))
+}
+
+func TestEntropyArea(t *testing.T) {
+	t.Run("DetailRight", func(t *testing.T) {
+		img := imaging.New(300, 100, color.Gray{Y: 128})
+
+		for y := 0; y < 100; y++ {
+			for x := 200; x < 300; x++ {
+				img.Set(x, y, color.Gray{Y: uint8((x*7 + y*13) % 256)})
+			}
+		}
+
+		area := EntropyArea(img, 50, 50)
+
+		assert.Equal(t, 100, area.Dx())
+		assert.Equal(t, 100, area.Dy())
+		assert.Greater(t, area.Min.X, 100)
+	})
+	t.Run("SameAspectRatio", func(t *testing.T) {
+		img := imaging.New(200, 100, color.Gray{Y: 128})
+
+		assert.Equal(t, img.Bounds(), EntropyArea(img, 100, 50))
+	})
+}
+
+func TestCreate_Entropy(t *testing.T) {
+	img := imaging.New(300, 100, color.Gray{Y: 128})
+
+	for y := 0; y < 100; y++ {
+		for x := 200; x < 300; x++ {
+			img.Set(x, y, color.Gray{Y: uint8((x*7 + y*13) % 256)})
+		}
+	}
+
+	dst := "testdata/entropy.region.jpg"
+
+	defer os.Remove(dst)
+
+	if _, err := Create(img, dst, 50, 50, ResampleFillEntropy, ResampleDefault); err != nil {
+		t.Fatal(err)
+	}
+
+	r, ok := CropRegion(dst)
+
+	assert.True(t, ok)
+	assert.InDelta(t, 1.0/3, r.W, 0.01)
+	assert.Equal(t, 1.0, r.H)
+	assert.Greater(t, r.X, 1.0/3)
+}
+
+func TestCreate_EntropySteps(t *testing.T) {
+	SetSteps([]Name{Tile50})
+	SetEntropy([]Name{Tile50})
+
+	defer SetSteps(nil)
+	defer SetEntropy(nil)
+
+	assert.True(t, UseSteps(50, 50, Sizes[Tile50].Options...))
+
+	img := imaging.New(3000, 1000, color.Gray{Y: 128})
+
+	for y := 0; y < 1000; y++ {
+		for x := 2000; x < 3000; x++ {
+			img.Set(x, y, color.Gray{Y: uint8((x*7 + y*13) % 256)})
+		}
+	}
+
+	dst := "testdata/entropy.steps.jpg"
+
+	defer os.Remove(dst)
+
+	result, err := Create(img, dst, 50, 50, Sizes[Tile50].Options...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, image.Rect(0, 0, 50, 50), result.Bounds())
+
+	// The region must be stored relative to the full image, not the downscaled copy.
+	r, ok := CropRegion(dst)
+
+	assert.True(t, ok)
+	assert.InDelta(t, 1.0/3, r.W, 0.01)
+	assert.Equal(t, 1.0, r.H)
+	assert.Greater(t, r.X, 1.0/3)
+}
@@ -5,5 +5,13 @@ import (
 )
 
 var (
-	ErrNotCached = errors.New("not cached")
+	ErrNotCached         = errors.New("not cached")
+	ErrTimeout           = errors.New("thumbnail creation timed out")
+	ErrRemoteDisabled    = errors.New("remote originals are disabled")
+	ErrNoPoster          = errors.New("no embedded video poster")
+	ErrNoPreview         = errors.New("no embedded preview image")
+	ErrTooManyPixels     = errors.New("image exceeds pixel limit")
+	ErrInvalidDimensions = errors.New("image has invalid dimensions")
+	ErrPending           = errors.New("thumbnail is being created")
+	ErrStopWalk          = errors.New("stop walking the cache")
 )
@@ -0,0 +1,153 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// UncachedTTL contains the time after which thumbnails of uncached sizes expire by size name, so that they are
+// created again when requested, see Size.Uncached. The empty name sets the default for all uncached sizes.
+// Thumbnails do not expire if no duration is set.
+var UncachedTTL = map[Name]time.Duration{}
+
+// ParseUncachedTTL parses a comma-separated list of durations by size name, e.g. "fit_3840=1h,fit_7680=10m".
+// A duration without size name applies to all uncached sizes that are not listed, e.g. "30m,fit_7680=10m".
+func ParseUncachedTTL(s string) (map[Name]time.Duration, error) {
+	result := make(map[Name]time.Duration)
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		var name Name
+
+		if k, v, found := strings.Cut(item, "="); found {
+			name, item = Name(strings.ToLower(strings.TrimSpace(k))), strings.TrimSpace(v)
+
+			if _, ok := Sizes[name]; !ok {
+				return nil, fmt.Errorf("thumb: unknown size %s", clean.LogQuote(name.String()))
+			}
+		}
+
+		d, err := time.ParseDuration(item)
+
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("thumb: invalid duration %s", clean.LogQuote(item))
+		}
+
+		result[name] = d
+	}
+
+	return result, nil
+}
+
+// UncachedTTLString returns the durations by size name as comma-separated list, see ParseUncachedTTL.
+func UncachedTTLString(ttl map[Name]time.Duration) string {
+	items := make([]string, 0, len(ttl))
+
+	for name, d := range ttl {
+		if name == "" {
+			items = append(items, d.String())
+		} else {
+			items = append(items, fmt.Sprintf("%s=%s", name, d))
+		}
+	}
+
+	sort.Strings(items)
+
+	return strings.Join(items, ",")
+}
+
+// TTL returns the time after which thumbnails of the size expire, or 0 if they do not expire.
+func (s Size) TTL() time.Duration {
+	if !s.Uncached() {
+		return 0
+	} else if d, ok := UncachedTTL[s.Name]; ok {
+		return d
+	}
+
+	return UncachedTTL[""]
+}
+
+// Expire removes the thumbnail of the size if it was created longer ago than its TTL, and returns true
+// if it was removed, so that it will be created again.
+func (s Size) Expire(hash, thumbPath string) bool {
+	ttl := s.TTL()
+
+	if ttl <= 0 {
+		return false
+	}
+
+	fileName, err := s.FileName(hash, thumbPath)
+
+	if err != nil {
+		return false
+	} else if info, err := os.Lstat(fileName); err != nil || time.Since(info.ModTime()) < ttl {
+		return false
+	} else if err = os.Remove(fileName); err != nil {
+		log.Warnf("thumb: %s while removing expired %s", err, clean.Log(filepath.Base(fileName)))
+		return false
+	}
+
+	log.Debugf("thumb: removed expired %s", clean.Log(filepath.Base(fileName)))
+
+	return true
+}
+
+// ExpireUncached removes the thumbnails of uncached sizes whose TTL has expired, and returns the number of
+// files removed. Sidecar folders below the originals folder are included if this layout is used, see Walk.
+func ExpireUncached(cachePath, originalsPath string) (removed int, err error) {
+	if cachePath == "" || !fs.PathExists(cachePath) {
+		return 0, fmt.Errorf("thumb: cache folder %s not found", clean.Log(cachePath))
+	}
+
+	// Find the file name suffixes of sizes that expire.
+	suffixes := make(map[string]time.Duration)
+
+	for _, s := range Sizes {
+		if ttl := s.TTL(); ttl > 0 {
+			suffixes["_"+Suffix(s.Width, s.Height, s.Options...)] = ttl
+		}
+	}
+
+	if len(suffixes) == 0 {
+		return 0, nil
+	}
+
+	err = Walk(cachePath, originalsPath, func(fileName string, d os.DirEntry) error {
+		base := d.Name()
+		i := strings.Index(base, "_")
+
+		// Thumbnails are named after the file hash, followed by the size suffix.
+		if i < 4 || strings.HasPrefix(base, ".") {
+			return nil
+		}
+
+		ttl, ok := suffixes[base[i:]]
+
+		if !ok {
+			return nil
+		} else if info, infoErr := d.Info(); infoErr != nil || time.Since(info.ModTime()) < ttl {
+			return nil
+		}
+
+		if removeErr := os.Remove(fileName); removeErr != nil {
+			log.Warnf("thumb: %s while removing expired %s", removeErr, clean.Log(base))
+			return nil
+		}
+
+		removed++
+
+		return nil
+	})
+
+	return removed, err
+}
@@ -0,0 +1,131 @@
+package thumb
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestParseUncachedTTL(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		result, err := ParseUncachedTTL("")
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+	t.Run("Default", func(t *testing.T) {
+		result, err := ParseUncachedTTL("30m")
+		assert.NoError(t, err)
+		assert.Equal(t, map[Name]time.Duration{"": 30 * time.Minute}, result)
+	})
+	t.Run("Sizes", func(t *testing.T) {
+		result, err := ParseUncachedTTL("1h, FIT_7680=10m")
+		assert.NoError(t, err)
+		assert.Equal(t, map[Name]time.Duration{"": time.Hour, Fit7680: 10 * time.Minute}, result)
+		assert.Equal(t, "1h0m0s,fit_7680=10m0s", UncachedTTLString(result))
+	})
+	t.Run("UnknownSize", func(t *testing.T) {
+		_, err := ParseUncachedTTL("fit_1=1h")
+		assert.Error(t, err)
+	})
+	t.Run("InvalidDuration", func(t *testing.T) {
+		_, err := ParseUncachedTTL("fit_7680=soon")
+		assert.Error(t, err)
+		_, err = ParseUncachedTTL("-1h")
+		assert.Error(t, err)
+	})
+}
+
+func TestSize_TTL(t *testing.T) {
+	UncachedTTL = map[Name]time.Duration{"": time.Hour, Fit7680: time.Minute}
+	defer func() { UncachedTTL = map[Name]time.Duration{} }()
+
+	assert.Equal(t, time.Duration(0), Sizes[Tile224].TTL())
+	assert.Equal(t, time.Hour, Sizes[Fit4096].TTL())
+	assert.Equal(t, time.Minute, Sizes[Fit7680].TTL())
+}
+
+func TestSize_Expire(t *testing.T) {
+	thumbPath := t.TempDir()
+	hash := "a0b1c2d3e4f5a0b1c2d3e4f5a0b1c2d3e4f5a0b1"
+	size := Sizes[Fit7680]
+
+	fileName, err := size.FileName(hash, thumbPath)
+
+	if err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(fileName, []byte("test"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("NoTTL", func(t *testing.T) {
+		assert.False(t, size.Expire(hash, thumbPath))
+		assert.True(t, fs.FileExists(fileName))
+	})
+	t.Run("NotExpired", func(t *testing.T) {
+		UncachedTTL = map[Name]time.Duration{Fit7680: time.Hour}
+		defer func() { UncachedTTL = map[Name]time.Duration{} }()
+
+		assert.False(t, size.Expire(hash, thumbPath))
+		assert.True(t, fs.FileExists(fileName))
+	})
+	t.Run("Expired", func(t *testing.T) {
+		UncachedTTL = map[Name]time.Duration{Fit7680: time.Hour}
+		defer func() { UncachedTTL = map[Name]time.Duration{} }()
+
+		past := time.Now().Add(-2 * time.Hour)
+
+		if err = os.Chtimes(fileName, past, past); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, size.Expire(hash, thumbPath))
+		assert.False(t, fs.FileExists(fileName))
+	})
+}
+
+func TestExpireUncached(t *testing.T) {
+	thumbPath := t.TempDir()
+	hash := "a0b1c2d3e4f5a0b1c2d3e4f5a0b1c2d3e4f5a0b1"
+	past := time.Now().Add(-2 * time.Hour)
+
+	var fileNames []string
+
+	for _, name := range []Name{Tile224, Fit4096, Fit7680} {
+		fileName, err := Sizes[name].FileName(hash, thumbPath)
+
+		if err != nil {
+			t.Fatal(err)
+		} else if err = os.WriteFile(fileName, []byte("test"), fs.ModeFile); err != nil {
+			t.Fatal(err)
+		} else if err = os.Chtimes(fileName, past, past); err != nil {
+			t.Fatal(err)
+		}
+
+		fileNames = append(fileNames, fileName)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		removed, err := ExpireUncached(thumbPath, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, removed)
+	})
+	t.Run("Expired", func(t *testing.T) {
+		UncachedTTL = map[Name]time.Duration{Fit7680: time.Hour, Fit4096: 3 * time.Hour}
+		defer func() { UncachedTTL = map[Name]time.Duration{} }()
+
+		removed, err := ExpireUncached(thumbPath, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, removed)
+		assert.True(t, fs.FileExists(fileNames[0]))
+		assert.True(t, fs.FileExists(fileNames[1]))
+		assert.False(t, fs.FileExists(fileNames[2]))
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := ExpireUncached("", "")
+		assert.Error(t, err)
+	})
+}
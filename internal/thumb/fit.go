@@ -28,6 +28,63 @@ func Fit(w, h int) (size Size) {
 	return FitSizes[0]
 }
 
+// FitWidth returns the smallest fitting thumbnail size with at least the specified width,
+// or the largest size within the size limit if the width exceeds it.
+func FitWidth(w int) (size Size) {
+	size = FitSizes[len(FitSizes)-1]
+
+	for i := len(FitSizes) - 1; i >= 0; i-- {
+		if FitSizes[i].ExceedsLimit() {
+			break
+		} else if size = FitSizes[i]; w <= size.Width {
+			return size
+		}
+	}
+
+	return size
+}
+
+// TileSizes contains square "fill" thumbnail sizes from smallest to largest.
+var TileSizes = SizeList{
+	Sizes[Tile50],
+	Sizes[Tile100],
+	Sizes[Tile224],
+	Sizes[Tile500],
+}
+
+// FitDimensions returns the thumbnail size matching explicit dimensions, e.g. as requested by clients
+// built for other servers: square dimensions snap to the smallest tile size with at least the width,
+// other dimensions to the smallest fit size that covers both, or the largest size within the size limit.
+func FitDimensions(w, h int) (size Size) {
+	if w < 1 {
+		w = 1
+	}
+
+	if h < 1 {
+		h = 1
+	}
+
+	if w == h {
+		for _, size = range TileSizes {
+			if w <= size.Width {
+				return size
+			}
+		}
+	}
+
+	size = FitSizes[len(FitSizes)-1]
+
+	for i := len(FitSizes) - 1; i >= 0; i-- {
+		if FitSizes[i].ExceedsLimit() {
+			break
+		} else if size = FitSizes[i]; w <= size.Width && h <= size.Height {
+			return size
+		}
+	}
+
+	return size
+}
+
 // FitBounds returns the largest thumbnail size fitting the rectangle.
 func FitBounds(r image.Rectangle) (s Size) {
 	return Fit(r.Dx(), r.Dy())
@@ -19,6 +19,19 @@ func TestFit(t *testing.T) {
 	assert.Equal(t, Sizes[Fit7680], Fit(5000, 5000))
 }
 
+func TestFitWidth(t *testing.T) {
+	assert.Equal(t, Sizes[Fit720], FitWidth(1))
+	assert.Equal(t, Sizes[Fit720], FitWidth(640))
+	assert.Equal(t, Sizes[Fit1280], FitWidth(721))
+	assert.Equal(t, Sizes[Fit1920], FitWidth(1920))
+	assert.Equal(t, Sizes[Fit7680], FitWidth(100000))
+
+	SizeUncached = 2048
+	defer func() { SizeUncached = 7680 }()
+
+	assert.Equal(t, Sizes[Fit2048], FitWidth(3000))
+}
+
 func TestFitBounds(t *testing.T) {
 	t.Run("example.jpg", func(t *testing.T) {
 		src := "testdata/example.jpg"
@@ -81,3 +94,20 @@ func TestFitBounds(t *testing.T) {
 		assert.Equal(t, "fit_720", size.Name.String())
 	})
 }
+
+func TestFitDimensions(t *testing.T) {
+	assert.Equal(t, Sizes[Tile50], FitDimensions(0, 0))
+	assert.Equal(t, Sizes[Tile50], FitDimensions(50, 50))
+	assert.Equal(t, Sizes[Tile224], FitDimensions(200, 200))
+	assert.Equal(t, Sizes[Tile500], FitDimensions(500, 500))
+	assert.Equal(t, Sizes[Fit720], FitDimensions(640, 480))
+	assert.Equal(t, Sizes[Fit720], FitDimensions(600, 600))
+	assert.Equal(t, Sizes[Fit1280], FitDimensions(1280, 720))
+	assert.Equal(t, Sizes[Fit2048], FitDimensions(1500, 1500))
+	assert.Equal(t, Sizes[Fit7680], FitDimensions(100000, 100000))
+
+	SizeUncached = 2048
+	defer func() { SizeUncached = 7680 }()
+
+	assert.Equal(t, Sizes[Fit2048], FitDimensions(3000, 2000))
+}
@@ -0,0 +1,123 @@
+package thumb
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Modern image encoders, which are optional and used if the client supports the format, see NegotiateFormat.
+var (
+	CwebpBin   = ""
+	AvifencBin = ""
+)
+
+// Formats returns the image formats that can currently be encoded, in order of preference.
+func Formats() (formats []fs.Type) {
+	if AvifencBin != "" {
+		formats = append(formats, fs.ImageAVIF)
+	}
+
+	if CwebpBin != "" {
+		formats = append(formats, fs.ImageWebP)
+	}
+
+	return append(formats, fs.ImageJPEG)
+}
+
+// NegotiateFormat returns the preferred format that can be encoded and is listed in the "Accept" request header,
+// or JPEG if the client doesn't advertise modern formats.
+func NegotiateFormat(accept string) fs.Type {
+	accept = strings.ToLower(accept)
+
+	for _, f := range Formats() {
+		if mimeType := FormatMimeType(f); mimeType != "" && strings.Contains(accept, mimeType) {
+			return f
+		}
+	}
+
+	return fs.ImageJPEG
+}
+
+// FormatMimeType returns the content type of images encoded in the format.
+func FormatMimeType(f fs.Type) string {
+	switch f {
+	case fs.ImageAVIF:
+		return fs.MimeTypeAVIF
+	case fs.ImageWebP:
+		return fs.MimeTypeWebP
+	case fs.ImagePNG:
+		return fs.MimeTypePNG
+	default:
+		return fs.MimeTypeJPEG
+	}
+}
+
+// SaveFormat saves the image in the specified format, using the JPEG encoder for unsupported formats.
+// The quality is chosen for each image if enabled, see UseAutoQuality, with the specified quality as maximum.
+func SaveFormat(img image.Image, fileName string, f fs.Type, quality Quality) error {
+	if UseAutoQuality(f) {
+		quality = AutoQuality(img, fileName, f, quality)
+	}
+
+	return saveFormat(img, fileName, f, quality)
+}
+
+// saveFormat saves the image in the specified format with a fixed quality.
+func saveFormat(img image.Image, fileName string, f fs.Type, quality Quality) error {
+	switch f {
+	case fs.ImageAVIF:
+		return saveExternal(img, fileName, AvifencBin, "--min", "0", "--max", "63", "-a", fmt.Sprintf("cq-level=%d", (100-quality)*63/100), "%s", "%s")
+	case fs.ImageWebP:
+		return saveExternal(img, fileName, CwebpBin, "-quiet", "-q", quality.String(), "%s", "-o", "%s")
+	default:
+		return SaveJpeg(img, fileName, quality)
+	}
+}
+
+// saveExternal saves the image with an encoder command that reads a temporary PNG file. The first
+// "%s" argument is replaced by the input file name, the second by the output file name.
+func saveExternal(img image.Image, fileName, bin string, args ...string) error {
+	if bin == "" {
+		return fmt.Errorf("thumb: no encoder for %s", filepath.Ext(fileName))
+	}
+
+	tmpName := fileName + ".tmp.png"
+
+	if err := imaging.Save(img, tmpName); err != nil {
+		return err
+	}
+
+	defer os.Remove(tmpName)
+
+	names := []string{tmpName, fileName}
+
+	for i := range args {
+		if args[i] == "%s" && len(names) > 0 {
+			args[i], names = names[0], names[1:]
+		}
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(bin, args...)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if s := strings.TrimSpace(stderr.String()); s != "" {
+			return fmt.Errorf("%s (%s)", s, filepath.Base(bin))
+		}
+
+		return fmt.Errorf("%s (%s)", err, filepath.Base(bin))
+	}
+
+	return nil
+}
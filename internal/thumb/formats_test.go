@@ -0,0 +1,74 @@
+package thumb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestFormats(t *testing.T) {
+	defer func() { CwebpBin, AvifencBin = "", "" }()
+
+	CwebpBin, AvifencBin = "", ""
+	assert.Equal(t, []fs.Type{fs.ImageJPEG}, Formats())
+
+	CwebpBin = "/usr/bin/cwebp"
+	assert.Equal(t, []fs.Type{fs.ImageWebP, fs.ImageJPEG}, Formats())
+
+	AvifencBin = "/usr/bin/avifenc"
+	assert.Equal(t, []fs.Type{fs.ImageAVIF, fs.ImageWebP, fs.ImageJPEG}, Formats())
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	defer func() { CwebpBin, AvifencBin = "", "" }()
+
+	accept := "image/avif,image/webp,image/apng,image/*,*/*;q=0.8"
+
+	CwebpBin, AvifencBin = "", ""
+	assert.Equal(t, fs.ImageJPEG, NegotiateFormat(accept))
+
+	CwebpBin = "/usr/bin/cwebp"
+	assert.Equal(t, fs.ImageWebP, NegotiateFormat(accept))
+	assert.Equal(t, fs.ImageJPEG, NegotiateFormat("image/*"))
+	assert.Equal(t, fs.ImageJPEG, NegotiateFormat(""))
+
+	AvifencBin = "/usr/bin/avifenc"
+	assert.Equal(t, fs.ImageAVIF, NegotiateFormat(accept))
+	assert.Equal(t, fs.ImageWebP, NegotiateFormat("image/webp,*/*"))
+}
+
+func TestFormatMimeType(t *testing.T) {
+	assert.Equal(t, fs.MimeTypeWebP, FormatMimeType(fs.ImageWebP))
+	assert.Equal(t, fs.MimeTypeAVIF, FormatMimeType(fs.ImageAVIF))
+	assert.Equal(t, fs.MimeTypeJPEG, FormatMimeType(fs.ImageJPEG))
+	assert.Equal(t, fs.MimeTypeJPEG, FormatMimeType(""))
+}
+
+func TestSaveFormat(t *testing.T) {
+	img, err := imaging.Open("testdata/example.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("NoEncoder", func(t *testing.T) {
+		CwebpBin = ""
+		fileName := "testdata/formats.webp"
+
+		assert.Error(t, SaveFormat(img, fileName, fs.ImageWebP, JpegQuality))
+		assert.NoFileExists(t, fileName)
+		assert.NoFileExists(t, fileName+".tmp.png")
+	})
+	t.Run("JPEG", func(t *testing.T) {
+		fileName := "testdata/formats.jpg"
+
+		assert.NoError(t, SaveFormat(img, fileName, fs.ImageJPEG, JpegQuality))
+		assert.FileExists(t, fileName)
+
+		_ = os.Remove(fileName)
+	})
+}
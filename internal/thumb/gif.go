@@ -0,0 +1,246 @@
+package thumb
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// GifMode specifies how thumbnails of animated GIF sources are created.
+type GifMode string
+
+const (
+	GifFirst    GifMode = "first"
+	GifMiddle   GifMode = "middle"
+	GifAnimated GifMode = "animated"
+)
+
+// Gif is the default mode for GIF sources, which can be overridden with a resample option, see GifMode.Option.
+var Gif = GifFirst
+
+// GifAnimatedLimit is the maximum width and height of animated thumbnails in pixels.
+var GifAnimatedLimit = 720
+
+// GifMaxFrames is the maximum number of frames in animated thumbnails, others are skipped.
+var GifMaxFrames = 200
+
+// ParseGifMode returns the GIF mode with the specified name, if valid.
+func ParseGifMode(s string) (GifMode, bool) {
+	switch m := GifMode(strings.ToLower(strings.TrimSpace(s))); m {
+	case GifFirst, GifMiddle, GifAnimated:
+		return m, true
+	default:
+		return GifFirst, false
+	}
+}
+
+// Option returns the resample option that requests the mode.
+func (m GifMode) Option() ResampleOption {
+	switch m {
+	case GifMiddle:
+		return ResampleGifMiddle
+	case GifAnimated:
+		return ResampleGifAnimated
+	default:
+		return ResampleGifFirst
+	}
+}
+
+// GifModeOf returns the GIF mode requested in the resample options, and false if none was requested.
+func GifModeOf(opts ...ResampleOption) (GifMode, bool) {
+	for _, option := range opts {
+		switch option {
+		case ResampleGifFirst:
+			return GifFirst, true
+		case ResampleGifMiddle:
+			return GifMiddle, true
+		case ResampleGifAnimated:
+			return GifAnimated, true
+		}
+	}
+
+	return GifFirst, false
+}
+
+// GifOptions returns the resample options for the source file. The default mode is added for GIF sources,
+// unless another mode was requested, and GIF modes are removed for all other sources, so that their
+// thumbnails are not created again.
+func GifOptions(imageFilename string, opts ...ResampleOption) []ResampleOption {
+	isGif := fs.FileType(imageFilename) == fs.ImageGIF
+	_, requested := GifModeOf(opts...)
+
+	if isGif && !requested && Gif != GifFirst {
+		return append(append(make([]ResampleOption, 0, len(opts)+1), opts...), Gif.Option())
+	} else if !isGif && requested {
+		result := make([]ResampleOption, 0, len(opts))
+
+		for _, option := range opts {
+			if option != ResampleGifFirst && option != ResampleGifMiddle && option != ResampleGifAnimated {
+				result = append(result, option)
+			}
+		}
+
+		return result
+	}
+
+	return opts
+}
+
+// gifSuffix returns the file name suffix of the GIF mode, if any.
+func gifSuffix(opts ...ResampleOption) string {
+	if mode, _ := GifModeOf(opts...); mode != GifFirst {
+		return "_" + string(mode)
+	}
+
+	return ""
+}
+
+// GifFrames decodes all frames of a GIF and returns them as full images, since frames may only contain
+// the parts that changed. At most GifMaxFrames frames are returned, together with their delays.
+func GifFrames(fileName string) (frames []image.Image, delays []int, err error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer f.Close()
+
+	g, err := gif.DecodeAll(bufio.NewReader(f))
+
+	if err != nil {
+		return nil, nil, err
+	} else if len(g.Image) == 0 {
+		return nil, nil, fmt.Errorf("thumb: %s has no frames", clean.Log(filepath.Base(fileName)))
+	}
+
+	// Skip frames if there are too many, and add their delays to the frames that are kept.
+	step := 1
+
+	if GifMaxFrames > 0 && len(g.Image) > GifMaxFrames {
+		step = (len(g.Image) + GifMaxFrames - 1) / GifMaxFrames
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+
+	if bounds.Empty() {
+		bounds = g.Image[0].Bounds()
+	}
+
+	canvas := image.NewRGBA(bounds)
+
+	for i, frame := range g.Image {
+		var previous *image.RGBA
+
+		disposal := byte(0)
+
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		if disposal == gif.DisposalPrevious {
+			previous = cloneRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		if i%step == 0 {
+			frames = append(frames, cloneRGBA(canvas))
+			delays = append(delays, 0)
+		}
+
+		if i < len(g.Delay) {
+			delays[len(delays)-1] += g.Delay[i]
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return frames, delays, nil
+}
+
+// GifFrame returns the middle frame of a GIF for GifMiddle, or the first frame otherwise.
+func GifFrame(fileName string, mode GifMode) (image.Image, error) {
+	frames, _, err := GifFrames(fileName)
+
+	if err != nil {
+		return nil, err
+	} else if mode == GifMiddle {
+		return frames[len(frames)/2], nil
+	}
+
+	return frames[0], nil
+}
+
+// CreateAnimated creates an animated thumbnail from a GIF source. Its width and height are limited
+// to GifAnimatedLimit, so the result may be smaller than requested.
+func CreateAnimated(imageFilename, fileName string, width, height int, opts ...ResampleOption) error {
+	if InvalidSize(width) || InvalidSize(height) {
+		return fmt.Errorf("thumb: invalid size %dx%d", width, height)
+	}
+
+	frames, delays, err := GifFrames(imageFilename)
+
+	if err != nil {
+		return err
+	}
+
+	// Limit the size of animated thumbnails, keeping the aspect ratio.
+	if GifAnimatedLimit > 0 && (width > GifAnimatedLimit || height > GifAnimatedLimit) {
+		if width >= height {
+			width, height = GifAnimatedLimit, height*GifAnimatedLimit/width
+		} else {
+			width, height = width*GifAnimatedLimit/height, GifAnimatedLimit
+		}
+	}
+
+	result := &gif.GIF{Image: make([]*image.Paletted, len(frames)), Delay: delays}
+
+	for i, frame := range frames {
+		img := Resample(frame, width, height, opts...)
+		pal := image.NewPaletted(img.Bounds(), gifPalette)
+
+		draw.Draw(pal, img.Bounds(), img, img.Bounds().Min, draw.Src)
+
+		result.Image[i] = pal
+	}
+
+	f, err := os.Create(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	if err = gif.EncodeAll(f, result); err != nil {
+		f.Close()
+		_ = os.Remove(fileName)
+		return err
+	}
+
+	return f.Close()
+}
+
+// gifPalette is the color palette of animated thumbnails, with a transparent color for frames that are not opaque.
+var gifPalette = append(color.Palette{color.Transparent}, palette.Plan9[:255]...)
+
+// cloneRGBA returns a copy of the image.
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	result := image.NewRGBA(img.Bounds())
+	copy(result.Pix, img.Pix)
+	return result
+}
@@ -0,0 +1,168 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// writeTestGif creates an animated GIF with the specified number of frames in different gray levels.
+func writeTestGif(t *testing.T, fileName string, frames int) {
+	g := &gif.GIF{}
+
+	for i := 0; i < frames; i++ {
+		img := image.NewPaletted(image.Rect(0, 0, 80, 40), palette.WebSafe)
+
+		for p := range img.Pix {
+			img.Pix[p] = uint8(img.Palette.Index(color.Gray{Y: uint8(i * 255 / frames)}))
+		}
+
+		g.Image = append(g.Image, img)
+		g.Delay = append(g.Delay, 10)
+	}
+
+	f, err := os.Create(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	if err = gif.EncodeAll(f, g); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseGifMode(t *testing.T) {
+	mode, ok := ParseGifMode(" Middle")
+	assert.True(t, ok)
+	assert.Equal(t, GifMiddle, mode)
+
+	mode, ok = ParseGifMode("last")
+	assert.False(t, ok)
+	assert.Equal(t, GifFirst, mode)
+}
+
+func TestGifOptions(t *testing.T) {
+	opts := Sizes[Tile224].Options
+
+	t.Run("Default", func(t *testing.T) {
+		assert.Equal(t, opts, GifOptions("example.gif", opts...))
+	})
+	t.Run("ConfiguredMode", func(t *testing.T) {
+		Gif = GifMiddle
+		defer func() { Gif = GifFirst }()
+
+		mode, _ := GifModeOf(GifOptions("example.gif", opts...)...)
+		assert.Equal(t, GifMiddle, mode)
+		mode, _ = GifModeOf(GifOptions("example.gif", append(opts, ResampleGifAnimated)...)...)
+		assert.Equal(t, GifAnimated, mode)
+		assert.Equal(t, append(opts, ResampleGifFirst), GifOptions("example.gif", append(opts, ResampleGifFirst)...))
+	})
+	t.Run("NoGif", func(t *testing.T) {
+		assert.Equal(t, opts, GifOptions("example.jpg", append(opts, ResampleGifAnimated)...))
+	})
+}
+
+func TestSuffix_Gif(t *testing.T) {
+	assert.Equal(t, "224x224_center_middle.jpg", Suffix(224, 224, ResampleFillCenter, ResampleGifMiddle))
+	assert.Equal(t, "224x224_center_animated.gif", Suffix(224, 224, ResampleFillCenter, ResampleGifAnimated))
+	assert.Equal(t, "224x224_center.jpg", Suffix(224, 224, ResampleFillCenter, ResampleGifFirst))
+}
+
+func TestGifFrames(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "animated.gif")
+	writeTestGif(t, fileName, 5)
+
+	t.Run("All", func(t *testing.T) {
+		frames, delays, err := GifFrames(fileName)
+
+		assert.NoError(t, err)
+		assert.Len(t, frames, 5)
+		assert.Equal(t, []int{10, 10, 10, 10, 10}, delays)
+	})
+	t.Run("MaxFrames", func(t *testing.T) {
+		GifMaxFrames = 2
+		defer func() { GifMaxFrames = 200 }()
+
+		frames, delays, err := GifFrames(fileName)
+
+		assert.NoError(t, err)
+		assert.Len(t, frames, 2)
+		assert.Equal(t, []int{30, 20}, delays)
+	})
+	t.Run("Middle", func(t *testing.T) {
+		first, err := GifFrame(fileName, GifFirst)
+		assert.NoError(t, err)
+		middle, err := GifFrame(fileName, GifMiddle)
+		assert.NoError(t, err)
+
+		r1, _, _, _ := first.At(10, 10).RGBA()
+		r2, _, _, _ := middle.At(10, 10).RGBA()
+		assert.Less(t, r1, r2)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, _, err := GifFrames("testdata/missing.gif")
+		assert.Error(t, err)
+	})
+}
+
+func TestFromFile_Gif(t *testing.T) {
+	thumbPath := t.TempDir()
+	src := filepath.Join(t.TempDir(), "animated.gif")
+	hash := "a0b1c2d3e4f5a0b1c2d3e4f5a0b1c2d3e4f5a0b1"
+
+	writeTestGif(t, src, 5)
+
+	t.Run("Middle", func(t *testing.T) {
+		fileName, err := FromFile(src, hash, thumbPath, 50, 50, 1, ResampleFillCenter, ResampleGifMiddle)
+
+		assert.NoError(t, err)
+		assert.Equal(t, hash+"_50x50_center_middle.jpg", filepath.Base(fileName))
+		assert.True(t, fs.FileExists(fileName))
+	})
+	t.Run("Animated", func(t *testing.T) {
+		GifAnimatedLimit = 32
+		defer func() { GifAnimatedLimit = 720 }()
+
+		fileName, err := FromFile(src, hash, thumbPath, 64, 48, 1, ResampleFit, ResampleGifAnimated)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, hash+"_64x48_fit_animated.gif", filepath.Base(fileName))
+
+		f, err := os.Open(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Close()
+
+		g, err := gif.DecodeAll(f)
+
+		assert.NoError(t, err)
+		assert.Len(t, g.Image, 5)
+		assert.LessOrEqual(t, g.Image[0].Bounds().Dx(), 32)
+	})
+	t.Run("ConfiguredMode", func(t *testing.T) {
+		Gif = GifMiddle
+		defer func() { Gif = GifFirst }()
+
+		fileName, err := FromFile(src, hash, thumbPath, 50, 50, 1, ResampleFillCenter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, hash+"_50x50_center_middle.jpg", filepath.Base(fileName))
+	})
+}
@@ -0,0 +1,39 @@
+package thumb
+
+// IsGray checks if the resample options include grayscale conversion.
+func IsGray(opts ...ResampleOption) bool {
+	for _, option := range opts {
+		if option == ResampleGray {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetGray enables grayscale conversion for the specified thumbnail sizes and disables it for all others.
+// The color detection size is never converted, as it would no longer be possible to detect colors.
+func SetGray(names []Name) {
+	gray := make(map[Name]bool, len(names))
+
+	for _, name := range names {
+		gray[name] = true
+	}
+
+	for name, size := range Sizes {
+		opts := make([]ResampleOption, 0, len(size.Options)+1)
+
+		for _, option := range size.Options {
+			if option != ResampleGray {
+				opts = append(opts, option)
+			}
+		}
+
+		if gray[name] && name != Colors {
+			opts = append(opts, ResampleGray)
+		}
+
+		size.Options = opts
+		Sizes[name] = size
+	}
+}
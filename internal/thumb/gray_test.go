@@ -0,0 +1,74 @@
+package thumb
+
+import (
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsGray(t *testing.T) {
+	assert.False(t, IsGray())
+	assert.False(t, IsGray(ResampleFillCenter, ResampleDefault))
+	assert.True(t, IsGray(ResampleFillCenter, ResampleDefault, ResampleGray))
+}
+
+func TestSetGray(t *testing.T) {
+	defer SetGray(nil)
+
+	SetGray([]Name{Tile500, Colors})
+
+	assert.True(t, IsGray(Sizes[Tile500].Options...))
+	assert.False(t, IsGray(Sizes[Tile224].Options...))
+	assert.False(t, IsGray(Sizes[Colors].Options...))
+	assert.Equal(t, "500x500_center_gray.jpg", Suffix(500, 500, Sizes[Tile500].Options...))
+
+	// Calling it again must not add the option twice.
+	SetGray([]Name{Tile500})
+
+	assert.Len(t, Sizes[Tile500].Options, 3)
+
+	SetGray(nil)
+
+	assert.False(t, IsGray(Sizes[Tile500].Options...))
+	assert.Equal(t, []ResampleOption{ResampleFillCenter, ResampleDefault}, Sizes[Tile500].Options)
+}
+
+func TestCreate_Gray(t *testing.T) {
+	img := imaging.New(100, 100, color.NRGBA{R: 200, G: 50, B: 50, A: 255})
+
+	t.Run("Gray", func(t *testing.T) {
+		dst := "testdata/red.gray.jpg"
+
+		defer os.Remove(dst)
+
+		result, err := Create(img, dst, 50, 50, ResampleFillCenter, ResampleDefault, ResampleGray)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, g, b, _ := result.At(25, 25).RGBA()
+
+		assert.Equal(t, r, g)
+		assert.Equal(t, g, b)
+		assert.FileExists(t, dst)
+	})
+	t.Run("Color", func(t *testing.T) {
+		dst := "testdata/red.color.jpg"
+
+		defer os.Remove(dst)
+
+		result, err := Create(img, dst, 50, 50, ResampleFillCenter, ResampleDefault)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, g, _, _ := result.At(25, 25).RGBA()
+
+		assert.Greater(t, r, g)
+	})
+}
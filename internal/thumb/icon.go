@@ -0,0 +1,321 @@
+package thumb
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/vector"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// IconFormat is the format of placeholder icons, e.g. for files that cannot be displayed. Icons may be served
+// as PNG instead of SVG if SVG images are blocked by a strict Content-Security-Policy, see IconPng.
+var IconFormat = fs.VectorSVG
+
+// IconSize is the width and height of icons rendered as PNG.
+var IconSize = 96
+
+var (
+	iconPngCache  sync.Map
+	iconSvgRegexp = regexp.MustCompile(`<svg\b[^>]*>`)
+	iconElRegexp  = regexp.MustCompile(`<(path|circle)\b([^>]*)>`)
+	iconAttRegexp = regexp.MustCompile(`([\w-]+)="([^"]*)"`)
+	iconCmdRegexp = regexp.MustCompile(`[A-Za-z]|[-+]?(?:\d*\.\d+|\d+\.?)(?:[eE][-+]?\d+)?`)
+)
+
+// iconCmdArgs maps the supported path commands to their number of arguments.
+var iconCmdArgs = map[byte]int{'m': 2, 'l': 2, 'h': 1, 'v': 1, 'c': 6, 's': 4, 'q': 4, 't': 2}
+
+// IconPng returns the SVG icon rendered as PNG image, so that it can be displayed if SVG images are blocked.
+// The result is cached, as icons are static.
+func IconPng(svg []byte) ([]byte, error) {
+	key := string(svg)
+
+	if data, ok := iconPngCache.Load(key); ok {
+		return data.([]byte), nil
+	}
+
+	img, err := RenderIcon(svg, IconSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	iconPngCache.Store(key, buf.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// RenderIcon renders the SVG icon with the specified width and height. Only the subset of SVG that icons
+// typically use is supported, i.e. paths without arcs and circles that are filled with a single color.
+func RenderIcon(svg []byte, size int) (*image.RGBA, error) {
+	root := iconSvgRegexp.Find(svg)
+
+	if root == nil {
+		return nil, fmt.Errorf("invalid svg icon")
+	}
+
+	attr := iconAttrs(string(root))
+	viewBox := []float64{0, 0, 24, 24}
+
+	if v := iconFloats(attr["viewBox"]); len(v) == 4 && v[2] > 0 && v[3] > 0 {
+		viewBox = v
+	} else if w, h := iconFloats(attr["width"]), iconFloats(attr["height"]); len(w) == 1 && len(h) == 1 && w[0] > 0 && h[0] > 0 {
+		viewBox = []float64{0, 0, w[0], h[0]}
+	}
+
+	fill := iconColor(attr["fill"], color.RGBA{A: 255})
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	p := iconPath{
+		sx: float64(size) / viewBox[2],
+		sy: float64(size) / viewBox[3],
+		tx: -viewBox[0],
+		ty: -viewBox[1],
+	}
+
+	for _, el := range iconElRegexp.FindAllSubmatch(svg, -1) {
+		elAttr := iconAttrs(string(el[2]))
+
+		if elAttr["fill"] == "none" {
+			continue
+		}
+
+		p.z = vector.NewRasterizer(size, size)
+
+		switch string(el[1]) {
+		case "circle":
+			c := iconFloats(elAttr["cx"] + " " + elAttr["cy"] + " " + elAttr["r"])
+
+			if len(c) != 3 {
+				return nil, fmt.Errorf("invalid circle in svg icon")
+			}
+
+			p.circle(c[0], c[1], c[2])
+		default:
+			if err := p.draw(elAttr["d"]); err != nil {
+				return nil, err
+			}
+		}
+
+		p.z.Draw(img, img.Bounds(), image.NewUniform(iconColor(elAttr["fill"], fill)), image.Point{})
+	}
+
+	return img, nil
+}
+
+// iconAttrs returns the attributes of an SVG element.
+func iconAttrs(s string) map[string]string {
+	result := make(map[string]string)
+
+	for _, m := range iconAttRegexp.FindAllStringSubmatch(s, -1) {
+		result[m[1]] = m[2]
+	}
+
+	return result
+}
+
+// iconFloats returns the numbers in an attribute value, ignoring "px" units.
+func iconFloats(s string) (result []float64) {
+	for _, v := range strings.Fields(strings.NewReplacer(",", " ", "px", "").Replace(s)) {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+// iconColor returns the color of a "#RRGGBB" fill value, or the default color otherwise.
+func iconColor(s string, defaultColor color.RGBA) color.RGBA {
+	if len(s) != 7 || s[0] != '#' {
+		return defaultColor
+	}
+
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+
+	if err != nil {
+		return defaultColor
+	}
+
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+}
+
+// iconPath draws SVG path data with a rasterizer, scaled from the view box to the image size.
+type iconPath struct {
+	z              *vector.Rasterizer
+	sx, sy, tx, ty float64
+}
+
+func (p *iconPath) pt(x, y float64) (float32, float32) {
+	return float32((x + p.tx) * p.sx), float32((y + p.ty) * p.sy)
+}
+
+func (p *iconPath) moveTo(x, y float64) {
+	p.z.MoveTo(p.pt(x, y))
+}
+
+func (p *iconPath) lineTo(x, y float64) {
+	p.z.LineTo(p.pt(x, y))
+}
+
+func (p *iconPath) quadTo(x1, y1, x, y float64) {
+	bx, by := p.pt(x1, y1)
+	cx, cy := p.pt(x, y)
+	p.z.QuadTo(bx, by, cx, cy)
+}
+
+func (p *iconPath) cubeTo(x1, y1, x2, y2, x, y float64) {
+	bx, by := p.pt(x1, y1)
+	cx, cy := p.pt(x2, y2)
+	dx, dy := p.pt(x, y)
+	p.z.CubeTo(bx, by, cx, cy, dx, dy)
+}
+
+// circle draws a circle with four cubic Bézier curves.
+func (p *iconPath) circle(cx, cy, r float64) {
+	k := r * 0.5522847498
+
+	p.moveTo(cx+r, cy)
+	p.cubeTo(cx+r, cy+k, cx+k, cy+r, cx, cy+r)
+	p.cubeTo(cx-k, cy+r, cx-r, cy+k, cx-r, cy)
+	p.cubeTo(cx-r, cy-k, cx-k, cy-r, cx, cy-r)
+	p.cubeTo(cx+k, cy-r, cx+r, cy-k, cx+r, cy)
+	p.z.ClosePath()
+}
+
+// draw draws SVG path data, see https://www.w3.org/TR/SVG/paths.html#PathData.
+func (p *iconPath) draw(d string) error {
+	tokens := iconCmdRegexp.FindAllString(d, -1)
+
+	var cmd byte
+	var x, y, startX, startY, ctrlX, ctrlY float64
+	var open bool
+	var prev byte
+
+	for i := 0; i < len(tokens); {
+		if c := tokens[i][0]; c >= 'A' && c <= 'z' && (c <= 'Z' || c >= 'a') {
+			cmd = c
+			i++
+
+			if cmd == 'z' || cmd == 'Z' {
+				if open {
+					p.z.ClosePath()
+					open = false
+				}
+
+				x, y = startX, startY
+				prev = cmd
+				continue
+			}
+		} else if cmd == 0 {
+			return fmt.Errorf("invalid path data in svg icon")
+		}
+
+		n := iconCmdArgs[cmd|0x20]
+
+		if n == 0 {
+			return fmt.Errorf("unsupported path command %c in svg icon", cmd)
+		} else if i+n > len(tokens) {
+			return fmt.Errorf("invalid path data in svg icon")
+		}
+
+		v := make([]float64, n)
+
+		for j := range v {
+			f, err := strconv.ParseFloat(tokens[i+j], 64)
+
+			if err != nil {
+				return fmt.Errorf("invalid path data in svg icon")
+			}
+
+			v[j] = f
+		}
+
+		i += n
+
+		// Relative commands are based on the current point.
+		rel := cmd >= 'a'
+		ox, oy := 0.0, 0.0
+
+		if rel {
+			ox, oy = x, y
+		}
+
+		// Control points of smooth curves are reflected if the previous command was of the same kind.
+		reflect := func(kinds string) (float64, float64) {
+			if strings.IndexByte(kinds, prev|0x20) >= 0 {
+				return 2*x - ctrlX, 2*y - ctrlY
+			}
+
+			return x, y
+		}
+
+		switch cmd | 0x20 {
+		case 'm':
+			if open {
+				p.z.ClosePath()
+			}
+
+			x, y = ox+v[0], oy+v[1]
+			startX, startY = x, y
+			p.moveTo(x, y)
+			open = true
+
+			// Subsequent pairs of coordinates are implicit line commands.
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'l':
+			x, y = ox+v[0], oy+v[1]
+			p.lineTo(x, y)
+		case 'h':
+			x = ox + v[0]
+			p.lineTo(x, y)
+		case 'v':
+			y = oy + v[0]
+			p.lineTo(x, y)
+		case 'c':
+			ctrlX, ctrlY = ox+v[2], oy+v[3]
+			p.cubeTo(ox+v[0], oy+v[1], ctrlX, ctrlY, ox+v[4], oy+v[5])
+			x, y = ox+v[4], oy+v[5]
+		case 's':
+			x1, y1 := reflect("cs")
+			ctrlX, ctrlY = ox+v[0], oy+v[1]
+			p.cubeTo(x1, y1, ctrlX, ctrlY, ox+v[2], oy+v[3])
+			x, y = ox+v[2], oy+v[3]
+		case 'q':
+			ctrlX, ctrlY = ox+v[0], oy+v[1]
+			p.quadTo(ctrlX, ctrlY, ox+v[2], oy+v[3])
+			x, y = ox+v[2], oy+v[3]
+		case 't':
+			ctrlX, ctrlY = reflect("qt")
+			p.quadTo(ctrlX, ctrlY, ox+v[0], oy+v[1])
+			x, y = ox+v[0], oy+v[1]
+		}
+
+		prev = cmd
+	}
+
+	if open {
+		p.z.ClosePath()
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package thumb
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderIcon(t *testing.T) {
+	t.Run("Square", func(t *testing.T) {
+		img, err := RenderIcon([]byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" fill="#FF0000"><path d="M4 4h16v16H4z"/></svg>`), 48)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 48, img.Bounds().Dx())
+		assert.Equal(t, color.RGBA{R: 255, A: 255}, img.RGBAAt(24, 24))
+		assert.Equal(t, color.RGBA{}, img.RGBAAt(2, 2))
+	})
+	t.Run("Hole", func(t *testing.T) {
+		img, err := RenderIcon([]byte(`<svg width="24" height="24"><path d="M2 2h20v20H2z M6 6v12h12V6z"/><path d="M0 0h24v24H0z" fill="none"/></svg>`), 24)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, color.RGBA{A: 255}, img.RGBAAt(3, 12))
+		assert.Equal(t, color.RGBA{}, img.RGBAAt(12, 12))
+		assert.Equal(t, color.RGBA{}, img.RGBAAt(0, 0))
+	})
+	t.Run("CurvesAndCircle", func(t *testing.T) {
+		img, err := RenderIcon([]byte(`<svg viewBox="0 0 24 24" fill="#27282A"><circle cx="12" cy="12" r="3.2"/>
+<path d="M9 2L7.17 4H4c-1.1 0-2 .9-2 2v12c0 1.1.9 2 2 2h16s1 0 1-1q0-1 0-2t1-1Z"/></svg>`), 24)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, color.RGBA{R: 0x27, G: 0x28, B: 0x2A, A: 255}, img.RGBAAt(12, 12))
+		assert.Equal(t, color.RGBA{}, img.RGBAAt(23, 0))
+	})
+	t.Run("Arc", func(t *testing.T) {
+		_, err := RenderIcon([]byte(`<svg viewBox="0 0 24 24"><path d="M2 2a4 4 0 1 0 8 0z"/></svg>`), 24)
+		assert.Error(t, err)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := RenderIcon([]byte(`<path d="M2 2h4v4z"/>`), 24)
+		assert.Error(t, err)
+	})
+}
+
+func TestIconPng(t *testing.T) {
+	svg := []byte(`<svg viewBox="0 0 24 24" fill="#27282A"><path d="M4 4h16v16H4z"/></svg>`)
+
+	data, err := IconPng(svg)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, IconSize, img.Bounds().Dx())
+
+	cached, err := IconPng(svg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, data, cached)
+}
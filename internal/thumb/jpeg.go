@@ -27,16 +27,16 @@ func Jpeg(srcFile, jpgFile string, orientation int) (img image.Image, err error)
 		return img, err
 	}
 
+	// Convert the colors of print sources to sRGB.
+	img = FromCMYK(img, clean.Log(filepath.Base(srcFile)))
+
 	// Adjust orientation.
 	if orientation > 1 {
 		img = Rotate(img, orientation)
 	}
 
-	// Get JPEG quality setting.
-	quality := JpegQuality.EncodeOption()
-
 	// Save JPEG file.
-	if err = imaging.Save(img, jpgFile, quality); err != nil {
+	if err = SaveJpeg(img, jpgFile, JpegQuality); err != nil {
 		log.Errorf("jpeg: failed to save %s", clean.Log(filepath.Base(jpgFile)))
 		return img, err
 	}
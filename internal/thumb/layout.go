@@ -0,0 +1,167 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Thumbnail storage layouts.
+const (
+	LayoutCentral = "central"
+	LayoutSidecar = "sidecar"
+)
+
+var (
+	Layout        = LayoutCentral
+	SidecarFolder = ".thumbs"
+)
+
+// Sharding contains the number of hash characters used for each subfolder level of the central cache,
+// e.g. 1,1,1 for "a/b/c" or 2,2 for "ab/cd", so that folders don't contain too many files.
+var Sharding = DefaultSharding
+
+// DefaultSharding is the default subfolder scheme of the central cache.
+var DefaultSharding = []int{1, 1, 1}
+
+// MaxShardingChars is the maximum total number of hash characters used for subfolders.
+const MaxShardingChars = 8
+
+// ParseSharding parses a comma-separated subfolder scheme, e.g. "2,2", see Sharding.
+func ParseSharding(s string) (result []int, err error) {
+	total := 0
+
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(v)
+
+		if err != nil || n < 1 {
+			return DefaultSharding, fmt.Errorf("thumb: invalid subfolder scheme %s", s)
+		}
+
+		total += n
+		result = append(result, n)
+	}
+
+	if len(result) == 0 || total > MaxShardingChars {
+		return DefaultSharding, fmt.Errorf("thumb: invalid subfolder scheme %s", s)
+	}
+
+	return result, nil
+}
+
+// ShardingString returns the subfolder scheme as comma-separated string.
+func ShardingString(sharding []int) string {
+	values := make([]string, len(sharding))
+
+	for i, n := range sharding {
+		values[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// ShardDir returns the folder for the specified hash and subfolder scheme.
+func ShardDir(hash, thumbPath string, sharding []int) string {
+	elem := make([]string, 0, len(sharding)+1)
+	elem = append(elem, thumbPath)
+
+	offset := 0
+
+	for _, n := range sharding {
+		if offset+n > len(hash) {
+			break
+		}
+
+		elem = append(elem, hash[offset:offset+n])
+		offset += n
+	}
+
+	return path.Join(elem...)
+}
+
+// Sidecar checks if thumbnails are stored in hidden folders next to the originals.
+func Sidecar() bool {
+	return Layout == LayoutSidecar
+}
+
+// Path returns the thumbnail folder for the specified image file, which is the central
+// cache path by default, or a hidden subfolder next to the file if the sidecar layout is used.
+func Path(cachePath, imageFilename string) string {
+	if !Sidecar() || imageFilename == "" || IsRemote(imageFilename) {
+		return cachePath
+	}
+
+	return filepath.Join(filepath.Dir(imageFilename), SidecarFolder)
+}
+
+// Dir returns the folder that contains the thumbnails of the file with the specified hash.
+// Central cache paths are divided into subfolders based on the hash, while sidecar folders
+// contain the thumbnails directly.
+func Dir(hash, thumbPath string) string {
+	if Sidecar() && filepath.Base(thumbPath) == SidecarFolder {
+		return thumbPath
+	}
+
+	return ShardDir(hash, thumbPath, Sharding)
+}
+
+// WalkFunc is called for each thumbnail file found by Walk. Walking stops if it returns an error.
+type WalkFunc func(fileName string, d os.DirEntry) error
+
+// Walk calls fn for each file in the central cache folder except cached remote originals and, if the sidecar
+// layout is used, for each file in the hidden sidecar folders below the originals folder, see Path.
+// If fn returns ErrStopWalk, no more files are visited and nil is returned.
+func Walk(cachePath, originalsPath string, fn WalkFunc) error {
+	if err := walk(cachePath, originalsPath, fn); err != ErrStopWalk {
+		return err
+	}
+
+	return nil
+}
+
+// walk visits the files like Walk, but returns ErrStopWalk if it was returned by fn.
+func walk(cachePath, originalsPath string, fn WalkFunc) error {
+	remotePath := filepath.Join(cachePath, "remote")
+
+	err := filepath.WalkDir(cachePath, func(fileName string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		} else if d.IsDir() {
+			if fileName == remotePath {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		return fn(fileName, d)
+	})
+
+	if err != nil || !Sidecar() || originalsPath == "" {
+		return err
+	}
+
+	return filepath.WalkDir(originalsPath, func(fileName string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		} else if d.IsDir() {
+			// Other hidden folders do not contain thumbnails.
+			if fileName != originalsPath && strings.HasPrefix(d.Name(), ".") && d.Name() != SidecarFolder {
+				return filepath.SkipDir
+			}
+
+			return nil
+		} else if filepath.Base(filepath.Dir(fileName)) != SidecarFolder {
+			return nil
+		}
+
+		return fn(fileName, d)
+	})
+}
@@ -0,0 +1,145 @@
+package thumb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath(t *testing.T) {
+	t.Run("Central", func(t *testing.T) {
+		assert.Equal(t, "/cache/thumbnails", Path("/cache/thumbnails", "/photos/2020/IMG_1234.jpg"))
+	})
+	t.Run("Sidecar", func(t *testing.T) {
+		Layout = LayoutSidecar
+		defer func() { Layout = LayoutCentral }()
+
+		assert.Equal(t, "/photos/2020/.thumbs", Path("/cache/thumbnails", "/photos/2020/IMG_1234.jpg"))
+		assert.Equal(t, "/cache/thumbnails", Path("/cache/thumbnails", "https://example.com/IMG_1234.jpg"))
+		assert.Equal(t, "/cache/thumbnails", Path("/cache/thumbnails", ""))
+	})
+}
+
+func TestDir(t *testing.T) {
+	hash := "ca1ea0b33b3d2fcf8bb732e51e9e4f4d7e1f5a2b"
+
+	t.Run("Central", func(t *testing.T) {
+		assert.Equal(t, "/cache/thumbnails/c/a/1", Dir(hash, "/cache/thumbnails"))
+		assert.Equal(t, "/photos/.thumbs/c/a/1", Dir(hash, "/photos/.thumbs"))
+	})
+	t.Run("Sidecar", func(t *testing.T) {
+		Layout = LayoutSidecar
+		defer func() { Layout = LayoutCentral }()
+
+		assert.Equal(t, "/cache/thumbnails/c/a/1", Dir(hash, "/cache/thumbnails"))
+		assert.Equal(t, "/photos/.thumbs", Dir(hash, "/photos/.thumbs"))
+	})
+}
+
+func TestFileName_Sidecar(t *testing.T) {
+	Layout = LayoutSidecar
+	defer func() { Layout = LayoutCentral }()
+
+	originalsPath := t.TempDir()
+	thumbPath := Path("/cache/thumbnails", filepath.Join(originalsPath, "IMG_1234.jpg"))
+
+	fileName, err := Sizes[Tile224].FileName("ca1ea0b33b3d2fcf8bb732e51e9e4f4d7e1f5a2b", thumbPath)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, filepath.Join(originalsPath, ".thumbs", "ca1ea0b33b3d2fcf8bb732e51e9e4f4d7e1f5a2b_224x224_center.jpg"), fileName)
+
+	if _, err = os.Stat(thumbPath); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	cachePath := t.TempDir()
+	originalsPath := t.TempDir()
+
+	files := []string{
+		filepath.Join(cachePath, "c", "a", "1", "ca1_224x224_center.jpg"),
+		filepath.Join(cachePath, "remote", "ca1.jpg"),
+		filepath.Join(originalsPath, "2020", ".thumbs", "ca2_224x224_center.jpg"),
+		filepath.Join(originalsPath, "2020", "IMG_224x224_center.jpg"),
+		filepath.Join(originalsPath, ".photoprism", "ca3_224x224_center.jpg"),
+	}
+
+	for _, fileName := range files {
+		if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+			t.Fatal(err)
+		} else if err = os.WriteFile(fileName, []byte("test"), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	walk := func() (found []string) {
+		err := Walk(cachePath, originalsPath, func(fileName string, d os.DirEntry) error {
+			found = append(found, fileName)
+			return nil
+		})
+
+		assert.NoError(t, err)
+
+		return found
+	}
+
+	t.Run("Central", func(t *testing.T) {
+		assert.Equal(t, []string{files[0]}, walk())
+	})
+	t.Run("Sidecar", func(t *testing.T) {
+		Layout = LayoutSidecar
+		defer func() { Layout = LayoutCentral }()
+
+		assert.Equal(t, []string{files[0], files[2]}, walk())
+	})
+	t.Run("Stop", func(t *testing.T) {
+		Layout = LayoutSidecar
+		defer func() { Layout = LayoutCentral }()
+
+		var found []string
+
+		err := Walk(cachePath, originalsPath, func(fileName string, d os.DirEntry) error {
+			found = append(found, fileName)
+			return ErrStopWalk
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{files[0]}, found)
+	})
+}
+
+func TestParseSharding(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		result, err := ParseSharding("1,1,1")
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 1, 1}, result)
+	})
+	t.Run("TwoLevels", func(t *testing.T) {
+		result, err := ParseSharding(" 2, 2 ")
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 2}, result)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		result, err := ParseSharding("2,x")
+		assert.Error(t, err)
+		assert.Equal(t, DefaultSharding, result)
+	})
+	t.Run("TooLong", func(t *testing.T) {
+		result, err := ParseSharding("4,4,4")
+		assert.Error(t, err)
+		assert.Equal(t, DefaultSharding, result)
+	})
+}
+
+func TestShardDir(t *testing.T) {
+	assert.Equal(t, "/cache/a/b/c", ShardDir("abcdef", "/cache", []int{1, 1, 1}))
+	assert.Equal(t, "/cache/ab/cd", ShardDir("abcdef", "/cache", []int{2, 2}))
+	assert.Equal(t, "/cache/ab", ShardDir("abc", "/cache", []int{2, 2}))
+	assert.Equal(t, "2,2", ShardingString([]int{2, 2}))
+}
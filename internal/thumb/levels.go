@@ -0,0 +1,114 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+var (
+	LevelsSizes          = make(map[Name]bool)
+	LevelsMaxGain        = 1.5
+	LevelsClip           = 0.005
+	LevelsMinRange uint8 = 232
+)
+
+// levelsAnalysisSize is the maximum size in pixels of the image copy used to find the brightness range.
+const levelsAnalysisSize = 256
+
+// UseLevels checks if auto levels should be applied to thumbnails with the specified dimensions and options.
+func UseLevels(width, height int, opts ...ResampleOption) bool {
+	if len(LevelsSizes) == 0 {
+		return false
+	}
+
+	method, _, format := ResampleOptions(opts...)
+
+	for name := range LevelsSizes {
+		if s, ok := Sizes[name]; !ok || s.Width != width || s.Height != height {
+			continue
+		} else if m, _, f := ResampleOptions(s.Options...); m == method && f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Levels conservatively stretches the brightness range of flat images. Images that already use
+// most of the range are returned unchanged, and the contrast gain is limited by LevelsMaxGain.
+func Levels(img image.Image) image.Image {
+	low, high, ok := levelsRange(img)
+
+	if !ok || high-low >= LevelsMinRange {
+		return img
+	}
+
+	gain := math.Min(255/float64(high-low), LevelsMaxGain)
+
+	// Keep the midpoint of the used range in place if the gain is limited.
+	mid := (float64(low) + float64(high)) / 2
+	offset := 127.5 - mid*gain
+
+	if gain == 255/float64(high-low) {
+		offset = -float64(low) * gain
+	}
+
+	return imaging.AdjustFunc(img, func(c color.NRGBA) color.NRGBA {
+		return color.NRGBA{
+			R: levelsValue(c.R, gain, offset),
+			G: levelsValue(c.G, gain, offset),
+			B: levelsValue(c.B, gain, offset),
+			A: c.A,
+		}
+	})
+}
+
+// levelsValue returns the adjusted channel value.
+func levelsValue(v uint8, gain, offset float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(float64(v)*gain+offset))))
+}
+
+// levelsRange returns the brightness range of an image, ignoring the darkest and brightest pixels.
+func levelsRange(img image.Image) (low, high uint8, ok bool) {
+	b := img.Bounds()
+
+	if b.Dx() < 1 || b.Dy() < 1 {
+		return 0, 255, false
+	}
+
+	small := img
+
+	if b.Dx() > levelsAnalysisSize || b.Dy() > levelsAnalysisSize {
+		small = imaging.Fit(img, levelsAnalysisSize, levelsAnalysisSize, imaging.Box)
+	}
+
+	gray := imaging.Grayscale(small)
+
+	var hist [256]int
+
+	for i := 0; i < len(gray.Pix); i += 4 {
+		hist[gray.Pix[i]]++
+	}
+
+	n := len(gray.Pix) / 4
+	clip := int(float64(n) * LevelsClip)
+
+	for sum, i := 0, 0; i < 256; i++ {
+		if sum += hist[i]; sum > clip {
+			low = uint8(i)
+			break
+		}
+	}
+
+	for sum, i := 0, 255; i >= 0; i-- {
+		if sum += hist[i]; sum > clip {
+			high = uint8(i)
+			break
+		}
+	}
+
+	return low, high, high > low
+}
@@ -0,0 +1,65 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// testFlat returns a low contrast gradient with brightness values from 100 to 150.
+func testFlat() *image.NRGBA {
+	img := imaging.New(100, 100, color.White)
+
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			v := uint8(100 + x/2)
+			img.Set(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+
+	return img
+}
+
+func TestLevels(t *testing.T) {
+	t.Run("Flat", func(t *testing.T) {
+		result := Levels(testFlat())
+
+		r0, _, _, _ := result.At(0, 0).RGBA()
+		r1, _, _, _ := result.At(99, 0).RGBA()
+
+		// Contrast is increased, but limited by the maximum gain.
+		assert.Less(t, r0>>8, uint32(100))
+		assert.Greater(t, r1>>8, uint32(150))
+		assert.InDelta(t, 75, float64(r1>>8)-float64(r0>>8), 2)
+	})
+	t.Run("WellExposed", func(t *testing.T) {
+		img := imaging.New(256, 10, color.White)
+
+		for x := 0; x < 256; x++ {
+			for y := 0; y < 10; y++ {
+				img.Set(x, y, color.Gray{Y: uint8(x)})
+			}
+		}
+
+		assert.Equal(t, image.Image(img), Levels(img))
+	})
+	t.Run("Uniform", func(t *testing.T) {
+		img := imaging.New(10, 10, color.Gray{Y: 128})
+
+		assert.Equal(t, image.Image(img), Levels(img))
+	})
+}
+
+func TestUseLevels(t *testing.T) {
+	assert.False(t, UseLevels(500, 500, ResampleFillCenter, ResampleDefault))
+
+	LevelsSizes = map[Name]bool{Tile500: true}
+	defer func() { LevelsSizes = make(map[Name]bool) }()
+
+	assert.True(t, UseLevels(500, 500, ResampleFillCenter, ResampleDefault))
+	assert.False(t, UseLevels(500, 500, ResampleFit, ResampleDefault))
+	assert.False(t, UseLevels(720, 720, ResampleFit, ResampleDefault))
+}
@@ -0,0 +1,241 @@
+package thumb
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// OverlayMap is the overlay type that shows a small map of the photo location.
+const OverlayMap = "map"
+
+// Map tile settings, the inset is skipped if MapTileUrl is empty.
+var (
+	MapTileUrl   = "https://tile.openstreetmap.org/{z}/{x}/{y}.png"
+	MapZoom      = 12
+	MapTimeout   = 10 * time.Second
+	MapTileSize  = 256
+	MapTileLimit = int64(1024 * 1024)
+	MapUserAgent = "PhotoPrism"
+)
+
+// mapTileFetches prevents the same map tile from being downloaded concurrently.
+var mapTileFetches singleflight.Group
+
+var (
+	mapBorder = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	mapMarker = color.NRGBA{R: 220, G: 40, B: 40, A: 255}
+)
+
+// MapName returns the file name of a thumbnail with a map inset of the location.
+func MapName(thumbName string, lat, lng float64) string {
+	ext := filepath.Ext(thumbName)
+	key := fmt.Sprintf("%.6f,%.6f,%d,%s", lat, lng, MapZoom, MapTileUrl)
+
+	return fmt.Sprintf("%s_map%08x%s", strings.TrimSuffix(thumbName, ext), crc32.ChecksumIEEE([]byte(key)), ext)
+}
+
+// FromMap returns the file name of the thumbnail with a map inset of the location, and creates it if needed.
+// Map tiles are cached in the specified path, and the thumbnail itself remains unchanged.
+func FromMap(thumbName string, lat, lng float64, cachePath string) (fileName string, err error) {
+	if MapTileUrl == "" {
+		return thumbName, nil
+	}
+
+	fileName = MapName(thumbName, lat, lng)
+
+	if fs.FileExists(fileName) {
+		return fileName, nil
+	}
+
+	inset, err := MapInset(lat, lng, cachePath)
+
+	if err != nil {
+		return "", err
+	}
+
+	img, err := imaging.Open(thumbName)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err = SaveJpeg(DrawMapInset(img, inset), fileName, JpegQuality); err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
+
+// DrawMapInset draws the map with a white border into the bottom right corner of the image.
+func DrawMapInset(img image.Image, inset image.Image) *image.NRGBA {
+	dst := imaging.Clone(img)
+	bounds := dst.Bounds()
+
+	// Scale the inset with the image size.
+	side := int(math.Max(48, math.Min(float64(bounds.Dx()), float64(bounds.Dy()))/4))
+	pad := int(math.Max(2, float64(side)/24))
+
+	area := image.Rect(bounds.Dx()-side-2*pad, bounds.Dy()-side-2*pad, bounds.Dx()-pad, bounds.Dy()-pad).Add(bounds.Min).Intersect(bounds)
+
+	if area.Empty() {
+		return dst
+	}
+
+	draw.Draw(dst, area, image.NewUniform(mapBorder), image.Point{}, draw.Src)
+	dst = imaging.Paste(dst, imaging.Resize(inset, area.Dx()-2*pad, area.Dy()-2*pad, imaging.Lanczos), area.Min.Add(image.Pt(pad, pad)))
+
+	return dst
+}
+
+// MapInset returns a map of the location with a marker in the center, composed of the tiles around it.
+func MapInset(lat, lng float64, cachePath string) (*image.NRGBA, error) {
+	if lat < -85.05 || lat > 85.05 || lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("thumb: invalid map location %.6f,%.6f", lat, lng)
+	}
+
+	size := MapTileSize
+	n := 1 << uint(MapZoom)
+
+	// Global pixel position of the location, see https://wiki.openstreetmap.org/wiki/Slippy_map_tilenames.
+	latRad := lat * math.Pi / 180
+	gx := int((lng + 180) / 360 * float64(n*size))
+	gy := int((1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * float64(n*size))
+
+	view := image.Rect(gx-size/2, gy-size/2, gx+size/2, gy+size/2)
+	dst := imaging.New(size, size, color.NRGBA{R: 230, G: 230, B: 230, A: 255})
+
+	for ty := floorDiv(view.Min.Y, size); ty <= floorDiv(view.Max.Y-1, size); ty++ {
+		if ty < 0 || ty >= n {
+			continue
+		}
+
+		for tx := floorDiv(view.Min.X, size); tx <= floorDiv(view.Max.X-1, size); tx++ {
+			tile, err := MapTile(MapZoom, ((tx%n)+n)%n, ty, cachePath)
+
+			if err != nil {
+				return nil, err
+			}
+
+			dst = imaging.Paste(dst, imaging.Resize(tile, size, size, imaging.Lanczos), image.Pt(tx*size-view.Min.X, ty*size-view.Min.Y))
+		}
+	}
+
+	// Mark the location in the center.
+	cx, cy := float64(size)/2, float64(size)/2
+	radius := float64(size) / 24
+
+	fillPixels(dst, image.Rect(int(cx-radius*1.5), int(cy-radius*1.5), int(cx+radius*1.5)+1, int(cy+radius*1.5)+1), mapBorder, func(x, y float64) bool {
+		return math.Hypot(x-cx, y-cy) <= radius*1.4
+	})
+
+	fillPixels(dst, image.Rect(int(cx-radius), int(cy-radius), int(cx+radius)+1, int(cy+radius)+1), mapMarker, func(x, y float64) bool {
+		return math.Hypot(x-cx, y-cy) <= radius
+	})
+
+	return dst, nil
+}
+
+// MapTileName returns the cache file name of a map tile.
+func MapTileName(zoom, x, y int, cachePath string) string {
+	key := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(MapTileUrl)))
+
+	return filepath.Join(cachePath, "maps", key, strconv.Itoa(zoom), strconv.Itoa(x), strconv.Itoa(y)+".png")
+}
+
+// MapTile returns a map tile from the cache, and downloads it if needed.
+func MapTile(zoom, x, y int, cachePath string) (image.Image, error) {
+	fileName := MapTileName(zoom, x, y, cachePath)
+
+	if !fs.FileExists(fileName) {
+		if _, err, _ := mapTileFetches.Do(fileName, func() (interface{}, error) {
+			return nil, downloadMapTile(zoom, x, y, fileName)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return imaging.Open(fileName)
+}
+
+// downloadMapTile downloads a map tile, so that it can be reused for other photos nearby.
+func downloadMapTile(zoom, x, y int, fileName string) error {
+	if fs.FileExists(fileName) {
+		return nil
+	}
+
+	tileUrl := strings.NewReplacer(
+		"{z}", strconv.Itoa(zoom),
+		"{x}", strconv.Itoa(x),
+		"{y}", strconv.Itoa(y),
+	).Replace(MapTileUrl)
+
+	req, err := http.NewRequest(http.MethodGet, tileUrl, nil)
+
+	if err != nil {
+		return fmt.Errorf("thumb: %s", clean.Error(err))
+	}
+
+	// Tile servers usually require a user agent that identifies the application.
+	req.Header.Set("User-Agent", MapUserAgent)
+
+	client := &http.Client{Timeout: MapTimeout}
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return fmt.Errorf("thumb: %s", clean.Error(err))
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("thumb: map tile server returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MapTileLimit+1))
+
+	if err != nil {
+		return fmt.Errorf("thumb: %s while downloading map tile", clean.Error(err))
+	} else if int64(len(data)) > MapTileLimit {
+		return fmt.Errorf("thumb: map tile exceeds size limit")
+	}
+
+	// Store decoded tiles as PNG, so that the cache does not contain invalid files.
+	tile, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return fmt.Errorf("thumb: %s while decoding map tile", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(fileName), fs.ModeDir); err != nil {
+		return err
+	}
+
+	return imaging.Save(tile, fileName)
+}
+
+// floorDiv returns the quotient rounded down, also for negative numbers.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+
+	return a / b
+}
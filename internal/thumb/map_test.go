@@ -0,0 +1,104 @@
+package thumb
+
+import (
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapName(t *testing.T) {
+	name := MapName("testdata/example_fit_720.jpg", 52.52, 13.405)
+
+	assert.Regexp(t, `^testdata/example_fit_720_map[0-9a-f]{8}\.jpg$`, name)
+	assert.NotEqual(t, name, MapName("testdata/example_fit_720.jpg", 48.137, 11.575))
+}
+
+func TestFromMap(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "image/png")
+		_ = png.Encode(w, imaging.New(256, 256, color.NRGBA{R: 100, G: 200, B: 100, A: 255}))
+	}))
+
+	defer server.Close()
+
+	defer func(u string) { MapTileUrl = u }(MapTileUrl)
+
+	cachePath, err := os.MkdirTemp("", "thumb-map")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(cachePath)
+
+	thumbName := cachePath + "/example.jpg"
+
+	if err = SaveJpeg(imaging.New(720, 480, color.White), thumbName, JpegQuality); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		MapTileUrl = ""
+
+		fileName, err := FromMap(thumbName, 52.52, 13.405, cachePath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, thumbName, fileName)
+	})
+	t.Run("Success", func(t *testing.T) {
+		MapTileUrl = server.URL + "/{z}/{x}/{y}.png"
+
+		fileName, err := FromMap(thumbName, 52.52, 13.405, cachePath)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, MapName(thumbName, 52.52, 13.405), fileName)
+
+		img, err := imaging.Open(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 720, img.Bounds().Dx())
+
+		// The inset is drawn into the bottom right corner.
+		r, g, b, _ := img.At(600, 440).RGBA()
+		assert.Greater(t, g>>8, r>>8)
+		assert.Greater(t, g>>8, b>>8)
+
+		// Tiles are cached.
+		n := atomic.LoadInt32(&requests)
+		assert.Greater(t, n, int32(0))
+
+		_, err = MapInset(52.52, 13.405, cachePath)
+		assert.NoError(t, err)
+		assert.Equal(t, n, atomic.LoadInt32(&requests))
+	})
+	t.Run("InvalidLocation", func(t *testing.T) {
+		MapTileUrl = server.URL + "/{z}/{x}/{y}.png"
+
+		_, err := FromMap(thumbName, 90, 0, cachePath)
+		assert.Error(t, err)
+	})
+}
+
+func TestFloorDiv(t *testing.T) {
+	assert.Equal(t, 1, floorDiv(256, 256))
+	assert.Equal(t, 0, floorDiv(255, 256))
+	assert.Equal(t, -1, floorDiv(-1, 256))
+	assert.Equal(t, -1, floorDiv(-256, 256))
+	assert.Equal(t, -2, floorDiv(-257, 256))
+}
@@ -0,0 +1,36 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// MigrateLegacy enables renaming cached thumbnails that use a legacy naming scheme when they are requested.
+var MigrateLegacy = false
+
+// LegacyFileName returns the file name of the thumbnail for the matching size as used by previous versions,
+// which stored all thumbnails directly in the cache folder without dividing them into subfolders.
+func LegacyFileName(hash, thumbPath string, width, height int, opts ...ResampleOption) string {
+	return fmt.Sprintf("%s/%s_%s", thumbPath, hash, Suffix(width, height, opts...))
+}
+
+// migrateLegacy renames an existing thumbnail with the legacy file name, so that it does not need to be created again.
+// It returns false if there is no such thumbnail or if it could not be renamed.
+func migrateLegacy(fileName, legacyName string) bool {
+	if fileName == legacyName || fs.FileExists(fileName) || !fs.FileExists(legacyName) {
+		return false
+	}
+
+	if err := os.Rename(legacyName, fileName); err != nil {
+		log.Warnf("thumb: %s while migrating %s", err, clean.Log(filepath.Base(legacyName)))
+		return false
+	}
+
+	log.Infof("thumb: migrated %s to current naming scheme", clean.Log(filepath.Base(legacyName)))
+
+	return true
+}
@@ -0,0 +1,56 @@
+package thumb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestLegacyFileName(t *testing.T) {
+	fit720 := Sizes[Fit720]
+
+	result := LegacyFileName("123456789098765432", "testdata", fit720.Width, fit720.Height, fit720.Options...)
+
+	assert.Equal(t, "testdata/123456789098765432_720x720_fit.jpg", result)
+}
+
+func TestFileName_MigrateLegacy(t *testing.T) {
+	hash := "f1e2d3c4b5a6978812345678"
+	thumbPath := t.TempDir()
+	size := Sizes[Tile224]
+	legacyName := LegacyFileName(hash, thumbPath, size.Width, size.Height, size.Options...)
+
+	if err := os.WriteFile(legacyName, []byte("thumb"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		fileName, err := size.FileName(hash, thumbPath)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, filepath.Join(thumbPath, "f/1/e", hash+"_224x224_center.jpg"), fileName)
+		assert.FileExists(t, legacyName)
+		assert.NoFileExists(t, fileName)
+	})
+	t.Run("Enabled", func(t *testing.T) {
+		MigrateLegacy = true
+		defer func() { MigrateLegacy = false }()
+
+		fileName, err := FromCache("testdata/example.jpg", hash, thumbPath, size.Width, size.Height, size.Options...)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, filepath.Join(thumbPath, "f/1/e", hash+"_224x224_center.jpg"), fileName)
+		assert.NoFileExists(t, legacyName)
+		assert.FileExists(t, fileName)
+	})
+}
@@ -0,0 +1,285 @@
+package thumb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// UseLargest enables creating thumbnails from the largest image in JPEG files that contain multiple images,
+// e.g. a small primary image followed by the full image, instead of always using the primary image.
+var UseLargest = true
+
+// mpfHeader is the identifier that precedes Multi-Picture Format data in JPEG APP2 segments.
+const mpfHeader = "MPF\x00"
+
+// mpfTagEntry is the tag of the MP entries, which contain the size and offset of each image.
+const mpfTagEntry = 0xB002
+
+// ContainerImage represents an image in a file that may contain multiple images, e.g. a JPEG file with
+// Multi-Picture Format (MPF) data. The primary image has index 0.
+type ContainerImage struct {
+	Index  int
+	Offset int64
+	Size   int64
+	Width  int
+	Height int
+}
+
+// Pixels returns the number of pixels of the image.
+func (m ContainerImage) Pixels() int {
+	return m.Width * m.Height
+}
+
+// Ratio returns the aspect ratio of the image, or 0 if the dimensions are unknown.
+func (m ContainerImage) Ratio() float64 {
+	if m.Width <= 0 || m.Height <= 0 {
+		return 0
+	}
+
+	return float64(m.Width) / float64(m.Height)
+}
+
+// ContainerImages returns the images in a JPEG file, starting with the primary image. Images that cannot
+// be decoded are not returned.
+func ContainerImages(fileName string) (images []ContainerImage, err error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	primary, err := jpeg.DecodeConfig(bufio.NewReader(f))
+
+	if err != nil {
+		return nil, err
+	}
+
+	images = append(images, ContainerImage{Index: 0, Offset: 0, Size: info.Size(), Width: primary.Width, Height: primary.Height})
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return images, err
+	}
+
+	mpf, base, err := readMpfSegment(bufio.NewReader(f))
+
+	if err != nil {
+		// Files without MPF data contain a single image.
+		return images, nil
+	}
+
+	for i, entry := range mpfEntries(mpf) {
+		offset, size := base+entry[1], entry[0]
+
+		// The offset of the primary image is 0, and other images must be within the file.
+		if entry[1] == 0 || size <= 0 || offset+size > info.Size() {
+			continue
+		}
+
+		if cfg, err := jpeg.DecodeConfig(io.NewSectionReader(f, offset, size)); err == nil {
+			images = append(images, ContainerImage{Index: i, Offset: offset, Size: size, Width: cfg.Width, Height: cfg.Height})
+		}
+	}
+
+	return images, nil
+}
+
+// SelectLargest returns the largest suitable image, i.e. one with the aspect ratio of the primary image, so that
+// depth maps or panorama parts are not used. The primary image is preferred if other images are not larger.
+func SelectLargest(images []ContainerImage) (result ContainerImage, ok bool) {
+	if len(images) == 0 {
+		return result, false
+	}
+
+	result = images[0]
+	ratio := result.Ratio()
+
+	for _, m := range images[1:] {
+		if m.Pixels() <= result.Pixels() || ratio <= 0 {
+			continue
+		} else if math.Abs(m.Ratio()-ratio)/ratio > EmbeddedMaxRatioDiff {
+			continue
+		}
+
+		result = m
+	}
+
+	return result, true
+}
+
+// OpenLargest returns the largest image in a JPEG file that contains multiple images, rotated according
+// to the orientation. It returns false if the primary image is the largest, or the file contains a single image.
+func OpenLargest(fileName string, orientation int) (img image.Image, ok bool) {
+	if !UseLargest || fs.FileType(fileName) != fs.ImageJPEG {
+		return nil, false
+	}
+
+	images, err := ContainerImages(fileName)
+
+	if err != nil || len(images) < 2 {
+		return nil, false
+	}
+
+	largest, ok := SelectLargest(images)
+
+	if !ok || largest.Index == 0 {
+		return nil, false
+	}
+
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, false
+	}
+
+	defer f.Close()
+
+	if err = checkPixels(io.NewSectionReader(f, largest.Offset, largest.Size)); err != nil {
+		log.Debugf("thumb: %s in image %d of %s", err, largest.Index, clean.Log(filepath.Base(fileName)))
+		return nil, false
+	}
+
+	if img, err = imaging.Decode(io.NewSectionReader(f, largest.Offset, largest.Size)); err != nil {
+		log.Debugf("thumb: %s while decoding image %d of %s", err, largest.Index, clean.Log(filepath.Base(fileName)))
+		return nil, false
+	}
+
+	log.Tracef("thumb: using image %d of %s (%dx%d)", largest.Index, clean.Log(filepath.Base(fileName)), largest.Width, largest.Height)
+
+	img = FromCMYK(img, clean.Log(filepath.Base(fileName)))
+
+	if orientation > 1 {
+		img = Rotate(img, orientation)
+	}
+
+	return img, true
+}
+
+// readMpfSegment reads the Multi-Picture Format data from the APP2 segment of a JPEG file, and returns it
+// together with the file offset of its header, to which the offsets of the images are relative.
+func readMpfSegment(r *bufio.Reader) (mpf []byte, base int64, err error) {
+	var marker [2]byte
+
+	if _, err = io.ReadFull(r, marker[:]); err != nil {
+		return nil, 0, err
+	} else if marker[0] != 0xFF || marker[1] != 0xD8 {
+		return nil, 0, errors.New("thumb: invalid jpeg data")
+	}
+
+	pos := int64(2)
+
+	for {
+		if _, err = io.ReadFull(r, marker[:]); err != nil {
+			return nil, 0, err
+		} else if marker[0] != 0xFF {
+			return nil, 0, errors.New("thumb: invalid jpeg data")
+		}
+
+		// MPF data precedes the image data.
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return nil, 0, errors.New("thumb: no mpf data")
+		}
+
+		var size [2]byte
+
+		if _, err = io.ReadFull(r, size[:]); err != nil {
+			return nil, 0, err
+		}
+
+		n := int(binary.BigEndian.Uint16(size[:])) - 2
+
+		if n < 0 {
+			return nil, 0, errors.New("thumb: invalid jpeg data")
+		}
+
+		pos += 4
+
+		if marker[1] != 0xE2 {
+			if _, err = r.Discard(n); err != nil {
+				return nil, 0, err
+			}
+
+			pos += int64(n)
+
+			continue
+		}
+
+		segment := make([]byte, n)
+
+		if _, err = io.ReadFull(r, segment); err != nil {
+			return nil, 0, err
+		} else if bytes.HasPrefix(segment, []byte(mpfHeader)) {
+			return segment[len(mpfHeader):], pos + int64(len(mpfHeader)), nil
+		}
+
+		pos += int64(n)
+	}
+}
+
+// mpfEntries returns the size and offset of each image listed in the MPF data.
+func mpfEntries(mpf []byte) (entries [][2]int64) {
+	if len(mpf) < 8 {
+		return entries
+	}
+
+	var order binary.ByteOrder
+
+	switch string(mpf[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return entries
+	}
+
+	offset := int(order.Uint32(mpf[4:8]))
+
+	if offset < 8 || offset+2 > len(mpf) {
+		return entries
+	}
+
+	count := int(order.Uint16(mpf[offset : offset+2]))
+
+	for j := 0; j < count && offset+2+j*12+12 <= len(mpf); j++ {
+		entry := mpf[offset+2+j*12 : offset+2+j*12+12]
+
+		if order.Uint16(entry[0:2]) != mpfTagEntry {
+			continue
+		}
+
+		// Each MP entry has 16 bytes: attributes, size, offset, and two dependent image entries.
+		length, start := int(order.Uint32(entry[4:8])), int(order.Uint32(entry[8:12]))
+
+		if start < 8 || start+length > len(mpf) {
+			return entries
+		}
+
+		for i := 0; i+16 <= length; i += 16 {
+			e := mpf[start+i : start+i+16]
+			entries = append(entries, [2]int64{int64(order.Uint32(e[4:8])), int64(order.Uint32(e[8:12]))})
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,121 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// multiJpeg returns a JPEG file with MPF data that contains a primary image and a second image.
+func multiJpeg(t *testing.T, primary, second image.Image) []byte {
+	var a, b bytes.Buffer
+
+	if err := jpeg.Encode(&a, primary, nil); err != nil {
+		t.Fatal(err)
+	} else if err = jpeg.Encode(&b, second, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The MPF data has a header, an IFD with a single MP entry tag, and two MP entries.
+	mpf := new(bytes.Buffer)
+	mpf.WriteString(mpfHeader)
+	mpf.WriteString("II*\x00")
+	_ = binary.Write(mpf, binary.LittleEndian, uint32(8))
+	_ = binary.Write(mpf, binary.LittleEndian, uint16(1))
+	_ = binary.Write(mpf, binary.LittleEndian, []uint16{mpfTagEntry, 7})
+	_ = binary.Write(mpf, binary.LittleEndian, []uint32{32, 26, 0})
+
+	segmentLen := 2 + mpf.Len() + 32
+	primaryLen := 2 + 2 + segmentLen + a.Len() - 2
+	secondOffset := primaryLen - (2 + 4 + len(mpfHeader))
+
+	_ = binary.Write(mpf, binary.LittleEndian, []uint32{0x030000, uint32(primaryLen), 0, 0})
+	_ = binary.Write(mpf, binary.LittleEndian, []uint32{0, uint32(b.Len()), uint32(secondOffset), 0})
+
+	result := new(bytes.Buffer)
+	result.Write([]byte{0xFF, 0xD8, 0xFF, 0xE2})
+	_ = binary.Write(result, binary.BigEndian, uint16(segmentLen))
+	result.Write(mpf.Bytes())
+	result.Write(a.Bytes()[2:])
+	result.Write(b.Bytes())
+
+	return result.Bytes()
+}
+
+func TestContainerImages(t *testing.T) {
+	t.Run("Single", func(t *testing.T) {
+		images, err := ContainerImages("testdata/example.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, images, 1)
+		assert.Equal(t, 0, images[0].Index)
+	})
+	t.Run("Multiple", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "multi.jpg")
+
+		if err := os.WriteFile(fileName, multiJpeg(t, image.NewRGBA(image.Rect(0, 0, 40, 30)), image.NewRGBA(image.Rect(0, 0, 400, 300))), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		images, err := ContainerImages(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, images, 2)
+		assert.Equal(t, 40, images[0].Width)
+		assert.Equal(t, 1, images[1].Index)
+		assert.Equal(t, 400, images[1].Width)
+		assert.Equal(t, 300, images[1].Height)
+
+		img, ok := OpenLargest(fileName, 1)
+
+		assert.True(t, ok)
+		assert.Equal(t, 400, img.Bounds().Dx())
+
+		img, err = Open(fileName, 6)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 300, img.Bounds().Dx())
+	})
+}
+
+func TestSelectLargest(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		_, ok := SelectLargest(nil)
+		assert.False(t, ok)
+	})
+	t.Run("Primary", func(t *testing.T) {
+		m, ok := SelectLargest([]ContainerImage{{Index: 0, Width: 4000, Height: 3000}, {Index: 1, Width: 1920, Height: 1440}})
+		assert.True(t, ok)
+		assert.Equal(t, 0, m.Index)
+	})
+	t.Run("Largest", func(t *testing.T) {
+		m, ok := SelectLargest([]ContainerImage{{Index: 0, Width: 640, Height: 480}, {Index: 1, Width: 1920, Height: 1440}, {Index: 2, Width: 4000, Height: 3000}})
+		assert.True(t, ok)
+		assert.Equal(t, 2, m.Index)
+	})
+	t.Run("OtherRatio", func(t *testing.T) {
+		m, ok := SelectLargest([]ContainerImage{{Index: 0, Width: 640, Height: 480}, {Index: 1, Width: 8000, Height: 2000}})
+		assert.True(t, ok)
+		assert.Equal(t, 0, m.Index)
+	})
+	t.Run("SameSize", func(t *testing.T) {
+		m, ok := SelectLargest([]ContainerImage{{Index: 0, Width: 640, Height: 480}, {Index: 1, Width: 640, Height: 480}})
+		assert.True(t, ok)
+		assert.Equal(t, 0, m.Index)
+	})
+}
@@ -53,15 +53,31 @@ var Names = []Name{
 	Tile50,
 }
 
-// Find returns the largest default thumbnail type for the given size limit.
+// Find returns the largest default thumbnail type for the given size limit, except sizes that are never created.
 func Find(limit int) (name Name, size Size) {
 	for _, name = range Names {
 		t := Sizes[name]
 
-		if t.Width <= limit && t.Height <= limit {
+		if t.Width <= limit && t.Height <= limit && !t.Never() {
 			return name, t
 		}
 	}
 
 	return "", Size{}
 }
+
+// FindCached returns the first size in the fallback list for which a cached thumbnail of the file
+// exists, or the largest default thumbnail type for the given size limit otherwise.
+func FindCached(hash, thumbPath string, limit int, fallback []string) (name Name, size Size) {
+	for _, s := range fallback {
+		name = Name(s)
+
+		if size, ok := Sizes[name]; !ok {
+			continue
+		} else if _, err := size.ResolvedName(hash, thumbPath); err == nil {
+			return name, size
+		}
+	}
+
+	return Find(limit)
+}
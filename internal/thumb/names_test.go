@@ -1,9 +1,12 @@
 package thumb
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
 )
 
 func TestName_Jpeg(t *testing.T) {
@@ -27,3 +30,33 @@ func TestFind(t *testing.T) {
 		assert.Equal(t, 1200, size.Height)
 	})
 }
+
+func TestFindCached(t *testing.T) {
+	thumbPath := t.TempDir()
+	hash := "b1cd9168fa6acc5c5c2965ddf6ec465ca42fd818"
+
+	fileName, err := Sizes[Fit1280].FileName(hash, thumbPath)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(fileName, []byte("jpeg"), fs.ModeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Cached", func(t *testing.T) {
+		name, size := FindCached(hash, thumbPath, 720, []string{"foo", "fit_1920", "fit_1280", "fit_720"})
+		assert.Equal(t, Fit1280, name)
+		assert.Equal(t, 1280, size.Width)
+	})
+	t.Run("NotCached", func(t *testing.T) {
+		name, size := FindCached(hash, thumbPath, 720, []string{"fit_1920"})
+		assert.Equal(t, Fit720, name)
+		assert.Equal(t, 720, size.Width)
+	})
+	t.Run("NoFallback", func(t *testing.T) {
+		name, _ := FindCached(hash, thumbPath, 2048, nil)
+		assert.Equal(t, Fit2048, name)
+	})
+}
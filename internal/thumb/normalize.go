@@ -0,0 +1,66 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Normalize enables the use of upright copies of rotated originals, so that thumbnails do not need to be rotated, see Upright.
+var Normalize = false
+
+// UprightQuality is the JPEG quality of upright copies, which should be high since they serve as source for all sizes.
+var UprightQuality Quality = 95
+
+// UprightName returns the file name of the upright copy of a file with the specified hash and orientation.
+func UprightName(hash, thumbPath string, orientation int) string {
+	return path.Join(Dir(hash, thumbPath), fmt.Sprintf("%s_upright_%d%s", hash, orientation, fs.ExtJPEG))
+}
+
+// Upright bakes the orientation into an upright copy of the image in the thumbnail cache, so that the original
+// remains untouched. It returns the file name and whether the copy was created, which means that existing
+// thumbnails should be created again. Existing copies are only replaced if force is true.
+func Upright(imageFilename, hash, thumbPath string, orientation int, force bool) (fileName string, created bool, err error) {
+	if len(hash) < 4 {
+		return "", false, fmt.Errorf("thumb: file hash is empty or too short (%s)", clean.Log(hash))
+	} else if orientation <= OrientationNormal {
+		return imageFilename, false, nil
+	}
+
+	fileName = UprightName(hash, thumbPath, orientation)
+
+	if !force && fs.FileExists(fileName) {
+		return fileName, false, nil
+	}
+
+	img, err := Open(imageFilename, orientation)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	if err = os.MkdirAll(Dir(hash, thumbPath), fs.ModeDir); err != nil {
+		return "", false, err
+	} else if err = SaveJpeg(img, fileName, UprightQuality); err != nil {
+		return "", false, err
+	}
+
+	log.Debugf("thumb: created upright copy of %s", clean.Log(path.Base(imageFilename)))
+
+	return fileName, true, nil
+}
+
+// FromUpright returns the upright copy of the image and the normal orientation if it exists and Normalize is enabled,
+// or else the unchanged file name and orientation.
+func FromUpright(imageFilename, hash, thumbPath string, orientation int) (string, int) {
+	if !Normalize || orientation <= OrientationNormal || len(hash) < 4 {
+		return imageFilename, orientation
+	} else if fileName := UprightName(hash, thumbPath, orientation); fs.FileExists(fileName) {
+		return fileName, OrientationNormal
+	}
+
+	return imageFilename, orientation
+}
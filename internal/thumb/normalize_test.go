@@ -0,0 +1,95 @@
+package thumb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUprightName(t *testing.T) {
+	assert.Equal(t, "testdata/u/p/r/upright123456789_upright_6.jpg", UprightName("upright123456789", "testdata", 6))
+}
+
+func TestUpright(t *testing.T) {
+	hash := "upright123456789"
+	src := "testdata/example.jpg"
+
+	t.Run("Normal", func(t *testing.T) {
+		fileName, created, err := Upright(src, hash, "testdata", OrientationNormal, false)
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, src, fileName)
+	})
+	t.Run("Rotate90", func(t *testing.T) {
+		fileName, created, err := Upright(src, hash, "testdata", OrientationRotate90, false)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(fileName)
+
+		assert.True(t, created)
+		assert.Equal(t, UprightName(hash, "testdata", OrientationRotate90), fileName)
+
+		orig, err := imaging.Open(src)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := imaging.Open(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, orig.Bounds().Dx(), img.Bounds().Dy())
+		assert.Equal(t, orig.Bounds().Dy(), img.Bounds().Dx())
+
+		// Existing copies are only replaced if forced.
+		_, created, err = Upright(src, hash, "testdata", OrientationRotate90, false)
+		assert.NoError(t, err)
+		assert.False(t, created)
+
+		_, created, err = Upright(src, hash, "testdata", OrientationRotate90, true)
+		assert.NoError(t, err)
+		assert.True(t, created)
+	})
+	t.Run("InvalidHash", func(t *testing.T) {
+		_, _, err := Upright(src, "abc", "testdata", OrientationRotate90, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestFromUpright(t *testing.T) {
+	hash := "upright123456789"
+	src := "testdata/example.jpg"
+
+	defer func() { Normalize = false }()
+
+	uprightName, _, err := Upright(src, hash, "testdata", OrientationRotate180, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(uprightName)
+
+	fileName, orientation := FromUpright(src, hash, "testdata", OrientationRotate180)
+	assert.Equal(t, src, fileName)
+	assert.Equal(t, OrientationRotate180, orientation)
+
+	Normalize = true
+
+	fileName, orientation = FromUpright(src, hash, "testdata", OrientationRotate180)
+	assert.Equal(t, uprightName, fileName)
+	assert.Equal(t, OrientationNormal, orientation)
+
+	fileName, orientation = FromUpright(src, hash, "testdata", OrientationRotate270)
+	assert.Equal(t, src, fileName)
+	assert.Equal(t, OrientationRotate270, orientation)
+}
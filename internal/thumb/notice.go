@@ -0,0 +1,91 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// xmpNamespace is the identifier that precedes XMP packets in JPEG APP1 segments.
+const xmpNamespace = "http://ns.adobe.com/xap/1.0/\x00"
+
+// Notice represents the creator and copyright information embedded in downloaded thumbnails.
+type Notice struct {
+	Artist    string
+	Copyright string
+}
+
+// Empty tests if the notice has neither an artist nor a copyright.
+func (n Notice) Empty() bool {
+	return n.Artist == "" && n.Copyright == ""
+}
+
+// Xmp returns the notice as XMP packet.
+func (n Notice) Xmp() []byte {
+	escape := func(s string) string {
+		var b strings.Builder
+		_ = xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+
+	var b strings.Builder
+
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>")
+	b.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">`)
+	b.WriteString(`<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">`)
+
+	if n.Artist != "" {
+		b.WriteString(`<dc:creator><rdf:Seq><rdf:li>` + escape(n.Artist) + `</rdf:li></rdf:Seq></dc:creator>`)
+	}
+
+	if n.Copyright != "" {
+		b.WriteString(`<dc:rights><rdf:Alt><rdf:li xml:lang="x-default">` + escape(n.Copyright) + `</rdf:li></rdf:Alt></dc:rights>`)
+	}
+
+	b.WriteString(`</rdf:Description></rdf:RDF></x:xmpmeta><?xpacket end="w"?>`)
+
+	return []byte(b.String())
+}
+
+// Embed returns a copy of the JPEG data with the notice added as XMP metadata.
+func (n Notice) Embed(jpeg []byte) ([]byte, error) {
+	if n.Empty() {
+		return jpeg, nil
+	} else if len(jpeg) < 4 || jpeg[0] != 0xFF || jpeg[1] != 0xD8 {
+		return jpeg, fmt.Errorf("thumb: invalid jpeg data")
+	}
+
+	payload := append([]byte(xmpNamespace), n.Xmp()...)
+
+	// Segment length includes the two length bytes and must not exceed 65535.
+	if len(payload)+2 > 0xFFFF {
+		return jpeg, fmt.Errorf("thumb: notice exceeds max xmp size")
+	}
+
+	// Insert after SOI, or after the JFIF segment if present.
+	pos := 2
+
+	if jpeg[2] == 0xFF && jpeg[3] == 0xE0 && len(jpeg) >= 6 {
+		pos = 4 + int(binary.BigEndian.Uint16(jpeg[4:6]))
+
+		if pos > len(jpeg) {
+			return jpeg, fmt.Errorf("thumb: invalid jpeg data")
+		}
+	}
+
+	segment := make([]byte, 4, 4+len(payload))
+	segment[0], segment[1] = 0xFF, 0xE1
+	binary.BigEndian.PutUint16(segment[2:4], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	var buf bytes.Buffer
+
+	buf.Grow(len(jpeg) + len(segment))
+	buf.Write(jpeg[:pos])
+	buf.Write(segment)
+	buf.Write(jpeg[pos:])
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,56 @@
+package thumb
+
+import (
+	"bytes"
+	"image/jpeg"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotice_Empty(t *testing.T) {
+	assert.True(t, Notice{}.Empty())
+	assert.False(t, Notice{Artist: "Jens Mander"}.Empty())
+	assert.False(t, Notice{Copyright: "© 2023"}.Empty())
+}
+
+func TestNotice_Xmp(t *testing.T) {
+	xmp := string(Notice{Artist: "Jens & Mander", Copyright: "© 2023 <PhotoPrism>"}.Xmp())
+
+	assert.Contains(t, xmp, "<dc:creator><rdf:Seq><rdf:li>Jens &amp; Mander</rdf:li></rdf:Seq></dc:creator>")
+	assert.Contains(t, xmp, `<rdf:li xml:lang="x-default">© 2023 &lt;PhotoPrism&gt;</rdf:li>`)
+}
+
+func TestNotice_Embed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/example.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := Notice{Artist: "Jens Mander", Copyright: "© 2023"}.Embed(data)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Greater(t, len(result), len(data))
+		assert.True(t, bytes.Contains(result, []byte(xmpNamespace)))
+
+		// Must still be a valid image.
+		_, err = jpeg.Decode(bytes.NewReader(result))
+		assert.NoError(t, err)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		data := []byte{0xFF, 0xD8, 0xFF, 0xD9}
+		result, err := Notice{}.Embed(data)
+		assert.NoError(t, err)
+		assert.Equal(t, data, result)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := Notice{Artist: "Jens Mander"}.Embed([]byte("foo"))
+		assert.Error(t, err)
+	})
+}
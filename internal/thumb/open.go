@@ -3,9 +3,12 @@ package thumb
 import (
 	"fmt"
 	"image"
+	"os"
+	"path/filepath"
 
 	"github.com/disintegration/imaging"
 
+	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
 
@@ -24,6 +27,41 @@ func Open(fileName string, orientation int) (result image.Image, err error) {
 		return result, err
 	}
 
+	// Limit the number of RAW images and videos decoded at the same time.
+	if IsHeavy(fileName) {
+		var fileSize int64
+
+		if info, statErr := os.Stat(fileName); statErr == nil {
+			fileSize = info.Size()
+		}
+
+		defer AcquireDecode(fileName, DecodeMemSize(0, 0, fileSize))()
+	}
+
+	// Use the poster embedded in videos, if any, rotated like the frames unless the orientation is known.
+	if IsVideo(fileName) {
+		if orientation <= OrientationNormal {
+			orientation = VideoOrientation(fileName)
+		}
+
+		return OpenPoster(fileName, orientation)
+	}
+
+	// Use the cover image of ZIP archives such as comic books.
+	if IsArchive(fileName) {
+		return OpenArchive(fileName, orientation)
+	}
+
+	// Reject images with huge dimensions before allocating memory for them.
+	if err = CheckPixels(fileName); err != nil {
+		return result, err
+	}
+
+	// Use the largest image if the file contains multiple images, e.g. a small preview and the full image.
+	if img, ok := OpenLargest(fileName, orientation); ok {
+		return img, nil
+	}
+
 	// Open JPEG?
 	if StandardRGB && fs.FileType(fileName) == fs.ImageJPEG {
 		return OpenJpeg(fileName, orientation)
@@ -36,6 +74,9 @@ func Open(fileName string, orientation int) (result image.Image, err error) {
 		return result, err
 	}
 
+	// Convert the colors of print sources to sRGB.
+	img = FromCMYK(img, clean.Log(filepath.Base(fileName)))
+
 	// Adjust orientation.
 	if orientation > 1 {
 		img = Rotate(img, orientation)
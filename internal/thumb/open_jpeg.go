@@ -59,7 +59,10 @@ func OpenJpeg(fileName string, orientation int) (image.Image, error) {
 	}
 
 	// Read ICC profile and convert colors if possible.
-	if md != nil {
+	if _, cmyk := img.(*image.CMYK); cmyk {
+		// CMYK profiles cannot be applied, so print colors are approximated instead.
+		img = FromCMYK(img, logName)
+	} else if md != nil {
 		if iccProfile, err := md.ICCProfile(); err != nil || iccProfile == nil {
 			// Do nothing.
 			log.Tracef("thumb: %s has no color profile", logName)
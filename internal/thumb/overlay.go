@@ -0,0 +1,190 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// OverlayRating is the overlay type that shows the rating or reject flag of a photo.
+const OverlayRating = "rating"
+
+// MaxStars is the maximum number of stars shown in rating overlays.
+const MaxStars = 5
+
+// Badge represents the rating or reject flag drawn into the corner of a thumbnail.
+type Badge struct {
+	Stars  int
+	Reject bool
+}
+
+// NewBadge returns a badge with the number of stars limited to the supported range.
+func NewBadge(stars int, reject bool) Badge {
+	if stars < 0 {
+		stars = 0
+	} else if stars > MaxStars {
+		stars = MaxStars
+	}
+
+	return Badge{Stars: stars, Reject: reject}
+}
+
+// String returns the badge as file name suffix, e.g. "rating3" or "reject".
+func (b Badge) String() string {
+	if b.Reject {
+		return "reject"
+	}
+
+	return fmt.Sprintf("rating%d", b.Stars)
+}
+
+var (
+	badgeBackground = color.NRGBA{R: 0, G: 0, B: 0, A: 160}
+	badgeStar       = color.NRGBA{R: 255, G: 204, B: 0, A: 255}
+	badgeStarEmpty  = color.NRGBA{R: 255, G: 255, B: 255, A: 90}
+	badgeReject     = color.NRGBA{R: 220, G: 40, B: 40, A: 255}
+	badgeCross      = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+// OverlayName returns the file name of a thumbnail with the badge drawn onto it.
+func OverlayName(thumbName string, b Badge) string {
+	ext := filepath.Ext(thumbName)
+
+	return fmt.Sprintf("%s_%s%s", strings.TrimSuffix(thumbName, ext), b, ext)
+}
+
+// FromOverlay returns the file name of the thumbnail with the badge drawn onto it, and creates it if needed.
+// Overlay thumbnails are stored separately, so that the original thumbnail remains unchanged.
+func FromOverlay(thumbName string, b Badge) (fileName string, err error) {
+	fileName = OverlayName(thumbName, b)
+
+	if fs.FileExists(fileName) {
+		return fileName, nil
+	}
+
+	img, err := imaging.Open(thumbName)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err = SaveJpeg(DrawBadge(img, b), fileName, JpegQuality); err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
+
+// DrawBadge draws the badge into the bottom left corner of the image.
+func DrawBadge(img image.Image, b Badge) *image.NRGBA {
+	dst := imaging.Clone(img)
+	bounds := dst.Bounds()
+
+	// Scale the badge with the image size.
+	unit := int(math.Max(12, math.Min(float64(bounds.Dx()), float64(bounds.Dy()))/12))
+	pad := unit / 4
+
+	width := unit + 2*pad
+
+	if !b.Reject {
+		width = MaxStars*unit + 2*pad
+	}
+
+	area := image.Rect(pad, bounds.Dy()-unit-3*pad, pad+width, bounds.Dy()-pad).Add(bounds.Min).Intersect(bounds)
+
+	draw.Draw(dst, area, image.NewUniform(badgeBackground), image.Point{}, draw.Over)
+
+	if b.Reject {
+		drawCross(dst, image.Rect(area.Min.X+pad, area.Min.Y+pad, area.Min.X+pad+unit, area.Min.Y+pad+unit))
+		return dst
+	}
+
+	for i := 0; i < MaxStars; i++ {
+		c := badgeStarEmpty
+
+		if i < b.Stars {
+			c = badgeStar
+		}
+
+		x := area.Min.X + pad + i*unit
+		drawStar(dst, image.Rect(x, area.Min.Y+pad, x+unit, area.Min.Y+pad+unit), c)
+	}
+
+	return dst
+}
+
+// drawStar fills a five-pointed star within the rectangle.
+func drawStar(dst *image.NRGBA, r image.Rectangle, c color.NRGBA) {
+	cx, cy := float64(r.Min.X+r.Max.X)/2, float64(r.Min.Y+r.Max.Y)/2
+	outer := float64(r.Dx()) * 0.48
+	inner := outer * 0.4
+
+	points := make([][2]float64, 10)
+
+	for i := range points {
+		radius := outer
+
+		if i%2 == 1 {
+			radius = inner
+		}
+
+		a := -math.Pi/2 + float64(i)*math.Pi/5
+		points[i] = [2]float64{cx + radius*math.Cos(a), cy + radius*math.Sin(a)}
+	}
+
+	fillPixels(dst, r, c, func(x, y float64) bool {
+		return inPolygon(points, x, y)
+	})
+}
+
+// drawCross fills a red circle with a white cross within the rectangle.
+func drawCross(dst *image.NRGBA, r image.Rectangle) {
+	cx, cy := float64(r.Min.X+r.Max.X)/2, float64(r.Min.Y+r.Max.Y)/2
+	radius := float64(r.Dx()) / 2
+	stroke := math.Max(1, radius/4)
+
+	fillPixels(dst, r, badgeReject, func(x, y float64) bool {
+		return math.Hypot(x-cx, y-cy) <= radius
+	})
+
+	fillPixels(dst, r.Inset(r.Dx()/4), badgeCross, func(x, y float64) bool {
+		return math.Abs((x-cx)-(y-cy)) <= stroke || math.Abs((x-cx)+(y-cy)) <= stroke
+	})
+}
+
+// fillPixels blends the color into all pixels within the rectangle whose center is inside the shape.
+func fillPixels(dst *image.NRGBA, r image.Rectangle, c color.NRGBA, inside func(x, y float64) bool) {
+	r = r.Intersect(dst.Bounds())
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if inside(float64(x)+0.5, float64(y)+0.5) {
+				draw.Draw(dst, image.Rect(x, y, x+1, y+1), image.NewUniform(c), image.Point{}, draw.Over)
+			}
+		}
+	}
+}
+
+// inPolygon checks if the point is inside the polygon using the even-odd rule.
+func inPolygon(points [][2]float64, x, y float64) bool {
+	inside := false
+
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		xi, yi := points[i][0], points[i][1]
+		xj, yj := points[j][0], points[j][1]
+
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
@@ -0,0 +1,87 @@
+package thumb
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBadge(t *testing.T) {
+	assert.Equal(t, Badge{Stars: 0}, NewBadge(-1, false))
+	assert.Equal(t, Badge{Stars: 3}, NewBadge(3, false))
+	assert.Equal(t, Badge{Stars: 5}, NewBadge(7, false))
+	assert.Equal(t, Badge{Stars: 2, Reject: true}, NewBadge(2, true))
+}
+
+func TestBadge_String(t *testing.T) {
+	assert.Equal(t, "rating3", NewBadge(3, false).String())
+	assert.Equal(t, "reject", NewBadge(3, true).String())
+}
+
+func TestOverlayName(t *testing.T) {
+	assert.Equal(t, "/cache/a/b/c/abc_224x224_center_rating4.jpg", OverlayName("/cache/a/b/c/abc_224x224_center.jpg", NewBadge(4, false)))
+	assert.Equal(t, "/cache/a/b/c/abc_224x224_center_reject.jpg", OverlayName("/cache/a/b/c/abc_224x224_center.jpg", NewBadge(0, true)))
+}
+
+func TestDrawBadge(t *testing.T) {
+	src := imaging.New(240, 240, color.White)
+
+	t.Run("Rating", func(t *testing.T) {
+		img := DrawBadge(src, NewBadge(2, false))
+
+		assert.Equal(t, src.Bounds(), img.Bounds())
+
+		// Stars are 20 pixels wide with a padding of 5 pixels, the first is filled but the last is not.
+		assert.Equal(t, badgeStar, img.NRGBAAt(20, 220))
+		assert.NotEqual(t, badgeStar, img.NRGBAAt(100, 220))
+		assert.NotEqual(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, img.NRGBAAt(100, 220))
+
+		// The top right corner is unchanged.
+		assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, img.NRGBAAt(230, 10))
+		assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, src.NRGBAAt(20, 220))
+	})
+	t.Run("Reject", func(t *testing.T) {
+		img := DrawBadge(src, NewBadge(4, true))
+
+		// The center of the badge shows the white cross on the red circle.
+		assert.Equal(t, badgeCross, img.NRGBAAt(20, 220))
+		assert.Equal(t, badgeReject, img.NRGBAAt(20, 212))
+	})
+}
+
+func TestFromOverlay(t *testing.T) {
+	thumbName := filepath.Join(t.TempDir(), "abc_224x224_center.jpg")
+
+	if err := imaging.Save(imaging.New(224, 224, color.White), thumbName); err != nil {
+		t.Fatal(err)
+	}
+
+	fileName, err := FromOverlay(thumbName, NewBadge(3, false))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, OverlayName(thumbName, NewBadge(3, false)), fileName)
+	assert.FileExists(t, fileName)
+
+	info, err := os.Stat(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Existing overlays are reused.
+	again, err := FromOverlay(thumbName, NewBadge(3, false))
+
+	assert.NoError(t, err)
+	assert.Equal(t, fileName, again)
+
+	if againInfo, err := os.Stat(again); err == nil {
+		assert.Equal(t, info.ModTime(), againInfo.ModTime())
+	}
+}
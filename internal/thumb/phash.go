@@ -0,0 +1,178 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/disintegration/imaging"
+)
+
+// PHash represents a 64-bit perceptual image hash based on the discrete cosine transform,
+// which changes little when images are resized, recompressed, or slightly edited. It is more robust than
+// AHash, but also more expensive to compute.
+type PHash uint64
+
+// String returns the hash as hex string.
+func (h PHash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// Distance returns the number of different bits, where 0 means the images are likely identical.
+func (h PHash) Distance(other PHash) int {
+	return bits.OnesCount64(uint64(h ^ other))
+}
+
+// ImageHashes represents the perceptual hashes of an image. Invariant matches rotated or mirrored copies,
+// while PHash only matches images with the same orientation.
+type ImageHashes struct {
+	PHash     PHash `json:"phash"`
+	Invariant PHash `json:"phashInvariant"`
+}
+
+// phashSize is the width and height of the image the transform is computed on.
+const phashSize = 32
+
+// phashBlock is the width and height of the block of low frequencies the hash is based on.
+const phashBlock = 8
+
+// PerceptualHash returns the perceptual hash of the image.
+func PerceptualHash(img image.Image) PHash {
+	return phashBits(phashDCT(img), false, false, false)
+}
+
+// PerceptualHashInvariant returns a perceptual hash that is the same for all rotations and mirrored copies
+// of the image, so that rotated duplicates can be found. It is the smallest hash of all eight orientations,
+// which are derived from a single transform since rotating an image only swaps and negates its frequencies.
+func PerceptualHashInvariant(img image.Image) PHash {
+	return phashInvariant(phashDCT(img))
+}
+
+// Hashes returns both perceptual hashes of the image.
+func Hashes(img image.Image) ImageHashes {
+	dct := phashDCT(img)
+
+	return ImageHashes{
+		PHash:     phashBits(dct, false, false, false),
+		Invariant: phashInvariant(dct),
+	}
+}
+
+// phashInvariant returns the smallest hash of all eight orientations.
+func phashInvariant(dct [][]float64) PHash {
+	var result PHash
+
+	for i := 0; i < 8; i++ {
+		if h := phashBits(dct, i&1 == 1, i&2 == 2, i&4 == 4); i == 0 || h < result {
+			result = h
+		}
+	}
+
+	return result
+}
+
+// phashBits returns the hash bits of the low frequencies, which are set if a frequency is above the median.
+// The frequencies are transposed and their signs flipped like the frequencies of a rotated or mirrored image.
+func phashBits(dct [][]float64, transpose, flipX, flipY bool) PHash {
+	values := make([]float64, 0, phashBlock*phashBlock)
+
+	for y := 0; y < phashBlock; y++ {
+		for x := 0; x < phashBlock; x++ {
+			v := dct[y][x]
+
+			if transpose {
+				v = dct[x][y]
+			}
+
+			// Mirroring negates the odd frequencies.
+			if flipX && x%2 == 1 {
+				v = -v
+			}
+
+			if flipY && y%2 == 1 {
+				v = -v
+			}
+
+			values = append(values, v)
+		}
+	}
+
+	// The median excludes the average brightness in the first value.
+	sorted := make([]float64, len(values)-1)
+	copy(sorted, values[1:])
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var h PHash
+
+	for i, v := range values {
+		if v > median {
+			h |= 1 << uint(i)
+		}
+	}
+
+	return h
+}
+
+// phashDCT returns the discrete cosine transform of the image luminance in reduced size.
+func phashDCT(img image.Image) [][]float64 {
+	gray := imaging.Grayscale(imaging.Resize(img, phashSize, phashSize, imaging.Lanczos))
+
+	pixels := make([][]float64, phashSize)
+
+	for y := 0; y < phashSize; y++ {
+		pixels[y] = make([]float64, phashSize)
+
+		for x := 0; x < phashSize; x++ {
+			pixels[y][x] = float64(gray.Pix[y*gray.Stride+x*4])
+		}
+	}
+
+	// Only the low frequencies are needed for the hash.
+	cos := make([][]float64, phashBlock)
+
+	for u := 0; u < phashBlock; u++ {
+		cos[u] = make([]float64, phashSize)
+
+		for x := 0; x < phashSize; x++ {
+			cos[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * phashSize))
+		}
+	}
+
+	// Transform rows first, then columns.
+	rows := make([][]float64, phashSize)
+
+	for y := 0; y < phashSize; y++ {
+		rows[y] = make([]float64, phashBlock)
+
+		for u := 0; u < phashBlock; u++ {
+			var sum float64
+
+			for x := 0; x < phashSize; x++ {
+				sum += pixels[y][x] * cos[u][x]
+			}
+
+			rows[y][u] = sum
+		}
+	}
+
+	result := make([][]float64, phashBlock)
+
+	for v := 0; v < phashBlock; v++ {
+		result[v] = make([]float64, phashBlock)
+
+		for u := 0; u < phashBlock; u++ {
+			var sum float64
+
+			for y := 0; y < phashSize; y++ {
+				sum += rows[y][u] * cos[v][y]
+			}
+
+			result[v][u] = sum
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,75 @@
+package thumb
+
+import (
+	"image"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPerceptualHash(t *testing.T) {
+	img, err := imaging.Open("testdata/example.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := PerceptualHash(img)
+
+	assert.Len(t, h.String(), 16)
+	assert.NotEqual(t, PHash(0), h)
+
+	t.Run("Resized", func(t *testing.T) {
+		resized := PerceptualHash(imaging.Resize(img, 300, 0, imaging.Lanczos))
+		assert.LessOrEqual(t, h.Distance(resized), 4)
+	})
+	t.Run("Rotated", func(t *testing.T) {
+		rotated := PerceptualHash(imaging.Rotate90(img))
+		assert.Greater(t, h.Distance(rotated), 10)
+	})
+	t.Run("Different", func(t *testing.T) {
+		other, err := imaging.Open("selftest.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Greater(t, h.Distance(PerceptualHash(other)), 10)
+	})
+}
+
+func TestPerceptualHashInvariant(t *testing.T) {
+	img, err := imaging.Open("testdata/example.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := PerceptualHashInvariant(img)
+
+	for name, rotated := range map[string]*image.NRGBA{
+		"Rotate90":   imaging.Rotate90(img),
+		"Rotate180":  imaging.Rotate180(img),
+		"Rotate270":  imaging.Rotate270(img),
+		"FlipH":      imaging.FlipH(img),
+		"Transverse": imaging.Transverse(img),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.LessOrEqual(t, h.Distance(PerceptualHashInvariant(rotated)), 2)
+		})
+	}
+}
+
+func TestHashes(t *testing.T) {
+	img, err := imaging.Open("testdata/example.jpg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := Hashes(img)
+
+	assert.Equal(t, PerceptualHash(img), result.PHash)
+	assert.Equal(t, PerceptualHashInvariant(img), result.Invariant)
+}
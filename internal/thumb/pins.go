@@ -0,0 +1,158 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Pin identifies a thumbnail that should never be evicted from the memory cache or purged, e.g. an album cover.
+type Pin struct {
+	Hash string `json:"hash" yaml:"Hash"`
+	Size Name   `json:"size" yaml:"Size"`
+}
+
+// Pins represents a list of pinned thumbnails.
+type Pins []Pin
+
+var (
+	pins      = make(map[Pin]bool)
+	pinsFile  string
+	pinsMutex sync.RWMutex
+)
+
+// LoadPins loads the pinned thumbnails from a YAML file, which is then used to store changes.
+func LoadPins(fileName string) error {
+	pinsMutex.Lock()
+	defer pinsMutex.Unlock()
+
+	pins = make(map[Pin]bool)
+	pinsFile = fileName
+
+	if !fs.FileExists(fileName) {
+		return nil
+	}
+
+	data, err := os.ReadFile(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	var list Pins
+
+	if err = yaml.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("thumb: %s in %s", err, clean.Log(filepath.Base(fileName)))
+	}
+
+	for _, p := range list {
+		if p.Hash != "" && p.Size != "" {
+			pins[p] = true
+		}
+	}
+
+	return nil
+}
+
+// AddPin pins the thumbnail with the specified file hash and size.
+func AddPin(hash string, size Name) error {
+	if len(hash) < 4 {
+		return fmt.Errorf("thumb: file hash is empty or too short (%s)", clean.Log(hash))
+	} else if _, ok := Sizes[size]; !ok {
+		return fmt.Errorf("thumb: invalid size %s", clean.Log(size.String()))
+	}
+
+	pinsMutex.Lock()
+	defer pinsMutex.Unlock()
+
+	pins[Pin{Hash: hash, Size: size}] = true
+
+	return savePins()
+}
+
+// RemovePin unpins the thumbnail with the specified file hash and size, and returns false if it was not pinned.
+func RemovePin(hash string, size Name) (bool, error) {
+	pinsMutex.Lock()
+	defer pinsMutex.Unlock()
+
+	p := Pin{Hash: hash, Size: size}
+
+	if !pins[p] {
+		return false, nil
+	}
+
+	delete(pins, p)
+
+	return true, savePins()
+}
+
+// Pinned checks if the thumbnail with the specified file hash and size is pinned.
+func Pinned(hash string, size Name) bool {
+	pinsMutex.RLock()
+	defer pinsMutex.RUnlock()
+
+	return pins[Pin{Hash: hash, Size: size}]
+}
+
+// PinnedHash checks if any thumbnail of the file with the specified hash is pinned.
+func PinnedHash(hash string) bool {
+	pinsMutex.RLock()
+	defer pinsMutex.RUnlock()
+
+	for p := range pins {
+		if p.Hash == hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PinList returns all pinned thumbnails sorted by hash and size.
+func PinList() Pins {
+	pinsMutex.RLock()
+	defer pinsMutex.RUnlock()
+
+	return pinList()
+}
+
+// pinList returns all pinned thumbnails sorted by hash and size. The caller must hold the lock.
+func pinList() Pins {
+	result := make(Pins, 0, len(pins))
+
+	for p := range pins {
+		result = append(result, p)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Hash == result[j].Hash {
+			return result[i].Size < result[j].Size
+		}
+
+		return result[i].Hash < result[j].Hash
+	})
+
+	return result
+}
+
+// savePins writes the pinned thumbnails to the YAML file, if any. The caller must hold the lock.
+func savePins() error {
+	if pinsFile == "" {
+		return nil
+	}
+
+	data, err := yaml.Marshal(pinList())
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(pinsFile, data, fs.ModeFile)
+}
@@ -0,0 +1,53 @@
+package thumb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPins(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "pins.yml")
+	hash := "ca1ea0b33b3d2fcf8bb732e51e9e4f4d7e1f5a2b"
+
+	if err := LoadPins(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = LoadPins("") }()
+
+	assert.Empty(t, PinList())
+	assert.NoError(t, AddPin(hash, Tile500))
+	assert.NoError(t, AddPin(hash, Fit720))
+	assert.Error(t, AddPin(hash, "invalid"))
+	assert.Error(t, AddPin("", Tile500))
+	assert.True(t, Pinned(hash, Tile500))
+	assert.False(t, Pinned(hash, Tile224))
+	assert.True(t, PinnedHash(hash))
+	assert.False(t, PinnedHash("0000000000000000000000000000000000000000"))
+	assert.Equal(t, Pins{{Hash: hash, Size: Fit720}, {Hash: hash, Size: Tile500}}, PinList())
+
+	// Pins are restored from the file.
+	if err := LoadPins(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, Pinned(hash, Fit720))
+
+	found, err := RemovePin(hash, Fit720)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = RemovePin(hash, Fit720)
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	if err = LoadPins(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, Pins{{Hash: hash, Size: Tile500}}, PinList())
+}
@@ -0,0 +1,56 @@
+package thumb
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"os"
+)
+
+// MaxPixels is the maximum number of pixels of images that are decoded, so that crafted files with
+// huge declared dimensions cannot exhaust memory (0 for unlimited).
+var MaxPixels int64 = 200 * 1000 * 1000
+
+// CheckPixels reads the image dimensions from the file header without decoding the image,
+// and returns ErrTooManyPixels if they exceed MaxPixels. Unknown formats are not rejected.
+func CheckPixels(fileName string) error {
+	if MaxPixels <= 0 {
+		return nil
+	}
+
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return checkPixels(f)
+}
+
+// CheckPixelsBytes checks the dimensions of an image in memory like CheckPixels.
+func CheckPixelsBytes(data []byte) error {
+	if MaxPixels <= 0 {
+		return nil
+	}
+
+	return checkPixels(bytes.NewReader(data))
+}
+
+// checkPixels compares the dimensions in the image header with MaxPixels.
+func checkPixels(r io.Reader) error {
+	cfg, _, err := image.DecodeConfig(r)
+
+	if err != nil {
+		// Unknown format, the decoder must check the dimensions.
+		return nil
+	}
+
+	if pixels := int64(cfg.Width) * int64(cfg.Height); pixels > MaxPixels || cfg.Width < 0 || cfg.Height < 0 {
+		return fmt.Errorf("%w (%dx%d)", ErrTooManyPixels, cfg.Width, cfg.Height)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package thumb
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPixels(t *testing.T) {
+	defer func(n int64) { MaxPixels = n }(MaxPixels)
+
+	t.Run("Allowed", func(t *testing.T) {
+		MaxPixels = 200 * 1000 * 1000
+		assert.NoError(t, CheckPixels("testdata/example.jpg"))
+	})
+	t.Run("TooLarge", func(t *testing.T) {
+		MaxPixels = 1000
+		err := CheckPixels("testdata/example.jpg")
+		assert.True(t, errors.Is(err, ErrTooManyPixels))
+	})
+	t.Run("Unlimited", func(t *testing.T) {
+		MaxPixels = 0
+		assert.NoError(t, CheckPixels("testdata/example.jpg"))
+	})
+	t.Run("UnknownFormat", func(t *testing.T) {
+		fileName := "testdata/pixels.dat"
+
+		if err := os.WriteFile(fileName, []byte("not an image"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(fileName)
+
+		MaxPixels = 1000
+		assert.NoError(t, CheckPixels(fileName))
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		MaxPixels = 1000
+		assert.Error(t, CheckPixels("testdata/xxx.jpg"))
+	})
+}
+
+func TestCheckPixelsBytes(t *testing.T) {
+	defer func(n int64) { MaxPixels = n }(MaxPixels)
+
+	data, err := os.ReadFile("testdata/example.png")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	MaxPixels = 1000
+	assert.True(t, errors.Is(CheckPixelsBytes(data), ErrTooManyPixels))
+
+	MaxPixels = 200 * 1000 * 1000
+	assert.NoError(t, CheckPixelsBytes(data))
+}
+
+func TestOpen_MaxPixels(t *testing.T) {
+	defer func(n int64) { MaxPixels = n }(MaxPixels)
+
+	MaxPixels = 1000
+
+	_, err := Open("testdata/example.png", OrientationNormal)
+
+	assert.True(t, errors.Is(err, ErrTooManyPixels))
+}
@@ -0,0 +1,91 @@
+package thumb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Policy specifies when thumbnails of a size are created.
+type Policy string
+
+const (
+	PolicyDefault  Policy = ""
+	PolicyPrecache Policy = "precache"
+	PolicyOnDemand Policy = "on-demand"
+	PolicyNever    Policy = "never"
+)
+
+// Policies contains the policy by size name. Sizes that are not listed are pre-cached when files are indexed
+// if they do not exceed SizePrecached, and are created on demand otherwise, see Size.Policy.
+var Policies = map[Name]Policy{}
+
+// ParsePolicies parses a comma-separated list of policies by size name, e.g. "fit_2560=precache,fit_7680=never".
+func ParsePolicies(s string) (map[Name]Policy, error) {
+	result := make(map[Name]Policy)
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		k, v, found := strings.Cut(item, "=")
+
+		if !found {
+			return nil, fmt.Errorf("thumb: missing size in policy %s", clean.LogQuote(item))
+		}
+
+		name, policy := Name(strings.ToLower(strings.TrimSpace(k))), Policy(strings.ToLower(strings.TrimSpace(v)))
+
+		if _, ok := Sizes[name]; !ok {
+			return nil, fmt.Errorf("thumb: unknown size %s", clean.LogQuote(name.String()))
+		}
+
+		switch policy {
+		case PolicyPrecache, PolicyOnDemand, PolicyNever:
+			result[name] = policy
+		default:
+			return nil, fmt.Errorf("thumb: invalid policy %s", clean.LogQuote(string(policy)))
+		}
+	}
+
+	return result, nil
+}
+
+// PoliciesString returns the policies by size name as comma-separated list, see ParsePolicies.
+func PoliciesString(policies map[Name]Policy) string {
+	items := make([]string, 0, len(policies))
+
+	for name, policy := range policies {
+		items = append(items, fmt.Sprintf("%s=%s", name, policy))
+	}
+
+	sort.Strings(items)
+
+	return strings.Join(items, ",")
+}
+
+// Policy returns the policy of the size, which is "precache" by default if it does not exceed SizePrecached,
+// and "on-demand" otherwise.
+func (s Size) Policy() Policy {
+	if policy := Policies[s.Name]; policy != PolicyDefault {
+		return policy
+	} else if s.Width > SizePrecached || s.Height > SizePrecached {
+		return PolicyOnDemand
+	}
+
+	return PolicyPrecache
+}
+
+// OnDemand checks if thumbnails of the size are explicitly configured to be created on demand,
+// even if the creation of uncached sizes is otherwise disabled.
+func (s Size) OnDemand() bool {
+	return Policies[s.Name] == PolicyOnDemand
+}
+
+// Never checks if thumbnails of the size are never created, so that another size must be used instead.
+func (s Size) Never() bool {
+	return Policies[s.Name] == PolicyNever
+}
@@ -0,0 +1,54 @@
+package thumb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePolicies(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		result, err := ParsePolicies("")
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+	t.Run("Valid", func(t *testing.T) {
+		result, err := ParsePolicies("fit_2560=precache, tile_50=on-demand,FIT_7680=never")
+		assert.NoError(t, err)
+		assert.Equal(t, map[Name]Policy{Fit2560: PolicyPrecache, Tile50: PolicyOnDemand, Fit7680: PolicyNever}, result)
+		assert.Equal(t, "fit_2560=precache,fit_7680=never,tile_50=on-demand", PoliciesString(result))
+	})
+	t.Run("UnknownSize", func(t *testing.T) {
+		_, err := ParsePolicies("fit_1=never")
+		assert.Error(t, err)
+	})
+	t.Run("InvalidPolicy", func(t *testing.T) {
+		_, err := ParsePolicies("fit_7680=later")
+		assert.Error(t, err)
+	})
+	t.Run("MissingSize", func(t *testing.T) {
+		_, err := ParsePolicies("never")
+		assert.Error(t, err)
+	})
+}
+
+func TestSize_Policy(t *testing.T) {
+	defer func() { Policies = map[Name]Policy{} }()
+
+	assert.Equal(t, PolicyPrecache, Sizes[Tile50].Policy())
+	assert.Equal(t, PolicyOnDemand, Sizes[Fit7680].Policy())
+	assert.False(t, Sizes[Tile50].Uncached())
+	assert.True(t, Sizes[Fit7680].Uncached())
+	assert.False(t, Sizes[Fit7680].OnDemand())
+
+	Policies = map[Name]Policy{Tile50: PolicyOnDemand, Fit7680: PolicyPrecache, Fit720: PolicyNever}
+
+	assert.True(t, Sizes[Tile50].Uncached())
+	assert.True(t, Sizes[Tile50].OnDemand())
+	assert.False(t, Sizes[Fit7680].Uncached())
+	assert.True(t, Sizes[Fit720].Never())
+	assert.True(t, Sizes[Fit720].Uncached())
+
+	name, _ := Find(720)
+	assert.Equal(t, Right224, name)
+}
@@ -0,0 +1,165 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"os"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/media"
+)
+
+// PosterSizeLimit is the maximum size of embedded video posters in bytes.
+var PosterSizeLimit int64 = 20 * 1024 * 1024
+
+// posterContainers contains the box types that are searched for cover art, see
+// https://developer.apple.com/documentation/quicktime-file-format/metadata_item_list_atom.
+var posterContainers = map[string]bool{
+	"moov": true,
+	"udta": true,
+	"meta": true,
+	"ilst": true,
+	"covr": true,
+}
+
+// IsVideo checks if the file is a video based on its extension.
+func IsVideo(fileName string) bool {
+	return media.Formats[fs.FileType(fileName)] == media.Video
+}
+
+// VideoPoster returns the poster image embedded in the metadata of an MP4 or QuickTime video,
+// so that it can be used instead of decoding a keyframe. It returns ErrNoPoster if there is none.
+func VideoPoster(fileName string) (data []byte, err error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return findPoster(f, 0, info.Size(), "", 0)
+}
+
+// OpenPoster decodes the poster image embedded in a video and rotates it if necessary.
+func OpenPoster(fileName string, orientation int) (image.Image, error) {
+	data, err := VideoPoster(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, fmt.Errorf("thumb: %s while decoding video poster", err)
+	}
+
+	if orientation > 1 {
+		img = Rotate(img, orientation)
+	}
+
+	return img, nil
+}
+
+// PosterJpeg saves the poster image embedded in a video as JPEG file.
+func PosterJpeg(videoFile, jpgFile string, orientation int) (img image.Image, err error) {
+	if img, err = OpenPoster(videoFile, orientation); err != nil {
+		return img, err
+	}
+
+	if err = SaveJpeg(img, jpgFile, JpegQuality); err != nil {
+		return img, err
+	}
+
+	return img, nil
+}
+
+// findPoster searches the boxes between start and end for cover art data.
+func findPoster(r io.ReadSeeker, start, end int64, parent string, depth int) ([]byte, error) {
+	if depth > 8 {
+		return nil, ErrNoPoster
+	}
+
+	for pos := start; pos+8 <= end; {
+		header := make([]byte, 16)
+
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return nil, err
+		} else if _, err = io.ReadFull(r, header[:8]); err != nil {
+			return nil, ErrNoPoster
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+
+		switch size {
+		case 0:
+			// Box extends to the end of the file.
+			size = end - pos
+		case 1:
+			// Box has a 64-bit size.
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return nil, ErrNoPoster
+			}
+
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+
+		if size < headerSize || pos+size > end {
+			return nil, ErrNoPoster
+		}
+
+		bodyStart, bodyEnd := pos+headerSize, pos+size
+
+		switch {
+		case boxType == "data" && parent == "covr" && bodyEnd-bodyStart > 8:
+			// Data boxes contain a type indicator and a locale before the value.
+			length := bodyEnd - bodyStart - 8
+
+			if length > PosterSizeLimit {
+				return nil, fmt.Errorf("thumb: video poster exceeds size limit (%d bytes)", length)
+			} else if _, err := r.Seek(bodyStart+8, io.SeekStart); err != nil {
+				return nil, err
+			}
+
+			data := make([]byte, length)
+
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, ErrNoPoster
+			}
+
+			return data, nil
+		case boxType == "meta":
+			// ISO meta boxes have a version and flags, QuickTime meta boxes start with a handler box.
+			peek := make([]byte, 8)
+
+			if _, err := io.ReadFull(r, peek); err == nil && string(peek[4:8]) != "hdlr" {
+				bodyStart += 4
+			}
+
+			fallthrough
+		case posterContainers[boxType]:
+			if data, err := findPoster(r, bodyStart, bodyEnd, boxType, depth+1); err == nil {
+				return data, nil
+			} else if err != ErrNoPoster {
+				return nil, err
+			}
+		}
+
+		pos += size
+	}
+
+	return nil, ErrNoPoster
+}
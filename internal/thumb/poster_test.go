@@ -0,0 +1,115 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// testBox returns an ISO base media file format box with the specified type and content.
+func testBox(boxType string, content ...[]byte) []byte {
+	body := bytes.Join(content, nil)
+	b := binary.BigEndian.AppendUint32(nil, uint32(len(body)+8))
+	b = append(b, boxType...)
+
+	return append(b, body...)
+}
+
+// testVideo returns a minimal MP4 file with the JPEG as cover art, if any.
+func testVideo(t *testing.T, jpeg []byte, quickTime bool) string {
+	hdlr := testBox("hdlr", make([]byte, 25))
+	ilst := testBox("ilst")
+
+	if jpeg != nil {
+		ilst = testBox("ilst", testBox("covr", testBox("data", []byte{0, 0, 0, 13, 0, 0, 0, 0}, jpeg)))
+	}
+
+	var moov []byte
+
+	if quickTime {
+		moov = testBox("moov", testBox("mvhd", make([]byte, 100)), testBox("meta", hdlr, ilst))
+	} else {
+		moov = testBox("moov", testBox("mvhd", make([]byte, 100)), testBox("udta", testBox("meta", make([]byte, 4), hdlr, ilst)))
+	}
+
+	data := bytes.Join([][]byte{testBox("ftyp", []byte("isom")), testBox("mdat", make([]byte, 64)), moov}, nil)
+	fileName := filepath.Join(t.TempDir(), "video.mp4")
+
+	if err := os.WriteFile(fileName, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+func testPosterJpeg(t *testing.T) []byte {
+	var buf bytes.Buffer
+
+	if err := imaging.Encode(&buf, imaging.New(32, 16, color.White), imaging.JPEG); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestVideoPoster(t *testing.T) {
+	t.Run("UserData", func(t *testing.T) {
+		jpeg := testPosterJpeg(t)
+		data, err := VideoPoster(testVideo(t, jpeg, false))
+
+		assert.NoError(t, err)
+		assert.Equal(t, jpeg, data)
+	})
+	t.Run("QuickTime", func(t *testing.T) {
+		jpeg := testPosterJpeg(t)
+		data, err := VideoPoster(testVideo(t, jpeg, true))
+
+		assert.NoError(t, err)
+		assert.Equal(t, jpeg, data)
+	})
+	t.Run("NoPoster", func(t *testing.T) {
+		_, err := VideoPoster(testVideo(t, nil, false))
+
+		assert.ErrorIs(t, err, ErrNoPoster)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := VideoPoster("testdata/missing.mp4")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestOpen_Video(t *testing.T) {
+	t.Run("Poster", func(t *testing.T) {
+		img, err := Open(testVideo(t, testPosterJpeg(t), false), 6)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Rotated by 90 degrees.
+		assert.Equal(t, image.Pt(16, 32), img.Bounds().Size())
+	})
+	t.Run("NoPoster", func(t *testing.T) {
+		_, err := Open(testVideo(t, nil, true), 1)
+
+		assert.ErrorIs(t, err, ErrNoPoster)
+	})
+}
+
+func TestPosterJpeg(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "video.jpg")
+
+	if _, err := PosterJpeg(testVideo(t, testPosterJpeg(t), false), dst, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.FileExists(t, dst)
+}
@@ -0,0 +1,406 @@
+package thumb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+)
+
+// PSD color modes, see https://www.adobe.com/devnet-apps/photoshop/fileformatashtml/.
+const (
+	psdModeGrayscale = 1
+	psdModeRGB       = 3
+)
+
+// PSD image resource IDs of the embedded JPEG previews.
+const (
+	psdResourceThumbnail    = 1036
+	psdResourceThumbnailBGR = 1033
+)
+
+// psdMaxComposite is the maximum number of pixels of the flattened composite image
+// that is decoded, larger files use their embedded preview instead.
+const psdMaxComposite = 48 * 1000 * 1000
+
+// psdMaxResource is the maximum size of the embedded preview resource in bytes.
+const psdMaxResource = 16 * 1024 * 1024
+
+func init() {
+	image.RegisterFormat("psd", "8BPS", DecodePsd, DecodePsdConfig)
+}
+
+// psdHeader contains the PSD file header.
+type psdHeader struct {
+	version  int
+	channels int
+	height   int
+	width    int
+	depth    int
+	mode     int
+}
+
+// psb checks if the file uses the large document format.
+func (h *psdHeader) psb() bool {
+	return h.version == 2
+}
+
+// composite checks if the flattened composite image can be decoded.
+func (h *psdHeader) composite() bool {
+	switch {
+	case h.width*h.height > psdMaxComposite:
+		return false
+	case h.depth != 8 && h.depth != 16:
+		return false
+	case h.mode == psdModeRGB && h.channels >= 3:
+		return true
+	case h.mode == psdModeGrayscale && h.channels >= 1:
+		return true
+	default:
+		return false
+	}
+}
+
+// DecodePsd decodes the flattened composite image of an Adobe Photoshop document. The JPEG preview
+// embedded in its image resources is used instead if the composite is too large or has an unsupported
+// color mode. ErrNoPreview is returned if neither can be decoded.
+func DecodePsd(r io.Reader) (image.Image, error) {
+	br := bufio.NewReader(r)
+
+	h, err := readPsdHeader(br)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip color mode data.
+	if err = psdSkipSection(br, false); err != nil {
+		return nil, err
+	}
+
+	preview, err := readPsdPreview(br)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !h.composite() {
+		return psdPreview(preview)
+	}
+
+	// Skip layer and mask information.
+	if err = psdSkipSection(br, h.psb()); err != nil {
+		return nil, err
+	}
+
+	img, err := readPsdComposite(br, h)
+
+	if err != nil {
+		log.Debugf("thumb: %s, using embedded preview", err)
+		return psdPreview(preview)
+	}
+
+	return img, nil
+}
+
+// DecodePsdConfig returns the color model and dimensions of an Adobe Photoshop document.
+func DecodePsdConfig(r io.Reader) (image.Config, error) {
+	h, err := readPsdHeader(bufio.NewReader(r))
+
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	if h.mode == psdModeGrayscale {
+		return image.Config{ColorModel: color.GrayModel, Width: h.width, Height: h.height}, nil
+	}
+
+	return image.Config{ColorModel: color.NRGBAModel, Width: h.width, Height: h.height}, nil
+}
+
+// readPsdHeader reads and validates the PSD file header.
+func readPsdHeader(r io.Reader) (*psdHeader, error) {
+	b := make([]byte, 26)
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("psd: %s", err)
+	} else if string(b[0:4]) != "8BPS" {
+		return nil, errors.New("psd: invalid file header")
+	}
+
+	h := &psdHeader{
+		version:  int(binary.BigEndian.Uint16(b[4:6])),
+		channels: int(binary.BigEndian.Uint16(b[12:14])),
+		height:   int(binary.BigEndian.Uint32(b[14:18])),
+		width:    int(binary.BigEndian.Uint32(b[18:22])),
+		depth:    int(binary.BigEndian.Uint16(b[22:24])),
+		mode:     int(binary.BigEndian.Uint16(b[24:26])),
+	}
+
+	if h.version != 1 && h.version != 2 {
+		return nil, fmt.Errorf("psd: version %d is not supported", h.version)
+	} else if h.width <= 0 || h.height <= 0 {
+		return nil, errors.New("psd: image has no dimensions")
+	}
+
+	return h, nil
+}
+
+// psdSkipSection skips a section that starts with its length.
+func psdSkipSection(r io.Reader, long bool) error {
+	var length int64
+
+	if long {
+		var n uint64
+
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return fmt.Errorf("psd: %s", err)
+		}
+
+		length = int64(n)
+	} else {
+		var n uint32
+
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return fmt.Errorf("psd: %s", err)
+		}
+
+		length = int64(n)
+	}
+
+	if _, err := io.CopyN(io.Discard, r, length); err != nil {
+		return fmt.Errorf("psd: %s", err)
+	}
+
+	return nil
+}
+
+// psdPreviewData contains an embedded JPEG preview and whether its colors are stored as BGR.
+type psdPreviewData struct {
+	jpeg []byte
+	bgr  bool
+}
+
+// readPsdPreview reads the image resources section and returns the embedded JPEG preview, if any.
+func readPsdPreview(r io.Reader) (result psdPreviewData, err error) {
+	var length uint32
+
+	if err = binary.Read(r, binary.BigEndian, &length); err != nil {
+		return result, fmt.Errorf("psd: %s", err)
+	}
+
+	section := io.LimitReader(r, int64(length))
+
+	// Skip the remaining resources when done.
+	defer func() {
+		if _, discardErr := io.Copy(io.Discard, section); discardErr != nil && err == nil {
+			err = fmt.Errorf("psd: %s", discardErr)
+		}
+	}()
+
+	for {
+		b := make([]byte, 8)
+
+		if _, err = io.ReadFull(section, b[:7]); err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return result, fmt.Errorf("psd: %s", err)
+		} else if string(b[0:4]) != "8BIM" {
+			return result, errors.New("psd: invalid image resource")
+		}
+
+		id := binary.BigEndian.Uint16(b[4:6])
+
+		// Skip the resource name, a Pascal string padded to an even size.
+		if nameLen := int64(b[6]); nameLen%2 == 0 {
+			if _, err = io.CopyN(io.Discard, section, nameLen+1); err != nil {
+				return result, fmt.Errorf("psd: %s", err)
+			}
+		} else if _, err = io.CopyN(io.Discard, section, nameLen); err != nil {
+			return result, fmt.Errorf("psd: %s", err)
+		}
+
+		var size uint32
+
+		if err = binary.Read(section, binary.BigEndian, &size); err != nil {
+			return result, fmt.Errorf("psd: %s", err)
+		}
+
+		padded := int64(size) + int64(size%2)
+
+		// The thumbnail resource has a 28 byte header followed by the JFIF data.
+		if (id == psdResourceThumbnail || id == psdResourceThumbnailBGR) && size > 28 && size <= psdMaxResource {
+			data := make([]byte, padded)
+
+			if _, err = io.ReadFull(section, data); err != nil {
+				return result, fmt.Errorf("psd: %s", err)
+			}
+
+			result = psdPreviewData{jpeg: data[28:size], bgr: id == psdResourceThumbnailBGR}
+		} else if _, err = io.CopyN(io.Discard, section, padded); err != nil {
+			return result, fmt.Errorf("psd: %s", err)
+		}
+	}
+}
+
+// psdPreview decodes the embedded JPEG preview.
+func psdPreview(preview psdPreviewData) (image.Image, error) {
+	if len(preview.jpeg) == 0 {
+		return nil, ErrNoPreview
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(preview.jpeg))
+
+	if err != nil {
+		return nil, fmt.Errorf("psd: %s", err)
+	} else if !preview.bgr {
+		return img, nil
+	}
+
+	// Photoshop 4.0 stored the preview with swapped red and blue channels.
+	b := img.Bounds()
+	result := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			result.SetNRGBA(x-b.Min.X, y-b.Min.Y, color.NRGBA{R: c.B, G: c.G, B: c.R, A: 255})
+		}
+	}
+
+	return result, nil
+}
+
+// readPsdComposite reads the flattened composite image from the image data section.
+func readPsdComposite(r io.Reader, h *psdHeader) (image.Image, error) {
+	var compression uint16
+
+	if err := binary.Read(r, binary.BigEndian, &compression); err != nil {
+		return nil, fmt.Errorf("psd: %s", err)
+	}
+
+	channels := 1
+
+	if h.mode == psdModeRGB {
+		channels = 3
+	}
+
+	rowSize := h.width * h.depth / 8
+	planes := make([][]byte, channels)
+
+	switch compression {
+	case 0:
+		for c := 0; c < channels; c++ {
+			planes[c] = make([]byte, rowSize*h.height)
+
+			if _, err := io.ReadFull(r, planes[c]); err != nil {
+				return nil, fmt.Errorf("psd: %s", err)
+			}
+		}
+	case 1:
+		// The byte counts of all rows in all channels precede the compressed data.
+		countSize := 2
+
+		if h.psb() {
+			countSize = 4
+		}
+
+		counts := make([]byte, h.height*h.channels*countSize)
+
+		if _, err := io.ReadFull(r, counts); err != nil {
+			return nil, fmt.Errorf("psd: %s", err)
+		}
+
+		for c := 0; c < channels; c++ {
+			planes[c] = make([]byte, rowSize*h.height)
+
+			for y := 0; y < h.height; y++ {
+				i := (c*h.height + y) * countSize
+
+				var n int
+
+				if h.psb() {
+					n = int(binary.BigEndian.Uint32(counts[i:]))
+				} else {
+					n = int(binary.BigEndian.Uint16(counts[i:]))
+				}
+
+				packed := make([]byte, n)
+
+				if _, err := io.ReadFull(r, packed); err != nil {
+					return nil, fmt.Errorf("psd: %s", err)
+				}
+
+				if err := unpackBits(packed, planes[c][y*rowSize:(y+1)*rowSize]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("psd: compression %d is not supported", compression)
+	}
+
+	// Use the most significant byte of 16-bit samples.
+	step := h.depth / 8
+	n := h.width * h.height
+
+	if channels == 1 {
+		img := image.NewGray(image.Rect(0, 0, h.width, h.height))
+
+		for i := 0; i < n; i++ {
+			img.Pix[i] = planes[0][i*step]
+		}
+
+		return img, nil
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, h.width, h.height))
+
+	for i := 0; i < n; i++ {
+		img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2], img.Pix[i*4+3] = planes[0][i*step], planes[1][i*step], planes[2][i*step], 255
+	}
+
+	return img, nil
+}
+
+// unpackBits decompresses PackBits encoded data into dst.
+func unpackBits(src, dst []byte) error {
+	var i, j int
+
+	for i < len(src) && j < len(dst) {
+		n := int(int8(src[i]))
+		i++
+
+		switch {
+		case n >= 0:
+			if i+n+1 > len(src) || j+n+1 > len(dst) {
+				return errors.New("psd: invalid packbits data")
+			}
+
+			j += copy(dst[j:], src[i:i+n+1])
+			i += n + 1
+		case n > -128:
+			if i >= len(src) || j+1-n > len(dst) {
+				return errors.New("psd: invalid packbits data")
+			}
+
+			for k := 0; k < 1-n; k++ {
+				dst[j] = src[i]
+				j++
+			}
+
+			i++
+		}
+	}
+
+	if j != len(dst) {
+		return errors.New("psd: packbits data is incomplete")
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPsd returns a Photoshop document with 4x2 pixels in the specified color mode and optionally an embedded JPEG preview.
+func testPsd(mode uint16, compression uint16, preview bool) []byte {
+	var w bytes.Buffer
+
+	w.WriteString("8BPS")
+	_ = binary.Write(&w, binary.BigEndian, uint16(1))
+	w.Write(make([]byte, 6))
+	_ = binary.Write(&w, binary.BigEndian, uint16(3))
+	_ = binary.Write(&w, binary.BigEndian, uint32(2))
+	_ = binary.Write(&w, binary.BigEndian, uint32(4))
+	_ = binary.Write(&w, binary.BigEndian, uint16(8))
+	_ = binary.Write(&w, binary.BigEndian, mode)
+
+	// Color mode data.
+	_ = binary.Write(&w, binary.BigEndian, uint32(0))
+
+	// Image resources.
+	var res bytes.Buffer
+
+	if preview {
+		img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+
+		for i := 0; i < 2; i++ {
+			img.Set(i, 0, color.RGBA{R: 0, G: 0, B: 255, A: 255})
+		}
+
+		var jpg bytes.Buffer
+
+		_ = jpeg.Encode(&jpg, img, nil)
+
+		data := append(make([]byte, 28), jpg.Bytes()...)
+
+		res.WriteString("8BIM")
+		_ = binary.Write(&res, binary.BigEndian, uint16(psdResourceThumbnail))
+		res.Write([]byte{0, 0})
+		_ = binary.Write(&res, binary.BigEndian, uint32(len(data)))
+		res.Write(data)
+
+		if len(data)%2 != 0 {
+			res.WriteByte(0)
+		}
+	}
+
+	_ = binary.Write(&w, binary.BigEndian, uint32(res.Len()))
+	w.Write(res.Bytes())
+
+	// Layer and mask information.
+	_ = binary.Write(&w, binary.BigEndian, uint32(4))
+	w.Write(make([]byte, 4))
+
+	// Composite image data with red, green, and blue planes.
+	_ = binary.Write(&w, binary.BigEndian, compression)
+
+	planes := [][]byte{
+		{255, 255, 255, 255, 0, 0, 0, 0},
+		{0, 0, 0, 0, 255, 255, 255, 255},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	}
+
+	if compression == 0 {
+		for _, p := range planes {
+			w.Write(p)
+		}
+	} else {
+		// Each row is a single PackBits run of 4 identical bytes.
+		for i := 0; i < 6; i++ {
+			_ = binary.Write(&w, binary.BigEndian, uint16(2))
+		}
+
+		for _, p := range planes {
+			w.Write([]byte{0xFD, p[0], 0xFD, p[4]})
+		}
+	}
+
+	return w.Bytes()
+}
+
+func TestDecodePsd(t *testing.T) {
+	t.Run("Raw", func(t *testing.T) {
+		img, err := DecodePsd(bytes.NewReader(testPsd(psdModeRGB, 0, false)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, image.Rect(0, 0, 4, 2), img.Bounds())
+		assert.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(0, 0))
+		assert.Equal(t, color.NRGBA{G: 255, A: 255}, img.At(3, 1))
+	})
+	t.Run("RLE", func(t *testing.T) {
+		img, err := DecodePsd(bytes.NewReader(testPsd(psdModeRGB, 1, true)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, image.Rect(0, 0, 4, 2), img.Bounds())
+		assert.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(3, 0))
+		assert.Equal(t, color.NRGBA{G: 255, A: 255}, img.At(0, 1))
+	})
+	t.Run("Preview", func(t *testing.T) {
+		// CMYK composites are not decoded, so that the embedded preview is used.
+		img, err := DecodePsd(bytes.NewReader(testPsd(4, 0, true)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, image.Rect(0, 0, 2, 1), img.Bounds())
+
+		r, g, b, _ := img.At(0, 0).RGBA()
+
+		assert.Less(t, r, b)
+		assert.Less(t, g, b)
+	})
+	t.Run("NoPreview", func(t *testing.T) {
+		_, err := DecodePsd(bytes.NewReader(testPsd(4, 0, false)))
+
+		assert.ErrorIs(t, err, ErrNoPreview)
+	})
+	t.Run("InvalidHeader", func(t *testing.T) {
+		_, err := DecodePsd(bytes.NewReader(make([]byte, 64)))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodePsdConfig(t *testing.T) {
+	cfg, err := DecodePsdConfig(bytes.NewReader(testPsd(psdModeRGB, 0, true)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 4, cfg.Width)
+	assert.Equal(t, 2, cfg.Height)
+}
+
+func TestUnpackBits(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dst := make([]byte, 5)
+
+		// A repeated run of three bytes, a no-op, and two literal bytes.
+		assert.NoError(t, unpackBits([]byte{0xFE, 7, 0x80, 1, 1, 2}, dst))
+		assert.Equal(t, []byte{7, 7, 7, 1, 2}, dst)
+	})
+	t.Run("Incomplete", func(t *testing.T) {
+		assert.Error(t, unpackBits([]byte{0xFE, 7}, make([]byte, 4)))
+	})
+}
+
+func TestFromFile_Psd(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "design.psd")
+
+	if err := os.WriteFile(fileName, testPsd(psdModeRGB, 1, true), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	thumbPath := t.TempDir()
+
+	result, err := FromFile(fileName, "ca2f7ee4b8d2e2ff5a8e0c3c7e6e0a9d5e7b1234", thumbPath, 2, 2, 1, ResampleFit)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.FileExists(t, result)
+}
@@ -0,0 +1,135 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+	gc "github.com/patrickmn/go-cache"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Quality modes of modern image formats, see SaveFormat.
+const (
+	QualityFixed = "fixed"
+	QualityAuto  = "auto"
+)
+
+var (
+	FormatQuality      = QualityFixed
+	AutoQualityTarget  = 0.98
+	AutoQualityMin     = Quality(40)
+	AutoQualitySteps   = 5
+	autoQualityResults = gc.New(24*time.Hour, 10*time.Minute)
+)
+
+// ParseFormatQuality returns the quality mode matching the config value, or QualityFixed if it is unknown.
+func ParseFormatQuality(s string) string {
+	if strings.ToLower(strings.TrimSpace(s)) == QualityAuto {
+		return QualityAuto
+	}
+
+	return QualityFixed
+}
+
+// UseAutoQuality checks if the encoder quality of the format is chosen for each image, see AutoQuality.
+func UseAutoQuality(f fs.Type) bool {
+	if FormatQuality != QualityAuto {
+		return false
+	}
+
+	switch f {
+	case fs.ImageWebP:
+		return CwebpBin != ""
+	case fs.ImageAVIF:
+		return AvifencBin != "" && AvifdecBin() != ""
+	default:
+		return false
+	}
+}
+
+// AvifdecBin returns the avifdec executable file name of the AVIF decoder, which is expected in the same
+// folder as the encoder, or an empty string if it was not found.
+func AvifdecBin() string {
+	if AvifencBin == "" {
+		return ""
+	} else if bin := filepath.Join(filepath.Dir(AvifencBin), "avifdec"); fs.FileExists(bin) {
+		return bin
+	}
+
+	return ""
+}
+
+// AutoQuality returns the lowest encoder quality up to the maximum at which the image in the specified
+// format is still similar enough to the original, see AutoQualityTarget. Flat images therefore get a lower
+// quality and smaller files than detailed ones. The search is limited to AutoQualitySteps encodings,
+// and its result is cached by file name. The maximum is returned if the quality cannot be determined.
+func AutoQuality(img image.Image, fileName string, f fs.Type, max Quality) Quality {
+	key := fmt.Sprintf("%s:%d", filepath.Base(fileName), max)
+
+	if cached, ok := autoQualityResults.Get(key); ok {
+		return cached.(Quality)
+	}
+
+	ext := filepath.Ext(fileName)
+	tmpName := strings.TrimSuffix(fileName, ext) + ".auto.tmp" + ext
+
+	defer os.Remove(tmpName)
+
+	result := max
+	lo, hi := AutoQualityMin, max
+
+	for i := 0; i < AutoQualitySteps && lo <= hi; i++ {
+		q := (lo + hi) / 2
+
+		if err := saveFormat(img, tmpName, f, q); err != nil {
+			log.Debugf("thumb: %s while choosing quality of %s", err, clean.Log(filepath.Base(fileName)))
+			return max
+		}
+
+		encoded, err := decodeFormat(tmpName, f)
+
+		if err != nil {
+			log.Debugf("thumb: %s while choosing quality of %s", err, clean.Log(filepath.Base(fileName)))
+			return max
+		}
+
+		if SSIM(img, encoded) >= AutoQualityTarget {
+			result, hi = q, q-1
+		} else {
+			lo = q + 1
+		}
+	}
+
+	autoQualityResults.SetDefault(key, result)
+
+	return result
+}
+
+// decodeFormat decodes an image that was saved in the specified format.
+func decodeFormat(fileName string, f fs.Type) (image.Image, error) {
+	if f != fs.ImageAVIF {
+		return imaging.Open(fileName)
+	}
+
+	pngName := fileName + ".png"
+
+	defer os.Remove(pngName)
+
+	if out, err := exec.Command(AvifdecBin(), fileName, pngName).CombinedOutput(); err != nil {
+		if s := strings.TrimSpace(string(out)); s != "" {
+			return nil, fmt.Errorf("%s (avifdec)", s)
+		}
+
+		return nil, fmt.Errorf("%s (avifdec)", err)
+	}
+
+	return imaging.Open(pngName)
+}
@@ -0,0 +1,73 @@
+package thumb
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestParseFormatQuality(t *testing.T) {
+	assert.Equal(t, QualityAuto, ParseFormatQuality("auto"))
+	assert.Equal(t, QualityAuto, ParseFormatQuality(" AUTO "))
+	assert.Equal(t, QualityFixed, ParseFormatQuality("fixed"))
+	assert.Equal(t, QualityFixed, ParseFormatQuality(""))
+	assert.Equal(t, QualityFixed, ParseFormatQuality("foo"))
+}
+
+func TestUseAutoQuality(t *testing.T) {
+	defer func() { FormatQuality, CwebpBin, AvifencBin = QualityFixed, "", "" }()
+
+	CwebpBin, AvifencBin = "/usr/bin/cwebp", "/usr/bin/avifenc"
+	assert.False(t, UseAutoQuality(fs.ImageWebP))
+
+	FormatQuality = QualityAuto
+	assert.True(t, UseAutoQuality(fs.ImageWebP))
+	assert.False(t, UseAutoQuality(fs.ImageJPEG))
+
+	CwebpBin = ""
+	assert.False(t, UseAutoQuality(fs.ImageWebP))
+}
+
+func TestAutoQuality(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Lossless fake encoder that copies the PNG input, so that every quality matches the original.
+	bin := filepath.Join(tempDir, "cwebp")
+
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\ncp \"$4\" \"$6\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { FormatQuality, CwebpBin = QualityFixed, "" }()
+
+	FormatQuality, CwebpBin = QualityAuto, bin
+
+	img := imaging.New(64, 64, color.NRGBA{R: 100, G: 150, B: 200, A: 255})
+	fileName := filepath.Join(tempDir, "flat.webp")
+
+	t.Run("Lowest", func(t *testing.T) {
+		assert.Equal(t, AutoQualityMin, AutoQuality(img, fileName, fs.ImageWebP, QualityDefault))
+		assert.NoFileExists(t, filepath.Join(tempDir, "flat.auto.tmp.webp"))
+	})
+	t.Run("Cached", func(t *testing.T) {
+		CwebpBin = "/bin/false"
+		assert.Equal(t, AutoQualityMin, AutoQuality(img, fileName, fs.ImageWebP, QualityDefault))
+		CwebpBin = bin
+	})
+	t.Run("EncoderFails", func(t *testing.T) {
+		CwebpBin = "/bin/false"
+		assert.Equal(t, QualityHigh, AutoQuality(img, filepath.Join(tempDir, "other.webp"), fs.ImageWebP, QualityHigh))
+		CwebpBin = bin
+	})
+	t.Run("SaveFormat", func(t *testing.T) {
+		saveName := filepath.Join(tempDir, "saved.webp")
+		assert.NoError(t, SaveFormat(img, saveName, fs.ImageWebP, QualityDefault))
+		assert.FileExists(t, saveName)
+	})
+}
@@ -0,0 +1,154 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// QualityChanged is the time at which the JPEG quality settings were last changed, see CheckQuality.
+// JPEG thumbnails created before are stale and get refreshed in the background when requested.
+var QualityChanged time.Time
+
+// RefreshJob represents a stale thumbnail that should be created again.
+type RefreshJob struct {
+	ThumbName string
+	Hash      string
+	ThumbPath string
+	Size      Size
+}
+
+var (
+	refreshJobs  = make(map[string]RefreshJob)
+	refreshMutex = sync.Mutex{}
+)
+
+// QualityStamp returns the current JPEG quality settings as string, e.g. "85,75".
+func QualityStamp() string {
+	return fmt.Sprintf("%d,%d", JpegQuality, JpegQualitySmall)
+}
+
+// CheckQuality compares the current JPEG quality settings with those stored in the stamp file, and sets
+// QualityChanged to the time at which they were last changed. The stamp file is updated if they differ,
+// so its modification time marks the change. It returns true if the settings have changed.
+func CheckQuality(stampFile string) (changed bool, err error) {
+	stamp := QualityStamp()
+
+	data, readErr := os.ReadFile(stampFile)
+
+	if readErr == nil && strings.TrimSpace(string(data)) == stamp {
+		if info, statErr := os.Stat(stampFile); statErr == nil {
+			QualityChanged = info.ModTime()
+		}
+
+		return false, nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(stampFile), fs.ModeDir); err != nil {
+		return false, err
+	} else if err = os.WriteFile(stampFile, []byte(stamp), fs.ModeFile); err != nil {
+		return false, err
+	}
+
+	// Existing thumbnails cannot be stale if the settings were unknown.
+	if readErr != nil {
+		QualityChanged = time.Time{}
+		return false, os.Chtimes(stampFile, time.Unix(0, 0), time.Unix(0, 0))
+	}
+
+	QualityChanged = time.Now()
+
+	return true, nil
+}
+
+// Stale checks if the JPEG thumbnail was created before the quality settings were changed.
+func Stale(thumbName string) bool {
+	if QualityChanged.IsZero() || fs.FileType(thumbName) != fs.ImageJPEG {
+		return false
+	}
+
+	info, err := os.Stat(thumbName)
+
+	return err == nil && info.ModTime().Before(QualityChanged)
+}
+
+// QueueRefresh adds the thumbnail to the refresh queue if it is stale, so that it can be served
+// as is while it is created again in the background. It returns true if the thumbnail is stale.
+func QueueRefresh(thumbName, hash, thumbPath string, size Size) bool {
+	if len(hash) < 4 || thumbPath == "" || !Stale(thumbName) {
+		return false
+	}
+
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+
+	refreshJobs[thumbName] = RefreshJob{
+		ThumbName: thumbName,
+		Hash:      hash,
+		ThumbPath: thumbPath,
+		Size:      size,
+	}
+
+	return true
+}
+
+// RefreshJobs removes all queued stale thumbnails from the queue and returns them.
+func RefreshJobs() (jobs []RefreshJob) {
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+
+	for key, job := range refreshJobs {
+		jobs = append(jobs, job)
+		delete(refreshJobs, key)
+	}
+
+	return jobs
+}
+
+// Refresh creates the stale thumbnail again from the original image. The new thumbnail is saved to a
+// temporary file first and then replaces the stale one, so that it can be served without interruption.
+func Refresh(job RefreshJob, imageFilename string, orientation int, angle float64) error {
+	if !Stale(job.ThumbName) {
+		return nil
+	}
+
+	img, err := openSource(imageFilename, job.Hash, job.ThumbPath, orientation, angle)
+
+	if err != nil {
+		return err
+	}
+
+	// The temporary file name must keep the extension, so that the right encoder is used.
+	ext := filepath.Ext(job.ThumbName)
+	tmp, err := os.CreateTemp(filepath.Dir(job.ThumbName), "."+strings.TrimSuffix(filepath.Base(job.ThumbName), ext)+".*"+ext)
+
+	if err != nil {
+		return err
+	}
+
+	tmpName := tmp.Name()
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	result := Render(img, job.ThumbName, job.Size.Width, job.Size.Height, job.Size.Options...)
+
+	if err = SaveJpeg(result, tmpName, SizeQuality(job.Size.Width, job.Size.Height)); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	} else if err = os.Rename(tmpName, job.ThumbName); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+
+	log.Debugf("thumb: refreshed %s", clean.Log(filepath.Base(job.ThumbName)))
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package thumb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckQuality(t *testing.T) {
+	defer func() { QualityChanged = time.Time{} }()
+
+	stampFile := filepath.Join(t.TempDir(), ".quality")
+
+	// Unknown settings.
+	changed, err := CheckQuality(stampFile)
+
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.True(t, QualityChanged.IsZero())
+
+	// Same settings.
+	changed, err = CheckQuality(stampFile)
+
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, int64(0), QualityChanged.Unix())
+
+	// Changed settings.
+	quality := JpegQuality
+	JpegQuality = quality - 1
+	defer func() { JpegQuality = quality }()
+
+	changed, err = CheckQuality(stampFile)
+
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.WithinDuration(t, time.Now(), QualityChanged, time.Minute)
+}
+
+func TestRefresh(t *testing.T) {
+	defer func() { QualityChanged = time.Time{} }()
+
+	hash := "1234567890abcdef1234567890abcdef12345678"
+	thumbPath := t.TempDir()
+	size := Sizes[Tile224]
+
+	thumbName, err := size.FromFile("testdata/example.jpg", hash, thumbPath, 1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, QueueRefresh(thumbName, hash, thumbPath, size))
+
+	old := time.Now().Add(-time.Hour)
+
+	if err = os.Chtimes(thumbName, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	QualityChanged = time.Now().Add(-time.Minute)
+
+	assert.True(t, Stale(thumbName))
+	assert.True(t, QueueRefresh(thumbName, hash, thumbPath, size))
+
+	jobs := RefreshJobs()
+
+	assert.Len(t, jobs, 1)
+	assert.Empty(t, RefreshJobs())
+
+	if err = Refresh(jobs[0], "testdata/example.jpg", 1, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.False(t, Stale(thumbName))
+
+	// No temporary files remain.
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(thumbName), ".*"))
+	assert.Empty(t, matches)
+}
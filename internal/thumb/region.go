@@ -0,0 +1,74 @@
+package thumb
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"time"
+
+	gc "github.com/patrickmn/go-cache"
+)
+
+// Region represents a crop area relative to the image dimensions, with values from 0 to 1.
+type Region struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+}
+
+// regionCache caches the computed crop regions by thumbnail file name.
+var regionCache = gc.New(24*time.Hour, 10*time.Minute)
+
+// NewRegion returns the crop area relative to the image bounds.
+func NewRegion(bounds, area image.Rectangle) Region {
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return Region{}
+	}
+
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+
+	return Region{
+		X: float64(area.Min.X-bounds.Min.X) / w,
+		Y: float64(area.Min.Y-bounds.Min.Y) / h,
+		W: float64(area.Dx()) / w,
+		H: float64(area.Dy()) / h,
+	}
+}
+
+// Empty tests if the region is empty.
+func (r Region) Empty() bool {
+	return r.W <= 0 || r.H <= 0
+}
+
+// String returns the region as comma-separated list of x, y, width, and height, e.g. "0.1250,0,0.7500,1".
+func (r Region) String() string {
+	if r.Empty() {
+		return ""
+	}
+
+	return fmt.Sprintf("%.4f,%.4f,%.4f,%.4f", r.X, r.Y, r.W, r.H)
+}
+
+// SetCropRegion remembers the computed crop region of a thumbnail.
+func SetCropRegion(fileName string, r Region) {
+	if fileName == "" || r.Empty() {
+		return
+	}
+
+	regionCache.SetDefault(filepath.Base(fileName), r)
+}
+
+// CropRegion returns the computed crop region of a thumbnail, if known. Regions are only known for
+// thumbnails with a computed crop, e.g. based on entropy, that were created since the last restart.
+func CropRegion(fileName string) (r Region, ok bool) {
+	if fileName == "" {
+		return r, false
+	}
+
+	if cached, found := regionCache.Get(filepath.Base(fileName)); found {
+		return cached.(Region), true
+	}
+
+	return r, false
+}
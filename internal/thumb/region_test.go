@@ -0,0 +1,46 @@
+package thumb
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRegion(t *testing.T) {
+	t.Run("Crop", func(t *testing.T) {
+		r := NewRegion(image.Rect(0, 0, 400, 200), image.Rect(100, 0, 300, 200))
+
+		assert.Equal(t, Region{X: 0.25, Y: 0, W: 0.5, H: 1}, r)
+		assert.Equal(t, "0.2500,0.0000,0.5000,1.0000", r.String())
+	})
+	t.Run("Offset", func(t *testing.T) {
+		r := NewRegion(image.Rect(10, 10, 110, 210), image.Rect(10, 60, 110, 160))
+
+		assert.Equal(t, Region{X: 0, Y: 0.25, W: 1, H: 0.5}, r)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		r := NewRegion(image.Rectangle{}, image.Rect(0, 0, 10, 10))
+
+		assert.True(t, r.Empty())
+		assert.Equal(t, "", r.String())
+	})
+}
+
+func TestCropRegion(t *testing.T) {
+	fileName := "testdata/1234567890abcdef_500x500_entropy.jpg"
+
+	_, ok := CropRegion(fileName)
+	assert.False(t, ok)
+
+	SetCropRegion(fileName, Region{})
+
+	_, ok = CropRegion(fileName)
+	assert.False(t, ok)
+
+	SetCropRegion(fileName, Region{X: 0.1, Y: 0.2, W: 0.3, H: 0.4})
+
+	r, ok := CropRegion("/other/path/1234567890abcdef_500x500_entropy.jpg")
+	assert.True(t, ok)
+	assert.Equal(t, "0.1000,0.2000,0.3000,0.4000", r.String())
+}
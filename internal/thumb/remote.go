@@ -0,0 +1,156 @@
+package thumb
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+var (
+	RemoteOriginals       = false
+	RemoteTimeout         = 30 * time.Second
+	RemoteSizeLimit int64 = 100 * 1024 * 1024
+)
+
+// remoteExtRegexp matches file extensions that are safe to use for cached remote originals.
+var remoteExtRegexp = regexp.MustCompile(`^\.[a-z0-9]{1,5}$`)
+
+// remoteDownloads prevents the same remote original from being downloaded concurrently.
+var remoteDownloads singleflight.Group
+
+// IsRemote checks if the original file name is an http(s) URL.
+func IsRemote(fileName string) bool {
+	return strings.HasPrefix(fileName, "https://") || strings.HasPrefix(fileName, "http://")
+}
+
+// RemoteFile returns the name of a local copy of a remote original in the thumbnail cache
+// folder and downloads it first if needed.
+func RemoteFile(rawUrl, thumbPath string) (fileName string, err error) {
+	if !RemoteOriginals {
+		return "", ErrRemoteDisabled
+	} else if fileName, err = remoteFileName(rawUrl, thumbPath); err != nil {
+		return "", err
+	} else if fs.FileExists(fileName) {
+		return fileName, nil
+	}
+
+	_, err, _ = remoteDownloads.Do(fileName, func() (interface{}, error) {
+		return nil, downloadRemote(rawUrl, fileName)
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
+
+// RemoteCached checks if a local copy of a remote original exists in the thumbnail cache folder.
+func RemoteCached(rawUrl, thumbPath string) bool {
+	fileName, err := remoteFileName(rawUrl, thumbPath)
+
+	return err == nil && fs.FileExists(fileName)
+}
+
+// remoteFileName returns the name of the local copy of a remote original in the thumbnail cache folder.
+func remoteFileName(rawUrl, thumbPath string) (string, error) {
+	if thumbPath == "" {
+		return "", fmt.Errorf("thumb: folder is empty")
+	}
+
+	u, err := url.Parse(rawUrl)
+
+	if err != nil || u.Host == "" || u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("thumb: invalid url %s", clean.Log(rawUrl))
+	}
+
+	// Cached files are named after the hash of the URL.
+	sum := sha1.Sum([]byte(rawUrl))
+	hash := hex.EncodeToString(sum[:])
+	ext := strings.ToLower(path.Ext(u.Path))
+
+	if !remoteExtRegexp.MatchString(ext) {
+		ext = ""
+	}
+
+	return filepath.Join(thumbPath, "remote", hash[0:1], hash[1:2], hash+ext), nil
+}
+
+// downloadRemote downloads a remote image file, respecting the timeout and size limit.
+func downloadRemote(rawUrl, fileName string) error {
+	if fs.FileExists(fileName) {
+		return nil
+	}
+
+	client := &http.Client{Timeout: RemoteTimeout}
+
+	resp, err := client.Get(rawUrl)
+
+	if err != nil {
+		return fmt.Errorf("thumb: %s", clean.Error(err))
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("thumb: remote original returned status %d", resp.StatusCode)
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return fmt.Errorf("thumb: remote original has unsupported content type %s", clean.Log(resp.Header.Get("Content-Type")))
+	}
+
+	if RemoteSizeLimit > 0 && resp.ContentLength > RemoteSizeLimit {
+		return fmt.Errorf("thumb: remote original exceeds size limit (%d bytes)", resp.ContentLength)
+	}
+
+	dir := filepath.Dir(fileName)
+
+	if err = os.MkdirAll(dir, fs.ModeDir); err != nil {
+		return err
+	}
+
+	// Download to a temporary file first, so that incomplete downloads are never used.
+	tmp, err := os.CreateTemp(dir, ".download-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	var body io.Reader = resp.Body
+
+	if RemoteSizeLimit > 0 {
+		body = io.LimitReader(resp.Body, RemoteSizeLimit+1)
+	}
+
+	n, err := io.Copy(tmp, body)
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("thumb: %s while downloading remote original", clean.Error(err))
+	} else if RemoteSizeLimit > 0 && n > RemoteSizeLimit {
+		return fmt.Errorf("thumb: remote original exceeds size limit (%d bytes)", RemoteSizeLimit)
+	}
+
+	return os.Rename(tmp.Name(), fileName)
+}
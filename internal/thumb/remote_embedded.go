@@ -0,0 +1,132 @@
+package thumb
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// RemoteRangeSize is the number of bytes requested from remote originals to read their embedded preview, see
+// FromRemoteEmbedded. EXIF data is limited to 64 KB, and is usually stored at the beginning of the file.
+var RemoteRangeSize int64 = 128 * 1024
+
+// RemoteEmbeddedLimit is the maximum width and height of thumbnails that are created from the preview embedded
+// in remote originals, as larger previews are rare and the original is downloaded for larger sizes anyway.
+var RemoteEmbeddedLimit = 720
+
+// RemoteEmbeddedPreview requests only the first bytes of a remote JPEG original with an HTTP range request,
+// e.g. from a WebDAV server, and returns the largest embedded preview and its dimensions, as well as the dimensions of the image.
+// It returns ErrNoPreview if there is none within the requested range.
+func RemoteEmbeddedPreview(rawUrl string) (data []byte, preview image.Config, width, height int, err error) {
+	if !RemoteOriginals {
+		return nil, preview, 0, 0, ErrRemoteDisabled
+	}
+
+	u, err := url.Parse(rawUrl)
+
+	if err != nil || u.Host == "" || u.Scheme != "http" && u.Scheme != "https" {
+		return nil, preview, 0, 0, fmt.Errorf("thumb: invalid url %s", clean.Log(rawUrl))
+	} else if fs.FileType("remote"+strings.ToLower(path.Ext(u.Path))) != fs.ImageJPEG {
+		return nil, preview, 0, 0, ErrNoPreview
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawUrl, nil)
+
+	if err != nil {
+		return nil, preview, 0, 0, err
+	}
+
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", RemoteRangeSize-1))
+
+	client := &http.Client{Timeout: RemoteTimeout}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return nil, preview, 0, 0, fmt.Errorf("thumb: %s", clean.Error(err))
+	}
+
+	// Servers that don't support range requests send the complete file, of which only
+	// the first bytes are read before the connection is closed.
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+		return nil, preview, 0, 0, fmt.Errorf("thumb: remote original returned status %d", resp.StatusCode)
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return nil, preview, 0, 0, fmt.Errorf("thumb: remote original has unsupported content type %s", clean.Log(resp.Header.Get("Content-Type")))
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, RemoteRangeSize))
+
+	if err != nil {
+		return nil, preview, 0, 0, fmt.Errorf("thumb: %s while reading remote original", clean.Error(err))
+	}
+
+	// The EXIF data or the dimensions may be beyond the requested range.
+	if data, preview, width, height, err = readEmbeddedPreview(bytes.NewReader(head)); err != nil {
+		return nil, preview, 0, 0, ErrNoPreview
+	}
+
+	return data, preview, width, height, nil
+}
+
+// FromRemoteEmbedded creates a thumbnail of a remote original from its embedded preview, so that the original
+// does not need to be downloaded, and returns the filename. It returns ErrNoPreview if the preview is missing
+// or too small, in which case the original must be downloaded with RemoteFile instead.
+func FromRemoteEmbedded(rawUrl, hash, thumbPath string, width, height, orientation int, opts ...ResampleOption) (fileName string, err error) {
+	if !UseEmbedded || width > RemoteEmbeddedLimit || height > RemoteEmbeddedLimit {
+		return "", ErrNoPreview
+	} else if fileName, err = FromCache(rawUrl, hash, thumbPath, width, height, opts...); err == nil {
+		return fileName, nil
+	} else if err != ErrNotCached {
+		return "", err
+	}
+
+	data, cfg, srcWidth, srcHeight, err := RemoteEmbeddedPreview(rawUrl)
+
+	if err != nil {
+		return "", err
+	}
+
+	// Check the dimensions before decoding the preview, as it is often too small.
+	if !EmbeddedAdequate(cfg.Width, cfg.Height, srcWidth, srcHeight, orientation, width, height, opts...) {
+		return "", ErrNoPreview
+	}
+
+	preview, err := jpeg.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		log.Debugf("thumb: %s while decoding embedded preview", err)
+		return "", ErrNoPreview
+	}
+
+	if orientation > 1 {
+		preview = Rotate(preview, orientation)
+	}
+
+	if fileName, err = FileName(hash, thumbPath, width, height, opts...); err != nil {
+		return "", err
+	} else if _, err = Create(preview, fileName, width, height, opts...); err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
+
+// FromRemoteEmbedded creates a thumbnail with the matching size from the preview embedded in a remote original,
+// see FromRemoteEmbedded.
+func (s Size) FromRemoteEmbedded(rawUrl, fileHash, cachePath string, fileOrientation int) (string, error) {
+	return FromRemoteEmbedded(rawUrl, fileHash, cachePath, s.Width, s.Height, fileOrientation, s.Options...)
+}
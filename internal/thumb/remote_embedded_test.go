@@ -0,0 +1,108 @@
+package thumb
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromRemoteEmbedded(t *testing.T) {
+	data, err := os.ReadFile(embeddedExample)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/photo.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			rec := httptest.NewRecorder()
+			http.ServeContent(rec, r, "photo.jpg", time.Time{}, bytes.NewReader(data))
+			atomic.AddInt64(&sent, int64(rec.Body.Len()))
+
+			for k, v := range rec.Header() {
+				w.Header()[k] = v
+			}
+
+			w.WriteHeader(rec.Code)
+			_, _ = w.Write(rec.Body.Bytes())
+		case "/photo.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(data)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	defer server.Close()
+
+	RemoteOriginals = true
+	UseEmbedded = true
+
+	defer func() {
+		RemoteOriginals = false
+		UseEmbedded = false
+	}()
+
+	thumbPath := t.TempDir()
+
+	t.Run("Tile100", func(t *testing.T) {
+		atomic.StoreInt64(&sent, 0)
+
+		fileName, err := FromRemoteEmbedded(server.URL+"/photo.jpg", "a9cd9168fa6acc5c5c2965ddf6ec465ca42fd818", thumbPath, 100, 100, 1, ResampleFillCenter)
+
+		assert.NoError(t, err)
+		assert.FileExists(t, fileName)
+
+		// Only the first bytes of the original have been requested.
+		assert.LessOrEqual(t, atomic.LoadInt64(&sent), RemoteRangeSize)
+		assert.Less(t, RemoteRangeSize, int64(len(data)))
+	})
+	t.Run("TooSmall", func(t *testing.T) {
+		_, err := FromRemoteEmbedded(server.URL+"/photo.jpg", "a9cd9168fa6acc5c5c2965ddf6ec465ca42fd818", thumbPath, 224, 224, 1, ResampleFillCenter)
+
+		assert.ErrorIs(t, err, ErrNoPreview)
+	})
+	t.Run("ExceedsLimit", func(t *testing.T) {
+		atomic.StoreInt64(&sent, 0)
+
+		_, err := FromRemoteEmbedded(server.URL+"/photo.jpg", "a9cd9168fa6acc5c5c2965ddf6ec465ca42fd818", thumbPath, 1280, 1024, 1, ResampleFit)
+
+		assert.ErrorIs(t, err, ErrNoPreview)
+		assert.Equal(t, int64(0), atomic.LoadInt64(&sent))
+	})
+	t.Run("NotJpeg", func(t *testing.T) {
+		_, err := FromRemoteEmbedded(server.URL+"/photo.png", "c9cd9168fa6acc5c5c2965ddf6ec465ca42fd818", thumbPath, 100, 100, 1, ResampleFillCenter)
+
+		assert.ErrorIs(t, err, ErrNoPreview)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := FromRemoteEmbedded(server.URL+"/missing.jpg", "d9cd9168fa6acc5c5c2965ddf6ec465ca42fd818", thumbPath, 100, 100, 1, ResampleFillCenter)
+
+		assert.ErrorContains(t, err, "status 404")
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		RemoteOriginals = false
+		defer func() { RemoteOriginals = true }()
+
+		_, err := FromRemoteEmbedded(server.URL+"/photo.jpg", "b9cd9168fa6acc5c5c2965ddf6ec465ca42fd818", thumbPath, 100, 100, 1, ResampleFillCenter)
+
+		assert.ErrorIs(t, err, ErrRemoteDisabled)
+	})
+}
+
+func TestRemoteCached(t *testing.T) {
+	thumbPath := t.TempDir()
+
+	assert.False(t, RemoteCached("https://example.com/photo.jpg", thumbPath))
+	assert.False(t, RemoteCached("https://", thumbPath))
+}
@@ -0,0 +1,105 @@
+package thumb
+
+import (
+	"bytes"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRemote(t *testing.T) {
+	assert.True(t, IsRemote("https://example.com/photo.jpg"))
+	assert.True(t, IsRemote("http://example.com/photo.jpg"))
+	assert.False(t, IsRemote("/photos/originals/photo.jpg"))
+	assert.False(t, IsRemote("ftp://example.com/photo.jpg"))
+}
+
+func TestRemoteFile(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := imaging.Encode(&buf, imaging.New(100, 50, color.White), imaging.PNG); err != nil {
+		t.Fatal(err)
+	}
+
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		switch r.URL.Path {
+		case "/photo.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(buf.Bytes())
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	defer server.Close()
+
+	RemoteOriginals = true
+	defer func() { RemoteOriginals = false }()
+
+	thumbPath := t.TempDir()
+
+	t.Run("Success", func(t *testing.T) {
+		fileName, err := RemoteFile(server.URL+"/photo.png", thumbPath)
+
+		assert.NoError(t, err)
+		assert.FileExists(t, fileName)
+		assert.Equal(t, ".png", fileName[len(fileName)-4:])
+
+		// The second request is served from the cache.
+		cached, err := RemoteFile(server.URL+"/photo.png", thumbPath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fileName, cached)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+	t.Run("FromFile", func(t *testing.T) {
+		fileName, err := FromFile(server.URL+"/photo.png", "a8cd9168fa6acc5c5c2965ddf6ec465ca42fd818", thumbPath, 50, 50, 0, ResampleFillCenter)
+
+		assert.NoError(t, err)
+		assert.FileExists(t, fileName)
+	})
+	t.Run("ContentType", func(t *testing.T) {
+		_, err := RemoteFile(server.URL+"/page.html", thumbPath)
+
+		assert.ErrorContains(t, err, "unsupported content type")
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := RemoteFile(server.URL+"/missing.jpg", thumbPath)
+
+		assert.ErrorContains(t, err, "status 404")
+	})
+	t.Run("SizeLimit", func(t *testing.T) {
+		limit := RemoteSizeLimit
+		RemoteSizeLimit = 10
+		defer func() { RemoteSizeLimit = limit }()
+
+		_, err := RemoteFile(server.URL+"/photo.png?large", thumbPath)
+
+		assert.ErrorContains(t, err, "exceeds size limit")
+	})
+	t.Run("InvalidUrl", func(t *testing.T) {
+		_, err := RemoteFile("https://", thumbPath)
+
+		assert.ErrorContains(t, err, "invalid url")
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		RemoteOriginals = false
+		defer func() { RemoteOriginals = true }()
+
+		_, err := RemoteFile(server.URL+"/photo.png", thumbPath)
+
+		assert.ErrorIs(t, err, ErrRemoteDisabled)
+	})
+}
@@ -0,0 +1,36 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Remove deletes all cached thumbnails and crops of the file with the specified hash,
+// so that they are recreated e.g. after the rendering settings have changed.
+func Remove(hash, thumbPath string) (removed int, err error) {
+	if len(hash) < 4 {
+		return 0, fmt.Errorf("thumb: file hash is empty or too short (%s)", clean.Log(hash))
+	} else if len(thumbPath) == 0 {
+		return 0, fmt.Errorf("thumb: folder is empty")
+	}
+
+	matches, err := filepath.Glob(path.Join(Dir(hash, thumbPath), hash) + "_*")
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, fileName := range matches {
+		if err = os.Remove(fileName); err != nil {
+			log.Warnf("thumb: %s", err)
+		} else {
+			removed++
+		}
+	}
+
+	return removed, nil
+}
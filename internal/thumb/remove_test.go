@@ -0,0 +1,36 @@
+package thumb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemove(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		tile := Sizes[Tile50]
+		hash := "a3ab0e0a6b9f2c6a5c2b4c8e4a0d737d6f4e0b1c"
+
+		fileName, err := FromFile("testdata/example.jpg", hash, "testdata", tile.Width, tile.Height, OrientationNormal, tile.Options...)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.FileExists(t, fileName)
+
+		removed, err := Remove(hash, "testdata")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, removed)
+		assert.NoFileExists(t, fileName)
+	})
+	t.Run("InvalidHash", func(t *testing.T) {
+		_, err := Remove("a", "testdata")
+		assert.Error(t, err)
+	})
+	t.Run("NoPath", func(t *testing.T) {
+		_, err := Remove("a3ab0e0a6b9f2c6a5c2b4c8e4a0d737d6f4e0b1c", "")
+		assert.Error(t, err)
+	})
+}
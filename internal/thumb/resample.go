@@ -8,10 +8,22 @@ import (
 
 // Resample downscales an image and returns it.
 func Resample(img image.Image, width, height int, opts ...ResampleOption) image.Image {
-	var resImg image.Image
-
 	method, filter, _ := ResampleOptions(opts...)
 
+	return resample(img, width, height, method, filter)
+}
+
+// ResampleWith downscales an image like Resample, but with the specified filter instead of the configured one.
+func ResampleWith(img image.Image, width, height int, filter ResampleFilter, opts ...ResampleOption) image.Image {
+	method, _, _ := ResampleOptions(opts...)
+
+	return resample(img, width, height, method, filter.Imaging())
+}
+
+// resample downscales an image with the specified method and filter.
+func resample(img image.Image, width, height int, method ResampleOption, filter imaging.ResampleFilter) image.Image {
+	var resImg image.Image
+
 	if method == ResampleFit {
 		resImg = imaging.Fit(img, width, height, filter)
 	} else if method == ResampleFillCenter {
@@ -20,6 +32,8 @@ func Resample(img image.Image, width, height int, opts ...ResampleOption) image.
 		resImg = imaging.Fill(img, width, height, imaging.TopLeft, filter)
 	} else if method == ResampleFillBottomRight {
 		resImg = imaging.Fill(img, width, height, imaging.BottomRight, filter)
+	} else if method == ResampleFillEntropy {
+		resImg = FillEntropy(img, width, height, filter)
 	} else if method == ResampleResize {
 		resImg = imaging.Resize(img, width, height, filter)
 	}
@@ -1,12 +1,17 @@
 package thumb
 
-import "github.com/disintegration/imaging"
+import (
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
 
 const (
 	ResampleBlackman ResampleFilter = "blackman"
 	ResampleLanczos  ResampleFilter = "lanczos"
 	ResampleCubic    ResampleFilter = "cubic"
 	ResampleLinear   ResampleFilter = "linear"
+	ResampleBox      ResampleFilter = "box"
 )
 
 type ResampleFilter string
@@ -21,7 +26,22 @@ func (a ResampleFilter) Imaging() imaging.ResampleFilter {
 		return imaging.CatmullRom
 	case ResampleLinear:
 		return imaging.Linear
+	case ResampleBox:
+		return imaging.Box
 	default:
 		return imaging.Lanczos
 	}
 }
+
+// ParseResampleFilter returns the resample filter with the specified name, where "bilinear" is an alias for "linear",
+// and false if it is unknown.
+func ParseResampleFilter(s string) (ResampleFilter, bool) {
+	switch f := ResampleFilter(strings.ToLower(strings.TrimSpace(s))); f {
+	case ResampleBlackman, ResampleLanczos, ResampleCubic, ResampleLinear, ResampleBox:
+		return f, true
+	case "bilinear":
+		return ResampleLinear, true
+	default:
+		return "", false
+	}
+}
@@ -16,6 +16,11 @@ const (
 	ResampleNearestNeighbor
 	ResampleDefault
 	ResamplePng
+	ResampleFillEntropy
+	ResampleGray
+	ResampleGifFirst
+	ResampleGifMiddle
+	ResampleGifAnimated
 )
 
 var ResampleMethods = map[ResampleOption]string{
@@ -24,6 +29,7 @@ var ResampleMethods = map[ResampleOption]string{
 	ResampleFillBottomRight: "right",
 	ResampleFit:             "fit",
 	ResampleResize:          "resize",
+	ResampleFillEntropy:     "entropy",
 }
 
 // ResampleOptions extracts filter, format, and method from resample options.
@@ -36,6 +42,8 @@ func ResampleOptions(opts ...ResampleOption) (method ResampleOption, filter imag
 		switch option {
 		case ResamplePng:
 			format = fs.ImagePNG
+		case ResampleGifAnimated:
+			format = fs.ImageGIF
 		case ResampleNearestNeighbor:
 			filter = imaging.NearestNeighbor
 		case ResampleDefault:
@@ -46,6 +54,8 @@ func ResampleOptions(opts ...ResampleOption) (method ResampleOption, filter imag
 			method = ResampleFillCenter
 		case ResampleFillBottomRight:
 			method = ResampleFillBottomRight
+		case ResampleFillEntropy:
+			method = ResampleFillEntropy
 		case ResampleFit:
 			method = ResampleFit
 		case ResampleResize:
@@ -0,0 +1,79 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Reshard moves cached files that are stored in a folder other than the one of the current subfolder scheme,
+// e.g. after Sharding has been changed, and removes folders that become empty. Remote originals are skipped.
+// It returns the number of files moved.
+func Reshard(thumbPath string) (moved int, err error) {
+	if thumbPath == "" || !fs.PathExists(thumbPath) {
+		return 0, fmt.Errorf("thumb: cache folder %s not found", clean.Log(thumbPath))
+	}
+
+	var dirs []string
+
+	err = filepath.Walk(thumbPath, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			if fileName != thumbPath && filepath.Dir(fileName) == thumbPath && info.Name() == "remote" {
+				return filepath.SkipDir
+			}
+
+			dirs = append(dirs, fileName)
+
+			return nil
+		}
+
+		base := info.Name()
+		i := strings.Index(base, "_")
+
+		// Cached files are named after the file hash, followed by an underscore.
+		if i < 4 || strings.HasPrefix(base, ".") {
+			return nil
+		}
+
+		dir := ShardDir(base[:i], thumbPath, Sharding)
+
+		if filepath.Clean(dir) == filepath.Dir(fileName) {
+			return nil
+		}
+
+		if mkdirErr := os.MkdirAll(dir, fs.ModeDir); mkdirErr != nil {
+			return mkdirErr
+		}
+
+		dest := filepath.Join(dir, base)
+
+		if fs.FileExists(dest) {
+			// Remove duplicate.
+			_ = os.Remove(fileName)
+		} else if renameErr := os.Rename(fileName, dest); renameErr != nil {
+			log.Warnf("thumb: %s while moving %s", renameErr, clean.Log(base))
+			return nil
+		}
+
+		moved++
+
+		return nil
+	})
+
+	// Remove empty folders, starting with the deepest.
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if dirs[i] != thumbPath && fs.DirIsEmpty(dirs[i]) {
+			_ = os.Remove(dirs[i])
+		}
+	}
+
+	return moved, err
+}
@@ -0,0 +1,52 @@
+package thumb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestReshard(t *testing.T) {
+	defer func() { Sharding = DefaultSharding }()
+
+	thumbPath, err := os.MkdirTemp("", "thumb-reshard")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(thumbPath)
+
+	hash := "abcdef1234567890"
+	oldName := filepath.Join(Dir(hash, thumbPath), hash+"_720x720_fit.jpg")
+	remoteName := filepath.Join(thumbPath, "remote", "a", "b", "abcdef.jpg")
+
+	for _, fileName := range []string{oldName, remoteName} {
+		if err = os.MkdirAll(filepath.Dir(fileName), fs.ModeDir); err != nil {
+			t.Fatal(err)
+		} else if err = os.WriteFile(fileName, []byte("thumb"), fs.ModeFile); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	Sharding = []int{2, 2}
+
+	moved, err := Reshard(thumbPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, moved)
+	assert.FileExists(t, filepath.Join(thumbPath, "ab", "cd", hash+"_720x720_fit.jpg"))
+	assert.NoFileExists(t, oldName)
+	assert.NoDirExists(t, filepath.Join(thumbPath, "a"))
+	assert.FileExists(t, remoteName)
+
+	// Files in the right place are not moved again.
+	moved, err = Reshard(thumbPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, moved)
+}
@@ -0,0 +1,131 @@
+package thumb
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/disintegration/imaging"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+//go:embed selftest.jpg
+var selfTestJpeg []byte
+
+// SelfTestHash is the file hash under which self-test thumbnails are created.
+const SelfTestHash = "5e1f7e575e1f7e575e1f7e575e1f7e575e1f7e57"
+
+// SelfTestSizes contains the sizes that are created by SelfTest with their expected dimensions for the
+// bundled 1024x768 test image.
+var SelfTestSizes = []struct {
+	Name          Name
+	Width, Height int
+}{
+	{Tile224, 224, 224},
+	{Fit720, 720, 540},
+}
+
+// SelfTestCheck represents the result of creating a single thumbnail size.
+type SelfTestCheck struct {
+	Size     Name   `json:"size"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Expected string `json:"expected"`
+	Passed   bool   `json:"passed"`
+	Ms       int64  `json:"ms"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SelfTestResult represents the result of a thumbnail pipeline self-test.
+type SelfTestResult struct {
+	Passed  bool            `json:"passed"`
+	Encoder string          `json:"encoder"`
+	Ms      int64           `json:"ms"`
+	Checks  []SelfTestCheck `json:"checks"`
+}
+
+// SelfTest creates thumbnails of a bundled test image in a temporary subfolder of the specified path with
+// FromFile, and verifies that they can be decoded and have the expected dimensions. Files are removed afterwards.
+func SelfTest(tempPath string) (result SelfTestResult) {
+	start := time.Now()
+	result = SelfTestResult{Encoder: ActiveEncoder(), Checks: make([]SelfTestCheck, 0, len(SelfTestSizes))}
+
+	defer func() {
+		result.Ms = time.Since(start).Milliseconds()
+	}()
+
+	dir, err := os.MkdirTemp(tempPath, "selftest-")
+
+	if err != nil {
+		result.Checks = append(result.Checks, SelfTestCheck{Error: err.Error()})
+		return result
+	}
+
+	defer os.RemoveAll(dir)
+
+	srcName := filepath.Join(dir, "selftest"+fs.ExtJPEG)
+
+	if err = os.WriteFile(srcName, selfTestJpeg, fs.ModeFile); err != nil {
+		result.Checks = append(result.Checks, SelfTestCheck{Error: err.Error()})
+		return result
+	}
+
+	result.Passed = true
+
+	for _, s := range SelfTestSizes {
+		check := selfTestSize(srcName, dir, s.Name, s.Width, s.Height)
+		result.Passed = result.Passed && check.Passed
+		result.Checks = append(result.Checks, check)
+	}
+
+	return result
+}
+
+// selfTestSize creates a thumbnail of the test image and compares its dimensions.
+func selfTestSize(srcName, thumbPath string, name Name, width, height int) (check SelfTestCheck) {
+	start := time.Now()
+	check = SelfTestCheck{Size: name, Expected: fmt.Sprintf("%dx%d", width, height)}
+
+	defer func() {
+		check.Ms = time.Since(start).Milliseconds()
+
+		if r := recover(); r != nil {
+			check.Passed = false
+			check.Error = fmt.Sprintf("%s (panic)", r)
+		}
+	}()
+
+	size, ok := Sizes[name]
+
+	if !ok {
+		check.Error = fmt.Sprintf("unknown size %s", name)
+		return check
+	}
+
+	fileName, err := size.FromFile(srcName, SelfTestHash, thumbPath, OrientationNormal)
+
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	var img image.Image
+
+	if img, err = imaging.Open(fileName); err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	check.Width, check.Height = img.Bounds().Dx(), img.Bounds().Dy()
+	check.Passed = check.Width == width && check.Height == height
+
+	if !check.Passed {
+		check.Error = fmt.Sprintf("unexpected size %dx%d", check.Width, check.Height)
+	}
+
+	return check
+}
@@ -0,0 +1,37 @@
+package thumb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfTest(t *testing.T) {
+	tempPath, err := os.MkdirTemp("", "thumb-selftest")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(tempPath)
+
+	result := SelfTest(tempPath)
+
+	assert.True(t, result.Passed)
+	assert.Len(t, result.Checks, len(SelfTestSizes))
+
+	assert.Equal(t, "720x540", result.Checks[1].Expected)
+	assert.Equal(t, 720, result.Checks[1].Width)
+	assert.Equal(t, 540, result.Checks[1].Height)
+
+	for _, check := range result.Checks {
+		assert.True(t, check.Passed, check.Error)
+		assert.Empty(t, check.Error)
+	}
+
+	// Temporary files must be removed.
+	entries, err := os.ReadDir(tempPath)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
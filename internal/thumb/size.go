@@ -2,6 +2,7 @@ package thumb
 
 import (
 	"image"
+	"io"
 )
 
 // Size represents a standard media resolution.
@@ -21,9 +22,10 @@ func (s Size) Bounds() image.Rectangle {
 	return image.Rectangle{Min: image.Point{}, Max: image.Point{X: s.Width, Y: s.Height}}
 }
 
-// Uncached tests if thumbnail type exceeds the cached thumbnails size limit.
+// Uncached tests if thumbnails of this type are not pre-cached, e.g. because they exceed the cached
+// thumbnails size limit, see Size.Policy.
 func (s Size) Uncached() bool {
-	return s.Width > SizePrecached || s.Height > SizePrecached
+	return s.Policy() != PolicyPrecache
 }
 
 // ExceedsLimit tests if thumbnail type is too large, and can not be rendered at all.
@@ -41,6 +43,11 @@ func (s Size) FromFile(fileName, fileHash, cachePath string, fileOrientation int
 	return FromFile(fileName, fileHash, cachePath, s.Width, s.Height, fileOrientation, s.Options...)
 }
 
+// FromFileAngle creates a new thumbnail with the matching size like FromFile, but straightens the image by angle degrees first.
+func (s Size) FromFileAngle(fileName, fileHash, cachePath string, fileOrientation int, angle float64) (string, error) {
+	return FromFileAngle(fileName, fileHash, cachePath, s.Width, s.Height, fileOrientation, angle, s.Options...)
+}
+
 // Create creates a thumbnail with the matching size and returns it as image.Image.
 func (s Size) Create(img image.Image, fileName string) (image.Image, error) {
 	return Create(img, fileName, s.Width, s.Height, s.Options...)
@@ -66,3 +73,13 @@ func (s Size) Skip(img image.Image) bool {
 
 	return false
 }
+
+// Streamable checks if thumbnails with the matching size may be streamed while they are encoded.
+func (s Size) Streamable() bool {
+	return Streamable(s.Width, s.Height, s.Options...)
+}
+
+// FromFileStream creates a new thumbnail with the matching size and writes it to w while it is being saved, see FromFileStream.
+func (s Size) FromFileStream(fileName, fileHash, cachePath string, fileOrientation int, angle float64, w io.Writer) (string, bool, error) {
+	return FromFileStream(fileName, fileHash, cachePath, s.Width, s.Height, fileOrientation, angle, w, s.Options...)
+}
@@ -63,4 +63,31 @@ func TestResampleFilter_Imaging(t *testing.T) {
 		r := ResampleLinear.Imaging()
 		assert.Equal(t, float64(1), r.Support)
 	})
+	t.Run("Box", func(t *testing.T) {
+		r := ResampleBox.Imaging()
+		assert.Equal(t, float64(0.5), r.Support)
+	})
+}
+
+func TestParseResampleFilter(t *testing.T) {
+	t.Run("Lanczos", func(t *testing.T) {
+		f, ok := ParseResampleFilter("Lanczos")
+		assert.True(t, ok)
+		assert.Equal(t, ResampleLanczos, f)
+	})
+	t.Run("Bilinear", func(t *testing.T) {
+		f, ok := ParseResampleFilter("bilinear")
+		assert.True(t, ok)
+		assert.Equal(t, ResampleLinear, f)
+	})
+	t.Run("Box", func(t *testing.T) {
+		f, ok := ParseResampleFilter(" box ")
+		assert.True(t, ok)
+		assert.Equal(t, ResampleBox, f)
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		f, ok := ParseResampleFilter("sinc")
+		assert.False(t, ok)
+		assert.Equal(t, ResampleFilter(""), f)
+	})
 }
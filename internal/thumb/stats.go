@@ -0,0 +1,114 @@
+package thumb
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/media"
+)
+
+// Source format labels used in thumbnail generation statistics.
+const (
+	FormatJPEG  = "jpeg"
+	FormatPNG   = "png"
+	FormatHEIC  = "heic"
+	FormatRAW   = "raw"
+	FormatVideo = "video"
+	FormatOther = "other"
+)
+
+// FormatStats represents the thumbnail generation statistics of a source format.
+type FormatStats struct {
+	Created  int64         `json:"created"`
+	Failed   int64         `json:"failed"`
+	Duration time.Duration `json:"-"`
+	TotalMs  int64         `json:"totalMs"`
+	AvgMs    int64         `json:"avgMs"`
+}
+
+var (
+	stats      = make(map[string]FormatStats)
+	statsMutex = sync.Mutex{}
+)
+
+// SourceFormat returns the statistics label of the original format. Previews of other formats are detected
+// by their double extension, e.g. "IMG_1234.CR2.jpg" is labeled "raw".
+func SourceFormat(fileName string) string {
+	t := fs.FileType(fileName)
+
+	if inner := fs.FileType(fs.StripExt(filepath.Base(fileName))); inner != fs.TypeUnknown {
+		t = inner
+	}
+
+	switch t {
+	case fs.ImageJPEG:
+		return FormatJPEG
+	case fs.ImagePNG:
+		return FormatPNG
+	case fs.ImageHEIC, fs.ImageHEIF, fs.ImageHEICS:
+		return FormatHEIC
+	}
+
+	switch media.Formats[t] {
+	case media.Raw:
+		return FormatRAW
+	case media.Video:
+		return FormatVideo
+	}
+
+	return FormatOther
+}
+
+// AddStats adds the number of thumbnails created from a source format and the time it took,
+// or counts a failure if failed is true.
+func AddStats(format string, count int, d time.Duration, failed bool) {
+	if format == "" {
+		format = FormatOther
+	}
+
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
+	s := stats[format]
+
+	if failed {
+		s.Failed++
+	} else if count > 0 {
+		s.Created += int64(count)
+		s.Duration += d
+	} else {
+		return
+	}
+
+	stats[format] = s
+}
+
+// Stats returns a copy of the thumbnail generation statistics by source format.
+func Stats() map[string]FormatStats {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
+	result := make(map[string]FormatStats, len(stats))
+
+	for format, s := range stats {
+		s.TotalMs = s.Duration.Milliseconds()
+
+		if s.Created > 0 {
+			s.AvgMs = s.TotalMs / s.Created
+		}
+
+		result[format] = s
+	}
+
+	return result
+}
+
+// ResetStats resets the thumbnail generation statistics.
+func ResetStats() {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
+	stats = make(map[string]FormatStats)
+}
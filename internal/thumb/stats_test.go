@@ -0,0 +1,41 @@
+package thumb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceFormat(t *testing.T) {
+	assert.Equal(t, FormatJPEG, SourceFormat("testdata/example.jpg"))
+	assert.Equal(t, FormatPNG, SourceFormat("testdata/example.png"))
+	assert.Equal(t, FormatRAW, SourceFormat("sidecar/2023/IMG_1234.CR2.jpg"))
+	assert.Equal(t, FormatHEIC, SourceFormat("sidecar/2023/IMG_1234.heic.jpg"))
+	assert.Equal(t, FormatVideo, SourceFormat("sidecar/2023/VID_1234.mp4.jpg"))
+	assert.Equal(t, FormatJPEG, SourceFormat("originals/My.Photo.jpg"))
+	assert.Equal(t, FormatOther, SourceFormat("originals/image.xyz"))
+}
+
+func TestStats(t *testing.T) {
+	ResetStats()
+	defer ResetStats()
+
+	AddStats(FormatJPEG, 5, time.Second, false)
+	AddStats(FormatJPEG, 1, 2*time.Second, false)
+	AddStats(FormatVideo, 0, time.Second, true)
+	AddStats(FormatHEIC, 0, time.Second, false)
+	AddStats("", 1, time.Millisecond, false)
+
+	result := Stats()
+
+	assert.Equal(t, int64(6), result[FormatJPEG].Created)
+	assert.Equal(t, int64(3000), result[FormatJPEG].TotalMs)
+	assert.Equal(t, int64(500), result[FormatJPEG].AvgMs)
+	assert.Equal(t, int64(1), result[FormatVideo].Failed)
+	assert.Equal(t, int64(0), result[FormatVideo].Created)
+	assert.Equal(t, int64(1), result[FormatOther].Created)
+
+	_, found := result[FormatHEIC]
+	assert.False(t, found)
+}
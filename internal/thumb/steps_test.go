@@ -0,0 +1,117 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// testStripes returns an image with vertical black and white stripes that are 3 pixels wide.
+func testStripes(width, height int) *image.NRGBA {
+	img := imaging.New(width, height, color.White)
+
+	for x := 0; x < width; x++ {
+		if (x/3)%2 == 1 {
+			continue
+		}
+
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.Black)
+		}
+	}
+
+	return img
+}
+
+// testDeviation returns the standard deviation of the red channel, which is 0 for uniform images.
+func testDeviation(img image.Image) float64 {
+	b := img.Bounds()
+
+	var sum, sq float64
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, _, _, _ := img.At(x, y).RGBA()
+			v := float64(r >> 8)
+			sum += v
+			sq += v * v
+		}
+	}
+
+	n := float64(b.Dx() * b.Dy())
+	mean := sum / n
+
+	return math.Sqrt(sq/n - mean*mean)
+}
+
+func TestUseSteps(t *testing.T) {
+	assert.False(t, UseSteps(50, 50, ResampleFillCenter, ResampleDefault))
+
+	SetSteps([]Name{Tile50})
+	defer SetSteps(nil)
+
+	assert.True(t, UseSteps(50, 50, ResampleFillCenter, ResampleDefault))
+	assert.False(t, UseSteps(50, 50, ResampleFit, ResampleDefault))
+	assert.False(t, UseSteps(100, 100, ResampleFillCenter, ResampleDefault))
+}
+
+func TestStepsRatio(t *testing.T) {
+	img := imaging.New(4000, 2000, color.White)
+
+	assert.Equal(t, 20.0, StepsRatio(img, 100, 100, false))
+	assert.Equal(t, 40.0, StepsRatio(img, 100, 50, true))
+	assert.Equal(t, 20.0, StepsRatio(img, 200, 200, true))
+	assert.Equal(t, 1.0, StepsRatio(img, 0, 100, true))
+}
+
+func TestDownscaleSteps(t *testing.T) {
+	t.Run("Large", func(t *testing.T) {
+		img := imaging.New(4000, 3000, color.White)
+		result := DownscaleSteps(img, 50, 50, false)
+
+		// Halved 4 times to 250x188, leaving a reduction of less than 4x.
+		assert.Equal(t, 250, result.Bounds().Dx())
+		assert.Equal(t, 188, result.Bounds().Dy())
+	})
+	t.Run("Small", func(t *testing.T) {
+		img := imaging.New(150, 150, color.White)
+		assert.Equal(t, image.Image(img), DownscaleSteps(img, 50, 50, false))
+	})
+	t.Run("MinRatio", func(t *testing.T) {
+		StepsMinRatio = 100
+		defer func() { StepsMinRatio = 4 }()
+
+		img := imaging.New(4000, 3000, color.White)
+		assert.Equal(t, image.Image(img), DownscaleSteps(img, 50, 50, false))
+	})
+	t.Run("Aliasing", func(t *testing.T) {
+		img := testStripes(4000, 4000)
+
+		direct := Resample(img, 50, 50, ResampleFillCenter, ResampleNearestNeighbor)
+		steps := Resample(DownscaleSteps(img, 50, 50, false), 50, 50, ResampleFillCenter, ResampleNearestNeighbor)
+
+		// The stripes should blend into uniform gray instead of leaving a moiré pattern.
+		assert.Less(t, testDeviation(steps), testDeviation(direct)/4)
+	})
+}
+
+// BenchmarkDownscaleSteps compares the CPU cost of creating small tiles from a large image with and without
+// downscaling in steps first.
+func BenchmarkDownscaleSteps(b *testing.B) {
+	img := testStripes(6000, 4000)
+
+	b.Run("Direct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Resample(img, 50, 50, ResampleFillCenter, ResampleDefault)
+		}
+	})
+	b.Run("Steps", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Resample(DownscaleSteps(img, 50, 50, false), 50, 50, ResampleFillCenter, ResampleDefault)
+		}
+	})
+}
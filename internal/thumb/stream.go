@@ -0,0 +1,132 @@
+package thumb
+
+import (
+	"bufio"
+	"fmt"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+var (
+	// Stream enables sending large thumbnails to clients while they are encoded, see FromFileStream.
+	Stream = false
+
+	// StreamMinWidth is the minimum width of thumbnails that are streamed if enabled.
+	StreamMinWidth = 1280
+)
+
+// Streamable checks if thumbnails with the specified size may be streamed while they are encoded.
+func Streamable(width, height int, opts ...ResampleOption) bool {
+	if !Stream || width < StreamMinWidth && height < StreamMinWidth {
+		return false
+	}
+
+	_, _, format := ResampleOptions(opts...)
+
+	return format == fs.ImageJPEG
+}
+
+// FromFileStream creates a new thumbnail like FromFileAngle, but writes the encoded image to w while it is
+// being saved, so that clients receive the first bytes before encoding is complete. It returns streamed = false
+// if the thumbnail was already cached, in which case nothing was written to w.
+//
+// The thumbnail is written to a temporary file that is renamed when complete, so that no truncated file remains
+// in the cache if encoding fails. If writing to w fails, e.g. because the client disconnected, the thumbnail is
+// still saved and the error is logged. Streamed thumbnails are always encoded with the standard library.
+func FromFileStream(imageFilename, hash, thumbPath string, width, height, orientation int, angle float64, w io.Writer, opts ...ResampleOption) (fileName string, streamed bool, err error) {
+	// GIF sources with frame modes are created as usual, see GifMode.
+	if mode, _ := GifModeOf(GifOptions(imageFilename, opts...)...); mode != GifFirst {
+		fileName, err = FromFileAngle(imageFilename, hash, thumbPath, width, height, orientation, angle, opts...)
+		return fileName, false, err
+	}
+
+	if fileName, err = FromCache(imageFilename, hash, thumbPath, width, height, opts...); err == nil {
+		return fileName, false, err
+	} else if err != ErrNotCached {
+		return "", false, err
+	}
+
+	// Generate thumb cache filename.
+	if fileName, err = FileName(hash, thumbPath, width, height, opts...); err != nil {
+		log.Error(err)
+		return "", false, err
+	}
+
+	// Load image from storage.
+	img, err := openSource(imageFilename, hash, thumbPath, orientation, angle)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	// Hidden temporary files are ignored by other cache operations.
+	tmp, err := os.CreateTemp(filepath.Dir(fileName), "."+filepath.Base(fileName)+".*")
+
+	if err != nil {
+		return "", false, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	client := &streamWriter{w: w}
+	buf := bufio.NewWriter(io.MultiWriter(tmp, client))
+
+	err = jpeg.Encode(buf, Render(img, fileName, width, height, opts...), &jpeg.Options{Quality: int(SizeQuality(width, height))})
+
+	if err == nil {
+		err = buf.Flush()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return "", client.written > 0, fmt.Errorf("thumb: %s while streaming %s", err, clean.Log(filepath.Base(fileName)))
+	}
+
+	if err = os.Chmod(tmp.Name(), fs.ModeFile); err != nil {
+		return "", true, err
+	} else if err = os.Rename(tmp.Name(), fileName); err != nil {
+		return "", true, err
+	}
+
+	if client.err != nil {
+		log.Debugf("thumb: %s while streaming %s, saved anyway", client.err, clean.Log(filepath.Base(fileName)))
+	}
+
+	return fileName, true, nil
+}
+
+// streamWriter writes to the client until the first error, after which all data is discarded,
+// so that the thumbnail can still be saved if the client disconnects.
+type streamWriter struct {
+	w       io.Writer
+	err     error
+	written int64
+}
+
+// Write implements io.Writer.
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if s.err != nil {
+		return len(p), nil
+	}
+
+	n, err := s.w.Write(p)
+	s.written += int64(n)
+
+	if err != nil {
+		s.err = err
+	}
+
+	return len(p), nil
+}
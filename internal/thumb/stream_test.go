@@ -0,0 +1,100 @@
+package thumb
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter simulates a client that disconnects after receiving the first bytes.
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n > 0 {
+		return 0, errors.New("connection reset by peer")
+	}
+
+	w.n += len(p)
+
+	return len(p), nil
+}
+
+func TestStreamable(t *testing.T) {
+	defer func() { Stream = false }()
+
+	assert.False(t, Streamable(1920, 1200, ResampleFit))
+	Stream = true
+	assert.True(t, Streamable(1920, 1200, ResampleFit))
+	assert.False(t, Streamable(720, 720, ResampleFit))
+	assert.False(t, Streamable(1920, 1200, ResampleFit, ResamplePng))
+}
+
+func TestFromFileStream(t *testing.T) {
+	hash := "1234567890abcdef1234567890abcdef12345678"
+
+	t.Run("Success", func(t *testing.T) {
+		thumbPath := t.TempDir()
+
+		var buf bytes.Buffer
+
+		fileName, streamed, err := FromFileStream("testdata/example.jpg", hash, thumbPath, 500, 500, 1, 0, &buf, ResampleFit)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, streamed)
+
+		data, err := os.ReadFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, data, buf.Bytes())
+
+		// Cached thumbnails are not streamed.
+		buf.Reset()
+
+		cached, streamed, err := FromFileStream("testdata/example.jpg", hash, thumbPath, 500, 500, 1, 0, &buf, ResampleFit)
+
+		assert.NoError(t, err)
+		assert.False(t, streamed)
+		assert.Equal(t, fileName, cached)
+		assert.Equal(t, 0, buf.Len())
+	})
+	t.Run("Disconnected", func(t *testing.T) {
+		thumbPath := t.TempDir()
+
+		fileName, streamed, err := FromFileStream("testdata/example.jpg", hash, thumbPath, 500, 500, 1, 0, &failingWriter{}, ResampleFit)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, streamed)
+
+		img, err := Open(fileName, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 500, img.Bounds().Max.X)
+
+		// No temporary files remain.
+		matches, _ := filepath.Glob(filepath.Join(filepath.Dir(fileName), ".*"))
+		assert.Empty(t, matches)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		thumbPath := t.TempDir()
+
+		_, streamed, err := FromFileStream("testdata/missing.jpg", hash, thumbPath, 500, 500, 1, 0, &bytes.Buffer{}, ResampleFit)
+
+		assert.Error(t, err)
+		assert.False(t, streamed)
+	})
+}
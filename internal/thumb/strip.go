@@ -0,0 +1,261 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Metadata kinds that may be found in JPEG segments, see JpegMetadata.
+const (
+	MetaExif    = "exif"
+	MetaXmp     = "xmp"
+	MetaIptc    = "iptc"
+	MetaComment = "comment"
+	MetaOther   = "other"
+)
+
+// Identifiers that precede extended XMP and IPTC data in JPEG APP segments, see also exifHeader and xmpNamespace.
+const (
+	xmpExtNamespace = "http://ns.adobe.com/xmp/extension/\x00"
+	iptcIdentifier  = "Photoshop 3.0\x00"
+)
+
+// JpegMetadata returns the kinds of metadata found in the JPEG data, e.g. Exif with GPS coordinates, XMP, IPTC,
+// or comments. JFIF headers, ICC color profiles, and Adobe color transforms are not considered metadata,
+// as they are required to display the image correctly.
+func JpegMetadata(jpeg []byte) (found []string, err error) {
+	_, found, err = stripJpeg(jpeg, false)
+	return found, err
+}
+
+// StripMetadata returns a copy of the JPEG data without metadata segments, see JpegMetadata.
+// The image data is copied as is, so that there is no quality loss.
+func StripMetadata(jpeg []byte) (result []byte, err error) {
+	result, _, err = stripJpeg(jpeg, true)
+	return result, err
+}
+
+// stripJpeg parses the segments of the JPEG data up to the start of the image data, and returns the
+// kinds of metadata found. A copy without metadata segments is returned as well if strip is true.
+func stripJpeg(jpeg []byte, strip bool) (result []byte, found []string, err error) {
+	if len(jpeg) < 4 || jpeg[0] != 0xFF || jpeg[1] != 0xD8 {
+		return nil, nil, fmt.Errorf("thumb: invalid jpeg data")
+	}
+
+	var buf bytes.Buffer
+
+	if strip {
+		buf.Grow(len(jpeg))
+		buf.Write(jpeg[:2])
+	}
+
+	kinds := make(map[string]bool)
+
+	for i := 2; i < len(jpeg); {
+		if jpeg[i] != 0xFF {
+			return nil, found, fmt.Errorf("thumb: invalid jpeg marker at offset %d", i)
+		}
+
+		// Skip fill bytes.
+		if i+1 < len(jpeg) && jpeg[i+1] == 0xFF {
+			i++
+			continue
+		}
+
+		if i+3 >= len(jpeg) {
+			return nil, found, fmt.Errorf("thumb: unexpected end of jpeg data")
+		}
+
+		marker := jpeg[i+1]
+
+		// Copy the image data as is once the scan starts.
+		if marker == 0xDA || marker == 0xD9 {
+			if strip {
+				buf.Write(jpeg[i:])
+			}
+
+			break
+		}
+
+		end := i + 2 + int(binary.BigEndian.Uint16(jpeg[i+2:i+4]))
+
+		if end > len(jpeg) || end < i+4 {
+			return nil, found, fmt.Errorf("thumb: invalid jpeg segment length at offset %d", i)
+		}
+
+		if kind := segmentMetadata(marker, jpeg[i+4:end]); kind != "" {
+			if !kinds[kind] {
+				kinds[kind] = true
+				found = append(found, kind)
+			}
+		} else if strip {
+			buf.Write(jpeg[i:end])
+		}
+
+		i = end
+	}
+
+	if strip {
+		result = buf.Bytes()
+	}
+
+	return result, found, nil
+}
+
+// segmentMetadata returns the kind of metadata in a JPEG segment, or an empty string if the segment
+// is required to display the image.
+func segmentMetadata(marker byte, data []byte) string {
+	switch {
+	case marker == 0xE1:
+		if bytes.HasPrefix(data, []byte(exifHeader)) {
+			return MetaExif
+		} else if bytes.HasPrefix(data, []byte(xmpNamespace)) || bytes.HasPrefix(data, []byte(xmpExtNamespace)) {
+			return MetaXmp
+		}
+
+		return MetaOther
+	case marker == 0xED:
+		if bytes.HasPrefix(data, []byte(iptcIdentifier)) {
+			return MetaIptc
+		}
+
+		return MetaOther
+	case marker == 0xFE:
+		return MetaComment
+	case marker == 0xE0, marker == 0xE2, marker == 0xEE:
+		// JFIF header, ICC color profile, and Adobe color transform.
+		return ""
+	case marker >= 0xE3 && marker <= 0xEF:
+		return MetaOther
+	default:
+		return ""
+	}
+}
+
+// StripCheck represents the result of a metadata stripping self-test, see StripSelfTest.
+type StripCheck struct {
+	Passed   bool     `json:"passed"`
+	Encoder  string   `json:"encoder"`
+	Ms       int64    `json:"ms"`
+	Source   []string `json:"source"`
+	Thumb    []string `json:"thumb"`
+	Stripped []string `json:"stripped"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// StripSelfTest adds Exif, XMP, and comment segments to the bundled test image, and verifies that neither
+// thumbnails created from it nor a stripped copy contain metadata. Files are removed afterwards.
+func StripSelfTest(tempPath string) (check StripCheck) {
+	start := time.Now()
+	check = StripCheck{Encoder: ActiveEncoder()}
+
+	defer func() {
+		check.Ms = time.Since(start).Milliseconds()
+	}()
+
+	src := withTestMetadata(selfTestJpeg)
+
+	if check.Source, check.Error = metadataCheck(src); check.Error != "" {
+		return check
+	} else if len(check.Source) == 0 {
+		check.Error = "test image has no metadata"
+		return check
+	}
+
+	dir, err := os.MkdirTemp(tempPath, "selftest-")
+
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	defer os.RemoveAll(dir)
+
+	srcName := filepath.Join(dir, "selftest"+fs.ExtJPEG)
+
+	if err = os.WriteFile(srcName, src, fs.ModeFile); err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	fileName, err := Sizes[Fit720].FromFile(srcName, SelfTestHash, dir, OrientationNormal)
+
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	data, err := os.ReadFile(fileName)
+
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	if check.Thumb, check.Error = metadataCheck(data); check.Error != "" {
+		return check
+	}
+
+	stripped, err := StripMetadata(src)
+
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	if check.Stripped, check.Error = metadataCheck(stripped); check.Error != "" {
+		return check
+	}
+
+	check.Passed = len(check.Thumb) == 0 && len(check.Stripped) == 0
+
+	if !check.Passed {
+		check.Error = "metadata found"
+	}
+
+	return check
+}
+
+// metadataCheck returns the kinds of metadata in the JPEG data, and an error message if it could not be parsed.
+func metadataCheck(jpeg []byte) ([]string, string) {
+	found, err := JpegMetadata(jpeg)
+
+	if err != nil {
+		return found, err.Error()
+	} else if found == nil {
+		found = []string{}
+	}
+
+	return found, ""
+}
+
+// withTestMetadata returns a copy of the JPEG data with Exif, XMP, and comment segments that contain
+// a GPS position, as found in photos taken with smartphones.
+func withTestMetadata(jpeg []byte) []byte {
+	segment := func(marker byte, data []byte) []byte {
+		s := make([]byte, 4, 4+len(data))
+		s[0], s[1] = 0xFF, marker
+		binary.BigEndian.PutUint16(s[2:4], uint16(len(data)+2))
+		return append(s, data...)
+	}
+
+	// Little-endian TIFF header followed by an empty IFD.
+	exif := append([]byte(exifHeader), 'I', 'I', 0x2A, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+	xmp := append([]byte(xmpNamespace), []byte(`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"><rdf:Description xmlns:exif="http://ns.adobe.com/exif/1.0/" exif:GPSLatitude="52,31.0N" exif:GPSLongitude="13,24.0E"/></rdf:RDF></x:xmpmeta>`)...)
+
+	var buf bytes.Buffer
+
+	buf.Write(jpeg[:2])
+	buf.Write(segment(0xE1, exif))
+	buf.Write(segment(0xE1, xmp))
+	buf.Write(segment(0xFE, []byte("selftest")))
+	buf.Write(jpeg[2:])
+
+	return buf.Bytes()
+}
@@ -0,0 +1,93 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// StrippedName returns the file name of the copy of a JPEG or PNG original without metadata, see FromStripped.
+func StrippedName(hash, thumbPath string, fileType fs.Type) string {
+	ext := fs.ExtJPEG
+
+	if fileType == fs.ImagePNG {
+		ext = fs.ExtPNG
+	}
+
+	return path.Join(Dir(hash, thumbPath), fmt.Sprintf("%s_stripped%s", hash, ext))
+}
+
+// FromStripped returns the file name of a copy of a JPEG or PNG original without metadata, and creates it
+// if it does not exist yet. The image data of JPEG files is copied as is, see StripMetadata, unless
+// the image must be rotated, as the orientation is stored in the Exif data. PNG files are re-encoded,
+// which is lossless.
+func FromStripped(fileName, hash, thumbPath string, orientation int) (result string, err error) {
+	if len(hash) < 4 || thumbPath == "" {
+		return "", fmt.Errorf("thumb: invalid hash or folder for stripped copy of %s", clean.Log(filepath.Base(fileName)))
+	}
+
+	fileType := fs.FileType(fileName)
+
+	if fileType != fs.ImageJPEG && fileType != fs.ImagePNG {
+		return "", fmt.Errorf("thumb: cannot strip metadata from %s files", clean.Log(fileType.String()))
+	}
+
+	if result = StrippedName(hash, thumbPath, fileType); fs.FileExists(result) {
+		return result, nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(result), fs.ModeDir); err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first, so that incomplete copies are never served.
+	ext := filepath.Ext(result)
+	tmpName := strings.TrimSuffix(result, ext) + ".tmp" + ext
+
+	switch {
+	case fileType == fs.ImagePNG:
+		_, err = Png(fileName, tmpName, orientation)
+	case orientation > OrientationNormal:
+		if img, openErr := OpenJpeg(fileName, orientation); openErr != nil {
+			err = openErr
+		} else {
+			err = SaveJpeg(img, tmpName, JpegQuality)
+		}
+	default:
+		err = stripJpegFile(fileName, tmpName)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpName)
+		return "", err
+	} else if err = os.Rename(tmpName, result); err != nil {
+		_ = os.Remove(tmpName)
+		return "", err
+	}
+
+	log.Debugf("thumb: created stripped copy of %s", clean.Log(filepath.Base(fileName)))
+
+	return result, nil
+}
+
+// stripJpegFile saves a copy of a JPEG file without metadata segments.
+func stripJpegFile(srcName, destName string) error {
+	data, err := os.ReadFile(srcName)
+
+	if err != nil {
+		return err
+	}
+
+	stripped, err := StripMetadata(data)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(destName, stripped, fs.ModeFile)
+}
@@ -0,0 +1,86 @@
+package thumb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestStrippedName(t *testing.T) {
+	assert.Equal(t, "thumbs/a/b/c/abcdef_stripped.jpg", StrippedName("abcdef", "thumbs", fs.ImageJPEG))
+	assert.Equal(t, "thumbs/a/b/c/abcdef_stripped.png", StrippedName("abcdef", "thumbs", fs.ImagePNG))
+}
+
+func TestFromStripped(t *testing.T) {
+	t.Run("Jpeg", func(t *testing.T) {
+		dir := t.TempDir()
+		srcName := filepath.Join(dir, "original.jpg")
+		src := withTestMetadata(selfTestJpeg)
+
+		if err := os.WriteFile(srcName, src, fs.ModeFile); err != nil {
+			t.Fatal(err)
+		}
+
+		fileName, err := FromStripped(srcName, "abcdef", dir, OrientationNormal)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := JpegMetadata(data)
+
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+
+		// The image data is copied as is.
+		expected, err := StripMetadata(selfTestJpeg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, data)
+
+		// Existing copies are returned.
+		cached, err := FromStripped(srcName, "abcdef", dir, OrientationNormal)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fileName, cached)
+	})
+	t.Run("Rotated", func(t *testing.T) {
+		dir := t.TempDir()
+		srcName := filepath.Join(dir, "original.jpg")
+
+		if err := os.WriteFile(srcName, withTestMetadata(selfTestJpeg), fs.ModeFile); err != nil {
+			t.Fatal(err)
+		}
+
+		fileName, err := FromStripped(srcName, "abcdef", dir, 6)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(fileName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := JpegMetadata(data)
+
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+	})
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := FromStripped("testdata/example.gif", "abcdef", t.TempDir(), OrientationNormal)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,143 @@
+package thumb
+
+import (
+	"bytes"
+	"image/jpeg"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJpegMetadata(t *testing.T) {
+	t.Run("Example", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/example.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := JpegMetadata(data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{MetaExif, MetaIptc, MetaXmp}, found)
+	})
+	t.Run("SelfTest", func(t *testing.T) {
+		found, err := JpegMetadata(selfTestJpeg)
+
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+
+		found, err = JpegMetadata(withTestMetadata(selfTestJpeg))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{MetaExif, MetaXmp, MetaComment}, found)
+	})
+	t.Run("Notice", func(t *testing.T) {
+		data, err := Notice{Artist: "Jens Mander"}.Embed(selfTestJpeg)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := JpegMetadata(data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{MetaXmp}, found)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := JpegMetadata([]byte("GIF89a"))
+		assert.Error(t, err)
+
+		_, err = JpegMetadata([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF})
+		assert.Error(t, err)
+	})
+}
+
+func TestStripMetadata(t *testing.T) {
+	t.Run("Example", func(t *testing.T) {
+		data, err := os.ReadFile("testdata/example.jpg")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := StripMetadata(data)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Less(t, len(result), len(data))
+		assert.False(t, bytes.Contains(result, []byte(exifHeader)))
+		assert.False(t, bytes.Contains(result, []byte(xmpNamespace)))
+		assert.False(t, bytes.Contains(result, []byte(iptcIdentifier)))
+
+		// Color profiles are kept.
+		assert.True(t, bytes.Contains(result, []byte("ICC_PROFILE")))
+
+		found, err := JpegMetadata(result)
+
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+
+		img, err := jpeg.Decode(bytes.NewReader(result))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 750, img.Bounds().Dx())
+	})
+	t.Run("Unchanged", func(t *testing.T) {
+		result, err := StripMetadata(selfTestJpeg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, selfTestJpeg, result)
+	})
+}
+
+func TestSize_FromFile_Metadata(t *testing.T) {
+	thumbPath, err := os.MkdirTemp("", "thumb-strip")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(thumbPath)
+
+	fileName, err := Sizes[Tile224].FromFile("testdata/example.jpg", "8f4e1f3d6b1a6c8a2d9e1b4f7a0c3e5d2b8f6a1c", thumbPath, OrientationNormal)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := JpegMetadata(data)
+
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestStripSelfTest(t *testing.T) {
+	tempPath, err := os.MkdirTemp("", "thumb-selftest")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(tempPath)
+
+	check := StripSelfTest(tempPath)
+
+	assert.True(t, check.Passed, check.Error)
+	assert.Empty(t, check.Error)
+	assert.Equal(t, []string{MetaExif, MetaXmp, MetaComment}, check.Source)
+	assert.Empty(t, check.Thumb)
+	assert.Empty(t, check.Stripped)
+}
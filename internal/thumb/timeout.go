@@ -0,0 +1,21 @@
+package thumb
+
+import (
+	"errors"
+	"time"
+)
+
+// WithTimeout calls the create function and returns ErrTimeout if it does not finish within the given duration.
+// Image decoding cannot be interrupted, so the function keeps running in the background after a timeout and the
+// thumbnail is still created. Calls with the same key wait for it instead of starting it again, see Async.
+func WithTimeout(key string, timeout time.Duration, create func() (string, error)) (fileName string, err error) {
+	if timeout <= 0 {
+		return create()
+	}
+
+	if fileName, err = Async(key, timeout, create); errors.Is(err, ErrPending) {
+		return "", ErrTimeout
+	}
+
+	return fileName, err
+}
@@ -0,0 +1,63 @@
+package thumb
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		fileName, err := WithTimeout("timeout-disabled", 0, func() (string, error) {
+			return "foo.jpg", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "foo.jpg", fileName)
+	})
+	t.Run("InTime", func(t *testing.T) {
+		fileName, err := WithTimeout("timeout-in-time", time.Second, func() (string, error) {
+			return "bar.jpg", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "bar.jpg", fileName)
+	})
+	t.Run("Error", func(t *testing.T) {
+		fileName, err := WithTimeout("timeout-error", time.Second, func() (string, error) {
+			return "", errors.New("failed")
+		})
+
+		assert.EqualError(t, err, "failed")
+		assert.Equal(t, "", fileName)
+	})
+	t.Run("TimedOut", func(t *testing.T) {
+		release := make(chan struct{})
+
+		var calls int32
+
+		create := func() (string, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return "baz.jpg", nil
+		}
+
+		fileName, err := WithTimeout("timeout-timed-out", 10*time.Millisecond, create)
+
+		assert.ErrorIs(t, err, ErrTimeout)
+		assert.Equal(t, "", fileName)
+
+		// Creation is not started again while it is still running in the background.
+		_, err = WithTimeout("timeout-timed-out", 10*time.Millisecond, create)
+		assert.ErrorIs(t, err, ErrTimeout)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		assert.True(t, Pending("timeout-timed-out"))
+
+		close(release)
+
+		assert.Eventually(t, func() bool { return !Pending("timeout-timed-out") }, time.Second, time.Millisecond)
+	})
+}
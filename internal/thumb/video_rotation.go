@@ -0,0 +1,159 @@
+package thumb
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+)
+
+// ErrNoRotation is returned if a video contains no rotation matrix, e.g. because it is not an MP4 file.
+var ErrNoRotation = errors.New("no video rotation matrix")
+
+// VideoRotation returns the clockwise rotation in degrees, i.e. 0, 90, 180, or 270, with which the frames of an
+// MP4 or QuickTime video must be displayed, as specified by the transformation matrix in the header of its
+// first video track. Phones store portrait videos this way instead of rotating the frames.
+func VideoRotation(fileName string) (degrees int, err error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return findRotation(f, 0, info.Size(), 0)
+}
+
+// VideoOrientation returns the Exif orientation matching the rotation of a video, see VideoRotation,
+// or OrientationNormal if it is unknown.
+func VideoOrientation(fileName string) int {
+	if degrees, err := VideoRotation(fileName); err != nil {
+		return OrientationNormal
+	} else {
+		return RotationOrientation(degrees)
+	}
+}
+
+// RotationOrientation returns the Exif orientation matching a clockwise rotation in degrees.
+func RotationOrientation(degrees int) int {
+	switch (degrees%360 + 360) % 360 {
+	case 90:
+		return OrientationRotate270
+	case 180:
+		return OrientationRotate180
+	case 270:
+		return OrientationRotate90
+	default:
+		return OrientationNormal
+	}
+}
+
+// findRotation searches the track header boxes between start and end for the rotation matrix.
+func findRotation(r io.ReadSeeker, start, end int64, depth int) (int, error) {
+	if depth > 4 {
+		return 0, ErrNoRotation
+	}
+
+	for pos := start; pos+8 <= end; {
+		header := make([]byte, 8)
+
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return 0, err
+		} else if _, err = io.ReadFull(r, header); err != nil {
+			return 0, ErrNoRotation
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		boxType := string(header[4:8])
+		headerSize := int64(8)
+
+		switch size {
+		case 0:
+			// Box extends to the end of the file.
+			size = end - pos
+		case 1:
+			// Box has a 64-bit size.
+			if _, err := io.ReadFull(r, header); err != nil {
+				return 0, ErrNoRotation
+			}
+
+			size = int64(binary.BigEndian.Uint64(header))
+			headerSize = 16
+		}
+
+		if size < headerSize || pos+size > end {
+			return 0, ErrNoRotation
+		}
+
+		switch boxType {
+		case "moov", "trak":
+			if degrees, err := findRotation(r, pos+headerSize, pos+size, depth+1); err == nil {
+				return degrees, nil
+			} else if err != ErrNoRotation {
+				return 0, err
+			}
+		case "tkhd":
+			if degrees, ok := tkhdRotation(r, pos+headerSize, pos+size); ok {
+				return degrees, nil
+			}
+
+			// Skip the other boxes of tracks without frames, e.g. audio tracks.
+			return 0, ErrNoRotation
+		}
+
+		pos += size
+	}
+
+	return 0, ErrNoRotation
+}
+
+// tkhdRotation reads the rotation from the matrix of a track header box, and returns false if the track
+// has no frames, e.g. because it is an audio track.
+func tkhdRotation(r io.ReadSeeker, start, end int64) (int, bool) {
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return 0, false
+	}
+
+	data := make([]byte, end-start)
+
+	if end-start > 128 {
+		return 0, false
+	} else if _, err := io.ReadFull(r, data); err != nil || len(data) < 84 {
+		return 0, false
+	}
+
+	// Version 1 headers have 64-bit times and duration.
+	offset := 40
+
+	if data[0] == 1 {
+		offset = 52
+	}
+
+	if len(data) < offset+44 {
+		return 0, false
+	}
+
+	matrix := data[offset : offset+36]
+	width := binary.BigEndian.Uint32(data[offset+36 : offset+40])
+	height := binary.BigEndian.Uint32(data[offset+40 : offset+44])
+
+	if width == 0 || height == 0 {
+		return 0, false
+	}
+
+	// The first values of the matrix are the 16.16 fixed-point numbers a and b.
+	a := float64(int32(binary.BigEndian.Uint32(matrix[0:4]))) / 65536
+	b := float64(int32(binary.BigEndian.Uint32(matrix[4:8]))) / 65536
+
+	degrees := int(math.Round(math.Atan2(b, a)*180/math.Pi/90)) * 90
+
+	return (degrees + 360) % 360, true
+}
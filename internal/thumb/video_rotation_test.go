@@ -0,0 +1,117 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testTkhd returns a version 0 track header box with the rotation matrix and dimensions.
+func testTkhd(a, b, c, d int32, width, height uint32) []byte {
+	body := make([]byte, 84)
+
+	for i, v := range []int32{a, b, 0, c, d, 0, 0, 0, 0x40000000} {
+		binary.BigEndian.PutUint32(body[40+i*4:], uint32(v))
+	}
+
+	binary.BigEndian.PutUint32(body[76:], width<<16)
+	binary.BigEndian.PutUint32(body[80:], height<<16)
+
+	return testBox("tkhd", body)
+}
+
+// testRotatedVideo returns a minimal MP4 file with an audio track and a rotated video track.
+func testRotatedVideo(t *testing.T, video []byte, jpeg []byte) string {
+	audio := testBox("trak", testTkhd(0x10000, 0, 0, 0x10000, 0, 0))
+	moov := testBox("moov", testBox("mvhd", make([]byte, 100)), audio, testBox("trak", video))
+
+	if jpeg != nil {
+		hdlr := testBox("hdlr", make([]byte, 25))
+		ilst := testBox("ilst", testBox("covr", testBox("data", []byte{0, 0, 0, 13, 0, 0, 0, 0}, jpeg)))
+		moov = testBox("moov", testBox("mvhd", make([]byte, 100)), audio, testBox("trak", video), testBox("udta", testBox("meta", make([]byte, 4), hdlr, ilst)))
+	}
+
+	data := bytes.Join([][]byte{testBox("ftyp", []byte("isom")), testBox("mdat", make([]byte, 64)), moov}, nil)
+	fileName := filepath.Join(t.TempDir(), "video.mp4")
+
+	if err := os.WriteFile(fileName, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return fileName
+}
+
+func TestVideoRotation(t *testing.T) {
+	one, minus := int32(0x10000), int32(-0x10000)
+
+	cases := []struct {
+		name        string
+		tkhd        []byte
+		degrees     int
+		orientation int
+	}{
+		{"0", testTkhd(one, 0, 0, one, 1920, 1080), 0, OrientationNormal},
+		{"90", testTkhd(0, one, minus, 0, 1920, 1080), 90, OrientationRotate270},
+		{"180", testTkhd(minus, 0, 0, minus, 1920, 1080), 180, OrientationRotate180},
+		{"270", testTkhd(0, minus, one, 0, 1920, 1080), 270, OrientationRotate90},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fileName := testRotatedVideo(t, tc.tkhd, nil)
+			degrees, err := VideoRotation(fileName)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.degrees, degrees)
+			assert.Equal(t, tc.orientation, VideoOrientation(fileName))
+		})
+	}
+
+	t.Run("NoTrack", func(t *testing.T) {
+		_, err := VideoRotation(testVideo(t, nil, false))
+		assert.ErrorIs(t, err, ErrNoRotation)
+		assert.Equal(t, OrientationNormal, VideoOrientation(testVideo(t, nil, false)))
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := VideoRotation("testdata/missing.mp4")
+		assert.Error(t, err)
+	})
+}
+
+func TestRotationOrientation(t *testing.T) {
+	assert.Equal(t, OrientationNormal, RotationOrientation(0))
+	assert.Equal(t, OrientationRotate270, RotationOrientation(90))
+	assert.Equal(t, OrientationRotate180, RotationOrientation(-180))
+	assert.Equal(t, OrientationRotate90, RotationOrientation(-90))
+	assert.Equal(t, OrientationNormal, RotationOrientation(45))
+}
+
+func TestOpen_VideoRotation(t *testing.T) {
+	// The poster is 32x16 pixels, so it must be 16x32 pixels when the video is rotated by 90 or 270 degrees.
+	one, minus := int32(0x10000), int32(-0x10000)
+	fileName := testRotatedVideo(t, testTkhd(0, one, minus, 0, 1920, 1080), testPosterJpeg(t))
+
+	t.Run("Rotated", func(t *testing.T) {
+		img, err := Open(fileName, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 16, img.Bounds().Dx())
+		assert.Equal(t, 32, img.Bounds().Dy())
+	})
+	t.Run("KnownOrientation", func(t *testing.T) {
+		img, err := Open(fileName, OrientationRotate180)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 32, img.Bounds().Dx())
+	})
+}
@@ -0,0 +1,96 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// XmpPreview enables creating thumbnails from the edited preview embedded in the XMP sidecar file of an image,
+// so that they reflect edits made in external tools, see FromXmp.
+var XmpPreview = false
+
+// xmpImageRegexp matches the base64 encoded JPEG of an xmp:Thumbnails entry as element or attribute value.
+var xmpImageRegexp = regexp.MustCompile(`xmpGImg:image(?:>|\s*=\s*")([^<"]+)`)
+
+// XmpNames returns the possible XMP sidecar file names of an image, e.g. "IMG_1234.CR2.xmp"
+// as used by Darktable, and "IMG_1234.xmp" as used by Adobe Lightroom.
+func XmpNames(imageFilename string) []string {
+	return []string{imageFilename + fs.ExtXMP, fs.StripKnownExt(imageFilename) + fs.ExtXMP}
+}
+
+// XmpPreviewData returns the JPEG preview embedded in an XMP sidecar file, or ErrNoPreview if there is none.
+func XmpPreviewData(xmpName string) ([]byte, error) {
+	content, err := os.ReadFile(xmpName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+
+	// Use the last match, as newer entries are usually appended.
+	for _, m := range xmpImageRegexp.FindAllSubmatch(content, -1) {
+		s := strings.ReplaceAll(string(m[1]), "&#xA;", "")
+		s = strings.Join(strings.Fields(s), "")
+
+		if b, err := base64.StdEncoding.DecodeString(s); err == nil && bytes.HasPrefix(b, []byte{0xFF, 0xD8}) {
+			data = b
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, ErrNoPreview
+	}
+
+	return data, nil
+}
+
+// FromXmp returns the name of the edited preview embedded in the XMP sidecar file of the image if XmpPreview
+// is enabled and there is one, see XmpNames. The preview is extracted to the thumbnail folder, and extracted
+// again if the sidecar file has been modified since. Previews are expected to be upright.
+func FromXmp(imageFilename, hash, thumbPath string) (fileName string, ok bool) {
+	if !XmpPreview || len(hash) < 4 || thumbPath == "" || IsRemote(imageFilename) {
+		return "", false
+	}
+
+	for _, xmpName := range XmpNames(imageFilename) {
+		xmpInfo, err := os.Stat(xmpName)
+
+		if err != nil || xmpInfo.IsDir() {
+			continue
+		}
+
+		fileName = filepath.Join(Dir(hash, thumbPath), fmt.Sprintf("%s_xmp%s", hash, fs.ExtJPEG))
+
+		// Use previously extracted preview, unless the sidecar file is newer.
+		if info, err := os.Stat(fileName); err == nil && !info.ModTime().Before(xmpInfo.ModTime()) {
+			return fileName, true
+		}
+
+		data, err := XmpPreviewData(xmpName)
+
+		if err != nil {
+			continue
+		} else if err = os.MkdirAll(filepath.Dir(fileName), fs.ModeDir); err != nil {
+			log.Warnf("thumb: %s (extract xmp preview)", err)
+			return "", false
+		} else if err = os.WriteFile(fileName, data, fs.ModeFile); err != nil {
+			log.Warnf("thumb: %s (extract xmp preview)", err)
+			return "", false
+		}
+
+		log.Debugf("thumb: extracted preview from %s", clean.Log(filepath.Base(xmpName)))
+
+		return fileName, true
+	}
+
+	return "", false
+}
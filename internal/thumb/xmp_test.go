@@ -0,0 +1,141 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// testXmp returns an XMP sidecar with the image embedded as preview, wrapped like in Lightroom sidecars.
+func testXmp(t *testing.T, c color.Color) []byte {
+	var buf bytes.Buffer
+
+	if err := imaging.Encode(&buf, imaging.New(32, 16, c), imaging.JPEG); err != nil {
+		t.Fatal(err)
+	}
+
+	s := base64.StdEncoding.EncodeToString(buf.Bytes())
+	s = s[:40] + "&#xA;" + s[40:]
+
+	return []byte(`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:xmp="http://ns.adobe.com/xap/1.0/" xmlns:xmpGImg="http://ns.adobe.com/xap/1.0/g/img/">
+<xmp:Thumbnails><rdf:Alt><rdf:li rdf:parseType="Resource">
+<xmpGImg:width>32</xmpGImg:width><xmpGImg:height>16</xmpGImg:height><xmpGImg:format>JPEG</xmpGImg:format>
+<xmpGImg:image>` + s + `</xmpGImg:image>
+</rdf:li></rdf:Alt></xmp:Thumbnails></rdf:Description></rdf:RDF></x:xmpmeta>`)
+}
+
+func TestXmpNames(t *testing.T) {
+	assert.Equal(t, []string{"/photos/IMG_1234.CR2.xmp", "/photos/IMG_1234.xmp"}, XmpNames("/photos/IMG_1234.CR2"))
+}
+
+func TestXmpPreviewData(t *testing.T) {
+	tempDir := t.TempDir()
+
+	t.Run("Element", func(t *testing.T) {
+		xmpName := filepath.Join(tempDir, "element.xmp")
+
+		if err := os.WriteFile(xmpName, testXmp(t, color.White), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := XmpPreviewData(xmpName)
+
+		assert.NoError(t, err)
+
+		if img, err := imaging.Decode(bytes.NewReader(data)); err != nil {
+			t.Fatal(err)
+		} else {
+			assert.Equal(t, 32, img.Bounds().Dx())
+			assert.Equal(t, 16, img.Bounds().Dy())
+		}
+	})
+	t.Run("Attribute", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		if err := imaging.Encode(&buf, imaging.New(8, 8, color.White), imaging.JPEG); err != nil {
+			t.Fatal(err)
+		}
+
+		xmpName := filepath.Join(tempDir, "attribute.xmp")
+		content := `<rdf:li xmpGImg:width="8" xmpGImg:height="8" xmpGImg:image="` + base64.StdEncoding.EncodeToString(buf.Bytes()) + `"/>`
+
+		if err := os.WriteFile(xmpName, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := XmpPreviewData(xmpName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, buf.Bytes(), data)
+	})
+	t.Run("NoPreview", func(t *testing.T) {
+		xmpName := filepath.Join(tempDir, "empty.xmp")
+
+		if err := os.WriteFile(xmpName, []byte(`<x:xmpmeta xmlns:x="adobe:ns:meta/"></x:xmpmeta>`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := XmpPreviewData(xmpName)
+
+		assert.ErrorIs(t, err, ErrNoPreview)
+	})
+}
+
+func TestFromXmp(t *testing.T) {
+	tempDir := t.TempDir()
+	thumbPath := filepath.Join(tempDir, "cache")
+	hash := "c0ffee9168fa6acc5c5c2965ddf6ec465ca42fd8"
+	imageName := filepath.Join(tempDir, "IMG_1234.jpg")
+
+	if err := imaging.Save(imaging.New(64, 32, color.White), imageName); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		_, ok := FromXmp(imageName, hash, thumbPath)
+		assert.False(t, ok)
+	})
+
+	XmpPreview = true
+	defer func() { XmpPreview = false }()
+
+	t.Run("NoSidecar", func(t *testing.T) {
+		_, ok := FromXmp(imageName, hash, thumbPath)
+		assert.False(t, ok)
+	})
+	t.Run("Sidecar", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(tempDir, "IMG_1234.xmp"), testXmp(t, color.Black), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		fileName, ok := FromXmp(imageName, hash, thumbPath)
+
+		assert.True(t, ok)
+		assert.FileExists(t, fileName)
+	})
+	t.Run("Thumbnail", func(t *testing.T) {
+		thumbName, err := FromFile(imageName, hash, thumbPath, 720, 720, OrientationNormal, ResampleFit, ResampleDefault)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := imaging.Open(thumbName)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The thumbnail must be created from the black preview rather than the white original.
+		assert.Equal(t, 32, img.Bounds().Dx())
+		r, g, b, _ := img.At(16, 8).RGBA()
+		assert.Less(t, r+g+b, uint32(3*0x1000))
+	})
+}
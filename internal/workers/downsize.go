@@ -0,0 +1,64 @@
+package workers
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/dustin/go-humanize/english"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Downsize represents a worker that creates capped derivatives of frequently requested originals,
+// which are otherwise served in full because the requested size exceeds the limit.
+type Downsize struct {
+	conf *config.Config
+}
+
+// NewDownsize returns a new downsize worker.
+func NewDownsize(conf *config.Config) *Downsize {
+	return &Downsize{conf: conf}
+}
+
+// Start creates derivatives of all originals that reached the request threshold, see thumb.DownsizeJobs.
+func (w *Downsize) Start() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("thumbs: %s (worker panic)\nstack: %s", r, debug.Stack())
+			log.Error(err)
+		}
+	}()
+
+	jobs := thumb.DownsizeJobs()
+
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	if err = mutex.ThumbsWorker.Start(); err != nil {
+		return err
+	}
+
+	defer mutex.ThumbsWorker.Stop()
+
+	created := 0
+
+	for _, job := range jobs {
+		if mutex.ThumbsWorker.Canceled() {
+			return nil
+		}
+
+		if _, err := thumb.Downsize(job); err != nil {
+			log.Warnf("thumbs: %s while downsizing %s", err, clean.Log(job.FileName))
+		} else {
+			created++
+		}
+	}
+
+	log.Infof("thumbs: downsized %s", english.Plural(created, "frequently requested original", "frequently requested originals"))
+
+	return nil
+}
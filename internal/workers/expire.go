@@ -0,0 +1,50 @@
+package workers
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/dustin/go-humanize/english"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// Expire represents a worker that removes thumbnails of uncached sizes after their TTL has expired.
+type Expire struct {
+	conf *config.Config
+}
+
+// NewExpire returns a new expire worker.
+func NewExpire(conf *config.Config) *Expire {
+	return &Expire{conf: conf}
+}
+
+// Start removes all expired thumbnails of uncached sizes, see thumb.UncachedTTL.
+func (w *Expire) Start() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("thumbs: %s (worker panic)\nstack: %s", r, debug.Stack())
+			log.Error(err)
+		}
+	}()
+
+	if len(thumb.UncachedTTL) == 0 {
+		return nil
+	}
+
+	if err = mutex.ThumbsWorker.Start(); err != nil {
+		return err
+	}
+
+	defer mutex.ThumbsWorker.Stop()
+
+	removed, err := thumb.ExpireUncached(w.conf.ThumbCachePath(), w.conf.OriginalsPath())
+
+	if removed > 0 {
+		log.Infof("thumbs: removed %s", english.Plural(removed, "expired thumbnail", "expired thumbnails"))
+	}
+
+	return err
+}
@@ -0,0 +1,80 @@
+package workers
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/mutex"
+)
+
+// ReconcileInterval is the minimum time between thumbnail reconciliation runs.
+var ReconcileInterval = 24 * time.Hour
+
+// reconcileLast is the time the thumbnails were last reconciled.
+var reconcileLast time.Time
+
+// Reconcile represents a worker that finds and optionally repairs differences between the index
+// and the thumbnail cache, see config.ThumbReconcile.
+type Reconcile struct {
+	conf *config.Config
+}
+
+// NewReconcile returns a new reconcile worker.
+func NewReconcile(conf *config.Config) *Reconcile {
+	return &Reconcile{conf: conf}
+}
+
+// Start reconciles the thumbnails if enabled, and if they have not been reconciled within the interval.
+func (w *Reconcile) Start() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("thumbs: %s (worker panic)\nstack: %s", r, debug.Stack())
+			log.Error(err)
+		}
+	}()
+
+	mode := w.conf.ThumbReconcile()
+
+	if mode == "" || time.Since(reconcileLast) < ReconcileInterval {
+		return nil
+	}
+
+	if err = mutex.ThumbsWorker.Start(); err != nil {
+		return err
+	}
+
+	defer mutex.ThumbsWorker.Stop()
+
+	start := time.Now()
+	repair := mode == "repair"
+
+	result, err := get.Thumbs().Reconcile(repair)
+
+	if err != nil {
+		return err
+	}
+
+	reconcileLast = time.Now()
+
+	switch {
+	case result.Found() == 0:
+		log.Debugf("thumbs: found no differences between index and cache in %d files [%s]", result.Files, time.Since(start))
+	case repair:
+		log.Infof("thumbs: created thumbnails for %s, removed %s [%s]",
+			english.Plural(result.Repaired, "file", "files"),
+			english.Plural(result.Removed, "orphaned thumbnail", "orphaned thumbnails"),
+			time.Since(start))
+	default:
+		log.Warnf("thumbs: found %s without thumbnails and %s [%s]",
+			english.Plural(len(result.Missing), "indexed file", "indexed files"),
+			english.Plural(len(result.Orphans), "orphaned thumbnail", "orphaned thumbnails"),
+			time.Since(start))
+	}
+
+	return nil
+}
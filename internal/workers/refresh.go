@@ -0,0 +1,74 @@
+package workers
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/dustin/go-humanize/english"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/thumb"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Refresh represents a worker that creates stale thumbnails again after the quality settings were changed.
+type Refresh struct {
+	conf *config.Config
+}
+
+// NewRefresh returns a new refresh worker.
+func NewRefresh(conf *config.Config) *Refresh {
+	return &Refresh{conf: conf}
+}
+
+// Start creates all stale thumbnails that have been requested again, see thumb.RefreshJobs.
+func (w *Refresh) Start() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("thumbs: %s (worker panic)\nstack: %s", r, debug.Stack())
+			log.Error(err)
+		}
+	}()
+
+	jobs := thumb.RefreshJobs()
+
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	if err = mutex.ThumbsWorker.Start(); err != nil {
+		return err
+	}
+
+	defer mutex.ThumbsWorker.Stop()
+
+	refreshed := 0
+
+	for _, job := range jobs {
+		if mutex.ThumbsWorker.Canceled() {
+			return nil
+		}
+
+		f, err := query.FileByHash(job.Hash)
+
+		if err != nil {
+			log.Debugf("thumbs: %s while refreshing %s", err, clean.Log(job.ThumbName))
+			continue
+		}
+
+		fileName := photoprism.FileName(f.FileRoot, f.FileName)
+
+		if err = thumb.Refresh(job, fileName, f.FileOrientation, float64(f.FileAngle)); err != nil {
+			log.Warnf("thumbs: %s while refreshing %s", err, clean.Log(job.ThumbName))
+		} else {
+			refreshed++
+		}
+	}
+
+	log.Infof("thumbs: refreshed %s", english.Plural(refreshed, "stale thumbnail", "stale thumbnails"))
+
+	return nil
+}
@@ -57,11 +57,16 @@ func Start(conf *config.Config) {
 				mutex.MetaWorker.Cancel()
 				mutex.ShareWorker.Cancel()
 				mutex.SyncWorker.Cancel()
+				mutex.ThumbsWorker.Cancel()
 				return
 			case <-ticker.C:
 				RunMeta(conf)
 				RunShare(conf)
 				RunSync(conf)
+				RunDownsize(conf)
+				RunRefresh(conf)
+				RunExpire(conf)
+				RunReconcile(conf)
 			}
 		}
 	}()
@@ -111,3 +116,51 @@ func RunSync(conf *config.Config) {
 		}()
 	}
 }
+
+// RunDownsize runs the downsize worker once.
+func RunDownsize(conf *config.Config) {
+	if !mutex.ThumbsWorker.Running() {
+		go func() {
+			worker := NewDownsize(conf)
+			if err := worker.Start(); err != nil {
+				log.Warnf("thumbs: %s", err)
+			}
+		}()
+	}
+}
+
+// RunRefresh runs the refresh worker once.
+func RunRefresh(conf *config.Config) {
+	if !mutex.ThumbsWorker.Running() {
+		go func() {
+			worker := NewRefresh(conf)
+			if err := worker.Start(); err != nil {
+				log.Warnf("thumbs: %s", err)
+			}
+		}()
+	}
+}
+
+// RunExpire runs the expire worker once.
+func RunExpire(conf *config.Config) {
+	if !mutex.ThumbsWorker.Running() {
+		go func() {
+			worker := NewExpire(conf)
+			if err := worker.Start(); err != nil {
+				log.Warnf("thumbs: %s", err)
+			}
+		}()
+	}
+}
+
+// RunReconcile runs the reconcile worker once.
+func RunReconcile(conf *config.Config) {
+	if !mutex.ThumbsWorker.Running() {
+		go func() {
+			worker := NewReconcile(conf)
+			if err := worker.Start(); err != nil {
+				log.Warnf("thumbs: %s", err)
+			}
+		}()
+	}
+}
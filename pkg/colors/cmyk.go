@@ -0,0 +1,58 @@
+package colors
+
+import (
+	"image"
+	"image/color"
+)
+
+// CMYKToSRGB converts a CMYK image, e.g. from a print source, to sRGB colors. Unlike the naive conversion of
+// the standard library, it approximates a coated press profile (U.S. Web Coated SWOP), so that colors do
+// not appear oversaturated and black is not rendered as pure black.
+func CMYKToSRGB(img *image.CMYK) *image.NRGBA {
+	b := img.Bounds()
+	out := image.NewNRGBA(b)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.SetNRGBA(x, y, CMYKToNRGBA(img.CMYKAt(x, y)))
+		}
+	}
+
+	return out
+}
+
+// CMYKToNRGBA converts a CMYK color to sRGB, see CMYKToSRGB.
+func CMYKToNRGBA(v color.CMYK) color.NRGBA {
+	c, m, y, k := float64(v.C)/255, float64(v.M)/255, float64(v.Y)/255, float64(v.K)/255
+
+	// Polynomial fit of the SWOP press profile, as used by pdf.js for device CMYK colors.
+	r := 255 +
+		c*(-4.387332384609988*c+54.48615194189176*m+18.82290502165302*y+212.25662451639585*k-285.2331026137004) +
+		m*(1.7149763477362134*m-5.6096736904047315*y-17.873870861415444*k-5.497006427196366) +
+		y*(-2.5217340131683033*y-21.248923337353073*k+17.5119270841813) +
+		k*(-21.86122147463605*k-189.48180835922747)
+	g := 255 +
+		c*(8.841041422036149*c+60.118027045597366*m+6.871425592049007*y+31.159100130055922*k-79.2970844816548) +
+		m*(-15.310361306967817*m+17.575251261109482*y+131.35250912493976*k-190.9453302588951) +
+		y*(4.444339102852739*y+9.8632861493405*k-24.86741582555878) +
+		k*(-20.737325471181034*k-187.80453709719578)
+	bl := 255 +
+		c*(0.8842522430003296*c+8.078677503112928*m+30.89978309703729*y-0.23883238689178934*k-14.183576799673286) +
+		m*(10.49593273432072*m+63.02378494754052*y+50.606957656360734*k-112.23884253719248) +
+		y*(0.03296041114873217*y+115.60384449646641*k-193.58209356861505) +
+		k*(-22.720276221369477*k-180.56591962186937)
+
+	return color.NRGBA{R: clampByte(r), G: clampByte(g), B: clampByte(bl), A: 255}
+}
+
+// clampByte rounds the value and limits it to the range of a byte.
+func clampByte(v float64) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	default:
+		return uint8(v + 0.5)
+	}
+}
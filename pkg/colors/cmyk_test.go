@@ -0,0 +1,39 @@
+package colors
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCMYKToNRGBA(t *testing.T) {
+	t.Run("White", func(t *testing.T) {
+		assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, CMYKToNRGBA(color.CMYK{}))
+	})
+	t.Run("Black", func(t *testing.T) {
+		result := CMYKToNRGBA(color.CMYK{K: 255})
+		assert.Less(t, result.R, uint8(60))
+		assert.Less(t, result.G, uint8(60))
+		assert.Less(t, result.B, uint8(60))
+	})
+	t.Run("Cyan", func(t *testing.T) {
+		result := CMYKToNRGBA(color.CMYK{C: 255})
+		assert.Equal(t, uint8(0), result.R)
+		assert.InDelta(t, 174, int(result.G), 15)
+		assert.InDelta(t, 239, int(result.B), 10)
+	})
+}
+
+func TestCMYKToSRGB(t *testing.T) {
+	img := image.NewCMYK(image.Rect(0, 0, 2, 1))
+	img.SetCMYK(0, 0, color.CMYK{C: 255})
+	img.SetCMYK(1, 0, color.CMYK{})
+
+	result := CMYKToSRGB(img)
+
+	assert.Equal(t, img.Bounds(), result.Bounds())
+	assert.Equal(t, CMYKToNRGBA(color.CMYK{C: 255}), result.NRGBAAt(0, 0))
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, result.NRGBAAt(1, 0))
+}
@@ -15,6 +15,8 @@ const (
 	ExtMP4  = ".mp4"
 	ExtMOV  = ".mov"
 	ExtYAML = ".yml"
+	ExtJSON = ".json"
+	ExtXMP  = ".xmp"
 )
 
 // Ext returns all extension of a file name including the dots.
@@ -39,6 +39,8 @@ var Extensions = FileExtensions{
 	".heifs":    ImageHEICS,
 	".heics":    ImageHEICS,
 	".webp":     ImageWebP,
+	".dcm":      ImageDICOM,
+	".dicom":    ImageDICOM,
 	".mpo":      ImageMPO,
 	".3fr":      ImageRaw,
 	".ari":      ImageRaw,
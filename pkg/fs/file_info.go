@@ -20,6 +20,7 @@ var TypeInfo = TypeMap{
 	ImageHEIC:       "High Efficiency Image Container",
 	ImageHEICS:      "HEIC Image Sequence",
 	ImageWebP:       "Google WebP",
+	ImageDICOM:      "Digital Imaging and Communications in Medicine",
 	VideoWebM:       "Google WebM",
 	VideoMP2:        "MPEG 2 (H.262)",
 	VideoAVC:        "Advanced Video Coding (H.264, MPEG-4 Part 10)",
@@ -28,6 +28,7 @@ const (
 	ImageBMP        Type = "bmp"   // BMP Image
 	ImageMPO        Type = "mpo"   // Stereoscopic Image that consists of two JPG images that are combined into one 3D image
 	ImageWebP       Type = "webp"  // Google WebP Image
+	ImageDICOM      Type = "dcm"   // DICOM Medical Image
 	VideoWebM       Type = "webm"  // Google WebM Video
 	VideoAVC        Type = "avc"   // H.264, Advanced Video Coding (AVC, MPEG-4 Part 10)
 	VideoHEVC       Type = "hevc"  // H.265, High Efficiency Video Coding (HEVC)
@@ -6,8 +6,27 @@ import (
 	"hash/crc32"
 	"io"
 	"os"
+	"strings"
 )
 
+// HashType represents a content hash algorithm.
+type HashType string
+
+// Supported hash types, sha1 is the default.
+const (
+	HashSHA1   HashType = "sha1"
+	HashBlake3 HashType = "blake3"
+)
+
+// ParseHash splits a hash string with optional type prefix like "blake3:..." into type and value.
+func ParseHash(s string) (hashType HashType, hash string) {
+	if i := strings.IndexRune(s, ':'); i < 0 {
+		return HashSHA1, s
+	} else {
+		return HashType(strings.ToLower(s[:i])), s[i+1:]
+	}
+}
+
 // Hash returns the SHA1 hash of a file as string.
 func Hash(fileName string) string {
 	var result []byte
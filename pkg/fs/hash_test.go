@@ -37,3 +37,26 @@ func TestIsHash(t *testing.T) {
 		assert.Equal(t, true, IsHash("516cb1fefbfd9fa66f1db50b94503a480cee30db"))
 	})
 }
+
+func TestParseHash(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		hashType, hash := ParseHash("516cb1fefbfd9fa66f1db50b94503a480cee30db")
+		assert.Equal(t, HashSHA1, hashType)
+		assert.Equal(t, "516cb1fefbfd9fa66f1db50b94503a480cee30db", hash)
+	})
+	t.Run("SHA1", func(t *testing.T) {
+		hashType, hash := ParseHash("sha1:516cb1fefbfd9fa66f1db50b94503a480cee30db")
+		assert.Equal(t, HashSHA1, hashType)
+		assert.Equal(t, "516cb1fefbfd9fa66f1db50b94503a480cee30db", hash)
+	})
+	t.Run("Blake3", func(t *testing.T) {
+		hashType, hash := ParseHash("BLAKE3:af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262")
+		assert.Equal(t, HashBlake3, hashType)
+		assert.Equal(t, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262", hash)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		hashType, hash := ParseHash("")
+		assert.Equal(t, HashSHA1, hashType)
+		assert.Equal(t, "", hash)
+	})
+}
@@ -22,6 +22,8 @@ const (
 	MimeTypeHEIC    = "image/heic"
 	MimeTypeHEICS   = "image/heic-sequence"
 	MimeTypeWebP    = "image/webp"
+	MimeTypeDICOM   = "application/dicom"
+	MimeTypePSD     = "image/vnd.adobe.photoshop"
 	MimeTypeMP4     = "video/mp4"
 	MimeTypeMOV     = "video/quicktime"
 	MimeTypeSVG     = "image/svg+xml"
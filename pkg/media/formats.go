@@ -20,6 +20,7 @@ var Formats = map[fs.Type]Type{
 	fs.ImageHEICS:      Image,
 	fs.VideoHEVC:       Video,
 	fs.ImageWebP:       Image,
+	fs.ImageDICOM:      Image,
 	fs.VideoWebM:       Video,
 	fs.VideoAVI:        Video,
 	fs.VideoAVC:        Video,